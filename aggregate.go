@@ -0,0 +1,213 @@
+package ranklist
+
+import (
+	"cmp"
+	"math"
+	"reflect"
+	"slices"
+	"unsafe"
+)
+
+// Aggregate 决定多个来源中同一个键的值如何合并
+// Aggregate decides how the values of the same key from several sources are combined
+type Aggregate int
+
+const (
+	// AggregateSum 取各来源值之和
+	// AggregateSum takes the sum of the values from every source
+	AggregateSum Aggregate = iota
+
+	// AggregateMin 取各来源值中的最小值
+	// AggregateMin takes the smallest of the values from every source
+	AggregateMin
+
+	// AggregateMax 取各来源值中的最大值
+	// AggregateMax takes the largest of the values from every source
+	AggregateMax
+)
+
+// UnionInto 将 sources 中的全部键按权重合并后写入 dst，效果与 Redis 的 ZUNIONSTORE 相同。
+// 每个值先乘以所在来源的权重，再按 agg 与其他来源中同一个键的值合并；weights 为 nil 时所有权重为 1。
+// 所有来源的读锁按固定顺序同时持有，因此结果来自同一时刻；随后 dst 在一把写锁内被原子地替换，dst 也可以是来源之一。
+// 运算按 float64 进行：整数类型的值在合并完成后按四舍五入（半数远离零）取整，超出类型范围时饱和到最大或最小值，
+// 超过 2^53 的整数会丢失精度。值类型不是数字时返回 ErrNonNumericValue，权重数量与来源数量不一致时返回 ErrInvalidWeights
+// UnionInto merges every key of sources into dst by weight, the same as Redis ZUNIONSTORE.
+// Every value is multiplied by the weight of its source, then combined by agg with the values of the same key
+// from the other sources; a nil weights means a weight of 1 for everything.
+// The read locks of all sources are held together in a fixed order, so the result reflects one moment;
+// dst is then replaced atomically under one write lock, and may itself be one of the sources.
+// The arithmetic is done in float64: integer values are rounded half away from zero once combined,
+// saturating at the bounds of the type, and integers beyond 2^53 lose precision.
+// Returns ErrNonNumericValue when the value type is not numeric and ErrInvalidWeights
+// when the number of weights does not match the number of sources
+func UnionInto[K Ordered, V Ordered](dst *RankList[K, V], sources []*RankList[K, V], weights []float64, agg Aggregate) error {
+	if kindOf[V]() == reflect.String {
+		return ErrNonNumericValue
+	}
+	if weights != nil && len(weights) != len(sources) {
+		return ErrInvalidWeights
+	}
+
+	combined := make(map[K]float64)
+	for i, entries := range SnapshotAll(sources...) {
+		weight := 1.0
+		if weights != nil {
+			weight = weights[i]
+		}
+		for _, entry := range entries {
+			value := toFloat(entry.Value) * weight
+			if prev, exists := combined[entry.Key]; exists {
+				value = agg.combine(prev, value)
+			}
+			combined[entry.Key] = value
+		}
+	}
+
+	entries := make([]Entry[K, V], 0, len(combined))
+	for key, value := range combined {
+		entries = append(entries, Entry[K, V]{Key: key, Value: fromFloat[V](value)})
+	}
+	dst.Restore(entries)
+	return nil
+}
+
+// IntersectInto 只保留在 sources 的每一个来源中都存在的键，按 agg 合并它们的值后写入 dst，效果与 Redis 的 ZINTERSTORE 相同。
+// 快照、加锁顺序、取整规则和错误与 UnionInto 相同；sources 为空时 dst 被清空
+// IntersectInto keeps only the keys present in every one of sources, combines their values by agg and writes them into dst,
+// the same as Redis ZINTERSTORE.
+// Snapshotting, lock order, rounding and errors are the same as UnionInto; an empty sources leaves dst empty
+func IntersectInto[K Ordered, V Ordered](dst *RankList[K, V], sources []*RankList[K, V], agg Aggregate) error {
+	if kindOf[V]() == reflect.String {
+		return ErrNonNumericValue
+	}
+
+	var combined map[K]float64
+	for i, entries := range SnapshotAll(sources...) {
+		next := make(map[K]float64, len(entries))
+		for _, entry := range entries {
+			value := toFloat(entry.Value)
+			if i > 0 {
+				prev, exists := combined[entry.Key]
+				if !exists {
+					continue
+				}
+				value = agg.combine(prev, value)
+			}
+			next[entry.Key] = value
+		}
+		combined = next
+	}
+
+	entries := make([]Entry[K, V], 0, len(combined))
+	for key, value := range combined {
+		entries = append(entries, Entry[K, V]{Key: key, Value: fromFloat[V](value)})
+	}
+	dst.Restore(entries)
+	return nil
+}
+
+// combine 按聚合方式合并两个值
+// combine combines two values according to the aggregate
+func (agg Aggregate) combine(a, b float64) float64 {
+	switch agg {
+	case AggregateMin:
+		return min(a, b)
+	case AggregateMax:
+		return max(a, b)
+	default:
+		return a + b
+	}
+}
+
+// SnapshotAll 同时持有所有跳表的读锁，按排名顺序返回它们各自的全部条目，结果与 lists 一一对应，
+// 例如在同一时刻捕获日榜、周榜和总榜。读锁按固定顺序获取，因此与其他多跳表操作并发时不会死锁；
+// 同一个跳表可以出现多次。复制期间所有跳表的写操作都会被阻塞，耗时与条目总数成正比
+// SnapshotAll holds the read locks of all lists together and returns the entries of each in rank order,
+// matching lists by index, e.g. to capture the daily, weekly and all-time boards at the same instant.
+// The read locks are taken in a fixed order, so it never deadlocks with other operations spanning several lists;
+// a list may appear more than once. Writers to every one of the lists are blocked while the copies are made,
+// which takes time proportional to the total number of entries
+func SnapshotAll[K Ordered, V Ordered](lists ...*RankList[K, V]) [][]Entry[K, V] {
+	defer rlockAll(lists)()
+
+	snapshots := make([][]Entry[K, V], len(lists))
+	for i, sl := range lists {
+		snapshots[i] = sl.entries()
+	}
+	return snapshots
+}
+
+// rlockAll 获取所有跳表的读锁并返回释放它们的函数。
+// 读锁按跳表的地址顺序获取，同一个跳表只锁一次，因此并发的多跳表操作之间不会死锁
+// rlockAll takes the read locks of all lists and returns a function releasing them.
+// The read locks are taken in address order and each list is locked once,
+// so concurrent operations spanning several lists never deadlock each other
+func rlockAll[K Ordered, V Ordered](lists []*RankList[K, V]) (unlock func()) {
+	locked := lockOrder(lists)
+	for _, sl := range locked {
+		sl.RLock()
+	}
+	return func() {
+		for _, sl := range locked {
+			sl.RUnlock()
+		}
+	}
+}
+
+// lockOrder 返回去重后按地址排序的跳表，多跳表操作都按这一顺序加锁
+// lockOrder returns the lists deduplicated and sorted by address, the order every operation spanning several lists locks in
+func lockOrder[K Ordered, V Ordered](lists []*RankList[K, V]) []*RankList[K, V] {
+	locked := slices.Clone(lists)
+	slices.SortFunc(locked, func(a, b *RankList[K, V]) int {
+		return cmp.Compare(uintptr(unsafe.Pointer(a)), uintptr(unsafe.Pointer(b)))
+	})
+	return slices.Compact(locked)
+}
+
+// toFloat 将数字类型的值转换为 float64
+// toFloat converts a numeric value to float64
+func toFloat[V Ordered](v V) float64 {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	}
+	return 0
+}
+
+// fromFloat 将 float64 转换为数字类型 V，整数按四舍五入（半数远离零）取整并饱和到类型的取值范围，NaN 转换为零
+// fromFloat converts a float64 to the numeric type V. Integers are rounded half away from zero
+// and saturate at the bounds of the type, NaN becomes zero
+func fromFloat[V Ordered](f float64) V {
+	var v V
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := rv.Type().Bits()
+		switch f = math.Round(f); {
+		case math.IsNaN(f):
+		case f >= math.Ldexp(1, bits-1):
+			rv.SetInt(1<<(bits-1) - 1)
+		case f < -math.Ldexp(1, bits-1):
+			rv.SetInt(-1 << (bits - 1))
+		default:
+			rv.SetInt(int64(f))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		bits := rv.Type().Bits()
+		switch f = math.Round(f); {
+		case math.IsNaN(f) || f <= 0:
+		case f >= math.Ldexp(1, bits):
+			rv.SetUint(1<<bits - 1)
+		default:
+			rv.SetUint(uint64(f))
+		}
+	case reflect.Float32, reflect.Float64:
+		rv.SetFloat(f)
+	}
+	return v
+}
@@ -0,0 +1,246 @@
+package ranklist
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestUnionInto(t *testing.T) {
+	week1 := New[string, int]()
+	week1.Set("a", 10)
+	week1.Set("b", 20)
+	week2 := New[string, int]()
+	week2.Set("b", 5)
+	week2.Set("c", 7)
+	bonus := New[string, int]()
+	bonus.Set("a", 3)
+	bonus.Set("d", 9)
+
+	dst := New[string, int]()
+	dst.Set("stale", 1)
+	err := UnionInto(dst, []*RankList[string, int]{week1, week2, bonus}, []float64{1, 1, 0.5}, AggregateSum)
+	if err != nil {
+		t.Fatalf("UnionInto failed: %v", err)
+	}
+	// a = 10 + 1.5 -> 12, d = 4.5 -> 5, both rounded half away from zero
+	requireModel(t, dst, map[string]int{"a": 12, "b": 25, "c": 7, "d": 5})
+
+	if err := UnionInto(dst, []*RankList[string, int]{week1, week2}, nil, AggregateMin); err != nil {
+		t.Fatalf("UnionInto failed: %v", err)
+	}
+	requireModel(t, dst, map[string]int{"a": 10, "b": 5, "c": 7})
+
+	if err := UnionInto(dst, []*RankList[string, int]{week1, week2}, nil, AggregateMax); err != nil {
+		t.Fatalf("UnionInto failed: %v", err)
+	}
+	requireModel(t, dst, map[string]int{"a": 10, "b": 20, "c": 7})
+}
+
+func TestUnionIntoZeroWeight(t *testing.T) {
+	a := New[string, int]()
+	a.Set("x", 10)
+	b := New[string, int]()
+	b.Set("x", 100)
+	b.Set("y", 50)
+
+	dst := New[string, int]()
+	if err := UnionInto(dst, []*RankList[string, int]{a, b}, []float64{1, 0}, AggregateSum); err != nil {
+		t.Fatalf("UnionInto failed: %v", err)
+	}
+	// 权重为零的来源仍然贡献成员
+	// A zero-weighted source still contributes its members
+	requireModel(t, dst, map[string]int{"x": 10, "y": 0})
+}
+
+func TestUnionIntoSelf(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	if err := UnionInto(sl, []*RankList[string, int]{sl, sl}, nil, AggregateSum); err != nil {
+		t.Fatalf("UnionInto failed: %v", err)
+	}
+	requireModel(t, sl, map[string]int{"a": 2, "b": 4})
+}
+
+func TestUnionIntoErrors(t *testing.T) {
+	ints := New[string, int]()
+	if err := UnionInto(ints, []*RankList[string, int]{ints}, []float64{1, 2}, AggregateSum); !errors.Is(err, ErrInvalidWeights) {
+		t.Errorf("expected ErrInvalidWeights, got %v", err)
+	}
+	strs := New[string, string]()
+	if err := UnionInto(strs, []*RankList[string, string]{strs}, nil, AggregateSum); !errors.Is(err, ErrNonNumericValue) {
+		t.Errorf("expected ErrNonNumericValue, got %v", err)
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	if v := fromFloat[int](2.5); v != 3 {
+		t.Errorf("expected 3, got %d", v)
+	}
+	if v := fromFloat[int](-2.5); v != -3 {
+		t.Errorf("expected -3, got %d", v)
+	}
+	if v := fromFloat[int8](1000); v != math.MaxInt8 {
+		t.Errorf("expected %d, got %d", math.MaxInt8, v)
+	}
+	if v := fromFloat[int64](-1e300); v != math.MinInt64 {
+		t.Errorf("expected %d, got %d", int64(math.MinInt64), v)
+	}
+	if v := fromFloat[uint64](1e300); v != math.MaxUint64 {
+		t.Errorf("expected %d, got %d", uint64(math.MaxUint64), v)
+	}
+	if v := fromFloat[uint](-5); v != 0 {
+		t.Errorf("expected 0, got %d", v)
+	}
+	if v := fromFloat[int](math.NaN()); v != 0 {
+		t.Errorf("expected 0, got %d", v)
+	}
+	if v := fromFloat[float64](0.25); v != 0.25 {
+		t.Errorf("expected 0.25, got %v", v)
+	}
+}
+
+func TestIntersectInto(t *testing.T) {
+	a := New[string, int]()
+	b := New[string, int]()
+	for i, key := range []string{"x", "y", "z"} {
+		a.Set(key, i+1)
+		b.Set(key, 10*(i+1))
+	}
+
+	dst := New[string, int]()
+	if err := IntersectInto(dst, []*RankList[string, int]{a, b}, AggregateSum); err != nil {
+		t.Fatalf("IntersectInto failed: %v", err)
+	}
+	requireModel(t, dst, map[string]int{"x": 11, "y": 22, "z": 33})
+
+	disjoint := New[string, int]()
+	disjoint.Set("w", 5)
+	if err := IntersectInto(dst, []*RankList[string, int]{a, disjoint}, AggregateSum); err != nil {
+		t.Fatalf("IntersectInto failed: %v", err)
+	}
+	if dst.Length() != 0 {
+		t.Fatalf("expected an empty result, got %v", dst.Snapshot())
+	}
+
+	if err := IntersectInto(dst, nil, AggregateSum); err != nil || dst.Length() != 0 {
+		t.Fatalf("expected an empty result for no sources, got %v, %v", dst.Snapshot(), err)
+	}
+}
+
+func TestIntersectIntoThreeWay(t *testing.T) {
+	a := New[string, int]()
+	a.Set("p", 5)
+	a.Set("q", 7)
+	a.Set("r", 1)
+	a.Set("s", 9)
+	b := New[string, int]()
+	b.Set("p", 3)
+	b.Set("q", 1)
+	b.Set("s", 2)
+	c := New[string, int]()
+	c.Set("p", 2)
+	c.Set("q", 2)
+	c.Set("r", 4)
+	sources := []*RankList[string, int]{a, b, c}
+
+	dst := New[string, int]()
+	if err := IntersectInto(dst, sources, AggregateSum); err != nil {
+		t.Fatalf("IntersectInto failed: %v", err)
+	}
+	// p 和 q 的和都是 10，按键排序
+	// p and q both sum to 10 and are ordered by key
+	requireModel(t, dst, map[string]int{"p": 10, "q": 10})
+	if entries := dst.Snapshot(); entries[0].Key != "p" || entries[1].Key != "q" {
+		t.Fatalf("expected ties ordered by key, got %v", entries)
+	}
+
+	if err := IntersectInto(dst, sources, AggregateMin); err != nil {
+		t.Fatalf("IntersectInto failed: %v", err)
+	}
+	requireModel(t, dst, map[string]int{"p": 2, "q": 1})
+
+	if err := IntersectInto(dst, sources, AggregateMax); err != nil {
+		t.Fatalf("IntersectInto failed: %v", err)
+	}
+	requireModel(t, dst, map[string]int{"p": 5, "q": 7})
+}
+
+func TestAggregateConcurrent(t *testing.T) {
+	a := New[int, int]()
+	b := New[int, int]()
+	for i := 0; i < 100; i++ {
+		a.Set(i, i)
+		b.Set(i, i)
+	}
+
+	// 两个方向的来源顺序同时运行，加锁顺序固定因此不会死锁
+	// Both source orders run at the same time, the fixed lock order prevents deadlocks
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			a.Set(i%100, i)
+			IntersectInto(b, []*RankList[int, int]{a, b}, AggregateMax)
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		b.Set(i%100, i)
+		UnionInto(a, []*RankList[int, int]{b, a}, nil, AggregateMax)
+	}
+	<-done
+}
+
+func TestSnapshotAllConsistent(t *testing.T) {
+	daily, weekly, allTime := New[string, int](), New[string, int](), New[string, int]()
+	boards := []*RankList[string, int]{daily, weekly, allTime}
+
+	// 写入方以不同的顺序传入跳表，同时把同一个成绩写入三个榜单
+	// Writers pass the lists in different orders and write the same score to all three boards
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for g := 0; g < 3; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			order := slices.Clone(boards)
+			slices.Reverse(order[:g+1])
+			for i := 0; ; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				key := "player" + strconv.Itoa(i%5)
+				TxnAll(order, func(txs []*Tx[string, int]) error {
+					for _, tx := range txs {
+						tx.IncrBy(key, 1)
+					}
+					return nil
+				})
+			}
+		}(g)
+	}
+
+	for i := 0; i < 200; i++ {
+		snapshots := SnapshotAll(daily, weekly, allTime, daily)
+		if len(snapshots) != 4 || !slices.Equal(snapshots[0], snapshots[3]) {
+			t.Fatalf("a repeated list should yield the same snapshot")
+		}
+		for _, snapshot := range snapshots[1:3] {
+			if !slices.Equal(snapshot, snapshots[0]) {
+				t.Fatalf("the boards were captured at different moments:\n%v\n%v", snapshots[0], snapshot)
+			}
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	if snapshots := SnapshotAll[string, int](); len(snapshots) != 0 {
+		t.Fatalf("expected no snapshots, got %v", snapshots)
+	}
+}
@@ -0,0 +1,68 @@
+package ranklist
+
+// arena 从预先分配的大块内存中切分节点及其前向指针和跨度，
+// 被删除的节点按层级挂在空闲链表上复用，重置跳表时整个 arena 一起释放
+// arena carves nodes, their forward pointers and their spans out of pre-allocated chunks.
+// Deleted nodes are kept on per-level free lists for reuse and the whole arena is released when the list is reset
+type arena[K Ordered, V Ordered] struct {
+	size  int
+	nodes []Node[K, V]
+	ptrs  []*Node[K, V]
+	spans []int
+	free  [MaxLevel][]*Node[K, V]
+}
+
+// WithArena 让节点从每块 chunkSize 个节点的大块内存中分配，以提高局部性并降低分配器开销，
+// 适合先批量加载再大量读取的静态榜单。Clear 等重置操作会一次性释放整个 arena。
+// 只要块中还有一个节点存活，整块内存就不会被回收，频繁增删的榜单不适合使用
+// WithArena allocates nodes out of chunks of chunkSize nodes to improve locality and cut allocator overhead,
+// which suits static boards that are loaded in bulk and then read heavily.
+// Resets such as Clear release the whole arena at once.
+// A chunk stays alive as long as any of its nodes does, so boards with heavy churn should not use it
+func WithArena[K Ordered, V Ordered](chunkSize int) Option[K, V] {
+	if chunkSize < 1 {
+		panic("ranklist: arena chunk size must be positive")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.arena = &arena[K, V]{size: chunkSize}
+	}
+}
+
+// alloc 分配一个指定层级的节点，优先复用空闲链表中的节点
+// alloc allocates a node of the given level, reusing one from the free list first
+func (a *arena[K, V]) alloc(key K, value V, level int) *Node[K, V] {
+	free := a.free[level-1]
+	if n := len(free); n > 0 {
+		node := free[n-1]
+		a.free[level-1] = free[:n-1]
+		node.data = Entry[K, V]{Key: key, Value: value}
+		return node
+	}
+
+	if len(a.nodes) == 0 {
+		a.nodes = make([]Node[K, V], a.size)
+	}
+	if len(a.ptrs) < level {
+		a.ptrs = make([]*Node[K, V], max(a.size, level))
+		a.spans = make([]int, max(a.size, level))
+	}
+
+	node := &a.nodes[0]
+	a.nodes = a.nodes[1:]
+	node.data = Entry[K, V]{Key: key, Value: value}
+	node.level = level
+
+	// 容量限制为层级，节点的切片不会越界写入相邻节点的区域
+	// Capacities are capped at the level so a node's slices can never spill into its neighbours
+	node.forward = a.ptrs[:level:level]
+	node.span = a.spans[:level:level]
+	a.ptrs = a.ptrs[level:]
+	a.spans = a.spans[level:]
+	return node
+}
+
+// release 将已清空的节点挂回空闲链表
+// release puts a cleared node back on the free list
+func (a *arena[K, V]) release(node *Node[K, V]) {
+	a.free[node.level-1] = append(a.free[node.level-1], node)
+}
@@ -0,0 +1,73 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"testing"
+)
+
+func TestArenaChurn(t *testing.T) {
+	sl := New(WithArena[string, int](64))
+	model := make(map[string]int)
+
+	for i := 0; i < 20000; i++ {
+		key := "key" + strconv.Itoa(rand.IntN(500))
+		if rand.IntN(4) == 0 {
+			sl.Del(key)
+			delete(model, key)
+		} else {
+			value := rand.IntN(100)
+			sl.Set(key, value)
+			model[key] = value
+		}
+	}
+	requireSpans(t, sl)
+	requireBackward(t, sl)
+	requireModel(t, sl, model)
+
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		if cap(curr.forward) != curr.level || cap(curr.span) != curr.level {
+			t.Fatalf("Key %v: slices carved from the arena should be capped at the node level", curr.data.Key)
+		}
+	}
+}
+
+func TestArenaReleasedOnReset(t *testing.T) {
+	sl := New(WithArena[int, int](16))
+	for i := 0; i < 100; i++ {
+		sl.Set(i, i)
+	}
+	sl.Del(1)
+	old := sl.arena
+
+	sl.Clear()
+	if sl.arena == old || sl.arena.size != 16 {
+		t.Fatalf("Clear should release the arena and start a fresh one of the same chunk size")
+	}
+	for _, free := range sl.arena.free {
+		if len(free) != 0 {
+			t.Fatalf("a fresh arena should have empty free lists")
+		}
+	}
+
+	entries := make([]Entry[int, int], 1000)
+	for i := range entries {
+		entries[i] = Entry[int, int]{Key: i, Value: rand.IntN(50)}
+	}
+	sl.Restore(entries)
+	model := make(map[int]int, len(entries))
+	for _, entry := range entries {
+		model[entry.Key] = entry.Value
+	}
+	requireSpans(t, sl)
+	requireModel(t, sl, model)
+}
+
+func TestWithArenaPanicsOnZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for a zero chunk size")
+		}
+	}()
+	WithArena[int, int](0)
+}
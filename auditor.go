@@ -0,0 +1,99 @@
+package ranklist
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// auditor 后台一致性审计的配置
+// auditor holds the configuration of the background consistency auditor
+type auditor struct {
+	interval time.Duration
+	onError  func(error)
+}
+
+// WithAuditor 在后台每隔 interval 校验一次 Check 描述的全部不变量，发现不一致时调用 onError，用于预发布环境中尽早发现结构缺陷。
+// 传给 onError 的错误包装了 ErrCorrupted，消息中依次是被违反的不变量和 DumpCompact 格式的结构，两者取自同一把读锁，便于定位问题。
+// 同一个违反只报告一次，之后的审计通过或者违反了不同的不变量时才再次报告。onError 在后台 goroutine 中调用，不持有任何锁。
+// 每次审计在一把读锁内遍历第 0 层一次且不分配内存，期间写操作需要等待，10 万个条目大约需要十几毫秒，
+// 更大的跳表应相应地加大 interval。审计直接在跳表上进行而不是在复制品上进行，因为复制内部结构与校验它需要同样长的锁。
+// 默认不开启；跳表被 Close 关闭时后台 goroutine 随之退出。interval 不是正数或 onError 为 nil 时 panic
+// WithAuditor validates every invariant described by Check in the background every interval and calls onError
+// when one is violated, to catch structural bugs early in staging.
+// The error passed to onError wraps ErrCorrupted, its message holds the violated invariant followed by the structure
+// in the DumpCompact format, both taken under the same read lock to aid diagnosis.
+// A violation is reported once, and again only after an audit passes or when a different invariant fails.
+// onError is called from the background goroutine without holding any lock.
+// Each audit walks level 0 once under one read lock without allocating, writers wait meanwhile,
+// roughly a dozen milliseconds for 100k entries, so larger lists should use a longer interval.
+// The audit runs on the list itself rather than on a copy, because copying the internal structure
+// takes the lock for as long as validating it.
+// It is off by default; the background goroutine exits when the list is closed with Close.
+// It panics if interval is not positive or onError is nil
+func WithAuditor[K Ordered, V Ordered](interval time.Duration, onError func(error)) Option[K, V] {
+	if interval <= 0 {
+		panic("ranklist: auditor interval must be positive")
+	}
+	if onError == nil {
+		panic("ranklist: nil auditor callback")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.auditor = &auditor{interval: interval, onError: onError}
+	}
+}
+
+// startAuditor 开启审计时启动后台 goroutine，在初始化时调用
+// startAuditor starts the background goroutine when the auditor is enabled, called during initialization
+func (sl *RankList[K, V]) startAuditor() {
+	if sl.auditor == nil {
+		return
+	}
+	ticker := time.NewTicker(sl.auditor.interval)
+	sl.background.Add(1)
+	go func() {
+		defer sl.background.Done()
+		defer ticker.Stop()
+		sl.runAuditor(ticker.C, sl.auditor.onError)
+	}()
+}
+
+// runAuditor 每次从 tick 收到信号时审计一次，直到跳表被关闭，便于测试时注入触发器
+// runAuditor audits the list every time tick fires until the list is closed, so tests can inject the trigger
+func (sl *RankList[K, V]) runAuditor(tick <-chan time.Time, onError func(error)) {
+	var reported string
+	for {
+		select {
+		case <-sl.done:
+			return
+		case <-tick:
+		}
+
+		dump, violation := sl.audit()
+		if violation == nil {
+			reported = ""
+			continue
+		}
+		if violation.Error() == reported {
+			continue
+		}
+		reported = violation.Error()
+		onError(fmt.Errorf("%w\n%s", violation, dump))
+	}
+}
+
+// audit 在一把读锁内校验跳表，不一致时同时返回 DumpCompact 格式的结构和被违反的不变量
+// audit validates the list under one read lock and returns the structure in the DumpCompact format
+// along with the violated invariant, if any
+func (sl *RankList[K, V]) audit() (string, error) {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	violation := sl.check()
+	if violation == nil {
+		return "", nil
+	}
+	var b strings.Builder
+	sl.dumpCompact(&b)
+	return strings.TrimSuffix(b.String(), "\n"), violation
+}
@@ -0,0 +1,106 @@
+package ranklist
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// auditFixture 启动一个由注入的 tick 驱动的审计，返回 tick、收到的错误和审计退出时关闭的通道
+// auditFixture starts an audit driven by an injected tick and returns the tick, the reported errors
+// and a channel closed when the audit exits
+func auditFixture(sl *RankList[string, int]) (chan<- time.Time, <-chan error, <-chan struct{}) {
+	tick := make(chan time.Time)
+	errs := make(chan error, 8)
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		sl.runAuditor(tick, func(err error) { errs <- err })
+	}()
+	return tick, errs, exited
+}
+
+// audits 触发 n 次审计，最后一次之后再发送一次以确保它已经完成
+// audits fires n audits and one more tick afterwards to make sure the last one has finished
+func audits(tick chan<- time.Time, n int) {
+	for i := 0; i <= n; i++ {
+		tick <- time.Time{}
+	}
+}
+
+func TestAuditorReports(t *testing.T) {
+	sl := New[string, int]()
+	for i := 0; i < 200; i++ {
+		sl.Set(strconv.Itoa(i), i%17)
+	}
+	tick, errs, exited := auditFixture(sl)
+
+	audits(tick, 1)
+	if len(errs) != 0 {
+		t.Fatalf("a consistent list should not be reported, got %v", <-errs)
+	}
+
+	sl.Lock()
+	node := sl.byRank(100)
+	node.span[0]++
+	sl.Unlock()
+	audits(tick, 1)
+	if len(errs) != 1 {
+		t.Fatalf("expected one report after a single audit, got %d", len(errs))
+	}
+	err := <-errs
+	if !errors.Is(err, ErrCorrupted) || !strings.Contains(err.Error(), "spans add up") {
+		t.Fatalf("expected a span violation, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "\nlevel=") || strings.Count(err.Error(), "\n") != 201 {
+		t.Fatalf("expected the report to carry a compact dump of 200 nodes, got %q", err)
+	}
+
+	// 同一个违反只报告一次 / The same violation is reported once
+	audits(tick, 3)
+	if len(errs) != 0 {
+		t.Fatalf("the same violation should not be reported again, got %v", <-errs)
+	}
+
+	// 修复之后再次损坏时重新报告 / Broken again after a repair, it is reported again
+	sl.Lock()
+	node.span[0]--
+	sl.Unlock()
+	audits(tick, 1)
+	sl.Lock()
+	sl.length++
+	sl.Unlock()
+	audits(tick, 1)
+	if len(errs) != 1 || !strings.Contains((<-errs).Error(), "length is") {
+		t.Fatalf("expected the new violation to be reported")
+	}
+
+	sl.Close()
+	<-exited
+}
+
+func TestAuditorClose(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	reports := 0
+	sl := New(WithAuditor[string, int](time.Millisecond, func(error) { reports++ }))
+	sl.Set("a", 1)
+	time.Sleep(10 * time.Millisecond)
+	if err := sl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	requireNoLeak(t, baseline)
+	if reports != 0 {
+		t.Fatalf("a consistent list should not be reported, got %d reports", reports)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for a non-positive interval")
+		}
+	}()
+	WithAuditor[string, int](0, func(error) {})
+}
@@ -0,0 +1,40 @@
+package ranklist
+
+// autoCompactMinPeak 是自动重建的最小规模，最大长度不足它的跳表重建的收益可以忽略
+// autoCompactMinPeak is the smallest size considered for automatic rebuilds,
+// below it the gain of rebuilding a list is negligible
+const autoCompactMinPeak = 1024
+
+// WithAutoCompact 在长度收缩到自上一次整体重建以来最大长度的 1/factor 以下时，在删除所在的写锁内自动执行 Compact。
+// 大量删除或淘汰之后，剩余节点仍带着原有规模下的层级和高大的头节点，字典和 arena 也不会归还内存；
+// 自动重建为当前规模重新生成层级并释放这些内存，使查找性能不会悄悄退化。
+// 每次重建耗时 O(n)，但两次重建之间至少发生了最大长度 (1-1/factor) 倍的删除，因此均摊到每次删除是 O(factor)。
+// 最大长度不足 1024 的跳表不会自动重建。factor 不大于 1 时 panic
+// WithAutoCompact runs Compact automatically, under the write lock of the delete that triggered it,
+// once the length shrinks below 1/factor of the largest length since the last whole rebuild.
+// After massive deletes or evictions the surviving nodes keep the levels and tall header of the original population,
+// and the dictionary and arena keep their memory; the automatic rebuild re-derives the levels for the current size
+// and releases that memory, so search performance does not silently degrade.
+// Every rebuild costs O(n), but at least (1-1/factor) of the largest length was deleted since the previous one,
+// so the cost amortizes to O(factor) per delete.
+// Lists whose largest length is below 1024 are never rebuilt automatically. It panics if factor is not greater than 1
+func WithAutoCompact[K Ordered, V Ordered](factor float64) Option[K, V] {
+	if !(factor > 1) {
+		panic("ranklist: auto compact factor must be greater than 1")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.compactFactor = factor
+	}
+}
+
+// autoCompact 在长度收缩超过 WithAutoCompact 设置的倍数时重建跳表，未开启时不做任何事，调用方需持有写锁
+// autoCompact rebuilds the list once the length has shrunk past the WithAutoCompact factor,
+// a no-op when disabled. The caller must hold the write lock
+func (sl *RankList[K, V]) autoCompact() {
+	if sl.compactFactor == 0 || sl.peak < autoCompactMinPeak {
+		return
+	}
+	if float64(sl.length)*sl.compactFactor < float64(sl.peak) {
+		sl.compact()
+	}
+}
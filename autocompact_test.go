@@ -0,0 +1,59 @@
+package ranklist
+
+import "testing"
+
+func TestAutoCompact(t *testing.T) {
+	const size = 4096
+	sl := New(WithAutoCompact[int, int](4), WithSeed[int, int](1), WithUpdateOrder[int, int]())
+	for i := 0; i < size; i++ {
+		sl.Set(i, i)
+	}
+	sl.Set(0, 1) // 0 成为最近更新的键 / 0 becomes the most recently updated key
+	sl.SetMeta(1, map[string]string{"team": "red"})
+
+	// 从尾部删除到 1/4 以下时在删除内重建
+	// Deleting from the tail below a quarter rebuilds within the delete
+	for i := size - 1; i >= size/4; i-- {
+		sl.Del(i)
+		if err := sl.Check(); err != nil {
+			t.Fatalf("check failed after deleting %d: %v", i, err)
+		}
+	}
+	sl.Del(size/4 - 1)
+	if sl.peak != sl.length {
+		t.Fatalf("expected a rebuild once the length fell below a quarter, peak %d length %d", sl.peak, sl.length)
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	if rank, ok := sl.Rank(0); !ok || rank != 1 {
+		t.Fatalf("expected 0 at rank 1, got %d, %v", rank, ok)
+	}
+	if recent := sl.RecentlyUpdated(1); len(recent) != 1 || recent[0].Key != 0 {
+		t.Fatalf("the rebuild must keep the update order, got %v", recent)
+	}
+	if meta, _ := sl.GetMeta(1); meta["team"] != "red" {
+		t.Fatalf("the rebuild must keep metadata, got %v", meta)
+	}
+	if _, version, _ := sl.GetVersioned(0); version != 2 {
+		t.Fatalf("the rebuild must keep entry versions, got %d", version)
+	}
+	requireSpans(t, sl)
+}
+
+func TestAutoCompactSmallList(t *testing.T) {
+	sl := New(WithAutoCompact[int, int](2))
+	for i := 0; i < 100; i++ {
+		sl.Set(i, i)
+	}
+	for i := 0; i < 99; i++ {
+		sl.Del(i)
+	}
+	if sl.peak != 100 {
+		t.Fatalf("small lists must not be rebuilt, peak %d", sl.peak)
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,96 @@
+package ranklist
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StartAutoSnapshot 在后台每隔 interval 将跳表以 Save 的格式写入 open 返回的 WriteCloser，返回停止函数。
+// 条目在读锁内复制，序列化和写入在锁外进行，不会长时间阻塞写操作。
+// 同一时间最多只有一个快照在进行，上一次快照未完成时到期的周期会被跳过。
+// open、写入或关闭失败时调用 onError（可以为 nil）。stop 会等待进行中的快照完成，可以重复调用；
+// 跳表被 Close 关闭时后台任务同样停止
+// StartAutoSnapshot periodically writes the list in the Save format to the WriteCloser returned by open,
+// every interval in the background, and returns a stop function.
+// Entries are copied under the read lock and serialized outside of it, so writers are not blocked for long.
+// At most one snapshot is in flight at a time, ticks that fire while the previous snapshot is still running are skipped.
+// Failures from open, write or close are passed to onError, which may be nil.
+// stop waits for an in-flight snapshot to finish and is safe to call more than once;
+// closing the list with Close stops the background work as well
+func (sl *RankList[K, V]) StartAutoSnapshot(interval time.Duration, open func() (io.WriteCloser, error), onError func(error)) (stop func()) {
+	ticker := time.NewTicker(interval)
+	halt := sl.autoSnapshot(ticker.C, open, onError)
+	return func() {
+		ticker.Stop()
+		halt()
+	}
+}
+
+// autoSnapshot 每次从 tick 收到信号时触发一次快照，便于测试时注入触发器
+// autoSnapshot takes a snapshot every time tick fires, so tests can inject the trigger
+func (sl *RankList[K, V]) autoSnapshot(tick <-chan time.Time, open func() (io.WriteCloser, error), onError func(error)) (stop func()) {
+	if !sl.startBackground() {
+		return func() {}
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	var inFlight atomic.Bool
+
+	report := func(err error) {
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+
+	// 进行中的快照同时登记为后台 goroutine，Close 也会等待它完成
+	// An in-flight snapshot is registered as a background goroutine too, so Close waits for it as well
+	wg.Add(1)
+	go func() {
+		defer sl.background.Done()
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			case <-sl.done:
+				return
+			case <-tick:
+				if !inFlight.CompareAndSwap(false, true) {
+					continue
+				}
+				wg.Add(1)
+				sl.background.Add(1)
+				go func() {
+					defer sl.background.Done()
+					defer wg.Done()
+					defer inFlight.Store(false)
+					report(sl.snapshotTo(open))
+				}()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// snapshotTo 打开一个 WriteCloser 并写入一次快照
+// snapshotTo opens a WriteCloser and writes one snapshot to it
+func (sl *RankList[K, V]) snapshotTo(open func() (io.WriteCloser, error)) error {
+	w, err := open()
+	if err != nil {
+		return err
+	}
+	if err := sl.Save(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
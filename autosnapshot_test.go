@@ -0,0 +1,135 @@
+package ranklist
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type blockingWriter struct {
+	bytes.Buffer
+	release chan struct{}
+	closed  chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return w.Buffer.Write(p)
+}
+
+func (w *blockingWriter) Close() error {
+	close(w.closed)
+	return nil
+}
+
+func TestAutoSnapshotAtMostOneInFlight(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+
+	var opened atomic.Int32
+	var writers []*blockingWriter
+	var mu sync.Mutex
+	open := func() (io.WriteCloser, error) {
+		opened.Add(1)
+		w := &blockingWriter{release: make(chan struct{}), closed: make(chan struct{})}
+		mu.Lock()
+		writers = append(writers, w)
+		mu.Unlock()
+		return w, nil
+	}
+
+	tick := make(chan time.Time)
+	stop := sl.autoSnapshot(tick, open, nil)
+	defer stop()
+
+	tick <- time.Now()
+	for opened.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 5; i++ {
+		tick <- time.Now()
+	}
+	if n := opened.Load(); n != 1 {
+		t.Fatalf("expected exactly one snapshot in flight, got %d", n)
+	}
+
+	mu.Lock()
+	first := writers[0]
+	mu.Unlock()
+	close(first.release)
+	<-first.closed
+
+	loaded := New[string, int]()
+	if err := loaded.Load(&first.Buffer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, _ := loaded.Get("a"); value != 1 {
+		t.Errorf("expected snapshot to contain a=1, got %d", value)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for opened.Load() != 2 && time.Now().Before(deadline) {
+		select {
+		case tick <- time.Now():
+		default:
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := opened.Load(); n != 2 {
+		t.Fatalf("expected a second snapshot after the first finished, got %d", n)
+	}
+
+	mu.Lock()
+	close(writers[1].release)
+	mu.Unlock()
+}
+
+func TestAutoSnapshotErrors(t *testing.T) {
+	sl := New[string, int]()
+
+	errs := make(chan error, 1)
+	tick := make(chan time.Time)
+	stop := sl.autoSnapshot(tick, func() (io.WriteCloser, error) {
+		return nil, errors.New("open failed")
+	}, func(err error) {
+		errs <- err
+	})
+
+	tick <- time.Now()
+	select {
+	case err := <-errs:
+		if err.Error() != "open failed" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the open error to be reported")
+	}
+	stop()
+	stop()
+}
+
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (*bufferCloser) Close() error {
+	return nil
+}
+
+func TestStartAutoSnapshot(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+
+	snapshots := make(chan *bufferCloser, 16)
+	stop := sl.StartAutoSnapshot(time.Millisecond, func() (io.WriteCloser, error) {
+		w := &bufferCloser{}
+		snapshots <- w
+		return w, nil
+	}, nil)
+	<-snapshots
+	stop()
+}
@@ -0,0 +1,312 @@
+package ranklist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"reflect"
+)
+
+const (
+	// 二进制快照的魔数
+	// Magic bytes of a binary snapshot
+	snapshotMagic = "RKLS"
+
+	// 二进制快照的格式版本
+	// Format version of a binary snapshot
+	snapshotVersion = 1
+)
+
+// Save 将跳表以紧凑的二进制格式写入 w。
+// 格式为：魔数、版本、键和值的类型、条目数量、按排名顺序排列的键值对，最后是 CRC32 校验和。
+// 条目在读锁内复制，编码和写入在锁外进行，不会长时间阻塞写操作。Reserve 插入的占位条目不写入快照
+// Save writes the skip list to w in a compact binary format:
+// magic, version, key and value kinds, entry count, key-value pairs in rank order, followed by a CRC32 checksum.
+// Entries are copied under the read lock and encoded outside of it, so writers are not blocked by slow writers.
+// Placeholders inserted by Reserve are left out of the snapshot
+func (sl *RankList[K, V]) Save(w io.Writer) error {
+	return writeSnapshot(w, sl.savedEntries())
+}
+
+// Load 从 r 读取 Save 写入的二进制快照并替换跳表的全部内容。
+// 数据被截断或校验和不匹配时返回 ErrInvalidSnapshot，跳表保持不变
+// Load reads a binary snapshot written by Save from r and replaces the whole content of the skip list.
+// Truncated data or a checksum mismatch returns ErrInvalidSnapshot and leaves the list untouched
+func (sl *RankList[K, V]) Load(r io.Reader) error {
+	entries, err := readSnapshot[K, V](r)
+	if err != nil {
+		return err
+	}
+
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return ErrClosed
+	}
+	zones := sl.zoneKeys()
+	sl.loadSorted(entries)
+	sl.evictOverflow()
+	sl.journalReset()
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	fireThresholds(events)
+	return nil
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler，使用与 Save 相同的二进制格式
+// MarshalBinary implements encoding.BinaryMarshaler using the same format as Save
+func (sl *RankList[K, V]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := sl.Save(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler，在一把写锁内整体替换跳表的内容
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing the content of the list under one write lock
+func (sl *RankList[K, V]) UnmarshalBinary(data []byte) error {
+	return sl.Load(bytes.NewReader(data))
+}
+
+// loadSorted 用快照中的条目替换跳表内容，条目已按排名排序且键唯一时直接以 O(n) 构建，
+// 否则退回到排序去重的 load，调用方需持有写锁
+// loadSorted replaces the content of the skip list with snapshot entries,
+// entries already in rank order with unique keys are built directly in O(n),
+// anything else falls back to the sorting and deduplicating load. The caller must hold the write lock
+func (sl *RankList[K, V]) loadSorted(entries []Entry[K, V]) {
+	for i := 1; i < len(entries); i++ {
+		if sl.compare(entries[i-1], entries[i]) >= 0 {
+			sl.load(entries)
+			return
+		}
+	}
+
+	// 有序的条目中仍可能出现值不同的重复键，此时字典的大小会小于条目数。
+	// 没有字典时无法以这种方式检查，直接退回到 load
+	// Sorted entries may still repeat a key with different values, which leaves the dict smaller than the input.
+	// Without a dictionary there is nothing to check against, so load is used directly
+	if sl.noDict {
+		sl.load(entries)
+		return
+	}
+	sl.build(entries)
+	if len(sl.dict) != len(entries) {
+		sl.load(entries)
+		return
+	}
+	sl.version.Add(1)
+}
+
+// writeSnapshot 将条目编码为二进制快照写入 w
+// writeSnapshot encodes entries as a binary snapshot into w
+func writeSnapshot[K Ordered, V Ordered](w io.Writer, entries []Entry[K, V]) error {
+	bw := bufio.NewWriter(w)
+	crc := crc32.NewIEEE()
+	out := io.MultiWriter(bw, crc)
+
+	buf := make([]byte, 0, 4096)
+	buf = append(buf, snapshotMagic...)
+	buf = append(buf, snapshotVersion, byte(kindOf[K]()), byte(kindOf[V]()))
+	buf = binary.AppendUvarint(buf, uint64(len(entries)))
+
+	for _, entry := range entries {
+		buf = appendOrdered(buf, entry.Key)
+		buf = appendOrdered(buf, entry.Value)
+		if len(buf) >= 4096 {
+			if _, err := out.Write(buf); err != nil {
+				return err
+			}
+			buf = buf[:0]
+		}
+	}
+	if _, err := out.Write(buf); err != nil {
+		return err
+	}
+	if _, err := bw.Write(binary.LittleEndian.AppendUint32(nil, crc.Sum32())); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// readSnapshot 从 r 读取并校验二进制快照，返回其中的条目
+// readSnapshot reads and verifies a binary snapshot from r and returns its entries
+func readSnapshot[K Ordered, V Ordered](r io.Reader) ([]Entry[K, V], error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	header := len(snapshotMagic) + 3
+	if len(data) < header+4 || string(data[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidSnapshot)
+	}
+
+	payload, sum := data[:len(data)-4], binary.LittleEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(payload) != sum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidSnapshot)
+	}
+
+	if payload[len(snapshotMagic)] != snapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, payload[len(snapshotMagic)])
+	}
+	if reflect.Kind(payload[len(snapshotMagic)+1]) != kindOf[K]() ||
+		reflect.Kind(payload[len(snapshotMagic)+2]) != kindOf[V]() {
+		return nil, fmt.Errorf("%w: key or value type mismatch", ErrInvalidSnapshot)
+	}
+
+	payload = payload[header:]
+	count, n := binary.Uvarint(payload)
+	if n <= 0 || count > uint64(len(payload)) {
+		return nil, fmt.Errorf("%w: invalid entry count", ErrInvalidSnapshot)
+	}
+	payload = payload[n:]
+
+	entries := make([]Entry[K, V], 0, count)
+	for i := uint64(0); i < count; i++ {
+		var entry Entry[K, V]
+		if entry.Key, n, err = readOrdered[K](payload); err != nil {
+			return nil, err
+		}
+		payload = payload[n:]
+		if entry.Value, n, err = readOrdered[V](payload); err != nil {
+			return nil, err
+		}
+		payload = payload[n:]
+		entries = append(entries, entry)
+	}
+	if len(payload) != 0 {
+		return nil, fmt.Errorf("%w: trailing data", ErrInvalidSnapshot)
+	}
+	return entries, nil
+}
+
+// kindOf 返回类型 T 的底层种类
+// kindOf returns the underlying kind of type T
+func kindOf[T Ordered]() reflect.Kind {
+	return reflect.TypeFor[T]().Kind()
+}
+
+// appendOrdered 将 v 按其底层种类编码后追加到 buf：
+// 有符号整数使用 zigzag 变长编码，无符号整数使用变长编码，浮点数使用小端定长编码，字符串使用长度前缀。
+// 内置类型通过类型分支直接编码，只有自定义的具名类型才退回到反射
+// appendOrdered appends the encoding of v to buf according to its underlying kind:
+// signed integers use zigzag varints, unsigned integers use uvarints,
+// floats use little-endian fixed width and strings are length-prefixed.
+// Built-in types are encoded directly through a type switch, only named types fall back to reflection
+func appendOrdered[T Ordered](buf []byte, v T) []byte {
+	switch x := any(v).(type) {
+	case int:
+		return binary.AppendVarint(buf, int64(x))
+	case int8:
+		return binary.AppendVarint(buf, int64(x))
+	case int16:
+		return binary.AppendVarint(buf, int64(x))
+	case int32:
+		return binary.AppendVarint(buf, int64(x))
+	case int64:
+		return binary.AppendVarint(buf, x)
+	case uint:
+		return binary.AppendUvarint(buf, uint64(x))
+	case uint8:
+		return binary.AppendUvarint(buf, uint64(x))
+	case uint16:
+		return binary.AppendUvarint(buf, uint64(x))
+	case uint32:
+		return binary.AppendUvarint(buf, uint64(x))
+	case uint64:
+		return binary.AppendUvarint(buf, x)
+	case uintptr:
+		return binary.AppendUvarint(buf, uint64(x))
+	case float32:
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(x))
+	case float64:
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(x))
+	case string:
+		buf = binary.AppendUvarint(buf, uint64(len(x)))
+		return append(buf, x...)
+	}
+	return appendReflected(buf, reflect.ValueOf(v))
+}
+
+// appendReflected 按底层种类编码具名类型的值，格式与 appendOrdered 相同
+// appendReflected encodes a value of a named type by its underlying kind, in the same format as appendOrdered
+func appendReflected(buf []byte, rv reflect.Value) []byte {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(buf, rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(buf, rv.Uint())
+	case reflect.Float32:
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(rv.Float())))
+	case reflect.Float64:
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(rv.Float()))
+	default:
+		s := rv.String()
+		buf = binary.AppendUvarint(buf, uint64(len(s)))
+		return append(buf, s...)
+	}
+}
+
+// errTruncated 表示快照数据在条目中间被截断
+// errTruncated reports snapshot data that ends in the middle of an entry
+var errTruncated = fmt.Errorf("%w: truncated entry", ErrInvalidSnapshot)
+
+// errOutOfRange 表示快照中的整数超出了目标类型的取值范围
+// errOutOfRange reports an integer in the snapshot that does not fit the target type
+var errOutOfRange = fmt.Errorf("%w: value out of range", ErrInvalidSnapshot)
+
+// readOrdered 从 data 解码一个 appendOrdered 编码的值，返回该值和读取的字节数。
+// 整数超出 T 的取值范围时返回 ErrInvalidSnapshot，例如 64 位平台写入的 int 在 32 位平台读取时
+// readOrdered decodes a value encoded by appendOrdered from data, returning the value and the bytes consumed.
+// An integer out of the range of T returns ErrInvalidSnapshot, e.g. an int written on a 64-bit platform read on a 32-bit one
+func readOrdered[T Ordered](data []byte) (T, int, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, n := binary.Varint(data)
+		if n <= 0 {
+			return v, 0, errTruncated
+		}
+		if rv.OverflowInt(x) {
+			return v, 0, errOutOfRange
+		}
+		rv.SetInt(x)
+		return v, n, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, n := binary.Uvarint(data)
+		if n <= 0 {
+			return v, 0, errTruncated
+		}
+		if rv.OverflowUint(x) {
+			return v, 0, errOutOfRange
+		}
+		rv.SetUint(x)
+		return v, n, nil
+	case reflect.Float32:
+		if len(data) < 4 {
+			return v, 0, errTruncated
+		}
+		rv.SetFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(data))))
+		return v, 4, nil
+	case reflect.Float64:
+		if len(data) < 8 {
+			return v, 0, errTruncated
+		}
+		rv.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(data)))
+		return v, 8, nil
+	default:
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return v, 0, errTruncated
+		}
+		rv.SetString(string(data[n : n+int(size)]))
+		return v, n + int(size), nil
+	}
+}
@@ -0,0 +1,224 @@
+package ranklist
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func testBinaryRoundTrip[K Ordered, V Ordered](t *testing.T, sl *RankList[K, V]) {
+	var buf bytes.Buffer
+	if err := sl.Save(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded := New[K, V]()
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := sl.Range(1, sl.Length()+1)
+	result := loaded.Range(1, loaded.Length()+1)
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(result))
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Fatalf("at index %d: expected %v, got %v", i, expected[i], result[i])
+		}
+		if rank, _ := loaded.Rank(expected[i].Key); rank != i+1 {
+			t.Fatalf("Key %v: expected rank %d, got %d", expected[i].Key, i+1, rank)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	strInt := New[string, int]()
+	intFloat := New[int64, float64]()
+	uintStr := New[uint32, string]()
+	strFloat32 := New[string, float32]()
+	for i := 0; i < 1000; i++ {
+		strInt.Set(strconv.Itoa(i), rand.IntN(200)-100)
+		intFloat.Set(int64(i)-500, rand.NormFloat64())
+		uintStr.Set(uint32(i), "v "+strconv.Itoa(rand.IntN(100)))
+		strFloat32.Set("k"+strconv.Itoa(i), rand.Float32())
+	}
+
+	testBinaryRoundTrip(t, strInt)
+	testBinaryRoundTrip(t, intFloat)
+	testBinaryRoundTrip(t, uintStr)
+	testBinaryRoundTrip(t, strFloat32)
+	testBinaryRoundTrip(t, New[string, int]())
+}
+
+func TestLoadReplaces(t *testing.T) {
+	src := New[string, int]()
+	src.Set("a", 1)
+
+	var buf bytes.Buffer
+	if err := src.Save(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dst := New[string, int]()
+	dst.Set("x", 1)
+	if err := dst.Load(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, exists := dst.Get("x"); exists {
+		t.Errorf("Key x should be replaced by Load")
+	}
+	if value, _ := dst.Get("a"); value != 1 {
+		t.Errorf("expected value 1 for a, got %d", value)
+	}
+}
+
+func TestLoadCorrupted(t *testing.T) {
+	sl := New[string, int]()
+	for i := 0; i < 100; i++ {
+		sl.Set(strconv.Itoa(i), i)
+	}
+
+	var buf bytes.Buffer
+	if err := sl.Save(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := buf.Bytes()
+
+	flipped := bytes.Clone(data)
+	flipped[len(flipped)/2] ^= 0xff
+
+	testCases := map[string][]byte{
+		"empty":     nil,
+		"truncated": data[:len(data)-10],
+		"flipped":   flipped,
+		"header":    data[:6],
+	}
+
+	for name, input := range testCases {
+		dst := New[string, int]()
+		dst.Set("keep", 1)
+		if err := dst.Load(bytes.NewReader(input)); !errors.Is(err, ErrInvalidSnapshot) {
+			t.Errorf("%s: expected ErrInvalidSnapshot, got %v", name, err)
+		}
+		if dst.Length() != 1 {
+			t.Errorf("%s: list should be untouched after a failed load", name)
+		}
+	}
+}
+
+func TestLoadTypeMismatch(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+
+	var buf bytes.Buffer
+	if err := sl.Save(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := New[string, float64]().Load(&buf); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("expected ErrInvalidSnapshot for a value type mismatch, got %v", err)
+	}
+}
+
+// withValueKind 将快照头中的值类型改为 kind 并重新计算校验和
+// withValueKind rewrites the value kind in the snapshot header to kind and recomputes the checksum
+func withValueKind(data []byte, kind reflect.Kind) []byte {
+	data = bytes.Clone(data)
+	data[len(snapshotMagic)+2] = byte(kind)
+	payload := data[:len(data)-4]
+	return binary.LittleEndian.AppendUint32(payload, crc32.ChecksumIEEE(payload))
+}
+
+func TestLoadOutOfRange(t *testing.T) {
+	signed := New[string, int64]()
+	signed.Set("a", 300)
+	data, _ := signed.MarshalBinary()
+	dst := New[string, int8]()
+	if err := dst.UnmarshalBinary(withValueKind(data, reflect.Int8)); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("expected ErrInvalidSnapshot for a signed value out of range, got %v", err)
+	}
+
+	unsigned := New[string, uint64]()
+	unsigned.Set("a", 300)
+	data, _ = unsigned.MarshalBinary()
+	if err := New[string, uint8]().UnmarshalBinary(withValueKind(data, reflect.Uint8)); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("expected ErrInvalidSnapshot for an unsigned value out of range, got %v", err)
+	}
+
+	// 取值范围内的值照常读取 / Values within range are read as usual
+	signed.Set("a", -100)
+	data, _ = signed.MarshalBinary()
+	if err := dst.UnmarshalBinary(withValueKind(data, reflect.Int8)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, _ := dst.Get("a"); value != -100 {
+		t.Errorf("expected -100, got %d", value)
+	}
+}
+
+type namedScore int16
+
+func TestSaveLoadNamedTypes(t *testing.T) {
+	testBinaryMarshaler(t, []Entry[string, namedScore]{{"a", -3}, {"b", 7}, {"c", 1000}})
+}
+
+func testBinaryMarshaler[K Ordered, V Ordered](t *testing.T, entries []Entry[K, V]) {
+	sl := New[K, V]()
+	for _, entry := range entries {
+		sl.Set(entry.Key, entry.Value)
+	}
+
+	var marshaler encoding.BinaryMarshaler = sl
+	data, err := marshaler.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded := New[K, V]()
+	decoded.Set(entries[0].Key, entries[0].Value)
+	var unmarshaler encoding.BinaryUnmarshaler = decoded
+	if err := unmarshaler.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := sl.Range(1, sl.Length()+1)
+	result := decoded.Range(1, decoded.Length()+1)
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(result))
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("at index %d: expected %v, got %v", i, expected[i], result[i])
+		}
+	}
+}
+
+func TestBinaryMarshaler(t *testing.T) {
+	testBinaryMarshaler(t, []Entry[string, float64]{
+		{Key: "a", Value: -1.5}, {Key: "b", Value: 0}, {Key: "c", Value: 3.25}, {Key: "d", Value: -1.5},
+	})
+	testBinaryMarshaler(t, []Entry[string, string]{
+		{Key: "a", Value: "zeta"}, {Key: "b", Value: ""}, {Key: "c", Value: "alpha beta"},
+	})
+	testBinaryMarshaler(t, []Entry[int, int64]{
+		{Key: 1, Value: -1 << 62}, {Key: 2, Value: 0}, {Key: 3, Value: 1<<62 + 1}, {Key: -4, Value: -7},
+	})
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+
+	if err := sl.UnmarshalBinary([]byte("garbage")); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("expected ErrInvalidSnapshot, got %v", err)
+	}
+	if sl.Length() != 1 {
+		t.Errorf("list should be untouched after a failed unmarshal")
+	}
+}
@@ -0,0 +1,122 @@
+package ranklist
+
+import "reflect"
+
+// First 返回值最小的条目，即排名 1 的条目，跳表为空时返回 false
+// First returns the entry with the smallest value, i.e. the entry at rank 1, returns false if the list is empty
+func (sl *RankList[K, V]) First() (Entry[K, V], bool) {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	if node := sl.header.forward[0]; node != nil {
+		return node.data, true
+	}
+	return Entry[K, V]{}, false
+}
+
+// Last 返回值最大的条目，即排名最后的条目，跳表为空时返回 false
+// Last returns the entry with the largest value, i.e. the entry at the last rank, returns false if the list is empty
+func (sl *RankList[K, V]) Last() (Entry[K, V], bool) {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	if sl.tail != nil {
+		return sl.tail.data, true
+	}
+	return Entry[K, V]{}, false
+}
+
+// MinValue 返回最小的值，跳表为空时返回 false，不会分配内存
+// MinValue returns the smallest value without allocating, returns false if the list is empty
+func (sl *RankList[K, V]) MinValue() (V, bool) {
+	entry, ok := sl.First()
+	return entry.Value, ok
+}
+
+// MaxValue 返回最大的值，跳表为空时返回 false，不会分配内存
+// MaxValue returns the largest value without allocating, returns false if the list is empty
+func (sl *RankList[K, V]) MaxValue() (V, bool) {
+	entry, ok := sl.Last()
+	return entry.Value, ok
+}
+
+// GetByRank 返回指定排名上的条目，排名从 1 开始，超出 [1, Length()] 时返回 false。
+// 通过跨度下降定位，耗时 O(log n)
+// GetByRank returns the entry at the given 1-based rank, returns false outside [1, Length()].
+// It is located with a span descent in O(log n)
+func (sl *RankList[K, V]) GetByRank(rank int) (Entry[K, V], bool) {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	if node := sl.byRank(rank); node != nil {
+		return node.data, true
+	}
+	return Entry[K, V]{}, false
+}
+
+// ValueAtRank 返回排名 rank 上的条目的值，即进入该排名所需达到的值，排名从 1 开始，超出 [1, Length()] 时返回 false
+// ValueAtRank returns the value held by the entry at the given 1-based rank, the threshold to reach that rank,
+// and returns false outside [1, Length()]
+func (sl *RankList[K, V]) ValueAtRank(rank int) (V, bool) {
+	entry, ok := sl.GetByRank(rank)
+	return entry.Value, ok
+}
+
+// Gap 返回键当前的值与排名 targetRank 上的值之间还差多少，排名规则与 Rank 相同，因此排名越靠后值越大，
+// 例如榜单按值从高到低展示时，进入前 100 名对应 targetRank = Length() - 99。
+// 结果是 ValueAtRank(targetRank) 减去键的值，键的值已经达到或超过该值时返回零值，因此结果从不为负。
+// 差值为零并不保证排名已经达到：值相同的条目按键排序，键较小的排在前面。
+// 是否达到按 WithValueCompare 设置的值排序规则判断，差值始终是两个值之差的绝对值。
+// 值类型是字符串时无法相减，直接返回该排名上的值。键不存在或 targetRank 超出 [1, Length()] 时返回 false。
+// 读取在一把读锁内完成，因此阈值与键的值来自同一时刻
+// Gap returns how far the current value of key is from the value at targetRank. Ranks follow Rank,
+// so later ranks hold larger values; for a board shown from the highest value down,
+// entering the top 100 means targetRank = Length() - 99.
+// The result is ValueAtRank(targetRank) minus the value of key, or the zero value once key has reached or passed it,
+// so it is never negative. A zero gap does not guarantee the rank is reached:
+// entries tied on value are ordered by key, the smaller key first.
+// Whether the value is reached follows the value order set by WithValueCompare,
+// and the gap is always the absolute difference between the two values.
+// String values cannot be subtracted, so the value at targetRank itself is returned.
+// Returns false if key does not exist or targetRank lies outside [1, Length()].
+// Both values are read under one read lock, so the threshold and the value of key come from the same moment
+func (sl *RankList[K, V]) Gap(key K, targetRank int) (V, bool) {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	var zero V
+	node, exists := sl.lookup(key)
+	target := sl.byRank(targetRank)
+	if !exists || target == nil {
+		return zero, false
+	}
+	threshold, value := target.data.Value, node.data.Value
+	if kindOf[V]() == reflect.String {
+		return threshold, true
+	}
+	if !sl.valueLess(value, threshold) {
+		return zero, true
+	}
+	if threshold < value {
+		// 自定义的值排序规则把较小的数排在后面，差值取绝对值
+		// A custom value order puts the smaller number later, take the distance as an absolute value
+		return subtract(value, threshold), true
+	}
+	return subtract(threshold, value), true
+}
+
+// subtract 返回数字类型的 a - b
+// subtract returns a - b for numeric types
+func subtract[V Ordered](a, b V) V {
+	var diff V
+	ra, rb, rd := reflect.ValueOf(a), reflect.ValueOf(b), reflect.ValueOf(&diff).Elem()
+	switch rd.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		rd.SetInt(ra.Int() - rb.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		rd.SetUint(ra.Uint() - rb.Uint())
+	case reflect.Float32, reflect.Float64:
+		rd.SetFloat(ra.Float() - rb.Float())
+	}
+	return diff
+}
@@ -0,0 +1,170 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestFirstLastEmpty(t *testing.T) {
+	sl := New[string, int]()
+	if _, ok := sl.First(); ok {
+		t.Errorf("First should return false for an empty list")
+	}
+	if _, ok := sl.Last(); ok {
+		t.Errorf("Last should return false for an empty list")
+	}
+	if _, ok := sl.MinValue(); ok {
+		t.Errorf("MinValue should return false for an empty list")
+	}
+	if _, ok := sl.MaxValue(); ok {
+		t.Errorf("MaxValue should return false for an empty list")
+	}
+}
+
+func TestBoundsConsistency(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 2000; i++ {
+		key := rand.IntN(50)
+		if rand.IntN(3) == 0 {
+			sl.Del(key)
+		} else {
+			sl.Set(key, rand.IntN(100)-50)
+		}
+
+		first, firstOK := sl.First()
+		last, lastOK := sl.Last()
+		minValue, minOK := sl.MinValue()
+		maxValue, maxOK := sl.MaxValue()
+		if firstOK != (sl.Length() > 0) || lastOK != firstOK || minOK != firstOK || maxOK != firstOK {
+			t.Fatalf("step %d: presence disagrees with length %d", i, sl.Length())
+		}
+		if !firstOK {
+			continue
+		}
+		if minValue != first.Value || maxValue != last.Value {
+			t.Fatalf("step %d: MinValue/MaxValue %d/%d disagree with First/Last %v/%v", i, minValue, maxValue, first, last)
+		}
+		if ranked := sl.Range(1, 2); ranked[0] != first {
+			t.Fatalf("step %d: First %v is not rank 1 %v", i, first, ranked[0])
+		}
+		if ranked := sl.Range(sl.Length(), sl.Length()+1); ranked[0] != last {
+			t.Fatalf("step %d: Last %v is not the last rank %v", i, last, ranked[0])
+		}
+	}
+}
+
+func TestMinMaxValueAllocs(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	allocs := testing.AllocsPerRun(100, func() {
+		sl.MinValue()
+		sl.MaxValue()
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestGetByRank(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 30)
+	sl.Set("b", 10)
+	sl.Set("c", 20)
+
+	for rank, key := range []string{"b", "c", "a"} {
+		if entry, ok := sl.GetByRank(rank + 1); !ok || entry.Key != key {
+			t.Errorf("rank %d: expected %s, got %v, %v", rank+1, key, entry, ok)
+		}
+		if entry, ok := sl.GetByRank0(rank); !ok || entry.Key != key {
+			t.Errorf("rank0 %d: expected %s, got %v, %v", rank, key, entry, ok)
+		}
+	}
+	for _, rank := range []int{0, -1, 4} {
+		if _, ok := sl.GetByRank(rank); ok {
+			t.Errorf("rank %d should be out of range", rank)
+		}
+	}
+	if _, ok := sl.GetByRank0(3); ok {
+		t.Errorf("rank0 3 should be out of range")
+	}
+}
+
+func TestValueAtRankAndGap(t *testing.T) {
+	sl := New[string, int]()
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		sl.Set(key, (i+1)*100)
+	}
+
+	if value, ok := sl.ValueAtRank(3); !ok || value != 300 {
+		t.Fatalf("expected 300 at rank 3, got %d, %v", value, ok)
+	}
+	for _, rank := range []int{0, 6} {
+		if _, ok := sl.ValueAtRank(rank); ok {
+			t.Fatalf("rank %d is out of range", rank)
+		}
+		if _, ok := sl.Gap("a", rank); ok {
+			t.Fatalf("a gap to rank %d should not exist", rank)
+		}
+	}
+	if _, ok := sl.Gap("missing", 3); ok {
+		t.Fatalf("a missing key has no gap")
+	}
+
+	// 还差多少才能进入前 2 名，即排名 Length()-1
+	// How far a is from the top 2, which is rank Length()-1
+	if gap, ok := sl.Gap("a", sl.Length()-1); !ok || gap != 300 {
+		t.Fatalf("expected a gap of 300, got %d, %v", gap, ok)
+	}
+	// 已经达到或超过目标排名时差值为零
+	// The gap is zero once the target rank is reached or passed
+	for _, key := range []string{"d", "e"} {
+		if gap, ok := sl.Gap(key, 4); !ok || gap != 0 {
+			t.Fatalf("%s: expected a zero gap, got %d, %v", key, gap, ok)
+		}
+	}
+
+	// 与阈值相同的值差值为零，但键较小的条目仍排在阈值之前
+	// A value tied with the threshold has a zero gap, yet the smaller key still ranks before the threshold
+	sl.Set("aa", 400)
+	if gap, ok := sl.Gap("aa", 5); !ok || gap != 0 {
+		t.Fatalf("expected a zero gap on a tie, got %d, %v", gap, ok)
+	}
+	if rank, _ := sl.Rank("aa"); rank != 4 {
+		t.Fatalf("expected the tied key before the threshold at rank 4, got %d", rank)
+	}
+
+	unsigned := New[string, uint]()
+	unsigned.Set("low", 1)
+	unsigned.Set("high", 10)
+	if gap, _ := unsigned.Gap("high", 1); gap != 0 {
+		t.Fatalf("an unsigned gap should not wrap around, got %d", gap)
+	}
+	if gap, _ := unsigned.Gap("low", 2); gap != 9 {
+		t.Fatalf("expected an unsigned gap of 9, got %d", gap)
+	}
+
+	names := New[int, string]()
+	names.Set(1, "alpha")
+	names.Set(2, "beta")
+	if gap, ok := names.Gap(1, 2); !ok || gap != "beta" {
+		t.Fatalf("string values should return the threshold, got %q, %v", gap, ok)
+	}
+}
+
+func TestGapValueCompare(t *testing.T) {
+	// 值越小排名越靠后 / Smaller values rank later
+	sl := New(WithValueCompare[string](func(a, b int) int { return b - a }))
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		sl.Set(key, (i+1)*100)
+	}
+	// 排名：e d c b a / Ranks: e d c b a
+	if gap, ok := sl.Gap("e", 4); !ok || gap != 300 {
+		t.Fatalf("expected a gap of 300, got %d, %v", gap, ok)
+	}
+	for _, key := range []string{"a", "b"} {
+		if gap, ok := sl.Gap(key, 4); !ok || gap != 0 {
+			t.Fatalf("%s: expected a zero gap, got %d, %v", key, gap, ok)
+		}
+	}
+}
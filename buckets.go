@@ -0,0 +1,292 @@
+package ranklist
+
+import (
+	"fmt"
+	"math/bits"
+	"reflect"
+)
+
+// valueBuckets 是按值索引的辅助结构，每个可能的值对应一个桶，记录持有该值的第一个节点和条目数，
+// 并用两棵树状数组分别维护条目数和非空桶数的前缀和
+// valueBuckets is an auxiliary index by value with one bucket per possible value, recording the first node holding it
+// and its number of entries, plus two Fenwick trees over the entry counts and the non-empty buckets
+type valueBuckets[K Ordered, V Ordered] struct {
+	max    int
+	signed bool
+
+	first []*Node[K, V]
+	count []int
+
+	// 条目数和非空桶数的前缀和，以及非空桶的总数
+	// Prefix sums of the entry counts and of the non-empty buckets, and the number of non-empty buckets
+	entries  fenwick
+	present  fenwick
+	distinct int
+
+	// 值不在 [0, max] 内的条目数，不为 0 时索引不完整，查询退回到跳表
+	// Number of entries whose value lies outside [0, max], the index is incomplete and queries fall back to the list
+	// while it is not 0
+	outside int
+}
+
+// WithValueBuckets 为取值在 [0, maxValue] 内的整数值开启按值索引的辅助结构：每个值对应一个桶，记录持有该值的
+// 第一个节点和条目数，每次修改时维护。开启后 TieCount、DistinctValues 为 O(1)，KeysWithValue 为 O(k)，
+// CountByScore 和 DenseRank 为 O(log maxValue)，RangeByScore 和 RangeByScoreOpt 为 O(log maxValue + k)，
+// 不再需要在跳表中下降；适合值域很小而成员很多、大量条目的值相同的榜单。
+// 内存开销为 O(maxValue)，即每个可能出现的值一个桶加上两棵树状数组，与成员数量无关；每次修改多 O(log maxValue) 的维护。
+// 有条目的值超出 [0, maxValue] 时索引不完整，上述查询退回到原来的实现，直到这些条目被删除或改回范围内。
+// 桶按值的自然顺序排列，不能与 WithValueCompare 或 WithCollation 同时使用。
+// 值类型不是整数或 maxValue 小于 0 时 panic，与自定义的值排序规则一起传给 New 时 New panic
+// WithValueBuckets enables an auxiliary index by value for integer values within [0, maxValue]: every value gets a bucket
+// recording the first node holding it and its number of entries, maintained on every mutation.
+// With it TieCount and DistinctValues run in O(1), KeysWithValue in O(k), CountByScore and DenseRank in O(log maxValue),
+// and RangeByScore and RangeByScoreOpt in O(log maxValue + k), without descending the list;
+// it suits boards with a small value range over many members where most entries share their value.
+// Memory cost is O(maxValue), one bucket per possible value plus two Fenwick trees, independent of the number of members;
+// every mutation pays an extra O(log maxValue) of maintenance.
+// While any entry holds a value outside [0, maxValue] the index is incomplete and those queries fall back
+// to their regular implementation until such entries are deleted or brought back into range.
+// The buckets follow the natural order of values, so the option cannot be combined with WithValueCompare or WithCollation.
+// It panics if the value type is not an integer or maxValue is negative,
+// and New panics if it is given together with a custom value order
+func WithValueBuckets[K Ordered, V Ordered](maxValue int) Option[K, V] {
+	if maxValue < 0 {
+		panic("ranklist: value buckets need a non-negative maximum")
+	}
+	var signed bool
+	switch kindOf[V]() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		signed = true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		panic("ranklist: value buckets need an integer value type")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.buckets = &valueBuckets[K, V]{
+			max:     maxValue,
+			signed:  signed,
+			first:   make([]*Node[K, V], maxValue+1),
+			count:   make([]int, maxValue+1),
+			entries: make(fenwick, maxValue+1),
+			present: make(fenwick, maxValue+1),
+		}
+	}
+}
+
+// position 返回 v 对应的桶下标，小于 0 的值返回 -1，大于 max 的值返回 max+1
+// position returns the bucket index of v, -1 for values below 0 and max+1 for values above max
+func (b *valueBuckets[K, V]) position(v V) int {
+	rv := reflect.ValueOf(v)
+	if b.signed {
+		return int(min(max(rv.Int(), -1), int64(b.max)+1))
+	}
+	return int(min(rv.Uint(), uint64(b.max)+1))
+}
+
+// index 返回 v 对应的桶下标，v 不在 [0, max] 内时返回 false
+// index returns the bucket index of v, false when v lies outside [0, max]
+func (b *valueBuckets[K, V]) index(v V) (int, bool) {
+	i := b.position(v)
+	return i, i >= 0 && i <= b.max
+}
+
+// ready 判断索引是否开启且完整，可以代替跳表回答查询，调用方需持有锁
+// ready reports whether the index is enabled and complete, so it can answer queries instead of the list.
+// The caller must hold the lock
+func (b *valueBuckets[K, V]) ready() bool {
+	return b != nil && b.outside == 0
+}
+
+// add 将刚链接进跳表的节点计入它的值对应的桶，调用方需持有写锁
+// add counts a node just linked into the list in the bucket of its value. The caller must hold the write lock
+func (b *valueBuckets[K, V]) add(sl *RankList[K, V], node *Node[K, V]) {
+	if b == nil {
+		return
+	}
+	i, ok := b.index(node.data.Value)
+	if !ok {
+		b.outside++
+		return
+	}
+	if b.count[i] == 0 {
+		b.present.add(i, 1)
+		b.distinct++
+	}
+	b.count[i]++
+	b.entries.add(i, 1)
+	if first := b.first[i]; first == nil || sl.compare(node.data, first.data) < 0 {
+		b.first[i] = node
+	}
+}
+
+// remove 在节点从跳表摘除之前将它移出所在的桶，此时它的第 0 层后继仍然有效，调用方需持有写锁
+// remove takes a node out of its bucket before it is unlinked, while its level-0 successor is still valid.
+// The caller must hold the write lock
+func (b *valueBuckets[K, V]) remove(node *Node[K, V]) {
+	if b == nil {
+		return
+	}
+	i, ok := b.index(node.data.Value)
+	if !ok {
+		b.outside--
+		return
+	}
+	b.count[i]--
+	b.entries.add(i, -1)
+	if b.count[i] == 0 {
+		b.present.add(i, -1)
+		b.distinct--
+		b.first[i] = nil
+		return
+	}
+	if b.first[i] == node {
+		b.first[i] = node.forward[0]
+	}
+}
+
+// rebuild 沿第 0 层重新计算全部桶，用于整体重建之后，耗时 O(n + maxValue)，调用方需持有写锁
+// rebuild recomputes every bucket along level 0 after a whole rebuild in O(n + maxValue). The caller must hold the write lock
+func (b *valueBuckets[K, V]) rebuild(sl *RankList[K, V]) {
+	if b == nil {
+		return
+	}
+	clear(b.first)
+	clear(b.count)
+	b.distinct, b.outside = 0, 0
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		i, ok := b.index(curr.data.Value)
+		if !ok {
+			b.outside++
+			continue
+		}
+		if b.count[i] == 0 {
+			b.first[i] = curr
+			b.distinct++
+		}
+		b.count[i]++
+	}
+	b.entries.build(b.count, func(count int) int { return count })
+	b.present.build(b.count, func(count int) int { return min(count, 1) })
+}
+
+// check 校验每个桶的第一个节点和条目数、非空桶数、范围外的条目数和两棵树状数组都与第 0 层一致，调用方需持有锁
+// check validates that the first node and count of every bucket, the number of non-empty buckets,
+// the entries out of range and both Fenwick trees agree with level 0. The caller must hold the lock
+func (b *valueBuckets[K, V]) check(sl *RankList[K, V]) error {
+	if b == nil {
+		return nil
+	}
+	count := make([]int, b.max+1)
+	outside, distinct := 0, 0
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		i, ok := b.index(curr.data.Value)
+		if !ok {
+			outside++
+			continue
+		}
+		if count[i] == 0 {
+			distinct++
+			if b.first[i] != curr {
+				return fmt.Errorf("%w: value bucket %d does not start at key %v", ErrCorrupted, i, curr.data.Key)
+			}
+		}
+		count[i]++
+	}
+	if outside != b.outside || distinct != b.distinct {
+		return fmt.Errorf("%w: value buckets count %d distinct values and %d out of range, level 0 holds %d and %d",
+			ErrCorrupted, b.distinct, b.outside, distinct, outside)
+	}
+	entries, present := 0, 0
+	for i := range count {
+		if count[i] != b.count[i] || (count[i] == 0) != (b.first[i] == nil) {
+			return fmt.Errorf("%w: value bucket %d holds %d entries, level 0 holds %d", ErrCorrupted, i, b.count[i], count[i])
+		}
+		entries += count[i]
+		present += min(count[i], 1)
+		if b.entries.sum(i+1) != entries || b.present.sum(i+1) != present {
+			return fmt.Errorf("%w: value bucket prefix sums disagree with level 0 at bucket %d", ErrCorrupted, i)
+		}
+	}
+	return nil
+}
+
+// window 将值区间转换为桶下标区间 [lo, hi)，调用方需持有锁
+// window converts an interval of values to the bucket range [lo, hi), the caller must hold the lock
+func (b *valueBuckets[K, V]) window(r ScoreRange[V]) (int, int) {
+	lo, hi := b.position(r.Min), b.position(r.Max)
+	if r.MinExclusive {
+		lo++
+	}
+	if !r.MaxExclusive {
+		hi++
+	}
+	return max(lo, 0), min(hi, b.max+1)
+}
+
+// countWindow 返回值在桶下标区间 [lo, hi) 内的条目数，调用方需持有锁
+// countWindow returns the number of entries whose bucket lies within [lo, hi), the caller must hold the lock
+func (b *valueBuckets[K, V]) countWindow(lo, hi int) int {
+	if lo >= hi {
+		return 0
+	}
+	return b.entries.sum(hi) - b.entries.sum(lo)
+}
+
+// firstFrom 返回下标不小于 lo 的第一个非空桶中排名最前的节点，不存在时返回 nil，调用方需持有锁
+// firstFrom returns the first node in rank order of the first non-empty bucket at or after lo, nil when there is none.
+// The caller must hold the lock
+func (b *valueBuckets[K, V]) firstFrom(lo int) *Node[K, V] {
+	before := b.present.sum(lo)
+	if before == b.distinct {
+		return nil
+	}
+	return b.first[b.present.find(before)]
+}
+
+// fenwick 是下标从 0 开始的树状数组，支持单点增减和前缀和
+// fenwick is a 0-based Fenwick tree supporting point updates and prefix sums
+type fenwick []int
+
+// add 将下标 i 处的值增加 delta
+// add increases the value at index i by delta
+func (f fenwick) add(i int, delta int) {
+	for i++; i <= len(f); i += i & -i {
+		f[i-1] += delta
+	}
+}
+
+// sum 返回下标 [0, i) 的值之和
+// sum returns the sum of the values at indexes [0, i)
+func (f fenwick) sum(i int) int {
+	total := 0
+	for ; i > 0; i -= i & -i {
+		total += f[i-1]
+	}
+	return total
+}
+
+// find 返回前缀和大于 k 的最小下标，要求所有值非负且 k 小于总和
+// find returns the smallest index whose prefix sum exceeds k, all values must be non-negative and k below the total
+func (f fenwick) find(k int) int {
+	pos := 0
+	for step := 1 << (bits.Len(uint(len(f))) - 1); step > 0; step >>= 1 {
+		if next := pos + step; next <= len(f) && f[next-1] <= k {
+			pos = next
+			k -= f[next-1]
+		}
+	}
+	return pos
+}
+
+// build 在 O(n) 时间内用 values 经 fn 转换后的值重建树状数组
+// build rebuilds the tree in O(n) from values mapped through fn
+func (f fenwick) build(values []int, fn func(int) int) {
+	for i, v := range values {
+		f[i] = fn(v)
+	}
+	for i := 1; i <= len(f); i++ {
+		if parent := i + i&-i; parent <= len(f) {
+			f[parent-1] += f[i-1]
+		}
+	}
+}
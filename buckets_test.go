@@ -0,0 +1,216 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// requireBucketQueries 检查开启 WithValueBuckets 的跳表与普通跳表对同一组查询给出相同的结果
+// requireBucketQueries checks that a list with WithValueBuckets answers a set of queries the same way as a plain list
+func requireBucketQueries(t *testing.T, step string, indexed, plain *RankList[string, int]) {
+	t.Helper()
+	if err := indexed.Check(); err != nil {
+		t.Fatalf("%s: %v", step, err)
+	}
+	if !slices.Equal(indexed.Entries(), plain.Entries()) {
+		t.Fatalf("%s: the lists diverged", step)
+	}
+	if a, b := indexed.DistinctValues(), plain.DistinctValues(); a != b {
+		t.Fatalf("%s: DistinctValues %d, expected %d", step, a, b)
+	}
+	for _, entry := range plain.Entries() {
+		a, _ := indexed.TieCount(entry.Key)
+		b, _ := plain.TieCount(entry.Key)
+		if a != b {
+			t.Fatalf("%s: TieCount(%s) %d, expected %d", step, entry.Key, a, b)
+		}
+		a, _ = indexed.DenseRank(entry.Key)
+		b, _ = plain.DenseRank(entry.Key)
+		if a != b {
+			t.Fatalf("%s: DenseRank(%s) %d, expected %d", step, entry.Key, a, b)
+		}
+	}
+	for range 20 {
+		r := ScoreRange[int]{
+			Min:          rand.IntN(130) - 15,
+			Max:          rand.IntN(130) - 15,
+			MinExclusive: rand.IntN(2) == 0,
+			MaxExclusive: rand.IntN(2) == 0,
+		}
+		if a, b := indexed.RangeByScoreOpt(r), plain.RangeByScoreOpt(r); !slices.Equal(a, b) {
+			t.Fatalf("%s: RangeByScoreOpt(%+v) %v, expected %v", step, r, a, b)
+		}
+		if a, b := indexed.CountByScore(r.Min, r.Max), plain.CountByScore(r.Min, r.Max); a != b {
+			t.Fatalf("%s: CountByScore(%d, %d) %d, expected %d", step, r.Min, r.Max, a, b)
+		}
+		if a, b := indexed.KeysWithValue(r.Min), plain.KeysWithValue(r.Min); !slices.Equal(a, b) {
+			t.Fatalf("%s: KeysWithValue(%d) %v, expected %v", step, r.Min, a, b)
+		}
+	}
+}
+
+func TestValueBuckets(t *testing.T) {
+	indexed := New(WithValueBuckets[string, int](100))
+	plain := New[string, int]()
+	both := func(fn func(sl *RankList[string, int])) {
+		fn(indexed)
+		fn(plain)
+	}
+
+	for i := range 3000 {
+		key := strconv.Itoa(rand.IntN(200))
+
+		// 值集中在少数几个值上，大部分写入落在桶的边界节点附近；偶尔超出范围以覆盖退回的路径
+		// Values concentrate on a few values so most writes hit bucket boundary nodes,
+		// occasionally going out of range to cover the fallback
+		value := rand.IntN(8) * 10
+		if rand.IntN(50) == 0 {
+			value = 100 + rand.IntN(20)
+		}
+		delta := rand.IntN(3) - 1
+		current, _ := plain.Get(key)
+
+		switch op := rand.IntN(10); {
+		case op < 4:
+			both(func(sl *RankList[string, int]) { sl.Set(key, value) })
+		case op < 6:
+			both(func(sl *RankList[string, int]) { sl.IncrBy(key, delta) })
+		case op < 8:
+			both(func(sl *RankList[string, int]) { sl.Del(key) })
+		case op == 8:
+			both(func(sl *RankList[string, int]) { sl.UpdateByValue(key, current, value) })
+		default:
+			both(func(sl *RankList[string, int]) { sl.DelByValue(key, current) })
+		}
+		if i%100 == 0 {
+			requireBucketQueries(t, "step "+strconv.Itoa(i), indexed, plain)
+		}
+	}
+	requireBucketQueries(t, "random writes", indexed, plain)
+
+	both(func(sl *RankList[string, int]) { sl.ApplyToAll(func(_ string, v int) int { return v / 2 }, true) })
+	requireBucketQueries(t, "ApplyToAll", indexed, plain)
+	entries := plain.Entries()
+	both(func(sl *RankList[string, int]) { sl.Restore(entries[:len(entries)/2]) })
+	requireBucketQueries(t, "Restore", indexed, plain)
+	both(func(sl *RankList[string, int]) { sl.Clear() })
+	requireBucketQueries(t, "Clear", indexed, plain)
+}
+
+func TestValueBucketsBoundaries(t *testing.T) {
+	sl := New(WithValueBuckets[string, int](10))
+	sl.SetBatch([]Entry[string, int]{{"a", 5}, {"b", 5}, {"c", 5}, {"d", 6}})
+	first := func(value int) string {
+		t.Helper()
+		if err := sl.Check(); err != nil {
+			t.Fatal(err)
+		}
+		if node := sl.buckets.first[value]; node != nil {
+			return node.data.Key
+		}
+		return ""
+	}
+
+	// 删除桶的第一个节点，下一个同值节点接替
+	// Deleting the first node of a bucket hands it to the next node with the value
+	sl.Del("a")
+	if got := first(5); got != "b" {
+		t.Fatalf("expected b to start bucket 5, got %q", got)
+	}
+
+	// 第一个节点原地改到相邻的桶，成为新桶的第一个节点
+	// The first node moving in place to the next bucket becomes the first node there
+	sl.Set("c", 6)
+	sl.Set("b", 6)
+	if got := first(6); got != "b" || first(5) != "" {
+		t.Fatalf("expected b to start bucket 6 and bucket 5 to be empty, got %q and %q", got, first(5))
+	}
+
+	// 新的最小键插入已有的桶，成为第一个节点
+	// A new smallest key inserted into an existing bucket becomes its first node
+	sl.Set("0", 6)
+	if got := first(6); got != "0" {
+		t.Fatalf("expected 0 to start bucket 6, got %q", got)
+	}
+
+	// 最后一个节点删除后桶为空
+	// The bucket empties once its last node is deleted
+	for _, key := range []string{"0", "b", "c", "d"} {
+		sl.Del(key)
+	}
+	if got := first(6); got != "" || sl.DistinctValues() != 0 {
+		t.Fatalf("expected bucket 6 to be empty, got %q", got)
+	}
+
+	// 超出范围的值让索引退回到跳表，删除之后重新启用
+	// A value out of range makes the index fall back to the list, and it is used again once deleted
+	sl.Set("x", 1)
+	sl.Set("big", 11)
+	if sl.buckets.ready() || sl.CountByScore(0, 100) != 2 {
+		t.Fatal("expected the index to step aside with an entry out of range")
+	}
+	sl.Del("big")
+	if !sl.buckets.ready() || sl.CountByScore(0, 100) != 1 {
+		t.Fatal("expected the index to be used again")
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValueBucketsPlaceholders(t *testing.T) {
+	for _, visible := range []bool{false, true} {
+		opts := func(extra ...Option[string, int]) []Option[string, int] {
+			if visible {
+				extra = append(extra, WithVisiblePlaceholders[string, int]())
+			}
+			return extra
+		}
+		indexed := New(opts(WithValueBuckets[string, int](100))...)
+		plain := New(opts()...)
+		for _, sl := range []*RankList[string, int]{indexed, plain} {
+			sl.Set("x", 1)
+			sl.Reserve("p", 2)
+			sl.Set("y", 3)
+		}
+		if !indexed.buckets.ready() {
+			t.Fatal("expected the index to be built")
+		}
+
+		expected := []Entry[string, int]{{"x", 1}, {"y", 3}}
+		if visible {
+			expected = []Entry[string, int]{{"x", 1}, {"p", 2}, {"y", 3}}
+		}
+		for _, sl := range []*RankList[string, int]{indexed, plain} {
+			if entries := sl.RangeByScore(1, 3); !slices.Equal(entries, expected) {
+				t.Fatalf("visible=%v: RangeByScore %v, expected %v", visible, entries, expected)
+			}
+			if n := sl.CountByScore(1, 3); n != len(expected) {
+				t.Fatalf("visible=%v: CountByScore %d, expected %d", visible, n, len(expected))
+			}
+		}
+		requireBucketQueries(t, "placeholders", indexed, plain)
+	}
+}
+
+func TestValueBucketsPanics(t *testing.T) {
+	for name, fn := range map[string]func(){
+		"negative maximum": func() { WithValueBuckets[string, int](-1) },
+		"string values":    func() { WithValueBuckets[string, string](10) },
+		"float values":     func() { WithValueBuckets[string, float64](10) },
+		"custom order": func() {
+			New(WithValueBuckets[string, int](10), WithValueCompare[string](func(a, b int) int { return b - a }))
+		},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s: expected a panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
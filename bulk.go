@@ -0,0 +1,397 @@
+package ranklist
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+// compareEntries 按照跳表的排序规则比较两个条目：先比较值，值相同时比较键
+// compareEntries compares two entries in skip list order: by value first, then by key for equal values
+func compareEntries[K Ordered, V Ordered](a, b Entry[K, V]) int {
+	switch {
+	case a.Value < b.Value:
+		return -1
+	case a.Value > b.Value:
+		return 1
+	case a.Key < b.Key:
+		return -1
+	case a.Key > b.Key:
+		return 1
+	}
+	return 0
+}
+
+// sortEntries 对条目去重并排序，重复的键以最后一次出现的值为准
+// sortEntries deduplicates and sorts entries, duplicate keys keep the value of their last occurrence
+func sortEntries[K Ordered, V Ordered](entries []Entry[K, V]) []Entry[K, V] {
+	sorted := dedupEntries(entries)
+	slices.SortFunc(sorted, compareEntries[K, V])
+	return sorted
+}
+
+// dedupEntries 对条目去重，保持每个键第一次出现的位置，值以最后一次出现的为准
+// dedupEntries deduplicates entries, keeping each key at its first position with the value of its last occurrence
+func dedupEntries[K Ordered, V Ordered](entries []Entry[K, V]) []Entry[K, V] {
+	index := make(map[K]int, len(entries))
+	deduped := make([]Entry[K, V], 0, len(entries))
+	for _, entry := range entries {
+		if i, exists := index[entry.Key]; exists {
+			deduped[i].Value = entry.Value
+			continue
+		}
+		index[entry.Key] = len(deduped)
+		deduped = append(deduped, entry)
+	}
+	return deduped
+}
+
+// load 用给定的条目替换跳表的全部内容，条目可以无序且包含重复键，调用方需持有写锁
+// load replaces the whole content of the skip list with the given entries,
+// which may be unordered and contain duplicate keys. The caller must hold the write lock
+func (sl *RankList[K, V]) load(entries []Entry[K, V]) {
+	sl.build(sl.sortEntries(entries))
+	sl.version.Add(1)
+}
+
+// build 用已排序且键唯一的条目在 O(n) 时间内重建跳表，调用方需持有写锁
+// build rebuilds the skip list in O(n) from entries that are sorted and have unique keys.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) build(sorted []Entry[K, V]) {
+	// 新字典构建完成后再整体替换，Get 不会看到重建了一半的字典
+	// The new dictionary is swapped in once complete, so Get never sees a half-rebuilt dictionary
+	sl.resetNodes()
+	dict := sl.newDict(len(sorted))
+
+	// 记录每层最后一个节点及其排名
+	// Records the last node at each level and its rank
+	var last [MaxLevel]*Node[K, V]
+	var lastRank [MaxLevel]int
+	for i := range last {
+		last[i] = sl.header
+	}
+	times := sl.stamp()
+
+	for i, entry := range sorted {
+		rank := i + 1
+		level := sl.randomLevel()
+		node := sl.newNode(entry.Key, entry.Value, level)
+		node.version = 1
+		node.times = times
+		if rank > 1 {
+			node.backward = last[0]
+		}
+		for l := 0; l < level; l++ {
+			last[l].forward[l] = node
+			node.span[l] = rank - lastRank[l]
+			last[l] = node
+			lastRank[l] = rank
+		}
+		if level > sl.level {
+			sl.level = level
+		}
+		if dict != nil {
+			dict[entry.Key] = node
+		}
+		sl.tail = node
+	}
+	sl.length = len(sorted)
+	sl.peak = sl.length
+	sl.swapDict(dict)
+	sl.rebuildStale()
+	sl.buckets.rebuild(sl)
+	sl.recency.rebuild(sl)
+}
+
+// SetBatch 在一把写锁内按顺序写入一批键值对，效果与依次调用 Set 相同，重复的键以最后一次为准。
+// 返回新插入的键的数量和更新已有键的写入次数，两者在同一个临界区内统计；
+// 批内重复 n 次的新键计为一次插入和 n-1 次更新，被 WithValueGuard 或 WithHardLimit 拒绝的条目不计入任何一项，
+// SetBatchReport 会报告它们的数量
+// SetBatch writes a batch of key-value pairs in order under one write lock,
+// with the same effect as calling Set for each of them, duplicate keys keep their last value.
+// Returns the number of newly inserted keys and the number of writes updating an existing key,
+// both counted within the same critical section;
+// a new key repeated n times within the batch counts as one insert plus n-1 updates,
+// and entries rejected by WithValueGuard or WithHardLimit count as neither, SetBatchReport reports how many there were
+func (sl *RankList[K, V]) SetBatch(entries []Entry[K, V]) (inserted int, updated int) {
+	return sl.SetBatchFunc(entries, nil)
+}
+
+// SetBatchFunc 与 SetBatch 相同，但还会对每个写入的条目按顺序调用一次 fn，inserted 表示该次写入是插入还是更新。
+// fn 在释放写锁之后调用，被拒绝的条目不会报告。fn 为 nil 时与 SetBatch 完全相同
+// SetBatchFunc behaves like SetBatch, but also calls fn once per written entry in order,
+// inserted telling whether that write was an insert or an update.
+// fn runs after the write lock is released and rejected entries are not reported. A nil fn makes it identical to SetBatch
+func (sl *RankList[K, V]) SetBatchFunc(entries []Entry[K, V], fn func(entry Entry[K, V], inserted bool)) (inserted int, updated int) {
+	inserted, updated, _ = sl.setBatchFunc(entries, fn)
+	return inserted, updated
+}
+
+// setBatchFunc 实现 SetBatchFunc，跳表已关闭时什么也不写并返回 ErrClosed
+// setBatchFunc implements SetBatchFunc, writing nothing and returning ErrClosed once the list is closed
+func (sl *RankList[K, V]) setBatchFunc(entries []Entry[K, V], fn func(entry Entry[K, V], inserted bool)) (inserted int, updated int, err error) {
+	start := sl.metricsStart()
+	var written []Entry[K, V]
+	var kinds []bool
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return 0, 0, ErrClosed
+	}
+	zones := sl.zoneKeys()
+	inserted, updated, _ = sl.setBatch(entries, func(entry Entry[K, V], isNew bool) {
+		if fn != nil {
+			written = append(written, entry)
+			kinds = append(kinds, isNew)
+		}
+	})
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	fireThresholds(events)
+	for i, entry := range written {
+		fn(entry, kinds[i])
+	}
+	sl.observe("set_batch", start, len(entries))
+	return inserted, updated, nil
+}
+
+// BatchReport 描述一次 SetBatchReport 的结果
+// BatchReport describes the outcome of one SetBatchReport
+type BatchReport[K Ordered] struct {
+	// 新插入的键的数量和更新已有键的写入次数，规则与 SetBatch 相同
+	// Number of newly inserted keys and of writes updating an existing key, counted like SetBatch
+	Inserted int
+	Updated  int
+
+	// 被 WithHardLimit、WithValueGuard 或外部存储拒绝而没有写入的条目数
+	// Number of entries not written because WithHardLimit, WithValueGuard or the external store refused them
+	Rejected int
+
+	// 写入后进入前 N 名的键，按写入后的名次从高到低排列
+	// Keys that entered the top N, from the highest standing after the batch down
+	Entered []K
+
+	// 写入后离开前 N 名的键，包括被删除或淘汰的键，按写入前的名次从高到低排列
+	// Keys that left the top N, evicted keys included, from the highest standing before the batch down
+	Exited []K
+}
+
+// SetBatchReport 与 SetBatch 相同，但还会报告前 watchTopN 名（即 Top(watchTopN) 返回的值最大的成员）的变化。
+// 写入前后的名单在同一个临界区内获取，因此即使批内的条目相互挤占，报告也是精确的；
+// 批内先进入又离开前 N 名的键不会出现在报告中。耗时额外增加 O(watchTopN)
+// SetBatchReport behaves like SetBatch, but also reports the changes to the top watchTopN members,
+// the ones with the largest values that Top(watchTopN) returns.
+// The membership before and after is captured within the same critical section,
+// so the report is exact even when entries of the batch displace each other;
+// a key that entered and left again within the batch does not appear. It costs an extra O(watchTopN)
+func (sl *RankList[K, V]) SetBatchReport(entries []Entry[K, V], watchTopN int) BatchReport[K] {
+	start := sl.metricsStart()
+	var report BatchReport[K]
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return report
+	}
+	zones := sl.zoneKeys()
+	before := sl.topKeys(watchTopN)
+	report.Inserted, report.Updated, report.Rejected = sl.setBatch(entries, nil)
+	after := sl.topKeys(watchTopN)
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	report.Entered = missingKeys(after, before)
+	report.Exited = missingKeys(before, after)
+	fireThresholds(events)
+	sl.observe("set_batch", start, len(entries))
+	return report
+}
+
+// setBatch 按顺序写入一批键值对并对每个写入的条目调用 record（可以为 nil），返回插入、更新和被拒绝的次数，调用方需持有写锁
+// setBatch writes a batch of key-value pairs in order and calls record, which may be nil, for every written entry.
+// Returns the number of inserts, updates and rejected entries. The caller must hold the write lock
+func (sl *RankList[K, V]) setBatch(entries []Entry[K, V], record func(entry Entry[K, V], inserted bool)) (inserted int, updated int, rejected int) {
+	for _, entry := range entries {
+		if sl.guardWrite(entry.Key, entry.Value) != nil || sl.persistWrite(entry.Key, entry.Value) != nil {
+			rejected++
+			continue
+		}
+		result := sl.store(entry.Key, entry.Value, keyspaceZadd)
+		switch result {
+		case storeInserted:
+			inserted++
+		case storeUpdated:
+			updated++
+		default:
+			continue
+		}
+		if record != nil {
+			record(entry, result == storeInserted)
+		}
+	}
+	return inserted, updated, rejected
+}
+
+// topKeys 沿后向指针返回值最大的 n 个键，从值最大的开始，与 Top 一样跳过占位条目，调用方需持有锁
+// topKeys returns the keys of the n largest values along the backward pointers, largest first,
+// skipping placeholders the way Top does. The caller must hold the lock
+func (sl *RankList[K, V]) topKeys(n int) []K {
+	keys := make([]K, 0, max(min(n, sl.length), 0))
+	sl.walkTop(n, func(node *Node[K, V]) {
+		keys = append(keys, node.data.Key)
+	})
+	return keys
+}
+
+// missingKeys 按顺序返回 keys 中不在 other 里的键
+// missingKeys returns the keys of keys that are not in other, in order
+func missingKeys[K Ordered](keys []K, other []K) []K {
+	set := make(map[K]struct{}, len(other))
+	for _, key := range other {
+		set[key] = struct{}{}
+	}
+	missing := make([]K, 0)
+	for _, key := range keys {
+		if _, ok := set[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// ImportChunked 将 entries 分块写入跳表，每块在一把写锁内以与 SetBatch 相同的方式写入，块与块之间释放写锁，
+// 因此大批量导入不会长时间阻塞读操作。chunkSize 小于 1 时按 1 处理。
+// 每块写入之前检查 ctx，ctx 被取消时停止并返回已经写入的条目数和 ctx.Err()，已写入的块不会回滚；
+// 跳表被 Close 关闭时同样停止并返回 ErrClosed。返回的条目数与 SetBatch 的插入数加更新数相同，
+// 被 WithHardLimit、WithValueGuard 或外部存储拒绝的条目不计入。
+// 读操作可能在块与块之间看到只导入了一部分的状态
+// ImportChunked writes entries into the skip list in chunks, each chunk under one write lock the same way SetBatch does,
+// releasing the lock between chunks so a large import never blocks readers for long. A chunkSize below 1 is treated as 1.
+// ctx is checked before every chunk; once it is cancelled the import stops and returns the number of entries written so far
+// together with ctx.Err(), and chunks already written are not rolled back;
+// closing the list with Close stops it the same way with ErrClosed. The count matches the inserts plus updates
+// SetBatch reports, leaving out entries rejected by WithHardLimit, WithValueGuard or the external store.
+// Readers may observe a partially imported state between chunks
+func (sl *RankList[K, V]) ImportChunked(ctx context.Context, entries []Entry[K, V], chunkSize int) (int, error) {
+	chunkSize = max(chunkSize, 1)
+	applied := 0
+	for chunk := range slices.Chunk(entries, chunkSize) {
+		if err := ctx.Err(); err != nil {
+			return applied, err
+		}
+		inserted, updated, err := sl.setBatchFunc(chunk, nil)
+		applied += inserted + updated
+		if err != nil {
+			return applied, err
+		}
+	}
+	return applied, nil
+}
+
+// FromMap 使用 map 中的键值对创建一个新的跳表，通过排序后的 O(n) 构建完成
+// FromMap creates a new skip list from the key-value pairs of a map, using the sorted O(n) build
+func FromMap[K Ordered, V Ordered](m map[K]V, opts ...Option[K, V]) *RankList[K, V] {
+	sl := New(opts...)
+	sl.LoadMap(m, true)
+	return sl
+}
+
+// LoadMap 在一把写锁内将 map 中的键值对批量写入跳表。
+// replace 为 true 时先清空跳表；为 false 时与现有内容合并，相同的键以 map 中的值为准，现有键的元数据、条目版本和创建时间保留。
+// 键按从小到大的顺序处理，因此 WithHardLimit 拒绝哪些新键是确定的，与 map 的遍历顺序无关
+// LoadMap bulk-loads the key-value pairs of a map under one write lock.
+// When replace is true the list is cleared first, otherwise the map is merged into the existing content,
+// keys present in both take the value from the map, and the metadata, entry versions and creation times of existing keys are kept.
+// Keys are handled in ascending order, so which new keys WithHardLimit rejects is deterministic
+// rather than following map iteration order
+func (sl *RankList[K, V]) LoadMap(m map[K]V, replace bool) {
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return
+	}
+	zones := sl.zoneKeys()
+
+	var entries []Entry[K, V]
+	var states map[K]entryState[V]
+	if !replace {
+		entries = sl.entries()
+		states = sl.states()
+	}
+	accepted := make([]Entry[K, V], 0, len(m))
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		if value := m[key]; sl.guardKey(key, value) == nil {
+			accepted = append(accepted, Entry[K, V]{Key: key, Value: value})
+		}
+	}
+	if replace {
+		accepted = sl.limitEntries(accepted, 0, func(K) bool { return false })
+	} else {
+		accepted = sl.limitEntries(accepted, sl.length, func(key K) bool {
+			_, exists := sl.lookup(key)
+			return exists
+		})
+	}
+	entries = append(entries, accepted...)
+
+	meta := sl.meta
+	sl.load(entries)
+	if replace {
+		sl.journalReset()
+	} else {
+		sl.meta = meta
+		sl.keepStates(states)
+		for _, entry := range accepted {
+			sl.journal(walOpSet, entry.Key, entry.Value)
+			sl.mirror(walOpSet, entry.Key, entry.Value)
+		}
+	}
+	sl.evictOverflow()
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	fireThresholds(events)
+}
+
+// Compact 在一把写锁内用跳表自身第 0 层的内容重新构建跳表。
+// 大量删除之后，剩余节点仍保留着原有规模下的层级分布，头节点也仍然很高，arena 和字典占用的内存也不会归还；
+// 重建会为当前规模重新生成层级，并释放旧的节点、arena 和字典。
+// 长度、排名、值、元数据、条目版本、时间戳和 WithUpdateOrder 的顺序在重建前后完全相同，因此不会写入预写日志，也不会触发阈值回调
+// Compact rebuilds the skip list from its own level-0 contents under one write lock.
+// After massive deletes the remaining nodes still carry the level distribution and header height
+// of the original population, and memory held by the arena and the dictionary is not returned;
+// rebuilding re-derives the levels for the current size and releases the old nodes, arena and dictionary.
+// Length, ranks, values, metadata, entry versions, timestamps and the WithUpdateOrder order are identical before and after,
+// so nothing is written to the write-ahead log and no threshold callbacks fire
+func (sl *RankList[K, V]) Compact() {
+	sl.Lock()
+	defer sl.Unlock()
+	sl.compact()
+}
+
+// compact 用第 0 层的内容重新构建跳表，保留元数据、条目版本和时间戳，调用方需持有写锁
+// compact rebuilds the skip list from its level-0 contents, keeping metadata, entry versions and timestamps.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) compact() {
+	meta := sl.meta
+	order := sl.recency.positions(sl)
+	states := make([]entryState[V], 0, sl.length)
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		states = append(states, entryState[V]{version: curr.version, times: curr.times, placeholder: curr.placeholder})
+	}
+	sl.build(sl.entries())
+	sl.meta = meta
+
+	// 顺序在重建前后不变，按位置恢复条目版本和时间戳；重建后的字典已经对 Get 可见，因此在字典锁内修改
+	// The order is unchanged by the rebuild, so entry versions and timestamps are restored by position;
+	// the rebuilt dictionary is already visible to Get, so they are written under the dictionary lock
+	sl.dictMu.Lock()
+	for curr, i := sl.header.forward[0], 0; curr != nil; curr, i = curr.forward[0], i+1 {
+		curr.version = states[i].version
+		curr.times = states[i].times
+		curr.placeholder = states[i].placeholder
+	}
+	sl.dictMu.Unlock()
+	sl.rebuildStale()
+	sl.recency.replay(sl, order)
+}
@@ -0,0 +1,251 @@
+package ranklist
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func requireModel[K Ordered, V Ordered](t *testing.T, sl *RankList[K, V], model map[K]V) {
+	t.Helper()
+
+	entries := make([]Entry[K, V], 0, len(model))
+	for key, value := range model {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return compareEntries(entries[i], entries[j]) < 0
+	})
+
+	if sl.Length() != len(entries) {
+		t.Fatalf("expected length %d, got %d", len(entries), sl.Length())
+	}
+	for i, entry := range entries {
+		rank, exists := sl.Rank(entry.Key)
+		if !exists || rank != i+1 {
+			t.Fatalf("Key %v: expected rank %d, got %d, %v", entry.Key, i+1, rank, exists)
+		}
+		if value, _ := sl.Get(entry.Key); value != entry.Value {
+			t.Fatalf("Key %v: expected value %v, got %v", entry.Key, entry.Value, value)
+		}
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	model := make(map[string]int)
+	for i := 0; i < 100000; i++ {
+		model[strconv.Itoa(i)] = rand.IntN(10000)
+	}
+
+	sl := FromMap(model)
+	requireModel(t, sl, model)
+}
+
+func TestLoadMapReplace(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("x", 1)
+	sl.Set("a", 5)
+
+	model := map[string]int{"a": 1, "b": 2}
+	sl.LoadMap(model, true)
+	requireModel(t, sl, model)
+}
+
+func TestLoadMapMerge(t *testing.T) {
+	sl := New[string, int]()
+	model := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(i)
+		value := rand.IntN(100)
+		sl.Set(key, value)
+		model[key] = value
+	}
+	sl.SetMeta("0", map[string]string{"name": "zero"})
+
+	overlay := make(map[string]int)
+	for i := 500; i < 1500; i++ {
+		key := strconv.Itoa(i)
+		value := rand.IntN(100)
+		overlay[key] = value
+		model[key] = value
+	}
+
+	sl.LoadMap(overlay, false)
+	requireModel(t, sl, model)
+
+	if meta, _ := sl.GetMeta("0"); meta["name"] != "zero" {
+		t.Errorf("merge should keep the metadata of existing keys, got %v", meta)
+	}
+
+	sl.Set("new", -1)
+	model["new"] = -1
+	requireModel(t, sl, model)
+}
+
+func TestCompact(t *testing.T) {
+	sl := New[string, int]()
+	model := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		key := strconv.Itoa(i)
+		value := rand.IntN(100)
+		if i%10 == 0 {
+			// 留下的节点都很高，模拟原有规模留下的层级分布
+			// Survivors are tall, mimicking the level distribution left behind by the original population
+			sl.setLevel(key, value, MaxLevel)
+			model[key] = value
+		} else {
+			sl.Set(key, value)
+		}
+	}
+	for i := 0; i < 10000; i++ {
+		if i%10 != 0 {
+			sl.Del(strconv.Itoa(i))
+		}
+	}
+	sl.SetMeta("0", map[string]string{"name": "zero"})
+
+	before := sl.Stats()
+	ranks := sl.Range(1, sl.Length()+1)
+	sl.Compact()
+	after := sl.Stats()
+
+	requireModel(t, sl, model)
+	requireSpans(t, sl)
+	requireBackward(t, sl)
+	if !slices.Equal(ranks, sl.Range(1, sl.Length()+1)) {
+		t.Fatalf("Compact changed the standings")
+	}
+	if meta, _ := sl.GetMeta("0"); meta["name"] != "zero" {
+		t.Errorf("Compact should keep metadata, got %v", meta)
+	}
+	if before.LevelHistogram[MaxLevel-1] != len(model) {
+		t.Fatalf("expected %d nodes of height %d before Compact, got %d", len(model), MaxLevel, before.LevelHistogram[MaxLevel-1])
+	}
+	if after.Level >= before.Level || len(after.LevelHistogram) >= len(before.LevelHistogram) {
+		t.Fatalf("expected the level histogram to shrink, got %v", after.LevelHistogram)
+	}
+	if after.SpanBytes >= before.SpanBytes {
+		t.Fatalf("expected span bytes to shrink, got %d -> %d", before.SpanBytes, after.SpanBytes)
+	}
+}
+
+// cancelAfter 在 Err 被调用 n 次之后报告取消的 context
+// cancelAfter is a context that reports cancellation once Err has been called n times
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n == 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func TestImportChunked(t *testing.T) {
+	entries := make([]Entry[int, int], 1000)
+	for i := range entries {
+		entries[i] = Entry[int, int]{Key: rand.IntN(700), Value: rand.IntN(100)}
+	}
+
+	model := func(entries []Entry[int, int]) map[int]int {
+		m := make(map[int]int)
+		for _, entry := range entries {
+			m[entry.Key] = entry.Value
+		}
+		return m
+	}
+
+	sl := New[int, int]()
+	applied, err := sl.ImportChunked(context.Background(), entries, 64)
+	if err != nil || applied != len(entries) {
+		t.Fatalf("expected %d entries, got %d, %v", len(entries), applied, err)
+	}
+	requireModel(t, sl, model(entries))
+
+	cancelled := New[int, int]()
+	applied, err = cancelled.ImportChunked(&cancelAfter{Context: context.Background(), n: 3}, entries, 64)
+	if !errors.Is(err, context.Canceled) || applied != 3*64 {
+		t.Fatalf("expected 192 entries and context.Canceled, got %d, %v", applied, err)
+	}
+	requireModel(t, cancelled, model(entries[:applied]))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if applied, err := New[int, int]().ImportChunked(ctx, entries, 0); applied != 0 || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected nothing applied, got %d, %v", applied, err)
+	}
+}
+
+func TestImportChunkedRejected(t *testing.T) {
+	entries := []Entry[string, int]{{"a", 1}, {"b", -2}, {"c", 3}, {"d", 4}}
+	sl := New(
+		WithHardLimit[string, int](2),
+		WithValueGuard[string, int](func(old, new int) error {
+			if new < 0 {
+				return errors.New("negative")
+			}
+			return nil
+		}),
+	)
+	applied, err := sl.ImportChunked(context.Background(), entries, 2)
+	if err != nil || applied != 2 {
+		t.Fatalf("expected 2 entries applied, got %d, %v", applied, err)
+	}
+	requireModel(t, sl, map[string]int{"a": 1, "c": 3})
+
+	// 已关闭的跳表不写入任何块 / A closed list takes no chunk
+	sl.Close()
+	if applied, err := sl.ImportChunked(context.Background(), entries, 2); applied != 0 || !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected nothing applied and ErrClosed, got %d, %v", applied, err)
+	}
+}
+
+func TestSetBatchReport(t *testing.T) {
+	sl := New[string, int]()
+	sl.SetBatch([]Entry[string, int]{{"a", 10}, {"b", 20}, {"c", 30}, {"d", 40}, {"e", 50}})
+
+	// a 被提升进前 3 名，c 被挤出；d 先被挤出又回到前 3 名，不会出现在报告中
+	// a is promoted into the top 3 and c is pushed out; d drops out and comes back, so it is not reported
+	report := sl.SetBatchReport([]Entry[string, int]{{"a", 60}, {"d", 1}, {"d", 45}, {"f", 5}}, 3)
+	if report.Inserted != 1 || report.Updated != 3 {
+		t.Fatalf("expected 1 insert and 3 updates, got %d and %d", report.Inserted, report.Updated)
+	}
+	if !slices.Equal(report.Entered, []string{"a"}) || !slices.Equal(report.Exited, []string{"c"}) {
+		t.Fatalf("expected a to enter and c to exit, got %v and %v", report.Entered, report.Exited)
+	}
+
+	report = sl.SetBatchReport([]Entry[string, int]{{"e", 55}, {"b", 25}}, 3)
+	if len(report.Entered) != 0 || len(report.Exited) != 0 || report.Updated != 2 {
+		t.Fatalf("expected no membership change, got %+v", report)
+	}
+
+	// 成员少于 N 时新成员直接进入
+	// With fewer than N members a new one enters directly
+	empty := New[string, int]()
+	if report := empty.SetBatchReport([]Entry[string, int]{{"x", 1}, {"y", 2}}, 3); !slices.Equal(report.Entered, []string{"y", "x"}) {
+		t.Fatalf("expected both keys to enter, got %v", report.Entered)
+	}
+}
+
+func TestSetBatchReportPlaceholders(t *testing.T) {
+	sl := New[string, int]()
+	sl.SetBatch([]Entry[string, int]{{"a", 10}, {"b", 20}, {"c", 30}})
+	sl.Reserve("seed", 100)
+
+	// 占位条目不在 Top 中，也不会出现在报告里
+	// A placeholder is not part of Top and never shows up in the report
+	report := sl.SetBatchReport([]Entry[string, int]{{"seed", 5}, {"d", 40}}, 2)
+	if !slices.Equal(report.Entered, []string{"d"}) || !slices.Equal(report.Exited, []string{"b"}) {
+		t.Fatalf("expected d to enter and b to exit, got %v and %v", report.Entered, report.Exited)
+	}
+	if top := entryKeys(sl.Top(2)); !slices.Equal(top, []string{"d", "c"}) {
+		t.Fatalf("unexpected top %v", top)
+	}
+}
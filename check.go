@@ -0,0 +1,119 @@
+package ranklist
+
+import "fmt"
+
+// Check 在一把读锁内遍历跳表的每一层，校验内部结构的不变量：
+// 第 0 层按 (值, 键) 严格递增，后向指针和尾节点与第 0 层一致，每一层的跨度之和等于节点在第 0 层的位置，
+// 每个节点都出现在它的每一层上，长度与第 0 层的节点数和字典大小一致，字典指向跳表中对应的节点，
+// 跳表的层级等于最高节点的层级，开启 WithUpdateOrder 时更新顺序链表的前后链接一致且恰好包含跳表的每个节点，
+// 开启 WithValueBuckets 时按值索引的每个桶与第 0 层一致。
+// 返回的错误包装了 ErrCorrupted，并说明第一个被违反的不变量及其位置，结构一致时返回 nil
+// Check walks every level of the skip list under one read lock and validates its internal invariants:
+// level 0 is strictly ascending by (value, key), backward pointers and the tail agree with level 0,
+// the spans of every level add up to each node's position at level 0, every node appears on each of its levels,
+// the length matches both the level-0 count and the dictionary size, the dictionary points at the nodes in the list,
+// the list level matches the tallest node, with WithUpdateOrder the update order list links both ways
+// and holds exactly the nodes of the list, and with WithValueBuckets every bucket of the index agrees with level 0.
+// The returned error wraps ErrCorrupted and names the first violated invariant and where, nil means the structure is consistent
+func (sl *RankList[K, V]) Check() error {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	return sl.check()
+}
+
+// check 校验 Check 描述的全部不变量，只遍历第 0 层一次，更高的层随之同步推进，调用方需持有锁
+// check validates every invariant described by Check in a single walk of level 0 that advances the higher levels in step.
+// The caller must hold the lock
+func (sl *RankList[K, V]) check() error {
+	// next[i] 是第 i 层上下一个应该经过的节点，reached[i] 是到达它之前第 i 层跨度之和，因此不需要节点位置的索引
+	// next[i] is the next node expected on level i and reached[i] the sum of the level-i spans before it,
+	// so no index of node positions is needed
+	var next [MaxLevel]*Node[K, V]
+	var reached [MaxLevel]int
+	copy(next[:], sl.header.forward)
+
+	// 只有校验更新顺序链表时才需要节点的位置
+	// Node positions are only needed to validate the update order list
+	var positions map[*Node[K, V]]int
+	if sl.recency != nil {
+		positions = make(map[*Node[K, V]]int, sl.length)
+	}
+
+	var prev *Node[K, V]
+	tallest := 1
+	position := 0
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		position++
+
+		// 严格递增同时排除了第 0 层上的环
+		// Strictly ascending order also rules out loops on level 0
+		if prev != nil && sl.compare(prev.data, curr.data) >= 0 {
+			return fmt.Errorf("%w: level 0 is out of order at position %d, key %v after key %v",
+				ErrCorrupted, position, curr.data.Key, prev.data.Key)
+		}
+		if curr.backward != prev {
+			return fmt.Errorf("%w: backward pointer of key %v at position %d does not point at its predecessor",
+				ErrCorrupted, curr.data.Key, position)
+		}
+		if curr.level < 1 || curr.level > sl.level || len(curr.forward) != curr.level || len(curr.span) != curr.level {
+			return fmt.Errorf("%w: key %v at position %d has level %d with %d forward pointers and %d spans, list level is %d",
+				ErrCorrupted, curr.data.Key, position, curr.level, len(curr.forward), len(curr.span), sl.level)
+		}
+		if !sl.noDict {
+			if node, exists := sl.dict[curr.data.Key]; !exists || node != curr {
+				return fmt.Errorf("%w: dictionary entry of key %v at position %d does not point at its node",
+					ErrCorrupted, curr.data.Key, position)
+			}
+		}
+
+		// 节点必须出现在它的每一层上，且每一层的跨度之和等于它的位置
+		// The node must be linked on each of its levels, with the spans of every level adding up to its position
+		for i := 0; i < curr.level; i++ {
+			if next[i] != curr {
+				return fmt.Errorf("%w: level %d links past key %v at position %d, which reaches it",
+					ErrCorrupted, i, curr.data.Key, position)
+			}
+			reached[i] += curr.span[i]
+			if reached[i] != position {
+				return fmt.Errorf("%w: level %d spans add up to %d at key %v, which is at position %d",
+					ErrCorrupted, i, reached[i], curr.data.Key, position)
+			}
+			next[i] = curr.forward[i]
+		}
+		if positions != nil {
+			positions[curr] = position
+		}
+		tallest = max(tallest, curr.level)
+		prev = curr
+	}
+
+	if sl.tail != prev {
+		return fmt.Errorf("%w: tail does not point at the last node", ErrCorrupted)
+	}
+	if position != sl.length {
+		return fmt.Errorf("%w: length is %d but level 0 holds %d nodes", ErrCorrupted, sl.length, position)
+	}
+	if !sl.noDict && len(sl.dict) != sl.length {
+		return fmt.Errorf("%w: length is %d but the dictionary holds %d keys", ErrCorrupted, sl.length, len(sl.dict))
+	}
+	if sl.level != tallest {
+		return fmt.Errorf("%w: list level is %d but the tallest node has level %d", ErrCorrupted, sl.level, tallest)
+	}
+
+	// 每一层在最后一个到达它的节点之后不能再链接任何节点
+	// No level may link anything past the last node reaching it
+	for i, node := range next {
+		if node == nil {
+			continue
+		}
+		if i >= sl.level {
+			return fmt.Errorf("%w: header links level %d above the list level %d", ErrCorrupted, i, sl.level)
+		}
+		return fmt.Errorf("%w: level %d links key %v which is not on that level", ErrCorrupted, i, node.data.Key)
+	}
+	if err := sl.recency.check(positions); err != nil {
+		return err
+	}
+	return sl.buckets.check(sl)
+}
@@ -0,0 +1,98 @@
+package ranklist
+
+import (
+	"errors"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCheckValid(t *testing.T) {
+	sl := New[string, int]()
+	if err := sl.Check(); err != nil {
+		t.Fatalf("empty list: %v", err)
+	}
+	for i := 0; i < 5000; i++ {
+		key := strconv.Itoa(rand.IntN(500))
+		if rand.IntN(4) == 0 {
+			sl.Del(key)
+		} else {
+			sl.Set(key, rand.IntN(20))
+		}
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatalf("after churn: %v", err)
+	}
+
+	noDict := New(WithNoDict[string, int]())
+	noDict.Restore(sl.Snapshot())
+	if err := noDict.Check(); err != nil {
+		t.Fatalf("without a dictionary: %v", err)
+	}
+}
+
+func TestCheckCorruption(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(sl *RankList[string, int])
+		message string
+	}{
+		{"order", func(sl *RankList[string, int]) {
+			sl.byRank(2).data.Value = 100
+		}, "out of order"},
+		{"backward", func(sl *RankList[string, int]) {
+			sl.byRank(3).backward = sl.byRank(1)
+		}, "backward pointer"},
+		{"tail", func(sl *RankList[string, int]) {
+			sl.tail = sl.byRank(2)
+		}, "tail"},
+		{"span", func(sl *RankList[string, int]) {
+			sl.byRank(4).span[1]++
+		}, "spans add up"},
+		{"length", func(sl *RankList[string, int]) {
+			sl.length++
+		}, "length is"},
+		{"dict size", func(sl *RankList[string, int]) {
+			sl.dict["ghost"] = NewNode("ghost", 0, 1)
+		}, "dictionary holds"},
+		{"dict node", func(sl *RankList[string, int]) {
+			sl.dict["c"] = NewNode("c", 3, 1)
+		}, "dictionary entry"},
+		{"level", func(sl *RankList[string, int]) {
+			sl.level++
+		}, "tallest node"},
+		{"missing link", func(sl *RankList[string, int]) {
+			b, d := sl.byRank(2), sl.byRank(4)
+			sl.header.forward[1] = d
+			d.span[1] += b.span[1]
+		}, "level 1 links"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 键 a..f，b 和 d 高两层
+			// Keys a..f, b and d are two levels tall
+			sl := New[string, int]()
+			for i, key := range []string{"a", "b", "c", "d", "e", "f"} {
+				level := 1
+				if key == "b" || key == "d" {
+					level = 2
+				}
+				sl.setLevel(key, i+1, level)
+			}
+			if err := sl.Check(); err != nil {
+				t.Fatalf("fixture: %v", err)
+			}
+
+			tt.corrupt(sl)
+			err := sl.Check()
+			if !errors.Is(err, ErrCorrupted) {
+				t.Fatalf("expected ErrCorrupted, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Fatalf("expected the error to mention %q, got %q", tt.message, err)
+			}
+		})
+	}
+}
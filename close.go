@@ -0,0 +1,69 @@
+package ranklist
+
+// Close 关闭跳表：停止 StartAutoSnapshot、PublishTop 和 RangeStream 启动的后台 goroutine 并等待它们退出，
+// RangeStream 返回的通道随之关闭；预写日志的 Writer 实现了 Flush() error（例如 *bufio.Writer）时将其刷出。
+// 关闭之后内容被冻结：返回 error 的修改方法返回 ErrClosed，其他修改方法不做任何修改，
+// 返回与未命中或被拒绝时相同的结果（例如 Set 和 Del 返回 false，IncrBy 返回当前值）；
+// 读取方法照常工作，因此被缓存引用的跳表在关闭后仍然可以查询。之后启动的后台任务立即结束。
+// 重复调用是安全的，只有第一次调用会刷出预写日志并返回其错误。
+// Close 会等待后台 goroutine 退出，因此不能在 PublishTop 的回调或快照的 open 函数中调用
+// Close shuts the list down: the background goroutines started by StartAutoSnapshot, PublishTop and RangeStream
+// are stopped and waited for, closing the channels returned by RangeStream,
+// and the write-ahead log writer is flushed when it implements Flush() error, e.g. a *bufio.Writer.
+// After Close the content is frozen: mutators returning an error return ErrClosed,
+// the other mutators change nothing and return what they return on a miss or a rejection
+// (e.g. false for Set and Del, the current value for IncrBy);
+// reads keep working, so a list still referenced by a cache remains queryable. Background work started later ends at once.
+// Calling it more than once is safe, only the first call flushes the log and returns its error.
+// Close waits for background goroutines, so it must not be called from a PublishTop callback or a snapshot open function
+func (sl *RankList[K, V]) Close() error {
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return nil
+	}
+	err := sl.shutdown()
+	sl.Unlock()
+
+	sl.background.Wait()
+	return err
+}
+
+// shutdown 将跳表标记为已关闭，通知后台 goroutine 退出并刷出预写日志，调用方需持有写锁且跳表尚未关闭。
+// 调用方在释放写锁之后需等待 sl.background
+// shutdown marks the list closed, tells background goroutines to exit and flushes the write-ahead log.
+// The caller must hold the write lock on a list not closed yet, and wait for sl.background once it is released
+func (sl *RankList[K, V]) shutdown() error {
+	sl.closed = true
+	close(sl.done)
+
+	if sl.wal != nil {
+		if f, ok := sl.wal.w.(interface{ Flush() error }); ok {
+			return f.Flush()
+		}
+	}
+	return nil
+}
+
+// Closed 返回跳表是否已经被 Close 关闭
+// Closed reports whether the list has been shut down by Close
+func (sl *RankList[K, V]) Closed() bool {
+	sl.RLock()
+	defer sl.RUnlock()
+	return sl.closed
+}
+
+// startBackground 登记一个后台 goroutine，跳表已关闭时返回 false，
+// 登记成功的 goroutine 退出时必须调用 sl.background.Done
+// startBackground registers a background goroutine and returns false once the list is closed,
+// a registered goroutine must call sl.background.Done when it exits
+func (sl *RankList[K, V]) startBackground() bool {
+	sl.Lock()
+	defer sl.Unlock()
+
+	if sl.closed {
+		return false
+	}
+	sl.background.Add(1)
+	return true
+}
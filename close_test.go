@@ -0,0 +1,256 @@
+package ranklist
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// requireNoLeak 等待 goroutine 数量回落到 baseline，超时则失败
+// requireNoLeak waits for the goroutine count to fall back to baseline and fails on timeout
+func requireNoLeak(t *testing.T, baseline int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			t.Fatalf("expected at most %d goroutines, got %d:\n%s",
+				baseline, runtime.NumGoroutine(), buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestCloseStopsBackground(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	sl := New[string, int]()
+	for i := 0; i < 5000; i++ {
+		sl.Set(strconv.Itoa(i), i)
+	}
+	sl.StartAutoSnapshot(time.Millisecond, func() (io.WriteCloser, error) {
+		return nopWriteCloser{io.Discard}, nil
+	}, nil)
+	published := make(chan struct{}, 1)
+	sl.PublishTop(context.Background(), 3, time.Millisecond, func(top []Entry[string, int]) {
+		select {
+		case published <- struct{}{}:
+		default:
+		}
+	})
+	// 没有人读取的流会一直阻塞在发送上
+	// Nobody reads this stream, so it blocks on sending
+	stream := sl.RangeStream(context.Background(), 1, 5001, 0)
+	<-published
+
+	if err := sl.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n := 0
+	for range stream {
+		n++
+	}
+	if n > 1 {
+		t.Fatalf("expected the stream to stop right after Close, got %d entries", n)
+	}
+	requireNoLeak(t, baseline)
+
+	// 关闭之后启动的后台任务立即结束
+	// Background work started after Close ends at once
+	if _, ok := <-sl.RangeStream(context.Background(), 1, 10, 0); ok {
+		t.Fatalf("a stream started after Close should be closed")
+	}
+	sl.StartAutoSnapshot(time.Millisecond, func() (io.WriteCloser, error) {
+		t.Errorf("no snapshot should be taken after Close")
+		return nopWriteCloser{io.Discard}, nil
+	}, nil)()
+	<-sl.publishTop(context.Background(), 3, make(chan time.Time), nil)
+	requireNoLeak(t, baseline)
+}
+
+func TestCloseWaitsForSnapshot(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+
+	w := &blockingWriter{release: make(chan struct{}), closed: make(chan struct{})}
+	opened := make(chan struct{})
+	tick := make(chan time.Time)
+	sl.autoSnapshot(tick, func() (io.WriteCloser, error) {
+		close(opened)
+		return w, nil
+	}, nil)
+	tick <- time.Now()
+	<-opened
+
+	closed := make(chan struct{})
+	go func() {
+		sl.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatalf("Close should wait for the in-flight snapshot")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(w.release)
+	<-closed
+	<-w.closed
+}
+
+func TestCloseMutations(t *testing.T) {
+	var wal bytes.Buffer
+	sl := New(WithWAL[string, int](&wal))
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.SetMeta("a", map[string]string{"team": "red"})
+	sl.Set("c", 30)
+	sl.Suspend("c")
+
+	if sl.Closed() {
+		t.Fatalf("a new list should not be closed")
+	}
+	if err := sl.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sl.Close(); err != nil {
+		t.Fatalf("a second Close should return nil, got %v", err)
+	}
+	if !sl.Closed() {
+		t.Fatalf("expected the list to be closed")
+	}
+	version, logged := sl.Version(), wal.Len()
+
+	var snapshot, jsonData, msgpack bytes.Buffer
+	New[string, int]().Save(&snapshot)
+	New[string, int]().EncodeMsgpack(&msgpack)
+	jsonData.WriteString(`[{"key":"x","value":1}]`)
+
+	errs := map[string]error{}
+	_, errs["SetChecked"] = sl.SetChecked("x", 1)
+	_, errs["TrySet"] = sl.TrySet("x", 1, time.Millisecond)
+	_, errs["IncrByChecked"] = sl.IncrByChecked("a", 1)
+	errs["Load"] = sl.Load(&snapshot)
+	errs["UnmarshalJSON"] = sl.UnmarshalJSON(jsonData.Bytes())
+	errs["DecodeMsgpack"] = sl.DecodeMsgpack(&msgpack)
+	errs["Replay"] = sl.Replay(bytes.NewReader(wal.Bytes()))
+	_, errs["ReadCSV"] = sl.ReadCSV(strings.NewReader("x,1\n"), nil, nil)
+	_, errs["ImportChunked"] = sl.ImportChunked(context.Background(), []Entry[string, int]{{"x", 1}}, 1)
+	for name, err := range errs {
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("%s: expected ErrClosed, got %v", name, err)
+		}
+	}
+
+	falses := map[string]bool{
+		"Set":           sl.Set("x", 1),
+		"Del":           sl.Del("a"),
+		"DelByValue":    sl.DelByValue("a", 10),
+		"UpdateByValue": sl.UpdateByValue("a", 10, 11),
+		"SetIfVersion":  sl.SetIfVersion("x", 1, 0),
+		"SetMeta":       sl.SetMeta("a", nil),
+		"Suspend":       sl.Suspend("a"),
+		"Resume":        sl.Resume("c"),
+	}
+	for name, ok := range falses {
+		if ok {
+			t.Errorf("%s: expected false after Close", name)
+		}
+	}
+
+	if value := sl.IncrBy("a", 5); value != 10 {
+		t.Errorf("IncrBy: expected the current value 10, got %d", value)
+	}
+	if value, clamped := sl.IncrByClamped("a", 5, 0, 100); value != 10 || clamped != 0 {
+		t.Errorf("IncrByClamped: expected 10, 0, got %d, %d", value, clamped)
+	}
+	if values := sl.AddAll(map[string]int{"a": 1, "x": 1}); values["a"] != 10 || values["x"] != 0 {
+		t.Errorf("AddAll: expected the current values, got %v", values)
+	}
+	if inserted, updated := sl.SetBatch([]Entry[string, int]{{"x", 1}, {"a", 1}}); inserted != 0 || updated != 0 {
+		t.Errorf("SetBatch: expected nothing written, got %d, %d", inserted, updated)
+	}
+	if report := sl.SetBatchReport([]Entry[string, int]{{"x", 1}}, 2); report.Inserted != 0 || len(report.Entered) != 0 {
+		t.Errorf("SetBatchReport: expected an empty report, got %+v", report)
+	}
+	if entries := sl.ResetAndSnapshot(); entries != nil {
+		t.Errorf("ResetAndSnapshot: expected nil, got %v", entries)
+	}
+	sl.LoadMap(map[string]int{"x": 1}, true)
+	sl.Restore([]Entry[string, int]{{"x", 1}})
+	sl.Clear()
+
+	// 读取照常工作，内容、版本和日志都没有变化
+	// Reads keep working and the content, version and log are all unchanged
+	if entries := sl.Entries(); !slices.Equal(entries, []Entry[string, int]{{"a", 10}, {"b", 20}}) {
+		t.Fatalf("the content should be frozen, got %v", entries)
+	}
+	if rank, ok := sl.Rank("b"); !ok || rank != 2 {
+		t.Fatalf("Rank should keep working, got %d, %v", rank, ok)
+	}
+	if meta, _ := sl.GetMeta("a"); meta["team"] != "red" {
+		t.Fatalf("GetMeta should keep working, got %v", meta)
+	}
+	if value, ok := sl.Suspended("c"); !ok || value != 30 {
+		t.Fatalf("Suspended should keep working, got %d, %v", value, ok)
+	}
+	if top := sl.Top(1); len(top) != 1 || top[0].Key != "b" {
+		t.Fatalf("Top should keep working, got %v", top)
+	}
+	var saved bytes.Buffer
+	if err := sl.Save(&saved); err != nil {
+		t.Fatalf("Save should keep working, got %v", err)
+	}
+	if sl.Version() != version || wal.Len() != logged {
+		t.Fatalf("a closed list should neither change its version nor write to the log")
+	}
+}
+
+func TestCloseFlushesWAL(t *testing.T) {
+	var log bytes.Buffer
+	bw := bufio.NewWriterSize(&log, 1<<16)
+	sl := New(WithWAL[string, int](bw))
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Del("a")
+
+	if log.Len() != 0 {
+		t.Fatalf("expected the records to sit in the buffer before Close")
+	}
+	if err := sl.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	replayed := New[string, int]()
+	if err := replayed.Replay(&log); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries := replayed.Entries(); !slices.Equal(entries, []Entry[string, int]{{"b", 20}}) {
+		t.Fatalf("expected the flushed log to replay to [b 20], got %v", entries)
+	}
+}
+
+type failingFlusher struct{ io.Writer }
+
+func (failingFlusher) Flush() error { return errors.New("disk full") }
+
+func TestCloseFlushError(t *testing.T) {
+	sl := New(WithWAL[string, int](failingFlusher{io.Discard}))
+	if err := sl.Close(); err == nil || err.Error() != "disk full" {
+		t.Fatalf("expected the flush error, got %v", err)
+	}
+	if err := sl.Close(); err != nil {
+		t.Fatalf("only the first Close should report the flush error, got %v", err)
+	}
+}
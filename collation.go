@@ -0,0 +1,99 @@
+package ranklist
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithCollation 以 less 作为字符串值的排序规则，例如 WithCollation[string, string](NumericStringLess)，
+// 调用方不需要自己编写比较函数。less(a, b) 和 less(b, a) 都为 false 的两个值视为相同，按键排列，
+// 其余行为与 WithValueCompare 相同。less 为 nil 时 panic
+// WithCollation orders string values by less, e.g. WithCollation[string, string](NumericStringLess),
+// without the caller writing comparison code. Two values for which neither less(a, b) nor less(b, a) holds
+// count as equal and are sorted by key; otherwise it behaves like WithValueCompare. It panics if less is nil
+func WithCollation[K Ordered, V ~string](less func(a, b string) bool) Option[K, V] {
+	if less == nil {
+		panic("ranklist: collation must not be nil")
+	}
+	return WithValueCompare[K](func(a, b V) int {
+		switch {
+		case less(string(a), string(b)):
+			return -1
+		case less(string(b), string(a)):
+			return 1
+		}
+		return 0
+	})
+}
+
+// NumericStringLess 按数字感知的顺序比较字符串：连续的数字按数值比较，其余部分按字节比较，因此 "v9" 排在 "v10" 之前。
+// 数值相同但写法不同的字符串（例如 "v01" 和 "v1"）再按字节比较，因此只有完全相同的字符串才被视为相同
+// NumericStringLess compares strings in numeric-aware order: runs of digits compare by numeric value
+// and everything else byte by byte, so "v9" sorts before "v10".
+// Strings that only differ in how a number is written, such as "v01" and "v1", fall back to byte order,
+// so only identical strings count as equal
+func NumericStringLess(a, b string) bool {
+	x, y := a, b
+	for x != "" && y != "" {
+		if isDigit(x[0]) && isDigit(y[0]) {
+			xn, xrest := cutDigits(x)
+			yn, yrest := cutDigits(y)
+			if c := compareDigits(xn, yn); c != 0 {
+				return c < 0
+			}
+			x, y = xrest, yrest
+			continue
+		}
+		if x[0] != y[0] {
+			return x[0] < y[0]
+		}
+		x, y = x[1:], y[1:]
+	}
+	if x != y {
+		return x == ""
+	}
+	return a < b
+}
+
+// FoldCaseLess 忽略大小写比较字符串，逐个字符按 Unicode 小写形式比较，因此 "Alice" 和 "alice" 被视为相同
+// FoldCaseLess compares strings ignoring case, rune by rune on their Unicode lower case,
+// so "Alice" and "alice" count as equal
+func FoldCaseLess(a, b string) bool {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if la, lb := unicode.ToLower(ra), unicode.ToLower(rb); la != lb {
+			return la < lb
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return a == "" && b != ""
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// cutDigits 从 s 的开头切出连续的数字，返回去掉前导零的数字和剩余部分
+// cutDigits cuts the run of digits off the front of s, returning it without leading zeros together with the remainder
+func cutDigits(s string) (string, string) {
+	end := 0
+	for end < len(s) && isDigit(s[end]) {
+		end++
+	}
+	return strings.TrimLeft(s[:end], "0"), s[end:]
+}
+
+// compareDigits 比较两个没有前导零的数字串的数值，位数多的更大，位数相同时按字节比较
+// compareDigits compares the numeric values of two digit runs without leading zeros,
+// the longer one is larger and equal lengths compare byte by byte
+func compareDigits(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,116 @@
+package ranklist
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNumericStringLess(t *testing.T) {
+	sorted := []string{"", "1", "2", "10", "v", "v01", "v1", "v1.2", "v1.10", "v2", "v9", "v10", "v10a", "va"}
+	for i, a := range sorted {
+		for j, b := range sorted {
+			if got := NumericStringLess(a, b); got != (i < j) {
+				t.Errorf("NumericStringLess(%q, %q) = %v", a, b, got)
+			}
+		}
+	}
+}
+
+func TestFoldCaseLess(t *testing.T) {
+	cases := []struct {
+		a, b string
+		less bool
+	}{
+		{"alice", "Bob", true},
+		{"Bob", "alice", false},
+		{"Alice", "alice", false},
+		{"alice", "ALICE", false},
+		{"al", "Alice", true},
+		{"Ärger", "ärger", false},
+	}
+	for _, c := range cases {
+		if got := FoldCaseLess(c.a, c.b); got != c.less {
+			t.Errorf("FoldCaseLess(%q, %q) = %v", c.a, c.b, got)
+		}
+	}
+}
+
+func TestCollationNumeric(t *testing.T) {
+	sl := New(WithCollation[string, string](NumericStringLess))
+	sl.Set("b", "v10")
+	sl.Set("a", "v2")
+	sl.Set("c", "v9")
+	sl.Set("d", "v1")
+
+	if keys := entryKeys(sl.Range(1, 5)); !slices.Equal(keys, []string{"d", "a", "c", "b"}) {
+		t.Fatalf("unexpected order %v", keys)
+	}
+	if rank, _ := sl.Rank("b"); rank != 4 {
+		t.Fatalf("expected v10 to rank last, got %d", rank)
+	}
+	if keys := entryKeys(sl.RangeByScore("v2", "v9")); !slices.Equal(keys, []string{"a", "c"}) {
+		t.Fatalf("unexpected score range %v", keys)
+	}
+	if n := sl.CountByScore("v3", "v100"); n != 2 {
+		t.Fatalf("expected 2 values within [v3, v100], got %d", n)
+	}
+
+	sl.Del("c")
+	sl.Set("a", "v11")
+	if keys := entryKeys(sl.Range(1, 4)); !slices.Equal(keys, []string{"d", "b", "a"}) {
+		t.Fatalf("unexpected order after updates %v", keys)
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollationFoldCase(t *testing.T) {
+	sl := New(WithCollation[int, string](FoldCaseLess))
+	sl.Set(3, "alice")
+	sl.Set(1, "Bob")
+	sl.Set(2, "ALICE")
+	sl.Set(4, "Alice")
+
+	// 忽略大小写后相同的值并列，按键排列 / Values equal under folding tie and sort by key
+	if keys := entryKeys(sl.Range(1, 5)); !slices.Equal(keys, []int{2, 3, 4, 1}) {
+		t.Fatalf("unexpected order %v", keys)
+	}
+	if n, _ := sl.TieCount(3); n != 3 {
+		t.Fatalf("expected 3 ties, got %d", n)
+	}
+	if rank, _ := sl.DenseRank(1); rank != 2 {
+		t.Fatalf("expected Bob at dense rank 2, got %d", rank)
+	}
+	if keys := sl.KeysWithValue("aLiCe"); !slices.Equal(keys, []int{2, 3, 4}) {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+
+	// 改变大小写不会打乱并列的顺序 / Changing case keeps the order of ties stable
+	sl.Set(3, "ALICE")
+	sl.Set(2, "alice")
+	if keys := entryKeys(sl.Range(1, 5)); !slices.Equal(keys, []int{2, 3, 4, 1}) {
+		t.Fatalf("ties must stay in key order, got %v", keys)
+	}
+	if value, _ := sl.Get(3); value != "ALICE" {
+		t.Fatalf("the stored value must keep its case, got %q", value)
+	}
+
+	if !sl.Del(3) {
+		t.Fatal("expected 3 to be deleted")
+	}
+	if rank, _ := sl.Rank(4); rank != 2 {
+		t.Fatalf("expected 4 at rank 2 after the delete, got %d", rank)
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func entryKeys[K Ordered, V Ordered](entries []Entry[K, V]) []K {
+	keys := make([]K, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+	return keys
+}
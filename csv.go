@@ -0,0 +1,117 @@
+package ranklist
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+)
+
+// csvFlushRows 写入 CSV 时每隔多少行刷新一次
+// csvFlushRows is how many rows are written between flushes when exporting CSV
+const csvFlushRows = 1024
+
+// WriteCSV 按排名顺序将跳表以 CSV 格式流式写入 w，每行为 key,value，includeRank 为 true 时为 rank,key,value，
+// 键和值按 WithKeyFormatter 和 WithValueFormatter 格式化。
+// 不写入表头。导出期间持有读锁，每写入一批行就刷新一次，不会在内存中缓冲全部数据
+// WriteCSV streams the skip list to w as CSV in rank order, one key,value row per entry,
+// or rank,key,value when includeRank is true, keys and values formatted by WithKeyFormatter and WithValueFormatter.
+// No header row is written.
+// The read lock is held during the export and rows are flushed in batches, so the output is never fully buffered
+func (sl *RankList[K, V]) WriteCSV(w io.Writer, includeRank bool) error {
+	sl.RLock()
+	defer sl.RUnlock()
+	return writeCSV(w, includeRank, sl.codec, sl.walk)
+}
+
+// writeCSV 按 entries 的顺序将条目以 WriteCSV 的格式写入 w
+// writeCSV writes entries to w in the WriteCSV format, in the order entries yields them
+func writeCSV[K Ordered, V Ordered](w io.Writer, includeRank bool, codec textCodec[K, V], entries iter.Seq[Entry[K, V]]) error {
+	cw := csv.NewWriter(w)
+	row := make([]string, 0, 3)
+	rank := 0
+	for entry := range entries {
+		rank++
+		row = row[:0]
+		if includeRank {
+			row = append(row, strconv.Itoa(rank))
+		}
+		row = append(row, codec.keyText(entry.Key), codec.valueText(entry.Value))
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+		if rank%csvFlushRows == 0 {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV 从 r 读取 WriteCSV 格式的行（key,value 或 rank,key,value，rank 列会被忽略）并通过 SetBatch 批量写入，
+// 返回实际写入的行数，被 WithHardLimit、WithValueGuard 或外部存储拒绝的行不计入。parseKey 和 parseValue 为 nil 时使用 WithKeyParser 和 WithValueParser 设置的函数，都没有时按键和值的底层类型使用 strconv 解析。
+// 所有行都解析成功后才会写入，任意一行解析失败时返回带行号和列号的错误且不写入任何数据。
+// 重复的键以最后一行为准，与依次调用 Set 相同
+// ReadCSV reads rows in the WriteCSV format (key,value or rank,key,value with the rank column ignored) from r,
+// writes them through SetBatch and returns the number of rows applied,
+// leaving out rows rejected by WithHardLimit, WithValueGuard or the external store.
+// When parseKey or parseValue is nil, the functions set by WithKeyParser and WithValueParser are used,
+// and without those keys and values are parsed with strconv according to their underlying kind.
+// Nothing is written unless every row parses, a parse failure returns an error carrying the row and column.
+// Duplicate keys keep the value of their last row, as with sequential Set calls
+func (sl *RankList[K, V]) ReadCSV(r io.Reader, parseKey func(string) (K, error), parseValue func(string) (V, error)) (int, error) {
+	if parseKey == nil {
+		parseKey = sl.codec.parseKey
+	}
+	if parseKey == nil {
+		parseKey = parseOrdered[K]
+	}
+	if parseValue == nil {
+		parseValue = sl.codec.parseValue
+	}
+	if parseValue == nil {
+		parseValue = parseOrdered[V]
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	var entries []Entry[K, V]
+	for row := 1; ; row++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		offset := 0
+		switch len(record) {
+		case 2:
+		case 3:
+			offset = 1
+		default:
+			return 0, fmt.Errorf("ranklist: csv row %d: expected 2 or 3 columns, got %d", row, len(record))
+		}
+
+		key, err := parseKey(record[offset])
+		if err != nil {
+			return 0, fmt.Errorf("ranklist: csv row %d column %d: %w", row, offset+1, err)
+		}
+		value, err := parseValue(record[offset+1])
+		if err != nil {
+			return 0, fmt.Errorf("ranklist: csv row %d column %d: %w", row, offset+2, err)
+		}
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+	}
+
+	inserted, updated, err := sl.setBatchFunc(entries, nil)
+	return inserted + updated, err
+}
@@ -0,0 +1,178 @@
+package ranklist
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	sl := New[string, float64]()
+	sl.Set("a,b", 1.5)
+	sl.Set("c", -2)
+	sl.Set("quote\"d", 0.1)
+
+	var buf bytes.Buffer
+	if err := sl.WriteCSV(&buf, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{
+		{"1", "c", "-2"},
+		{"2", "quote\"d", "0.1"},
+		{"3", "a,b", "1.5"},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("expected %d rows, got %d", len(expected), len(rows))
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if rows[i][j] != expected[i][j] {
+				t.Errorf("row %d column %d: expected %q, got %q", i, j, expected[i][j], rows[i][j])
+			}
+		}
+	}
+}
+
+func TestWriteCSVMatchesRange(t *testing.T) {
+	sl := New[int, uint32]()
+	for i := 0; i < 5000; i++ {
+		sl.Set(i, rand.Uint32N(1000))
+	}
+
+	var buf bytes.Buffer
+	if err := sl.WriteCSV(&buf, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := sl.Range(1, sl.Length()+1)
+	if len(rows) != len(entries) {
+		t.Fatalf("expected %d rows, got %d", len(entries), len(rows))
+	}
+	for i, entry := range entries {
+		if rows[i][0] != strconv.Itoa(entry.Key) || rows[i][1] != strconv.FormatUint(uint64(entry.Value), 10) {
+			t.Fatalf("row %d: expected %v, got %v", i, entry, rows[i])
+		}
+	}
+}
+
+func TestWriteCSVEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New[string, int]().WriteCSV(&buf, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output for an empty list, got %q", buf.String())
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	src := New[string, float64]()
+	for i := 0; i < 1000; i++ {
+		src.Set("k"+strconv.Itoa(i), rand.NormFloat64())
+	}
+
+	for _, includeRank := range []bool{true, false} {
+		var buf bytes.Buffer
+		if err := src.WriteCSV(&buf, includeRank); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		dst := New[string, float64]()
+		n, err := dst.ReadCSV(&buf, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 1000 {
+			t.Errorf("expected 1000 rows applied, got %d", n)
+		}
+		requireSameEntries(t, src, dst)
+	}
+}
+
+func TestReadCSVMalformed(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("keep", 1)
+
+	input := "a,1\nb,2\nc,oops\nd,4\n"
+	n, err := sl.ReadCSV(bytes.NewBufferString(input), nil, nil)
+	if err == nil {
+		t.Fatalf("expected a parse error")
+	}
+	if n != 0 {
+		t.Errorf("expected no rows applied, got %d", n)
+	}
+	if !strings.Contains(err.Error(), "row 3 column 2") {
+		t.Errorf("expected row and column context, got %v", err)
+	}
+	if sl.Length() != 1 {
+		t.Errorf("list should be untouched after a failed import, got %d entries", sl.Length())
+	}
+
+	if _, err := sl.ReadCSV(bytes.NewBufferString("a,1,2,3\n"), nil, nil); err == nil {
+		t.Errorf("expected an error for a row with too many columns")
+	}
+}
+
+func TestReadCSVDuplicates(t *testing.T) {
+	sl := New[string, int]()
+
+	input := "a,1\nb,2\na,3\n"
+	n, err := sl.ReadCSV(bytes.NewBufferString(input), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 rows applied, got %d", n)
+	}
+	if value, _ := sl.Get("a"); value != 3 {
+		t.Errorf("duplicate key should keep the last value 3, got %d", value)
+	}
+	if sl.Length() != 2 {
+		t.Errorf("expected 2 entries, got %d", sl.Length())
+	}
+}
+
+func TestReadCSVHardLimit(t *testing.T) {
+	sl := New(WithHardLimit[string, int](2))
+	sl.Set("a", 1)
+
+	// 超出上限的新键不计入写入的行数 / New keys past the limit are not counted as applied
+	n, err := sl.ReadCSV(bytes.NewBufferString("a,5\nb,2\nc,3\n"), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 rows applied, got %d", n)
+	}
+	requireModel(t, sl, map[string]int{"a": 5, "b": 2})
+}
+
+func TestReadCSVCustomParsers(t *testing.T) {
+	sl := New[string, int]()
+
+	input := "ALICE,0x10\n"
+	_, err := sl.ReadCSV(bytes.NewBufferString(input), func(s string) (string, error) {
+		return strings.ToLower(s), nil
+	}, func(s string) (int, error) {
+		v, err := strconv.ParseInt(s, 0, 64)
+		return int(v), err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, _ := sl.Get("alice"); value != 16 {
+		t.Errorf("expected alice=16, got %d", value)
+	}
+}
@@ -0,0 +1,84 @@
+package ranklist
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// cursorVersion 游标格式的版本号，作为编码的第一个字节
+// cursorVersion is the version of the cursor format, stored as the first byte of the encoding
+const cursorVersion = 1
+
+// RangeCursor 按排名顺序分页读取跳表，返回 cursor 之后的最多 limit 个条目和下一页的游标。
+// 游标记录了上一页最后一个条目的 (值, 键)，下一页严格从这个位置之后继续，
+// 因此即使两页之间发生了插入或删除、排名发生了移动，也不会重复；只有在两页之间移动到游标另一侧的键才可能被跳过或重复。
+// cursor 为空时从排名 1 开始，没有更多条目时 next 为空字符串；游标无法解析时返回 ErrInvalidCursor
+// RangeCursor pages through the skip list in rank order, returning at most limit entries after cursor
+// and the cursor of the next page.
+// The cursor records the (value, key) of the last entry of the previous page and the next page resumes strictly after it,
+// so inserts, deletes and shifted ranks between pages cause no duplicates;
+// only keys moving across the cursor between pages may be skipped or seen twice.
+// An empty cursor starts at rank 1, next is empty when there are no more entries,
+// and ErrInvalidCursor is returned when the cursor cannot be decoded
+func (sl *RankList[K, V]) RangeCursor(cursor string, limit int) (entries []Entry[K, V], next string, err error) {
+	var after Entry[K, V]
+	if cursor != "" {
+		if after, err = decodeCursor[K, V](cursor); err != nil {
+			return nil, "", err
+		}
+	}
+	if limit <= 0 {
+		return []Entry[K, V]{}, cursor, nil
+	}
+
+	sl.vars.add(opRange)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	curr := sl.header.forward[0]
+	if cursor != "" {
+		curr = sl.seekAfter(after)
+	}
+	entries = make([]Entry[K, V], 0, min(limit, sl.length))
+	for ; curr != nil && len(entries) < limit; curr = curr.forward[0] {
+		entries = append(entries, curr.data)
+	}
+	if curr != nil {
+		next = encodeCursor(entries[len(entries)-1])
+	}
+	return entries, next, nil
+}
+
+// encodeCursor 将条目的值和键编码为 URL 安全的 base64 游标
+// encodeCursor encodes the value and key of an entry as a URL-safe base64 cursor
+func encodeCursor[K Ordered, V Ordered](entry Entry[K, V]) string {
+	buf := []byte{cursorVersion}
+	buf = appendOrdered(buf, entry.Value)
+	buf = appendOrdered(buf, entry.Key)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// decodeCursor 解码 encodeCursor 生成的游标
+// decodeCursor decodes a cursor produced by encodeCursor
+func decodeCursor[K Ordered, V Ordered](cursor string) (Entry[K, V], error) {
+	var entry Entry[K, V]
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return entry, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if len(data) == 0 || data[0] != cursorVersion {
+		return entry, fmt.Errorf("%w: unknown version", ErrInvalidCursor)
+	}
+	data = data[1:]
+
+	value, n, err := readOrdered[V](data)
+	if err != nil {
+		return entry, fmt.Errorf("%w: truncated value", ErrInvalidCursor)
+	}
+	data = data[n:]
+	key, n, err := readOrdered[K](data)
+	if err != nil || n != len(data) {
+		return entry, fmt.Errorf("%w: malformed key", ErrInvalidCursor)
+	}
+	return Entry[K, V]{Key: key, Value: value}, nil
+}
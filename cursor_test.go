@@ -0,0 +1,105 @@
+package ranklist
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestRangeCursor(t *testing.T) {
+	sl := New[string, int]()
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		sl.Set(key, i)
+	}
+
+	var keys []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		entries, next, err := sl.RangeCursor(cursor, 2)
+		if err != nil {
+			t.Fatalf("RangeCursor failed: %v", err)
+		}
+		for _, entry := range entries {
+			keys = append(keys, entry.Key)
+		}
+		if next == "" {
+			if pages != 2 {
+				t.Fatalf("expected 3 pages, got %d", pages+1)
+			}
+			break
+		}
+		cursor = next
+	}
+	if len(keys) != 5 || keys[0] != "a" || keys[4] != "e" {
+		t.Fatalf("unexpected pages %v", keys)
+	}
+
+	// 恰好用完时不返回下一页游标
+	// No next cursor is returned when a page ends exactly at the last entry
+	if _, next, _ := sl.RangeCursor("", 5); next != "" {
+		t.Fatalf("expected no next cursor, got %q", next)
+	}
+	if entries, next, _ := sl.RangeCursor(cursor, 0); len(entries) != 0 || next != cursor {
+		t.Fatalf("a zero limit should return nothing and keep the cursor")
+	}
+}
+
+func TestRangeCursorShifting(t *testing.T) {
+	sl := New[int, int]()
+	stable := make(map[int]bool)
+	for i := 0; i < 500; i++ {
+		sl.Set(i, i*2)
+		stable[i] = true
+	}
+
+	seen := make(map[int]int)
+	cursor := ""
+	for page := 0; ; page++ {
+		entries, next, err := sl.RangeCursor(cursor, 7)
+		if err != nil {
+			t.Fatalf("RangeCursor failed: %v", err)
+		}
+		for _, entry := range entries {
+			seen[entry.Key]++
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+
+		// 两页之间删除原有的键，并插入只在此期间存在的键，排名因此整体移动
+		// Original keys are deleted and transient keys inserted between pages, so ranks shift
+		victim := rand.IntN(500)
+		if sl.Del(victim) {
+			delete(stable, victim)
+		}
+		sl.Set(1000+page, rand.IntN(1000))
+	}
+
+	for key, count := range seen {
+		if count > 1 {
+			t.Fatalf("key %d returned %d times", key, count)
+		}
+	}
+	for key := range stable {
+		if seen[key] != 1 {
+			t.Fatalf("surviving key %d was skipped", key)
+		}
+	}
+}
+
+func TestRangeCursorInvalid(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	_, valid, _ := sl.RangeCursor("", 1)
+
+	for _, cursor := range []string{"!!!", "AA", valid[:len(valid)-1], valid + "AA"} {
+		if _, _, err := sl.RangeCursor(cursor, 1); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("cursor %q: expected ErrInvalidCursor, got %v", cursor, err)
+		}
+	}
+	if entries, _, err := sl.RangeCursor(valid, 1); err != nil || len(entries) != 1 || entries[0].Key != "b" {
+		t.Fatalf("expected b, got %v, %v", entries, err)
+	}
+}
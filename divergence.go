@@ -0,0 +1,118 @@
+package ranklist
+
+import "fmt"
+
+// WithDivergenceHook 开启字典与跳表不一致时的调试报告。
+// Rank、Del 和 Set 总会修复字典中指向不在跳表里的节点的条目，此时 Del 还会按键扫描第 0 层，
+// 删除值与字典不一致的节点，删除总以键为准；
+// 开启后，Rank 和 Del 在字典中找不到键时还会以 O(n) 扫描第 0 层，修复跳表中有节点但字典缺失的键，
+// 并在释放锁之后对 Rank 和 Del 的每次修复调用 fn，err 包装了 ErrCorrupted 并说明不一致的方向。
+// 开启 WithExpvar 时，每次修复还会累加 divergences 计数器
+// WithDivergenceHook enables debug reporting of divergences between the dictionary and the list.
+// Rank, Del and Set always repair a dictionary entry pointing at a node that is not in the list,
+// and Del then also scans level 0 for the key and removes a node whose value disagrees with the dictionary,
+// so deletion always goes by the key;
+// with the hook enabled, a dictionary miss in Rank and Del additionally scans level 0 in O(n) and repairs keys that have a node
+// in the list but no dictionary entry, and fn is called for every repair Rank and Del make after the lock is released,
+// with err wrapping ErrCorrupted and describing the direction of the divergence.
+// With WithExpvar enabled every repair also increments the divergences counter
+func WithDivergenceHook[K Ordered, V Ordered](fn func(key K, err error)) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.onDivergence = fn
+	}
+}
+
+// linked 判断节点是否仍链接在跳表中，按节点的值和键下降，耗时 O(log n)，调用方需持有锁
+// linked reports whether node is still linked into the list, descending by its value and key in O(log n).
+// The caller must hold the lock
+func (sl *RankList[K, V]) linked(node *Node[K, V]) bool {
+	found, ok := sl.seek(node.data.Key, node.data.Value)
+	return ok && found == node
+}
+
+// scan 沿第 0 层查找键对应的节点，不使用字典，调用方需持有锁
+// scan looks for the node holding key along level 0 without the dictionary. The caller must hold the lock
+func (sl *RankList[K, V]) scan(key K) (*Node[K, V], bool) {
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		if curr.data.Key == key {
+			return curr, true
+		}
+	}
+	return nil, false
+}
+
+// diverged 判断字典与跳表对键是否存在的看法不一致，调用方需持有锁。
+// 跳表中有节点而字典缺失的情况只有在开启 WithDivergenceHook 时才会检查
+// diverged reports whether the dictionary and the list disagree about key. The caller must hold the lock.
+// Keys present in the list but missing from the dictionary are only checked with WithDivergenceHook enabled
+func (sl *RankList[K, V]) diverged(key K) bool {
+	if sl.noDict {
+		return false
+	}
+	if node, exists := sl.dict[key]; exists {
+		return !sl.linked(node)
+	}
+	if sl.onDivergence == nil {
+		return false
+	}
+	_, inList := sl.scan(key)
+	return inList
+}
+
+// heal 修复键在字典与跳表之间的不一致，返回描述修复内容的错误，一致时返回 nil，调用方需持有写锁。
+// 字典中多余的条目被删除；跳表中有节点而字典缺失时，重新写入字典条目
+// heal repairs a divergence of key between the dictionary and the list and returns an error describing the repair,
+// nil when they agree. The caller must hold the write lock.
+// A dictionary entry without a node is removed, a node without a dictionary entry gets its entry back
+func (sl *RankList[K, V]) heal(key K) error {
+	if !sl.diverged(key) {
+		return nil
+	}
+	if _, exists := sl.dict[key]; exists {
+		sl.dictDelete(key)
+		return sl.dictOrphan(key)
+	}
+	node, _ := sl.scan(key)
+	sl.dictPut(key, node)
+	return sl.listOrphan(key)
+}
+
+// dictOrphan 记录一次字典中有条目而跳表中没有节点的不一致，返回描述它的错误
+// dictOrphan records a divergence where the dictionary has an entry but the list has no node, and returns an error describing it
+func (sl *RankList[K, V]) dictOrphan(key K) error {
+	sl.vars.add(opDivergence)
+	return fmt.Errorf("%w: key %v is in the dictionary but not in the list", ErrCorrupted, key)
+}
+
+// listOrphan 记录一次跳表中有节点而字典中没有条目的不一致，返回描述它的错误
+// listOrphan records a divergence where the list has a node but the dictionary has no entry, and returns an error describing it
+func (sl *RankList[K, V]) listOrphan(key K) error {
+	sl.vars.add(opDivergence)
+	return fmt.Errorf("%w: key %v is in the list but not in the dictionary", ErrCorrupted, key)
+}
+
+// misplaced 记录一次跳表中键的节点与字典给出的节点或值不一致的情况，返回描述它的错误
+// misplaced records a divergence where the list's node for key disagrees with the node or value the dictionary gives,
+// and returns an error describing it
+func (sl *RankList[K, V]) misplaced(key K) error {
+	sl.vars.add(opDivergence)
+	return fmt.Errorf("%w: key %v is in the list but not where the dictionary places it", ErrCorrupted, key)
+}
+
+// repair 获取写锁修复键的不一致，并在释放锁之后报告
+// repair takes the write lock to repair a divergence of key and reports it after the lock is released
+func (sl *RankList[K, V]) repair(key K) {
+	sl.Lock()
+	err := sl.heal(key)
+	sl.Unlock()
+
+	sl.reportDivergence(key, err)
+}
+
+// reportDivergence 在开启 WithDivergenceHook 时报告一次修复，err 为 nil 时不做任何事
+// reportDivergence reports a repair when WithDivergenceHook is enabled, it does nothing when err is nil
+func (sl *RankList[K, V]) reportDivergence(key K, err error) {
+	if err != nil && sl.onDivergence != nil {
+		sl.onDivergence(key, err)
+	}
+}
@@ -0,0 +1,178 @@
+package ranklist
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// divergenceRecorder 记录 WithDivergenceHook 报告的键和错误
+// divergenceRecorder records the keys and errors reported through WithDivergenceHook
+type divergenceRecorder struct {
+	keys []string
+	errs []error
+}
+
+func (r *divergenceRecorder) hook(key string, err error) {
+	r.keys = append(r.keys, key)
+	r.errs = append(r.errs, err)
+}
+
+func (r *divergenceRecorder) require(t *testing.T, key string) {
+	t.Helper()
+	if len(r.keys) != 1 || r.keys[0] != key || !errors.Is(r.errs[0], ErrCorrupted) {
+		t.Fatalf("expected one ErrCorrupted report for %s, got %v %v", key, r.keys, r.errs)
+	}
+}
+
+func newDivergenceFixture() (*RankList[string, int], *divergenceRecorder) {
+	r := &divergenceRecorder{}
+	sl := New(WithDivergenceHook[string, int](r.hook))
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	sl.Set("c", 3)
+	return sl, r
+}
+
+func TestDictOrphanRank(t *testing.T) {
+	sl, r := newDivergenceFixture()
+	sl.dict["x"] = NewNode("x", 0, 1)
+
+	if rank, ok := sl.Rank("x"); ok || rank != 0 {
+		t.Fatalf("expected 0, false, got %d, %v", rank, ok)
+	}
+	if _, exists := sl.dict["x"]; exists {
+		t.Fatalf("the orphaned dictionary entry should be removed")
+	}
+	r.require(t, "x")
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDictOrphanDel(t *testing.T) {
+	sl, r := newDivergenceFixture()
+	sl.dict["x"] = NewNode("x", 2, 1)
+
+	if sl.Del("x") {
+		t.Fatalf("Del should return false for a key that is not in the list")
+	}
+	if sl.Length() != 3 {
+		t.Fatalf("Del of an orphan must not touch the list, length is %d", sl.Length())
+	}
+	r.require(t, "x")
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDictOrphanSet(t *testing.T) {
+	sl, _ := newDivergenceFixture()
+	sl.dict["x"] = NewNode("x", 2, 1)
+
+	sl.Set("x", 5)
+	if rank, ok := sl.Rank("x"); !ok || rank != 4 {
+		t.Fatalf("expected rank 4, got %d, %v", rank, ok)
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListOrphanRank(t *testing.T) {
+	sl, r := newDivergenceFixture()
+	delete(sl.dict, "b")
+
+	if rank, ok := sl.Rank("b"); !ok || rank != 2 {
+		t.Fatalf("expected rank 2, got %d, %v", rank, ok)
+	}
+	if value, ok := sl.Get("b"); !ok || value != 2 {
+		t.Fatalf("the dictionary entry should be restored, got %d, %v", value, ok)
+	}
+	r.require(t, "b")
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListOrphanDel(t *testing.T) {
+	sl, r := newDivergenceFixture()
+	delete(sl.dict, "b")
+
+	if !sl.Del("b") {
+		t.Fatalf("Del should remove a node that is missing from the dictionary")
+	}
+	if sl.Length() != 2 {
+		t.Fatalf("expected length 2, got %d", sl.Length())
+	}
+	r.require(t, "b")
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListOrphanWithoutHook(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	delete(sl.dict, "a")
+
+	// 未开启调试回调时不会为找不到的键扫描跳表
+	// Without the debug hook a missing key never triggers a scan of the list
+	if _, ok := sl.Rank("a"); ok {
+		t.Fatalf("Rank should not find a key missing from the dictionary without the hook")
+	}
+	if sl.Del("a") {
+		t.Fatalf("Del should not find a key missing from the dictionary without the hook")
+	}
+}
+
+func TestStaleValueDel(t *testing.T) {
+	for name, corrupt := range map[string]func(sl *RankList[string, int]){
+		// 跳表中的节点自身的值与它的位置不一致，值小于所有条目，按值下降总是停在它之前
+		// The list's own node carries a value that disagrees with its position,
+		// below every entry so a descent by value always stops short of it
+		"node value": func(sl *RankList[string, int]) { sl.dict["b"].data.Value = -1 },
+
+		// 字典指向另一个带有旧值的节点，跳表中的节点仍在原位
+		// The dictionary points at another node with a stale value while the list's node stays in place
+		"dict node": func(sl *RankList[string, int]) { sl.dict["b"] = NewNode("b", 10, 1) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			sl, r := newDivergenceFixture()
+			for i := range 50 {
+				sl.Set(fmt.Sprintf("k%02d", i), i%7)
+			}
+			corrupt(sl)
+			if err := sl.Check(); err == nil {
+				t.Fatal("the corrupted list passes Check")
+			}
+
+			if !sl.Del("b") {
+				t.Fatal("Del should remove the key whatever value the dictionary gives")
+			}
+			r.require(t, "b")
+			if sl.Length() != 52 {
+				t.Fatalf("expected length 52, got %d", sl.Length())
+			}
+			if _, ok := sl.Rank("b"); ok {
+				t.Fatal("Rank still finds the deleted key")
+			}
+			for _, entry := range sl.Range(1, sl.Length()+1) {
+				if entry.Key == "b" {
+					t.Fatal("Range still returns the deleted key")
+				}
+			}
+			if err := sl.Check(); err != nil {
+				t.Fatal(err)
+			}
+
+			sl.Set("b", 4)
+			if rank, ok := sl.Rank("b"); !ok || sl.Range(rank, rank+1)[0] != (Entry[string, int]{"b", 4}) {
+				t.Fatalf("the key cannot be written back, rank %d, %v", rank, ok)
+			}
+			if err := sl.Check(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
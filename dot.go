@@ -0,0 +1,69 @@
+package ranklist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDot 在读锁内将跳表结构以 Graphviz DOT 格式写入 w，用于调试。
+// 每个元素对应一个图节点并按排名从左到右排列，每层的前向指针对应一条边，边上标注层级和跨度，头节点高亮显示
+// WriteDot writes the structure of the skip list to w in Graphviz DOT format under a read lock, intended for debugging.
+// Every element becomes a graph node laid out left to right in rank order,
+// every forward pointer of every level becomes an edge labeled with its level and span,
+// and the header is highlighted
+func (sl *RankList[K, V]) WriteDot(w io.Writer) error {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph ranklist {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	fmt.Fprintln(bw, "\tnode [shape=box];")
+	fmt.Fprintf(bw, "\theader [label=%s, style=filled, fillcolor=lightgrey];\n",
+		dotQuote(fmt.Sprintf("header\nlevel %d, length %d", sl.level, sl.length)))
+
+	// 节点以排名命名，从而每条边都能直接引用
+	// Nodes are named by rank so that every edge can refer to them directly
+	ids := make(map[*Node[K, V]]string, sl.length+1)
+	ids[sl.header] = "header"
+	rank := 0
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		rank++
+		ids[curr] = fmt.Sprintf("n%d", rank)
+		key, value := sl.codec.display(curr.data)
+		fmt.Fprintf(bw, "\t%s [label=%s];\n", ids[curr],
+			dotQuote(fmt.Sprintf("#%d\n%v: %v", rank, key, value)))
+	}
+
+	for i := 0; i < sl.level; i++ {
+		for curr := sl.header; curr.forward[i] != nil; curr = curr.forward[i] {
+			next := curr.forward[i]
+			fmt.Fprintf(bw, "\t%s -> %s [label=\"L%d span %d\"];\n", ids[curr], ids[next], i+1, next.span[i])
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// dotQuote 将 s 转义为 DOT 的双引号字符串
+// dotQuote escapes s as a double-quoted DOT string
+func dotQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
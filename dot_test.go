@@ -0,0 +1,81 @@
+package ranklist
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteDot(t *testing.T) {
+	sl := New(WithSeed[string, int](1))
+	for i := 0; i < 200; i++ {
+		sl.Set("key"+strconv.Itoa(i), i%13)
+	}
+	sl.Set(`quote"back\slash`, 5)
+	sl.Set("new\nline", 6)
+
+	var buf bytes.Buffer
+	if err := sl.WriteDot(&buf); err != nil {
+		t.Fatalf("WriteDot failed: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "digraph ranklist {\n") || !strings.HasSuffix(out, "}\n") {
+		t.Fatalf("output is not a digraph:\n%s", out)
+	}
+
+	// 去掉字符串内容后检查括号是否配对
+	// Check that braces are balanced outside of quoted strings
+	depth, inString, escaped := 0, false, false
+	for _, r := range out {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && r == '\\':
+			escaped = true
+		case r == '"':
+			inString = !inString
+		case !inString && r == '{':
+			depth++
+		case !inString && r == '}':
+			depth--
+			if depth < 0 {
+				t.Fatalf("unbalanced braces")
+			}
+		}
+	}
+	if depth != 0 || inString {
+		t.Fatalf("unbalanced braces or unterminated string")
+	}
+
+	// 每个节点在其每一层上恰好有一个指向它的前向指针
+	// Every node is the target of exactly one forward pointer per level it is present at
+	pointers := 0
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		pointers += curr.level
+	}
+	if edges := strings.Count(out, " -> "); edges != pointers {
+		t.Errorf("expected %d edges, got %d", pointers, edges)
+	}
+	if nodes := strings.Count(out, "[label=\"#"); nodes != sl.Length() {
+		t.Errorf("expected %d element nodes, got %d", sl.Length(), nodes)
+	}
+
+	if !strings.Contains(out, `quote\"back\\slash`) || !strings.Contains(out, `new\nline`) {
+		t.Errorf("string keys were not escaped")
+	}
+	if !strings.Contains(out, "fillcolor") {
+		t.Errorf("header should be highlighted")
+	}
+}
+
+func TestWriteDotEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New[int, int]().WriteDot(&buf); err != nil {
+		t.Fatalf("WriteDot failed: %v", err)
+	}
+	if strings.Contains(buf.String(), " -> ") {
+		t.Errorf("an empty list should have no edges")
+	}
+}
@@ -0,0 +1,89 @@
+package ranklist
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Dump 在读锁内将跳表的每一层写入 w，每个节点输出为 [键:值:跨度]，设置了 WithKeyFormatter 和 WithValueFormatter 时使用它们，用于调试
+// Dump writes every level of the skip list to w under a read lock,
+// each node is printed as [key:value:span] using WithKeyFormatter and WithValueFormatter when set, intended for debugging
+func (sl *RankList[K, V]) Dump(w io.Writer) error {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	if _, err := fmt.Fprintf(w, "SkipList Level: %d, Length: %d\n", sl.level, sl.length); err != nil {
+		return err
+	}
+	for i := sl.level - 1; i >= 0; i-- {
+		if _, err := fmt.Fprintf(w, "L%d -> ", i+1); err != nil {
+			return err
+		}
+		for curr := sl.header.forward[i]; curr != nil; curr = curr.forward[i] {
+			key, value := sl.codec.display(curr.data)
+			if _, err := fmt.Fprintf(w, "[%v:%v:%v] -> ", key, value, curr.span[i]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, "NIL"); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "===================================")
+	return err
+}
+
+// DumpCompact 在读锁内以稳定的规范格式将跳表的结构写入 w：第一行是层级和长度，之后按排名顺序每个节点一行，
+// 依次是键、值、节点层级和从第 1 层起每一层到前一个节点的跨度，字段以制表符分隔，跨度以逗号分隔。
+// 设置了 WithKeyFormatter 和 WithValueFormatter 时使用它们。
+// 配合 WithSeed，相同的种子和相同的操作序列总是得到相同的输出，适合用作结构的黄金文件
+// DumpCompact writes the structure of the skip list to w under a read lock in a stable canonical format:
+// a first line with the level and the length, then one line per node in rank order holding the key, the value,
+// the node level and the span from the previous node at every level from level 1 up, fields separated by tabs and spans by commas.
+// WithKeyFormatter and WithValueFormatter are used when set.
+// Together with WithSeed the same seed and sequence of operations always produce the same output,
+// which makes it suitable for golden files of the structure
+func (sl *RankList[K, V]) DumpCompact(w io.Writer) error {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	return sl.dumpCompact(w)
+}
+
+// dumpCompact 以 DumpCompact 的格式将跳表的结构写入 w，调用方需持有锁
+// dumpCompact writes the structure of the list to w in the DumpCompact format. The caller must hold the lock
+func (sl *RankList[K, V]) dumpCompact(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "level=%d\tlength=%d\n", sl.level, sl.length); err != nil {
+		return err
+	}
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		key, value := sl.codec.display(curr.data)
+		if _, err := fmt.Fprintf(w, "%v\t%v\t%d\t%s\n", key, value, curr.level, joinSpans(curr.span)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinSpans 以逗号连接跨度
+// joinSpans joins spans with commas
+func joinSpans(spans []int) string {
+	var b strings.Builder
+	for i, span := range spans {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(span))
+	}
+	return b.String()
+}
+
+// String 以 Dump 的格式返回跳表的结构
+// String returns the structure of the skip list in the Dump format
+func (sl *RankList[K, V]) String() string {
+	var b strings.Builder
+	sl.Dump(&b)
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package ranklist
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// updateGolden 为 true 时重新生成 testdata 中的黄金文件，而不是与之比较
+// updateGolden regenerates the golden files in testdata instead of comparing against them
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// fixedList 以固定的层级构建一个小跳表，使结构不受随机数影响
+// fixedList builds a small skip list with fixed levels so its structure does not depend on randomness
+func fixedList() *RankList[string, int] {
+	sl := New[string, int]()
+	sl.setLevel("a", 10, 1)
+	sl.setLevel("b", 20, 3)
+	sl.setLevel("c", 30, 1)
+	sl.setLevel("d", 40, 2)
+	sl.setLevel("e", 50, 1)
+	return sl
+}
+
+func TestDump(t *testing.T) {
+	const golden = "SkipList Level: 3, Length: 5\n" +
+		"L3 -> [b:20:2] -> NIL\n" +
+		"L2 -> [b:20:2] -> [d:40:2] -> NIL\n" +
+		"L1 -> [a:10:1] -> [b:20:1] -> [c:30:1] -> [d:40:1] -> [e:50:1] -> NIL\n" +
+		"===================================\n"
+
+	if got := fixedList().String(); got != golden {
+		t.Errorf("unexpected dump:\n%s\nexpected:\n%s", got, golden)
+	}
+}
+
+func TestDumpEmpty(t *testing.T) {
+	const golden = "SkipList Level: 1, Length: 0\n" +
+		"L1 -> NIL\n" +
+		"===================================\n"
+
+	if got := New[int, int]().String(); got != golden {
+		t.Errorf("unexpected dump:\n%s\nexpected:\n%s", got, golden)
+	}
+}
+
+func TestDumpWriteError(t *testing.T) {
+	if err := fixedList().Dump(failingWriter{}); err == nil {
+		t.Errorf("expected the write error to be returned")
+	}
+}
+
+func TestDumpCompact(t *testing.T) {
+	const golden = "level=3\tlength=5\n" +
+		"a\t10\t1\t1\n" +
+		"b\t20\t3\t1,2,2\n" +
+		"c\t30\t1\t1\n" +
+		"d\t40\t2\t1,2\n" +
+		"e\t50\t1\t1\n"
+
+	var b bytes.Buffer
+	if err := fixedList().DumpCompact(&b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != golden {
+		t.Errorf("unexpected dump:\n%s\nexpected:\n%s", b.String(), golden)
+	}
+	if err := fixedList().DumpCompact(failingWriter{}); err == nil {
+		t.Errorf("expected the write error to be returned")
+	}
+}
+
+// TestDumpCompactGolden 用固定种子执行若干操作脚本，并将结构与 testdata 中的黄金文件比较，
+// 任何改变 Set 或删除后跳表形状的修改都会让它失败。go test -run DumpCompactGolden -update 重新生成黄金文件
+// TestDumpCompactGolden runs a few operation scripts with fixed seeds and compares the structure
+// against the golden files in testdata, so any change to the shape left by Set or deletes fails it.
+// go test -run DumpCompactGolden -update regenerates the golden files
+func TestDumpCompactGolden(t *testing.T) {
+	scripts := map[string]func(sl *RankList[string, int], r *rand.Rand){
+		"sorted": func(sl *RankList[string, int], r *rand.Rand) {
+			for i := range 200 {
+				sl.Set(fmt.Sprintf("k%03d", i), i)
+			}
+		},
+		"random": func(sl *RankList[string, int], r *rand.Rand) {
+			for range 300 {
+				sl.Set(fmt.Sprintf("k%03d", r.IntN(150)), r.IntN(1000))
+			}
+		},
+		"ties": func(sl *RankList[string, int], r *rand.Rand) {
+			for range 300 {
+				sl.Set(fmt.Sprintf("k%03d", r.IntN(200)), r.IntN(4))
+			}
+		},
+		"deletes": func(sl *RankList[string, int], r *rand.Rand) {
+			for i := range 500 {
+				key := fmt.Sprintf("k%03d", r.IntN(120))
+				if i%3 == 2 {
+					sl.Del(key)
+				} else {
+					sl.Set(key, r.IntN(50))
+				}
+			}
+		},
+	}
+	for name, script := range scripts {
+		t.Run(name, func(t *testing.T) {
+			sl := New(WithSeed[string, int](1))
+			script(sl, rand.New(rand.NewPCG(2, 3)))
+			if err := sl.Check(); err != nil {
+				t.Fatal(err)
+			}
+			var got bytes.Buffer
+			if err := sl.DumpCompact(&got); err != nil {
+				t.Fatal(err)
+			}
+
+			path := filepath.Join("testdata", "dumpcompact", name+".golden")
+			if *updateGolden {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, got.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v, run go test -run DumpCompactGolden -update to create it", err)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Errorf("the structure differs from %s, run go test -run DumpCompactGolden -update "+
+					"if the change is intended:\n%s", path, got.String())
+			}
+		})
+	}
+}
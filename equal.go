@@ -0,0 +1,45 @@
+package ranklist
+
+// Equal 判断两个跳表是否包含完全相同的键值对。顺序由值决定，因此只比较内容。
+// 两个跳表的读锁按固定顺序同时持有，两者按排名顺序同步遍历一次，耗时 O(n)
+// Equal reports whether both skip lists hold exactly the same key-value pairs.
+// The order follows from the values, so only the content is compared.
+// The read locks of both lists are held together in a fixed order and both are walked once in rank order, in O(n)
+func (sl *RankList[K, V]) Equal(other *RankList[K, V]) bool {
+	if sl == other {
+		return true
+	}
+	defer rlockAll([]*RankList[K, V]{sl, other})()
+
+	if sl.length != other.length {
+		return false
+	}
+	a, b := sl.header.forward[0], other.header.forward[0]
+	for ; a != nil && b != nil; a, b = a.forward[0], b.forward[0] {
+		if a.data != b.data {
+			return false
+		}
+	}
+	return a == nil && b == nil
+}
+
+// EqualFunc 与 Equal 相同，但用 eq 比较同一个键的两个值，例如允许浮点误差。
+// 两个跳表的键集合必须完全相同；值在容差内相等的条目在两个跳表中的排名可能不同，因此按键逐个查找 other，
+// other 开启 WithNoDict 时每次查找需要扫描，耗时 O(n²)
+// EqualFunc behaves like Equal, but compares the two values of each key with eq, e.g. to allow float tolerance.
+// Both lists must hold exactly the same keys; entries equal within a tolerance may rank differently in each list,
+// so other is searched key by key, which scans and costs O(n²) when other uses WithNoDict
+func (sl *RankList[K, V]) EqualFunc(other *RankList[K, V], eq func(a, b V) bool) bool {
+	defer rlockAll([]*RankList[K, V]{sl, other})()
+
+	if sl.length != other.length {
+		return false
+	}
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		node, exists := other.lookup(curr.data.Key)
+		if !exists || !eq(curr.data.Value, node.data.Value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package ranklist
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	entries := make([]Entry[int, int], 500)
+	for i := range entries {
+		entries[i] = Entry[int, int]{Key: i, Value: rand.IntN(50)}
+	}
+	a := New[int, int]()
+	for _, entry := range entries {
+		a.Set(entry.Key, entry.Value)
+	}
+	b := New(WithNoDict[int, int]())
+	for _, i := range rand.Perm(len(entries)) {
+		b.Set(entries[i].Key, entries[i].Value)
+	}
+
+	if !a.Equal(b) || !b.Equal(a) || !a.Equal(a) {
+		t.Fatalf("lists built in different orders should be equal")
+	}
+
+	b.IncrBy(42, 1)
+	if a.Equal(b) {
+		t.Fatalf("a differing value should make the lists unequal")
+	}
+	b.IncrBy(42, -1)
+
+	b.Set(1000, 1)
+	if a.Equal(b) || b.Equal(a) {
+		t.Fatalf("an extra key should make the lists unequal")
+	}
+	b.Del(1000)
+	if !a.Equal(b) {
+		t.Fatalf("expected the lists to be equal again")
+	}
+}
+
+func TestEqualFunc(t *testing.T) {
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+	a := New[string, float64]()
+	a.Set("x", 0.3)
+	a.Set("y", 0.3)
+	b := New[string, float64]()
+	tenth, fifth := 0.1, 0.2
+	b.Set("x", tenth+fifth)
+	b.Set("y", 0.3)
+
+	if a.Equal(b) {
+		t.Fatalf("Equal should see the rounding difference")
+	}
+	if !a.EqualFunc(b, near) {
+		t.Fatalf("EqualFunc should tolerate the rounding difference")
+	}
+
+	b.Set("y", 0.4)
+	if a.EqualFunc(b, near) {
+		t.Fatalf("a value beyond the tolerance should be unequal")
+	}
+	b.Del("y")
+	b.Set("z", 0.3)
+	if a.EqualFunc(b, near) {
+		t.Fatalf("different keys should be unequal")
+	}
+}
@@ -0,0 +1,95 @@
+package ranklist
+
+import "errors"
+
+// rejection 写入被拒绝时的错误类型，通过 Unwrap 归入 ErrRejected
+// rejection is the error type of a rejected write, it falls under ErrRejected through Unwrap
+type rejection string
+
+func (e rejection) Error() string { return string(e) }
+
+func (e rejection) Unwrap() error { return ErrRejected }
+
+var (
+	// ErrInvalidCutoffs 表示分档比例不是 (0, 1] 区间内的升序小数
+	// ErrInvalidCutoffs is returned when cutoffs are not ascending fractions in (0, 1]
+	ErrInvalidCutoffs = errors.New("ranklist: cutoffs must be ascending fractions in (0, 1]")
+
+	// ErrInvalidPercentiles 表示百分位不是 (0, 1] 区间内的升序小数
+	// ErrInvalidPercentiles is returned when percentiles are not ascending fractions in (0, 1]
+	ErrInvalidPercentiles = errors.New("ranklist: percentiles must be ascending fractions in (0, 1]")
+
+	// ErrDeltaTooLarge 表示增量的绝对值超过了 WithMaxDelta 设置的上限，属于 ErrRejected
+	// ErrDeltaTooLarge is returned when the absolute value of a delta exceeds the WithMaxDelta limit, it is an ErrRejected
+	ErrDeltaTooLarge error = rejection("ranklist: delta exceeds the maximum allowed")
+
+	// ErrFull 表示插入新键会使成员数超出 WithHardLimit 的限制，属于 ErrRejected
+	// ErrFull is returned when inserting a new key would take the size past the WithHardLimit limit, it is an ErrRejected
+	ErrFull error = rejection("ranklist: list is full")
+
+	// ErrInvalidSnapshot 表示二进制快照被截断、校验和不匹配或格式不兼容
+	// ErrInvalidSnapshot is returned when a binary snapshot is truncated, fails its checksum or has an incompatible format
+	ErrInvalidSnapshot = errors.New("ranklist: invalid snapshot")
+
+	// ErrInvalidWAL 表示预写日志中间的记录已损坏
+	// ErrInvalidWAL is returned when a record in the middle of a write-ahead log is corrupted
+	ErrInvalidWAL = errors.New("ranklist: invalid write-ahead log")
+
+	// ErrNonNumericValue 表示操作要求数字类型的值，但值类型是字符串
+	// ErrNonNumericValue is returned when an operation requires numeric values but the value type is a string
+	ErrNonNumericValue = errors.New("ranklist: value type is not numeric")
+
+	// ErrInvalidWeights 表示权重的数量与来源的数量不一致
+	// ErrInvalidWeights is returned when the number of weights does not match the number of sources
+	ErrInvalidWeights = errors.New("ranklist: weights must match sources")
+
+	// ErrInvalidCursor 表示分页游标不是 RangeCursor 生成的，或者已损坏
+	// ErrInvalidCursor is returned when a pagination cursor was not produced by RangeCursor or is damaged
+	ErrInvalidCursor = errors.New("ranklist: invalid cursor")
+
+	// ErrValueRejected 表示写入的值被 WithValueGuard 注册的校验函数拒绝，属于 ErrRejected
+	// ErrValueRejected is returned when a value is rejected by the function registered with WithValueGuard,
+	// it is an ErrRejected
+	ErrValueRejected error = rejection("ranklist: value rejected")
+
+	// ErrInvalidMsgpack 表示 MessagePack 数据格式不正确、被截断或数值超出目标类型的范围
+	// ErrInvalidMsgpack is returned when MessagePack data is malformed, truncated or holds a number out of range
+	ErrInvalidMsgpack = errors.New("ranklist: invalid msgpack data")
+
+	// ErrNotFound 表示键不存在
+	// ErrNotFound is returned when the key does not exist
+	ErrNotFound = errors.New("ranklist: key not found")
+
+	// ErrRejected 表示写入被配置的限制拒绝，ErrValueRejected、ErrDeltaTooLarge 和 ErrFull 都属于它
+	// ErrRejected is returned when a write is refused by a configured limit,
+	// ErrValueRejected, ErrDeltaTooLarge and ErrFull all fall under it
+	ErrRejected = errors.New("ranklist: write rejected")
+
+	// ErrVersionMismatch 表示键当前的条目版本与期望的版本不一致
+	// ErrVersionMismatch is returned when the current entry version of a key differs from the expected one
+	ErrVersionMismatch = errors.New("ranklist: version mismatch")
+
+	// ErrFrozen 表示试图修改不可变的 FrozenRankList
+	// ErrFrozen is returned when trying to modify an immutable FrozenRankList
+	ErrFrozen = errors.New("ranklist: list is frozen")
+
+	// ErrClosed 表示跳表已经被 Close 关闭，不再接受修改
+	// ErrClosed is returned by mutations on a list that has been shut down by Close
+	ErrClosed = errors.New("ranklist: list is closed")
+
+	// ErrStore 表示 WithStore 配置的外部存储写入失败，会包装外部存储返回的错误
+	// ErrStore is returned when the external store configured by WithStore fails, wrapping the error it returned
+	ErrStore = errors.New("ranklist: external store failed")
+
+	// ErrNotEmpty 表示操作要求空跳表，而跳表中已有条目
+	// ErrNotEmpty is returned when an operation requires an empty list but the list already holds entries
+	ErrNotEmpty = errors.New("ranklist: list is not empty")
+
+	// ErrCorrupted 表示 Check 发现跳表的内部结构不一致
+	// ErrCorrupted is returned when Check finds the internal structure of the skip list inconsistent
+	ErrCorrupted = errors.New("ranklist: corrupted structure")
+
+	// ErrBusy 表示 TryGet 或 TrySet 在超时之前没能获取锁
+	// ErrBusy is returned when TryGet or TrySet could not acquire the lock before the timeout
+	ErrBusy = errors.New("ranklist: lock busy")
+)
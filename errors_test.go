@@ -0,0 +1,95 @@
+package ranklist
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	sl := New(
+		WithValueGuard[string, int](func(old, new int) error {
+			if new < 0 {
+				return errors.New("negative")
+			}
+			return nil
+		}),
+		WithMaxDelta[string, int](10),
+	)
+	sl.Set("a", 1)
+	_, version, _ := sl.GetVersioned("a")
+
+	_, getErr := sl.GetChecked("missing")
+	_, rankErr := sl.RankChecked("missing")
+	_, setErr := sl.SetChecked("a", -1)
+	_, incrErr := sl.IncrByChecked("a", 11)
+
+	var frozen FrozenRankList[string, int]
+	cases := []struct {
+		name     string
+		err      error
+		sentinel error
+	}{
+		{"GetChecked", getErr, ErrNotFound},
+		{"RankChecked", rankErr, ErrNotFound},
+		{"DelChecked", sl.DelChecked("missing"), ErrNotFound},
+		{"SetChecked", setErr, ErrRejected},
+		{"SetChecked", setErr, ErrValueRejected},
+		{"IncrByChecked", incrErr, ErrRejected},
+		{"IncrByChecked", incrErr, ErrDeltaTooLarge},
+		{"SetIfVersionChecked", sl.SetIfVersionChecked("a", 2, version+1), ErrVersionMismatch},
+		{"SetIfVersionChecked", sl.SetIfVersionChecked("a", -2, version), ErrRejected},
+		{"SetIfVersionChecked", sl.SetIfVersionChecked("missing", 2, 1), ErrVersionMismatch},
+		{"Frozen.UnmarshalJSON", json.Unmarshal([]byte(`[]`), &frozen), ErrFrozen},
+		{"Frozen.UnmarshalBinary", frozen.UnmarshalBinary(nil), ErrFrozen},
+	}
+	for _, c := range cases {
+		if !errors.Is(c.err, c.sentinel) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.sentinel, c.err)
+		}
+	}
+
+	// 成功的调用返回 nil
+	// Successful calls return nil
+	if value, err := sl.GetChecked("a"); err != nil || value != 1 {
+		t.Fatalf("GetChecked: expected 1, got %d, %v", value, err)
+	}
+	if rank, err := sl.RankChecked("a"); err != nil || rank != 1 {
+		t.Fatalf("RankChecked: expected 1, got %d, %v", rank, err)
+	}
+	if err := sl.SetIfVersionChecked("a", 5, version); err != nil {
+		t.Fatalf("SetIfVersionChecked: unexpected error %v", err)
+	}
+	if err := sl.DelChecked("a"); err != nil {
+		t.Fatalf("DelChecked: unexpected error %v", err)
+	}
+
+	// 跳表关闭之后修改返回 ErrClosed，读取仍然按键报告 ErrNotFound
+	// After Close mutations return ErrClosed while reads still report ErrNotFound per key
+	sl.Set("b", 2)
+	sl.Close()
+	if err := sl.DelChecked("b"); !errors.Is(err, ErrClosed) {
+		t.Errorf("DelChecked: expected ErrClosed, got %v", err)
+	}
+	if err := sl.SetIfVersionChecked("b", 3, 1); !errors.Is(err, ErrClosed) {
+		t.Errorf("SetIfVersionChecked: expected ErrClosed, got %v", err)
+	}
+	if _, err := sl.GetChecked("b"); err != nil {
+		t.Errorf("GetChecked should keep working after Close, got %v", err)
+	}
+
+	busy := New[string, int]()
+	busy.Lock()
+	_, err := busy.TrySet("a", 1, time.Millisecond)
+	busy.Unlock()
+	if !errors.Is(err, ErrBusy) {
+		t.Errorf("TrySet: expected ErrBusy, got %v", err)
+	}
+
+	for _, err := range []error{ErrNotFound, ErrVersionMismatch, ErrFrozen, ErrClosed, ErrBusy} {
+		if errors.Is(err, ErrRejected) {
+			t.Errorf("%v should not be an ErrRejected", err)
+		}
+	}
+}
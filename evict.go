@@ -0,0 +1,212 @@
+package ranklist
+
+import (
+	"container/heap"
+	"time"
+)
+
+// EvictPolicy 决定跳表超出 WithMaxSize 的容量时淘汰哪个成员
+// EvictPolicy decides which member is evicted when the skip list exceeds its WithMaxSize capacity
+type EvictPolicy int
+
+const (
+	// EvictLowest 淘汰值最小的成员，即排名 1 的成员，为默认策略
+	// EvictLowest evicts the member with the smallest value, the one at rank 1. It is the default
+	EvictLowest EvictPolicy = iota
+
+	// EvictStalest 淘汰值最久没有改变的成员，需要同时开启 WithTimestamps
+	// EvictStalest evicts the member whose value has gone unchanged the longest, it requires WithTimestamps
+	EvictStalest
+)
+
+// WithMaxSize 将跳表的容量限制为 n 个成员，插入新键使成员数超过 n 时按淘汰策略淘汰成员，
+// 新插入的键本身也可能被淘汰。淘汰与插入在同一把写锁内完成，并以删除的形式写入预写日志。
+// 整体替换内容的操作（Restore、Load 等）在替换后同样淘汰到容量以内
+// WithMaxSize caps the skip list at n members. When inserting a new key takes the size past n,
+// members are evicted by the eviction policy, and the newly inserted key may itself be the one evicted.
+// Evictions happen under the same write lock as the insert and are journaled as deletes.
+// Whole-content replacements such as Restore and Load also evict down to the capacity afterwards
+func WithMaxSize[K Ordered, V Ordered](n int) Option[K, V] {
+	if n < 1 {
+		panic("ranklist: max size must be positive")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.maxSize = n
+	}
+}
+
+// WithEvictPolicy 设置超出容量时的淘汰策略，默认为 EvictLowest。
+// EvictStalest 通过按更新时间排序的最小堆找到淘汰对象，每次淘汰耗时 O(log n)；未开启 WithTimestamps 时 New 会 panic
+// WithEvictPolicy sets the eviction policy applied past the capacity, EvictLowest by default.
+// EvictStalest finds its victim with a min-heap ordered by update time, so every eviction costs O(log n);
+// New panics when it is used without WithTimestamps
+func WithEvictPolicy[K Ordered, V Ordered](policy EvictPolicy) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.evictPolicy = policy
+	}
+}
+
+// WithOnEvict 注册淘汰回调，每淘汰一个成员调用一次 fn，传入被淘汰的条目和触发淘汰的策略。
+// 回调在释放锁之后执行
+// WithOnEvict registers an eviction callback, fn is called once per evicted member
+// with the evicted entry and the policy that chose it. Callbacks run after the lock is released
+func WithOnEvict[K Ordered, V Ordered](fn func(entry Entry[K, V], policy EvictPolicy)) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.onEvict = fn
+	}
+}
+
+// initEviction 在所有 Option 应用之后检查淘汰配置并创建 EvictStalest 需要的堆
+// initEviction validates the eviction settings once every Option is applied
+// and creates the heap EvictStalest needs
+func (sl *RankList[K, V]) initEviction() {
+	if sl.evictPolicy != EvictStalest {
+		return
+	}
+	if sl.clock == nil {
+		panic("ranklist: EvictStalest requires WithTimestamps")
+	}
+	sl.stale = &staleHeap[K]{index: make(map[K]*staleItem[K])}
+}
+
+// evictOverflow 淘汰成员直到成员数不超过容量，被淘汰的条目在下一次计算回调事件时报告，调用方需持有写锁
+// evictOverflow evicts members until the size is within the capacity,
+// the evicted entries are reported the next time callback events are computed. The caller must hold the write lock
+func (sl *RankList[K, V]) evictOverflow() {
+	for sl.maxSize > 0 && sl.length > sl.maxSize {
+		victim := sl.header.forward[0]
+		if sl.stale != nil && sl.stale.Len() > 0 {
+			victim, _ = sl.lookup(sl.stale.items[0].key)
+		}
+		entry := victim.data
+		if ok, _ := sl.del(entry.Key); !ok {
+			return
+		}
+		delete(sl.meta, entry.Key)
+		sl.journalAs(walOpDel, keyspaceEvicted, entry.Key, ZeroValue[V]())
+		sl.mirror(walOpDel, entry.Key, ZeroValue[V]())
+		sl.evicted = append(sl.evicted, entry)
+		sl.vars.add(opEvict)
+	}
+}
+
+// evictEvents 取出尚未报告的淘汰，转换为在释放锁之后执行的回调事件，调用方需持有写锁
+// evictEvents takes the evictions not yet reported and turns them into events fired after the lock is released.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) evictEvents() []thresholdEvent[K] {
+	if len(sl.evicted) == 0 {
+		return nil
+	}
+
+	var events []thresholdEvent[K]
+	if sl.onEvict != nil {
+		fn, policy := sl.onEvict, sl.evictPolicy
+		for _, entry := range sl.evicted {
+			events = append(events, thresholdEvent[K]{
+				fn:  func(K, bool) { fn(entry, policy) },
+				key: entry.Key,
+			})
+		}
+	}
+	sl.evicted = sl.evicted[:0]
+	return events
+}
+
+// trackStale 记录节点最新的更新时间，未使用 EvictStalest 时不做任何事，调用方需持有写锁
+// trackStale records the latest update time of node, a no-op without EvictStalest. The caller must hold the write lock
+func (sl *RankList[K, V]) trackStale(node *Node[K, V]) {
+	if sl.stale != nil {
+		sl.stale.touch(node.data.Key, node.times.updated)
+	}
+}
+
+// untrackStale 不再跟踪已删除的键，调用方需持有写锁
+// untrackStale stops tracking a deleted key, the caller must hold the write lock
+func (sl *RankList[K, V]) untrackStale(key K) {
+	if sl.stale != nil {
+		sl.stale.remove(key)
+	}
+}
+
+// rebuildStale 在整体重建或时间戳整体恢复之后按第 0 层重新建堆，耗时 O(n)，调用方需持有写锁
+// rebuildStale rebuilds the heap from level 0 after a whole rebuild or a bulk restore of timestamps in O(n).
+// The caller must hold the write lock
+func (sl *RankList[K, V]) rebuildStale() {
+	if sl.stale == nil {
+		return
+	}
+	sl.stale.items = sl.stale.items[:0]
+	clear(sl.stale.index)
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		item := &staleItem[K]{key: curr.data.Key, updated: curr.times.updated, index: len(sl.stale.items)}
+		sl.stale.items = append(sl.stale.items, item)
+		sl.stale.index[item.key] = item
+	}
+	heap.Init(sl.stale)
+}
+
+// staleItem 堆中的一个键及其更新时间
+// staleItem is one key in the heap together with its update time
+type staleItem[K Ordered] struct {
+	key     K
+	updated time.Time
+	index   int
+}
+
+// staleHeap 按更新时间排序的最小堆，更新时间相同时按键排序，index 用于按键定位堆中的元素
+// staleHeap is a min-heap ordered by update time and then by key, index locates the item of a key
+type staleHeap[K Ordered] struct {
+	items []*staleItem[K]
+	index map[K]*staleItem[K]
+}
+
+func (h *staleHeap[K]) Len() int { return len(h.items) }
+
+func (h *staleHeap[K]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if !a.updated.Equal(b.updated) {
+		return a.updated.Before(b.updated)
+	}
+	return a.key < b.key
+}
+
+func (h *staleHeap[K]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *staleHeap[K]) Push(x any) {
+	item := x.(*staleItem[K])
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *staleHeap[K]) Pop() any {
+	item := h.items[len(h.items)-1]
+	h.items[len(h.items)-1] = nil
+	h.items = h.items[:len(h.items)-1]
+	return item
+}
+
+// touch 添加键或更新已有键的更新时间
+// touch adds key or updates the update time of a tracked key
+func (h *staleHeap[K]) touch(key K, updated time.Time) {
+	if item, exists := h.index[key]; exists {
+		item.updated = updated
+		heap.Fix(h, item.index)
+		return
+	}
+	item := &staleItem[K]{key: key, updated: updated}
+	h.index[key] = item
+	heap.Push(h, item)
+}
+
+// remove 从堆中移除键，键不存在时不做任何事
+// remove drops key from the heap, a no-op for keys that are not tracked
+func (h *staleHeap[K]) remove(key K) {
+	if item, exists := h.index[key]; exists {
+		heap.Remove(h, item.index)
+		delete(h.index, key)
+	}
+}
@@ -0,0 +1,123 @@
+package ranklist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvictLowest(t *testing.T) {
+	var evicted []Entry[string, int]
+	sl := New(
+		WithMaxSize[string, int](3),
+		WithOnEvict(func(entry Entry[string, int], policy EvictPolicy) {
+			if policy != EvictLowest {
+				t.Errorf("expected EvictLowest, got %v", policy)
+			}
+			evicted = append(evicted, entry)
+		}),
+	)
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	sl.Set("b", 5) // 更新不会淘汰 / updates never evict
+	if len(evicted) != 0 {
+		t.Fatalf("unexpected evictions %v", evicted)
+	}
+
+	sl.Set("d", 40)
+	if len(evicted) != 1 || evicted[0].Key != "b" {
+		t.Fatalf("expected b to be evicted, got %v", evicted)
+	}
+	// 新键本身最小时会被立即淘汰
+	// A new key that is itself the lowest is evicted right away
+	sl.Set("e", 1)
+	if len(evicted) != 2 || evicted[1].Key != "e" {
+		t.Fatalf("expected e to be evicted, got %v", evicted)
+	}
+	requireModel(t, sl, map[string]int{"a": 10, "c": 30, "d": 40})
+
+	sl.Restore([]Entry[string, int]{{"v", 1}, {"w", 2}, {"x", 3}, {"y", 4}, {"z", 5}})
+	requireModel(t, sl, map[string]int{"x": 3, "y": 4, "z": 5})
+	requireSpans(t, sl)
+}
+
+func TestEvictStalest(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	var evicted []string
+	sl := New(
+		WithTimestamps[string, int](clock.read),
+		WithMaxSize[string, int](4),
+		WithEvictPolicy[string, int](EvictStalest),
+		WithOnEvict(func(entry Entry[string, int], policy EvictPolicy) {
+			if policy != EvictStalest {
+				t.Errorf("expected EvictStalest, got %v", policy)
+			}
+			evicted = append(evicted, entry.Key)
+		}),
+	)
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	sl.Set("d", 40)
+
+	// 除 c 之外的成员都被更新过，包括原地更新和重新插入
+	// Every member but c gets touched, both in place and by reinsertion
+	sl.Set("a", 11)
+	sl.Set("b", 50)
+	sl.IncrBy("d", 1)
+
+	sl.Set("e", 1)
+	if len(evicted) != 1 || evicted[0] != "c" {
+		t.Fatalf("expected the untouched c to be evicted, got %v", evicted)
+	}
+
+	// 删除的键不再参与淘汰，a 成为最久未更新的成员
+	// Deleted keys leave the heap, which makes a the stalest member
+	sl.Del("b")
+	sl.Set("f", 2)
+	sl.Set("g", 3)
+	if len(evicted) != 2 || evicted[1] != "a" {
+		t.Fatalf("expected a to be evicted, got %v", evicted)
+	}
+
+	// Compact 保留时间戳，因此淘汰顺序不变
+	// Compact keeps the timestamps, so the eviction order is unchanged
+	sl.Compact()
+	sl.Set("h", 4)
+	if len(evicted) != 3 || evicted[2] != "d" {
+		t.Fatalf("expected d to be evicted, got %v", evicted)
+	}
+	if sl.Length() != 4 || sl.stale.Len() != 4 {
+		t.Fatalf("expected 4 members and 4 tracked keys, got %d and %d", sl.Length(), sl.stale.Len())
+	}
+	requireSpans(t, sl)
+}
+
+func TestEvictThresholds(t *testing.T) {
+	zone := make(map[string]bool)
+	sl := New(
+		WithMaxSize[string, int](3),
+		WithThreshold[string, int](2, func(key string, entered bool) {
+			zone[key] = entered
+		}),
+	)
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+
+	// 插入 d 淘汰了 a，c 随之移入前两名，这一变化由整个区域的对比发现
+	// Inserting d evicts a and moves c into the first two ranks, which is found by comparing the zone as a whole
+	sl.Set("d", 40)
+	if zone["a"] || !zone["b"] || !zone["c"] || zone["d"] {
+		t.Fatalf("unexpected zone %v", zone)
+	}
+}
+
+func TestEvictStalestRequiresTimestamps(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic")
+		}
+	}()
+	New(WithEvictPolicy[string, int](EvictStalest))
+}
@@ -0,0 +1,81 @@
+package ranklist
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// 通过 expvar 发布、由 StatsSnapshot 读取的操作计数器
+// Operation counters published through expvar and read by StatsSnapshot
+const (
+	opSet = iota
+	opUpdate
+	opDel
+	opGet
+	opRank
+	opRange
+	opDivergence
+	opRejected
+	opEvict
+	opCount
+)
+
+var opNames = [opCount]string{
+	opSet:        "sets",
+	opUpdate:     "updates",
+	opDel:        "deletes",
+	opGet:        "gets",
+	opRank:       "ranks",
+	opRange:      "ranges",
+	opDivergence: "divergences",
+	opRejected:   "rejected",
+	opEvict:      "evictions",
+}
+
+// opVars 保存通过 expvar 发布的计数器，未开启时为 nil。
+// ResetStats 只移动 base 而不修改计数器，因此 expvar 发布的值始终单调递增
+// opVars holds the counters published through expvar, nil when disabled.
+// ResetStats only moves base and never touches the counters, so the values published through expvar stay monotonic
+type opVars struct {
+	counters [opCount]expvar.Int
+	base     [opCount]atomic.Int64
+}
+
+// WithExpvar 以 prefix 为名注册一个 expvar.Map，发布以下计数器：
+// sets、updates（覆盖已有键的 Set）、deletes、gets、ranks、ranges、divergences（修复的字典不一致）、
+// rejected（被 WithValueGuard、WithMaxDelta 或 WithHardLimit 拒绝的写入）、evictions（WithMaxSize 淘汰的成员），
+// 以及在读锁下读取的 length 和 level。
+// 计数器原子地累加，读取时不获取跳表的锁。WithOpStats 和 WithExpvar 共用同一组计数器。expvar 的名字是全局的，同一个 prefix 重复注册会 panic
+// WithExpvar registers an expvar.Map named prefix publishing the counters
+// sets, updates (Sets that replaced an existing key), deletes, gets, ranks, ranges, divergences (repaired dictionary inconsistencies)
+// rejected (writes refused by WithValueGuard, WithMaxDelta or WithHardLimit) and evictions (members evicted by WithMaxSize),
+// along with length and level read under the read lock.
+// Counters are added atomically and read without taking the list's lock. WithOpStats and WithExpvar share the same counters.
+// expvar names are global, registering the same prefix twice panics
+func WithExpvar[K Ordered, V Ordered](prefix string) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		if sl.vars == nil {
+			sl.vars = &opVars{}
+		}
+		m := expvar.NewMap(prefix)
+		for op, name := range opNames {
+			m.Set(name, &sl.vars.counters[op])
+		}
+		m.Set("length", expvar.Func(func() any {
+			return sl.Length()
+		}))
+		m.Set("level", expvar.Func(func() any {
+			sl.RLock()
+			defer sl.RUnlock()
+			return sl.level
+		}))
+	}
+}
+
+// add 将操作 op 的计数器加一，未开启时不做任何事
+// add increments the counter of op, it does nothing when disabled
+func (v *opVars) add(op int) {
+	if v != nil {
+		v.counters[op].Add(1)
+	}
+}
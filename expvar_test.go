@@ -0,0 +1,62 @@
+package ranklist
+
+import (
+	"expvar"
+	"testing"
+)
+
+func TestWithExpvar(t *testing.T) {
+	sl := New(WithExpvar[string, int]("ranklist_test_expvar"))
+
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	sl.Set("a", 3)
+	sl.IncrBy("c", 4)
+	sl.IncrBy("c", 1)
+	sl.Get("a")
+	sl.Get("missing")
+	sl.Rank("b")
+	sl.Range(1, 3)
+	sl.Del("b")
+	sl.Del("missing")
+
+	m, ok := expvar.Get("ranklist_test_expvar").(*expvar.Map)
+	if !ok {
+		t.Fatalf("expected an expvar.Map to be published")
+	}
+
+	expected := map[string]string{
+		"sets":    "5",
+		"updates": "2",
+		"deletes": "2",
+		"gets":    "2",
+		"ranks":   "1",
+		"ranges":  "1",
+		"length":  "2",
+		"level":   "1",
+	}
+	sl.RLock()
+	expected["level"] = expvar.Func(func() any { return sl.level }).String()
+	sl.RUnlock()
+
+	for name, want := range expected {
+		v := m.Get(name)
+		if v == nil {
+			t.Errorf("expected %s to be published", name)
+			continue
+		}
+		if got := v.String(); got != want {
+			t.Errorf("expected %s=%s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestWithoutExpvar(t *testing.T) {
+	sl := New[int, int]()
+	sl.Set(1, 1)
+	sl.Get(1)
+	sl.Del(1)
+	if sl.vars != nil {
+		t.Errorf("expvar counters should be disabled by default")
+	}
+}
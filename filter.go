@@ -0,0 +1,53 @@
+package ranklist
+
+// RangeFilter 在排名区间 [start, end) 内按排名顺序扫描，返回 pred 为 true 的条目，最多返回 limit 个，limit 为 0 时不限制数量。
+// 区间的规则与 Range 相同。收集到 limit 个条目后立即停止扫描，因此匹配较多时只会访问区间的开头部分；
+// 最坏情况下（匹配很少或没有匹配）会扫描整个区间，耗时 O(log n + end - start)。
+// pred 在读锁内调用，不能再调用跳表的写方法
+// RangeFilter scans the rank range [start, end) in rank order and returns the entries for which pred is true,
+// at most limit of them, a limit of 0 means no limit. The window follows the rules of Range.
+// The scan stops as soon as limit entries are collected, so with plenty of matches only the head of the window is visited;
+// in the worst case, with few or no matches, the whole window is scanned in O(log n + end - start).
+// pred runs under the read lock and must not call the write methods of the list
+func (sl *RankList[K, V]) RangeFilter(start int, end int, pred func(entry Entry[K, V]) bool, limit int) []Entry[K, V] {
+	sl.vars.add(opRange)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	entries := make([]Entry[K, V], 0)
+	n := sl.rangeSize(start, end)
+	if n == 0 {
+		return entries
+	}
+	for curr := sl.byRank(max(start, 1)); curr != nil && n > 0; curr, n = curr.forward[0], n-1 {
+		if !pred(curr.data) {
+			continue
+		}
+		entries = append(entries, curr.data)
+		if len(entries) == limit {
+			break
+		}
+	}
+	return entries
+}
+
+// RangeWhile 从排名 start 开始按排名顺序收集条目，直到 cond 第一次返回 false 或到达末尾为止，
+// 返回 cond 为 true 的连续条目，不包含使 cond 返回 false 的那一个。start 小于 1 时按 1 处理，超过长度时返回空切片。
+// 适合“从第 1 名开始直到值低于某个阈值”这类查询，不需要事先计算边界的位置，耗时 O(log n + k)。
+// cond 在读锁内调用，不能再调用跳表的写方法
+// RangeWhile collects entries in rank order starting at rank start while cond returns true,
+// stopping at the first false or at the end of the list, the entry that made cond return false is not included.
+// A start below 1 is treated as 1 and a start past the length returns an empty slice.
+// It suits queries like "from rank 1 until the value drops below a threshold" without computing the boundary first,
+// and runs in O(log n + k). cond runs under the read lock and must not call the write methods of the list
+func (sl *RankList[K, V]) RangeWhile(start int, cond func(entry Entry[K, V]) bool) []Entry[K, V] {
+	sl.vars.add(opRange)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	entries := make([]Entry[K, V], 0)
+	for curr := sl.byRank(max(start, 1)); curr != nil && cond(curr.data); curr = curr.forward[0] {
+		entries = append(entries, curr.data)
+	}
+	return entries
+}
@@ -0,0 +1,97 @@
+package ranklist
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRangeFilter(t *testing.T) {
+	sl := New[string, int]()
+	for i, region := range []string{"eu", "us", "us", "eu", "us", "us", "us", "us", "us", "eu"} {
+		sl.Set(region+":"+string(rune('a'+i)), i)
+	}
+	eu := func(entry Entry[string, int]) bool { return strings.HasPrefix(entry.Key, "eu:") }
+
+	if entries := sl.RangeFilter(1, 11, eu, 0); !slices.Equal(entries, []Entry[string, int]{{"eu:a", 0}, {"eu:d", 3}, {"eu:j", 9}}) {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+	if entries := sl.RangeFilter(1, 11, eu, 2); !slices.Equal(entries, []Entry[string, int]{{"eu:a", 0}, {"eu:d", 3}}) {
+		t.Fatalf("expected the limit to cap the output, got %v", entries)
+	}
+
+	// 稀疏的匹配位于区间末尾，区间之外的匹配不会被返回
+	// A sparse match sits at the end of the window, matches outside of it are not returned
+	if entries := sl.RangeFilter(5, 11, eu, 5); !slices.Equal(entries, []Entry[string, int]{{"eu:j", 9}}) {
+		t.Fatalf("expected the match at the end of the window, got %v", entries)
+	}
+	if entries := sl.RangeFilter(2, 10, eu, 0); !slices.Equal(entries, []Entry[string, int]{{"eu:d", 3}}) {
+		t.Fatalf("expected the window to bound the scan, got %v", entries)
+	}
+
+	if entries := sl.RangeFilter(1, 11, func(Entry[string, int]) bool { return false }, 3); len(entries) != 0 {
+		t.Fatalf("expected no entries, got %v", entries)
+	}
+	all := func(Entry[string, int]) bool { return true }
+	if entries := sl.RangeFilter(-3, 100, all, 0); !slices.Equal(entries, sl.Range(1, 11)) {
+		t.Fatalf("expected every entry, got %v", entries)
+	}
+	if entries := sl.RangeFilter(3, 100, all, 4); !slices.Equal(entries, sl.Range(3, 7)) {
+		t.Fatalf("expected the first four entries from rank 3, got %v", entries)
+	}
+	if entries := sl.RangeFilter(8, 3, all, 0); len(entries) != 0 {
+		t.Fatalf("expected an empty window to return nothing, got %v", entries)
+	}
+}
+
+func TestRangeFilterStopsAtLimit(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 100; i++ {
+		sl.Set(i, i)
+	}
+
+	visited := 0
+	sl.RangeFilter(1, 101, func(entry Entry[int, int]) bool {
+		visited++
+		return entry.Key%2 == 0
+	}, 3)
+	if visited != 5 {
+		t.Fatalf("expected the scan to stop after 5 entries, visited %d", visited)
+	}
+}
+
+func TestRangeWhile(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	sl.Set("d", 40)
+
+	below := func(limit int) func(Entry[string, int]) bool {
+		return func(entry Entry[string, int]) bool { return entry.Value < limit }
+	}
+	if entries := sl.RangeWhile(1, below(30)); !slices.Equal(entries, []Entry[string, int]{{"a", 10}, {"b", 20}}) {
+		t.Fatalf("expected the entries below 30, got %v", entries)
+	}
+	if entries := sl.RangeWhile(2, below(35)); !slices.Equal(entries, []Entry[string, int]{{"b", 20}, {"c", 30}}) {
+		t.Fatalf("expected the scan to begin at rank 2, got %v", entries)
+	}
+	if entries := sl.RangeWhile(1, below(0)); len(entries) != 0 {
+		t.Fatalf("expected nothing when cond is immediately false, got %v", entries)
+	}
+	if entries := sl.RangeWhile(-2, below(100)); !slices.Equal(entries, sl.Range(1, 5)) {
+		t.Fatalf("expected the scan to run to the end, got %v", entries)
+	}
+	if entries := sl.RangeWhile(5, below(100)); len(entries) != 0 {
+		t.Fatalf("expected nothing past the length, got %v", entries)
+	}
+
+	calls := 0
+	sl.RangeWhile(1, func(entry Entry[string, int]) bool {
+		calls++
+		return entry.Value < 20
+	})
+	if calls != 2 {
+		t.Fatalf("expected the scan to stop at the first false, cond ran %d times", calls)
+	}
+}
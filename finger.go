@@ -0,0 +1,56 @@
+package ranklist
+
+// finger 记录最近一次插入时每层的前驱节点及其排名，
+// 下一次插入时可以直接从这些节点继续查找，而不必每次都从头节点开始
+// finger records the predecessor at each level and its rank as of the last insertion,
+// so the next insertion can resume from those nodes instead of always descending from the header
+type finger[K Ordered, V Ordered] struct {
+	valid bool
+	level int
+	prev  [MaxLevel]*Node[K, V]
+	rank  [MaxLevel]int
+}
+
+// resume 返回第 i 层上可以开始查找 entry 的位置：
+// 若该层的 finger 节点排在新条目之前并且比当前位置更靠后，则跳到 finger 节点，否则保持当前位置
+// resume returns where the search for entry may continue at level i:
+// it jumps to the finger node of that level when the node sorts before the new entry
+// and lies past the current position, otherwise it keeps the current position
+func (f *finger[K, V]) resume(sl *RankList[K, V], i int, curr *Node[K, V], sum int, entry Entry[K, V]) (*Node[K, V], int) {
+	if !f.valid || i >= f.level || f.rank[i] <= sum {
+		return curr, sum
+	}
+	node := f.prev[i]
+	if sl.compare(node.data, entry) < 0 {
+		return node, f.rank[i]
+	}
+	return curr, sum
+}
+
+// record 在插入 node 之后记录每层的前驱，插入位置之后的条目可以从这里继续查找。
+// 只有发生变化的指针才会被写入，以减少顺序插入时的写屏障开销
+// record saves the per-level predecessors right after node was inserted,
+// entries sorting after it can resume from here.
+// Only pointers that changed are written, which keeps write barriers off the sorted insert path
+func (f *finger[K, V]) record(sl *RankList[K, V], node *Node[K, V], prev *[MaxLevel]*Node[K, V], rank *[MaxLevel]int) {
+	f.valid = true
+	f.level = sl.level
+	for i := 0; i < sl.level; i++ {
+		p, r := prev[i], rank[i]
+		if i < node.level {
+			p, r = node, rank[0]+1
+		}
+		if f.prev[i] != p {
+			f.prev[i] = p
+		}
+		f.rank[i] = r
+	}
+}
+
+// invalidate 使 finger 失效，删除节点或重建跳表后 finger 中的节点和排名可能已经过期
+// invalidate drops the finger, its nodes and ranks may be stale after a deletion or a rebuild
+func (f *finger[K, V]) invalidate() {
+	if f.valid {
+		*f = finger[K, V]{}
+	}
+}
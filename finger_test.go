@@ -0,0 +1,91 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// requireSpans 检查每一层的跨度之和与节点在第 0 层的位置一致
+// requireSpans checks that the spans of every level add up to each node's position at level 0
+func requireSpans[K Ordered, V Ordered](t *testing.T, sl *RankList[K, V]) {
+	t.Helper()
+
+	positions := make(map[*Node[K, V]]int, sl.length)
+	position := 0
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		position++
+		positions[curr] = position
+	}
+	for i := 0; i < sl.level; i++ {
+		rank := 0
+		for curr := sl.header.forward[i]; curr != nil; curr = curr.forward[i] {
+			rank += curr.span[i]
+			if rank != positions[curr] {
+				t.Fatalf("level %d, key %v: spans add up to %d, expected %d", i, curr.data.Key, rank, positions[curr])
+			}
+		}
+	}
+}
+
+func TestFingerSortedInserts(t *testing.T) {
+	sl := New[int, int]()
+	model := make(map[int]int)
+	for i := 0; i < 2000; i++ {
+		sl.Set(i, i)
+		model[i] = i
+		if !sl.finger.valid {
+			t.Fatalf("finger should stay valid across sorted inserts")
+		}
+	}
+	requireSpans(t, sl)
+	requireModel(t, sl, model)
+}
+
+func TestFingerInterleavedDeletes(t *testing.T) {
+	sl := New[int, int]()
+	model := make(map[int]int)
+	value := 0
+	for i := 0; i < 5000; i++ {
+		switch rand.IntN(5) {
+		case 0:
+			// 删除会使 finger 失效，包括删除 finger 节点本身
+			// Deletes invalidate the finger, including deleting the finger node itself
+			key := rand.IntN(i + 1)
+			if sl.finger.valid && rand.IntN(2) == 0 {
+				key = sl.finger.prev[0].data.Key
+			}
+			sl.Del(key)
+			delete(model, key)
+		case 1:
+			// 插入到 finger 之前的位置
+			// Insert before the finger
+			key := rand.IntN(i + 1)
+			sl.Set(key, rand.IntN(value+1))
+			model[key] = sl.dict[key].data.Value
+		default:
+			value += rand.IntN(3)
+			sl.Set(i, value)
+			model[i] = value
+		}
+	}
+	requireSpans(t, sl)
+	requireBackward(t, sl)
+	requireModel(t, sl, model)
+}
+
+func TestFingerInvalidatedByRebuild(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 100; i++ {
+		sl.Set(i, i)
+	}
+	sl.Restore([]Entry[int, int]{{Key: 1, Value: 1}, {Key: 2, Value: 2}})
+	if sl.finger.valid {
+		t.Fatalf("finger should be invalidated by a rebuild")
+	}
+	sl.Set(3, 3)
+	sl.Clear()
+	sl.Set(4, 4)
+	sl.Set(5, 5)
+	requireSpans(t, sl)
+	requireModel(t, sl, map[int]int{4: 4, 5: 5})
+}
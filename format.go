@@ -0,0 +1,236 @@
+package ranklist
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// formatOrdered 按 v 的底层类型将其格式化为字符串，浮点数使用能够精确还原的最短表示
+// formatOrdered formats v according to its underlying kind, floats use the shortest representation that round-trips
+func formatOrdered[T Ordered](v T) string {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64)
+	default:
+		return rv.String()
+	}
+}
+
+// parseOrdered 按 T 的底层类型从字符串解析出一个值，是 formatOrdered 的逆操作
+// parseOrdered parses a value of type T from s according to its underlying kind, the inverse of formatOrdered
+func parseOrdered[T Ordered](s string) (T, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return v, err
+		}
+		rv.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+		if err != nil {
+			return v, err
+		}
+		rv.SetUint(x)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(s, rv.Type().Bits())
+		if err != nil {
+			return v, err
+		}
+		rv.SetFloat(x)
+	default:
+		rv.SetString(s)
+	}
+	return v, nil
+}
+
+// textCodec 保存文本导出和导入使用的键值格式化与解析函数，为 nil 的函数使用默认格式
+// textCodec holds the key and value formatters and parsers used by textual exports and imports,
+// a nil function falls back to the default format
+type textCodec[K Ordered, V Ordered] struct {
+	formatKey   func(key K) string
+	formatValue func(value V) string
+	parseKey    func(s string) (K, error)
+	parseValue  func(s string) (V, error)
+
+	// JSON 输出中条目的字段名和自定义形式，未设置时为 nil
+	// Field names and custom shape of entries in JSON output, nil when unset
+	jsonNames    *jsonNames
+	marshalEntry func(entry RankedEntry[K, V]) any
+}
+
+// WithKeyFormatter 使用 fn 格式化 CSV、JSON、Redis 协议、Dump、String 和 WriteDot 输出中的键，
+// 未设置时 CSV 和 Redis 协议使用 strconv，JSON 使用 encoding/json，Dump 和 WriteDot 使用 fmt。
+// 设置后 JSON 中的键编码为字符串，需要配合 WithKeyParser 才能读回
+// WithKeyFormatter formats keys with fn in the CSV, JSON, Redis protocol, Dump, String and WriteDot outputs.
+// Without it CSV and the Redis protocol use strconv, JSON uses encoding/json and Dump and WriteDot use fmt.
+// With it keys are encoded as JSON strings, which need WithKeyParser to be read back
+func WithKeyFormatter[K Ordered, V Ordered](fn func(key K) string) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.codec.formatKey = fn
+	}
+}
+
+// WithValueFormatter 使用 fn 格式化文本输出中的值，例如把以分为单位的 int64 输出为 "12.34"，适用范围与 WithKeyFormatter 相同。
+// Redis 协议中的值是分数，fn 的结果必须仍是 Redis 能够解析的数字
+// WithValueFormatter formats values with fn in the textual outputs, e.g. an int64 of cents written as "12.34",
+// covering the same outputs as WithKeyFormatter.
+// Values are scores in the Redis protocol, so there fn must still produce a number Redis can parse
+func WithValueFormatter[K Ordered, V Ordered](fn func(value V) string) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.codec.formatValue = fn
+	}
+}
+
+// WithKeyParser 使用 fn 解析 ReadCSV 和 UnmarshalJSON 读入的键，是 WithKeyFormatter 的逆操作。
+// 设置后 JSON 中的键必须是字符串；ReadCSV 传入的 parseKey 优先于 fn
+// WithKeyParser parses the keys read by ReadCSV and UnmarshalJSON with fn, the inverse of WithKeyFormatter.
+// With it keys must be JSON strings; a parseKey passed to ReadCSV takes precedence over fn
+func WithKeyParser[K Ordered, V Ordered](fn func(s string) (K, error)) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.codec.parseKey = fn
+	}
+}
+
+// WithValueParser 使用 fn 解析 ReadCSV 和 UnmarshalJSON 读入的值，是 WithValueFormatter 的逆操作，规则与 WithKeyParser 相同
+// WithValueParser parses the values read by ReadCSV and UnmarshalJSON with fn,
+// the inverse of WithValueFormatter, following the rules of WithKeyParser
+func WithValueParser[K Ordered, V Ordered](fn func(s string) (V, error)) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.codec.parseValue = fn
+	}
+}
+
+// keyText 返回键的文本形式
+// keyText returns the text form of key
+func (c textCodec[K, V]) keyText(key K) string {
+	if c.formatKey != nil {
+		return c.formatKey(key)
+	}
+	return formatOrdered(key)
+}
+
+// valueText 返回值的文本形式
+// valueText returns the text form of value
+func (c textCodec[K, V]) valueText(value V) string {
+	if c.formatValue != nil {
+		return c.formatValue(value)
+	}
+	return formatOrdered(value)
+}
+
+// display 返回用于 fmt 输出的键和值，设置了格式化函数时为格式化后的字符串，否则为原值
+// display returns the key and value to print with fmt, the formatted strings when formatters are set and the raw values otherwise
+func (c textCodec[K, V]) display(entry Entry[K, V]) (any, any) {
+	var key, value any = entry.Key, entry.Value
+	if c.formatKey != nil {
+		key = c.formatKey(entry.Key)
+	}
+	if c.formatValue != nil {
+		value = c.formatValue(entry.Value)
+	}
+	return key, value
+}
+
+// formattedEntry 是设置了格式化函数时条目的 JSON 形式
+// formattedEntry is the JSON form of an entry when formatters are set
+type formattedEntry struct {
+	Key   any `json:"key"`
+	Value any `json:"value"`
+}
+
+// jsonEntries 返回用于编码 entries 的 JSON 值，没有格式化函数和自定义形式时就是 entries 本身
+// jsonEntries returns the value to JSON-encode entries with, entries itself when no formatter or custom shape is set
+func (c textCodec[K, V]) jsonEntries(entries []Entry[K, V]) any {
+	if c.customJSON() {
+		shaped := make([]any, len(entries))
+		for i, entry := range entries {
+			shaped[i] = c.rankedJSON(RankedEntry[K, V]{Entry: entry, Rank: i + 1})
+		}
+		return shaped
+	}
+	if c.formatKey == nil && c.formatValue == nil {
+		return entries
+	}
+	formatted := make([]formattedEntry, len(entries))
+	for i, entry := range entries {
+		formatted[i].Key, formatted[i].Value = c.display(entry)
+	}
+	return formatted
+}
+
+// jsonEntry 返回用于编码排名为 rank 的单个条目的 JSON 值
+// jsonEntry returns the value to JSON-encode a single entry at rank with
+func (c textCodec[K, V]) jsonEntry(entry Entry[K, V], rank int) any {
+	if c.customJSON() {
+		return c.rankedJSON(RankedEntry[K, V]{Entry: entry, Rank: rank})
+	}
+	if c.formatKey == nil && c.formatValue == nil {
+		return entry
+	}
+	key, value := c.display(entry)
+	return formattedEntry{Key: key, Value: value}
+}
+
+// unmarshalEntries 解码 {"key":..,"value":..} 对象数组，设置了解析函数的字段必须是字符串并由解析函数转换
+// unmarshalEntries decodes an array of {"key":..,"value":..} objects,
+// a field with a parser set must be a string and is converted by the parser
+func (c textCodec[K, V]) unmarshalEntries(data []byte) ([]Entry[K, V], error) {
+	if c.jsonNames != nil {
+		return c.unmarshalNamed(data)
+	}
+	if c.parseKey == nil && c.parseValue == nil {
+		var entries []Entry[K, V]
+		err := json.Unmarshal(data, &entries)
+		return entries, err
+	}
+
+	var raw []struct {
+		Key   json.RawMessage `json:"key"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	entries := make([]Entry[K, V], len(raw))
+	for i, r := range raw {
+		var err error
+		if entries[i].Key, err = parseJSONField(r.Key, c.parseKey); err != nil {
+			return nil, fmt.Errorf("ranklist: json entry %d: key: %w", i, err)
+		}
+		if entries[i].Value, err = parseJSONField(r.Value, c.parseValue); err != nil {
+			return nil, fmt.Errorf("ranklist: json entry %d: value: %w", i, err)
+		}
+	}
+	return entries, nil
+}
+
+// parseJSONField 解码一个 JSON 字段，parse 不为 nil 时字段必须是字符串并由 parse 转换，字段缺失时返回零值
+// parseJSONField decodes one JSON field, which must be a string converted by parse when parse is not nil.
+// A missing field yields the zero value
+func parseJSONField[T Ordered](raw json.RawMessage, parse func(string) (T, error)) (T, error) {
+	var v T
+	if raw == nil {
+		return v, nil
+	}
+	if parse == nil {
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return v, err
+	}
+	return parse(s)
+}
@@ -0,0 +1,135 @@
+package ranklist
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// formatCents 将以分为单位的金额格式化为 "12.34"
+// formatCents formats an amount of cents as "12.34"
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// parseCents 是 formatCents 的逆操作
+// parseCents is the inverse of formatCents
+func parseCents(s string) (int64, error) {
+	dollars, cents, ok := strings.Cut(s, ".")
+	if !ok || len(cents) != 2 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return strconv.ParseInt(dollars+cents, 10, 64)
+}
+
+func moneyOptions() []Option[string, int64] {
+	return []Option[string, int64]{
+		WithValueFormatter[string, int64](formatCents),
+		WithValueParser[string, int64](parseCents),
+	}
+}
+
+func TestValueFormatterCSV(t *testing.T) {
+	sl := New(moneyOptions()...)
+	for i := 0; i < 500; i++ {
+		sl.Set("player"+strconv.Itoa(i), rand.Int64N(2000000)-1000000)
+	}
+	sl.Set("exact", 1234)
+
+	var buf bytes.Buffer
+	if err := sl.WriteCSV(&buf, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "exact,12.34\n") {
+		t.Fatalf("expected the value in dollars, got:\n%s", buf.String())
+	}
+
+	restored := New(moneyOptions()...)
+	if n, err := restored.ReadCSV(&buf, nil, nil); err != nil || n != sl.Length() {
+		t.Fatalf("unexpected result %d, %v", n, err)
+	}
+	if !slices.Equal(restored.Entries(), sl.Entries()) {
+		t.Fatalf("the CSV round trip changed the content")
+	}
+
+	// 没有解析函数时默认的 strconv 解析会拒绝格式化后的值
+	// Without a parser the default strconv parsing rejects the formatted values
+	buf.Reset()
+	sl.WriteCSV(&buf, true)
+	if _, err := New[string, int64]().ReadCSV(&buf, nil, nil); err == nil {
+		t.Fatalf("expected the default parser to reject dollar amounts")
+	}
+}
+
+func TestValueFormatterJSON(t *testing.T) {
+	sl := New(append(moneyOptions(), WithKeyFormatter[string, int64](strings.ToUpper))...)
+	sl.Set("a", 1234)
+	sl.Set("b", -5)
+
+	data, err := json.Marshal(sl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `[{"key":"B","value":"-0.05"},{"key":"A","value":"12.34"}]`; string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+	var streamed bytes.Buffer
+	if err := sl.EncodeJSON(&streamed); err != nil || streamed.String() != string(data) {
+		t.Fatalf("EncodeJSON should match MarshalJSON, got %s, %v", streamed.String(), err)
+	}
+	if frozen, _ := json.Marshal(sl.Freeze()); string(frozen) != string(data) {
+		t.Fatalf("a frozen copy should keep the formatters, got %s", frozen)
+	}
+
+	restored := New(append(moneyOptions(), WithKeyParser[string, int64](func(s string) (string, error) {
+		return strings.ToLower(s), nil
+	}))...)
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(restored.Entries(), sl.Entries()) {
+		t.Fatalf("the JSON round trip changed the content: %v", restored.Entries())
+	}
+	if err := restored.UnmarshalJSON([]byte(`[{"key":"A","value":12}]`)); err == nil {
+		t.Fatalf("a parsed field should have to be a string")
+	}
+	if err := restored.UnmarshalJSON([]byte(`[{"key":"A","value":"12"}]`)); err == nil || !strings.Contains(err.Error(), "entry 0: value") {
+		t.Fatalf("expected the parser error with its position, got %v", err)
+	}
+}
+
+func TestValueFormatterText(t *testing.T) {
+	sl := New(moneyOptions()...)
+	sl.Set("a", 1234)
+
+	if dump := sl.String(); !strings.Contains(dump, "[a:12.34:1]") {
+		t.Fatalf("Dump should use the formatter, got:\n%s", dump)
+	}
+	var dot bytes.Buffer
+	sl.WriteDot(&dot)
+	if !strings.Contains(dot.String(), `a: 12.34`) {
+		t.Fatalf("WriteDot should use the formatter, got:\n%s", dot.String())
+	}
+	var resp bytes.Buffer
+	sl.WriteRedisProto(&resp, "board")
+	if !strings.Contains(resp.String(), "$5\r\n12.34\r\n") {
+		t.Fatalf("WriteRedisProto should use the formatter, got %q", resp.String())
+	}
+
+	// 未设置格式化函数时输出保持不变
+	// The outputs are unchanged without formatters
+	plain := New[string, int64]()
+	plain.Set("a", 1234)
+	if dump := plain.String(); !strings.Contains(dump, "[a:1234:1]") {
+		t.Fatalf("unexpected default Dump:\n%s", dump)
+	}
+}
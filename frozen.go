@@ -0,0 +1,173 @@
+package ranklist
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"io"
+	"reflect"
+	"slices"
+)
+
+// FrozenRankList 跳表在某一时刻的不可变副本，适合归档后只读的榜单。
+// 条目按排名顺序保存在一个连续的切片中，另有一个按键排序的下标切片用于按键查找，
+// 没有字典、锁和节点指针，每个条目只比条目本身多占用 4 字节。
+// 没有任何修改方法，因此可以不加锁地被任意多个 goroutine 并发读取。
+// 元数据、条目版本和时间戳不会被复制，WithKeyFormatter 和 WithValueFormatter 设置的格式化函数会被复制
+// FrozenRankList is an immutable copy of a skip list at one moment, meant for archived boards that are only read.
+// Entries are kept in rank order in one contiguous slice, with a slice of positions sorted by key for lookups by key;
+// there is no dictionary, lock or node pointer, so every entry costs just 4 bytes on top of the entry itself.
+// It has no mutators and can be read by any number of goroutines without locking.
+// Metadata, entry versions and timestamps are not copied, the formatters of WithKeyFormatter and WithValueFormatter are
+type FrozenRankList[K Ordered, V Ordered] struct {
+	// 按排名顺序排列的全部条目
+	// All entries in rank order
+	entries []Entry[K, V]
+
+	// entries 的下标，按对应条目的键排序
+	// Positions into entries, sorted by the key of the entry they point at
+	byKey []int32
+
+	// 从跳表复制的文本格式化函数
+	// Text formatters copied from the list
+	codec textCodec[K, V]
+}
+
+// Freeze 在一把读锁内复制跳表的全部条目，返回与此刻内容完全一致的 FrozenRankList，耗时 O(n log n)。
+// 之后对跳表的修改不会影响返回的副本
+// Freeze copies every entry of the skip list under one read lock and returns a FrozenRankList
+// matching the content at that moment, in O(n log n). Later changes to the list do not affect the copy
+func (sl *RankList[K, V]) Freeze() *FrozenRankList[K, V] {
+	entries := sl.Entries()
+	byKey := make([]int32, len(entries))
+	for i := range byKey {
+		byKey[i] = int32(i)
+	}
+	slices.SortFunc(byKey, func(a, b int32) int {
+		return cmp.Compare(entries[a].Key, entries[b].Key)
+	})
+	return &FrozenRankList[K, V]{entries: slices.Clip(entries), byKey: byKey, codec: sl.codec}
+}
+
+// Length 返回条目数量
+// Length returns the number of entries
+func (f *FrozenRankList[K, V]) Length() int {
+	return len(f.entries)
+}
+
+// position 二分查找键所在的下标，键不存在时返回 false
+// position binary searches the position of key, false if the key does not exist
+func (f *FrozenRankList[K, V]) position(key K) (int, bool) {
+	i, found := slices.BinarySearchFunc(f.byKey, key, func(pos int32, key K) int {
+		return cmp.Compare(f.entries[pos].Key, key)
+	})
+	if !found {
+		return 0, false
+	}
+	return int(f.byKey[i]), true
+}
+
+// Get 根据键获取值，键不存在时返回 false，耗时 O(log n)
+// Get retrieves the value of key, false if the key does not exist, in O(log n)
+func (f *FrozenRankList[K, V]) Get(key K) (V, bool) {
+	if pos, ok := f.position(key); ok {
+		return f.entries[pos].Value, true
+	}
+	return ZeroValue[V](), false
+}
+
+// Rank 返回键的排名，排名从 1 开始，与 RankList.Rank 一致，键不存在时返回 false，耗时 O(log n)
+// Rank returns the 1-based rank of key like RankList.Rank, false if the key does not exist, in O(log n)
+func (f *FrozenRankList[K, V]) Rank(key K) (int, bool) {
+	if pos, ok := f.position(key); ok {
+		return pos + 1, true
+	}
+	return 0, false
+}
+
+// GetByRank 返回指定排名上的条目，超出 [1, Length()] 时返回 false，耗时 O(1)
+// GetByRank returns the entry at the given 1-based rank, false outside [1, Length()], in O(1)
+func (f *FrozenRankList[K, V]) GetByRank(rank int) (Entry[K, V], bool) {
+	if rank < 1 || rank > len(f.entries) {
+		return Entry[K, V]{}, false
+	}
+	return f.entries[rank-1], true
+}
+
+// Range 获取排名区间 [start, end) 内的条目，规则与 RankList.Range 相同，返回的切片是副本
+// Range retrieves the entries within the rank range [start, end) following the rules of RankList.Range,
+// the returned slice is a copy
+func (f *FrozenRankList[K, V]) Range(start int, end int) []Entry[K, V] {
+	start = max(start, 1)
+	end = min(end, len(f.entries)+1)
+	if start >= end {
+		return make([]Entry[K, V], 0)
+	}
+	return slices.Clone(f.entries[start-1 : end-1])
+}
+
+// Top 返回值最大的 n 个条目，按值从高到低排列，与 RankList.Top 相同
+// Top returns the n entries with the largest values from the highest value down, like RankList.Top
+func (f *FrozenRankList[K, V]) Top(n int) []Entry[K, V] {
+	n = min(max(n, 0), len(f.entries))
+	entries := slices.Clone(f.entries[len(f.entries)-n:])
+	slices.Reverse(entries)
+	return entries
+}
+
+// Entries 按排名顺序返回全部条目，返回的切片是副本
+// Entries returns every entry in rank order, the returned slice is a copy
+func (f *FrozenRankList[K, V]) Entries() []Entry[K, V] {
+	return slices.Clone(f.entries)
+}
+
+// MarshalJSON 使用与 RankList.MarshalJSON 相同的格式编码全部条目
+// MarshalJSON encodes every entry in the same format as RankList.MarshalJSON
+func (f *FrozenRankList[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.codec.jsonEntries(f.entries))
+}
+
+// UnmarshalJSON 总是返回 ErrFrozen：FrozenRankList 不可修改，应当解码到 RankList 之后再调用 Freeze
+// UnmarshalJSON always returns ErrFrozen: a FrozenRankList cannot be modified,
+// decode into a RankList and call Freeze instead
+func (f *FrozenRankList[K, V]) UnmarshalJSON([]byte) error {
+	return ErrFrozen
+}
+
+// Save 使用与 RankList.Save 相同的二进制格式写入 w，可以由 RankList.Load 读回
+// Save writes to w in the same binary format as RankList.Save, readable by RankList.Load
+func (f *FrozenRankList[K, V]) Save(w io.Writer) error {
+	return writeSnapshot(w, f.entries)
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler，使用与 Save 相同的二进制格式
+// MarshalBinary implements encoding.BinaryMarshaler using the same format as Save
+func (f *FrozenRankList[K, V]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := f.Save(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 总是返回 ErrFrozen，原因与 UnmarshalJSON 相同
+// UnmarshalBinary always returns ErrFrozen for the same reason as UnmarshalJSON
+func (f *FrozenRankList[K, V]) UnmarshalBinary([]byte) error {
+	return ErrFrozen
+}
+
+// WriteCSV 使用与 RankList.WriteCSV 相同的格式写入 w
+// WriteCSV writes to w in the same format as RankList.WriteCSV
+func (f *FrozenRankList[K, V]) WriteCSV(w io.Writer, includeRank bool) error {
+	return writeCSV(w, includeRank, f.codec, slices.Values(f.entries))
+}
+
+// WriteRedisProto 使用与 RankList.WriteRedisProto 相同的格式写入 w，值类型不是数字时返回 ErrNonNumericValue
+// WriteRedisProto writes to w in the same format as RankList.WriteRedisProto,
+// returns ErrNonNumericValue when the value type is not numeric
+func (f *FrozenRankList[K, V]) WriteRedisProto(w io.Writer, zsetKey string) error {
+	if kindOf[V]() == reflect.String {
+		return ErrNonNumericValue
+	}
+	return writeRedisProto(w, zsetKey, f.codec, slices.Values(f.entries))
+}
@@ -0,0 +1,99 @@
+package ranklist
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 2000; i++ {
+		sl.Set(rand.IntN(1000), rand.IntN(100))
+	}
+	frozen := sl.Freeze()
+
+	if frozen.Length() != sl.Length() {
+		t.Fatalf("expected length %d, got %d", sl.Length(), frozen.Length())
+	}
+	for key := -1; key <= 1000; key++ {
+		value, ok := sl.Get(key)
+		gotValue, gotOk := frozen.Get(key)
+		rank, _ := sl.Rank(key)
+		gotRank, _ := frozen.Rank(key)
+		if value != gotValue || ok != gotOk || rank != gotRank {
+			t.Fatalf("key %d: expected %d, %v at rank %d, got %d, %v at rank %d", key, value, ok, rank, gotValue, gotOk, gotRank)
+		}
+	}
+	for _, window := range [][2]int{{1, 11}, {-5, 3}, {500, 2000}, {10, 5}, {0, 0}} {
+		if expected, got := sl.Range(window[0], window[1]), frozen.Range(window[0], window[1]); !slices.Equal(expected, got) {
+			t.Fatalf("Range%v: expected %v, got %v", window, expected, got)
+		}
+	}
+	for _, n := range []int{0, 1, 10, 5000} {
+		if expected, got := sl.Top(n), frozen.Top(n); !slices.Equal(expected, got) {
+			t.Fatalf("Top(%d): expected %v, got %v", n, expected, got)
+		}
+	}
+	for _, rank := range []int{0, 1, sl.Length(), sl.Length() + 1} {
+		expected, ok := sl.GetByRank(rank)
+		if got, gotOk := frozen.GetByRank(rank); got != expected || gotOk != ok {
+			t.Fatalf("GetByRank(%d): expected %v, %v, got %v, %v", rank, expected, ok, got, gotOk)
+		}
+	}
+
+	// 冻结之后的修改不影响副本
+	// Changes after freezing do not affect the copy
+	before := frozen.Range(1, frozen.Length()+1)
+	sl.Clear()
+	if after := frozen.Range(1, frozen.Length()+1); !slices.Equal(before, after) {
+		t.Fatalf("the frozen copy changed with its source")
+	}
+}
+
+func TestFreezeSerialization(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 30)
+	sl.Set("b", 10)
+	sl.Set("c", 20)
+	frozen := sl.Freeze()
+
+	expectedJSON, _ := json.Marshal(sl)
+	if got, err := json.Marshal(frozen); err != nil || !bytes.Equal(got, expectedJSON) {
+		t.Fatalf("expected JSON %s, got %s, %v", expectedJSON, got, err)
+	}
+
+	expectedBinary, _ := sl.MarshalBinary()
+	gotBinary, err := frozen.MarshalBinary()
+	if err != nil || !bytes.Equal(gotBinary, expectedBinary) {
+		t.Fatalf("binary snapshot differs from the source list")
+	}
+	restored := New[string, int]()
+	if err := restored.UnmarshalBinary(gotBinary); err != nil || !restored.Equal(sl) {
+		t.Fatalf("expected the frozen snapshot to load back, got %v", err)
+	}
+
+	var expectedCSV, gotCSV bytes.Buffer
+	sl.WriteCSV(&expectedCSV, true)
+	if err := frozen.WriteCSV(&gotCSV, true); err != nil || gotCSV.String() != expectedCSV.String() {
+		t.Fatalf("expected CSV %q, got %q, %v", expectedCSV.String(), gotCSV.String(), err)
+	}
+
+	var expectedRESP, gotRESP bytes.Buffer
+	sl.WriteRedisProto(&expectedRESP, "board")
+	if err := frozen.WriteRedisProto(&gotRESP, "board"); err != nil || gotRESP.String() != expectedRESP.String() {
+		t.Fatalf("expected RESP %q, got %q, %v", expectedRESP.String(), gotRESP.String(), err)
+	}
+}
+
+func TestFreezeEmpty(t *testing.T) {
+	frozen := New[string, int]().Freeze()
+	if _, ok := frozen.Get("a"); ok || frozen.Length() != 0 {
+		t.Fatalf("expected an empty frozen list")
+	}
+	if len(frozen.Range(1, 10)) != 0 || len(frozen.Top(3)) != 0 {
+		t.Fatalf("expected empty results")
+	}
+}
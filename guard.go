@@ -0,0 +1,74 @@
+package ranklist
+
+import "fmt"
+
+// WithValueGuard 注册一个值校验函数，在写入任何单个值之前于写锁内调用，old 是键当前的值，键不存在时为零值。
+// fn 返回错误时写入被拒绝、不做任何修改：SetChecked、TrySet 和 IncrByChecked 返回包装了 ErrValueRejected 和该错误的错误，
+// Set、IncrBy、IncrByClamped、SetIfVersion 和 UpdateByValue 不做修改，SetBatch 和 LoadMap 跳过被拒绝的键。
+// 从快照整体恢复的 Restore、Load 和 UnmarshalJSON 不经过校验。
+// 例如对于无符号类型的值，IncrBy 传入回绕的增量来表示减少时会得到一个巨大的值，校验函数可以拒绝这样的写入
+// WithValueGuard registers a validation function called under the write lock before any single value is stored,
+// old is the current value of the key, the zero value for a missing key.
+// When fn returns an error the write is rejected and nothing changes: SetChecked, TrySet and IncrByChecked
+// return an error wrapping both ErrValueRejected and that error, Set, IncrBy, IncrByClamped, SetIfVersion and UpdateByValue
+// leave the list untouched, and SetBatch and LoadMap skip the rejected keys.
+// Whole-content restores from snapshots, Restore, Load and UnmarshalJSON, are not validated.
+// For example with unsigned values, passing IncrBy a wrapped-around delta to express a decrement yields a huge value,
+// which a guard can reject
+func WithValueGuard[K Ordered, V Ordered](fn func(old, new V) error) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.valueGuard = fn
+	}
+}
+
+// SetChecked 与 Set 相同，但在值被 WithValueGuard 拒绝或新键超出 WithHardLimit 时返回错误
+// SetChecked behaves like Set, but returns an error when the value is rejected by WithValueGuard
+// or a new key would exceed WithHardLimit
+func (sl *RankList[K, V]) SetChecked(key K, value V) (bool, error) {
+	start := sl.metricsStart()
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		sl.observe("set", start, 0)
+		return false, ErrClosed
+	}
+	if err := sl.guardWrite(key, value); err != nil {
+		sl.Unlock()
+		sl.observe("set", start, 0)
+		return false, err
+	}
+	if err := sl.persistWrite(key, value); err != nil {
+		sl.Unlock()
+		sl.observe("set", start, 0)
+		return false, err
+	}
+	inserted := sl.setAndUnlock(key, value, keyspaceZadd)
+	sl.observe("set", start, 1)
+	return inserted, nil
+}
+
+// guardKey 用键当前的值校验即将写入的 value，调用方需持有写锁
+// guardKey validates value against the current value of key, the caller must hold the write lock
+func (sl *RankList[K, V]) guardKey(key K, value V) error {
+	if sl.valueGuard == nil {
+		return nil
+	}
+	var old V
+	if node, exists := sl.lookup(key); exists {
+		old = node.data.Value
+	}
+	return sl.guardValue(old, value)
+}
+
+// guardValue 校验从 old 到 value 的写入，调用方需持有写锁
+// guardValue validates a write from old to value, the caller must hold the write lock
+func (sl *RankList[K, V]) guardValue(old V, value V) error {
+	if sl.valueGuard == nil {
+		return nil
+	}
+	if err := sl.valueGuard(old, value); err != nil {
+		sl.vars.add(opRejected)
+		return fmt.Errorf("%w: %w", ErrValueRejected, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package ranklist
+
+import (
+	"errors"
+	"testing"
+)
+
+var errWrapped = errors.New("decrement wrapped around")
+
+// noWrap 拒绝无符号值从较小的值跳到接近上限的值，这种跳变只可能来自回绕
+// noWrap rejects unsigned values jumping from small to near the maximum, which only a wrap-around can cause
+func noWrap(old, new uint32) error {
+	if new > old && new-old > 1<<31 {
+		return errWrapped
+	}
+	return nil
+}
+
+func TestValueGuardUnsignedWrap(t *testing.T) {
+	// 没有校验时，用回绕的增量表示减 10 会让值变得巨大，成员跳到榜首
+	// Without a guard, expressing minus 10 as a wrapped delta makes the value huge and rockets the member to the top
+	plain := New[string, uint32]()
+	plain.Set("a", 5)
+	plain.Set("b", 100)
+	if value := plain.IncrBy("a", ^uint32(9)); value != 4294967291 {
+		t.Fatalf("expected the wrapped value 4294967291, got %d", value)
+	}
+	if rank, _ := plain.Rank("a"); rank != 2 {
+		t.Fatalf("expected a to jump to the last rank, got %d", rank)
+	}
+
+	sl := New(WithValueGuard[string, uint32](noWrap))
+	sl.Set("a", 5)
+	sl.Set("b", 100)
+	value, err := sl.IncrByChecked("a", ^uint32(9))
+	if !errors.Is(err, ErrValueRejected) || !errors.Is(err, errWrapped) || value != 5 {
+		t.Fatalf("expected the wrap to be rejected, got %d, %v", value, err)
+	}
+	if value := sl.IncrBy("a", ^uint32(2)); value != 2 {
+		t.Fatalf("a decrement within range should apply, got %d", value)
+	}
+	requireModel(t, sl, map[string]uint32{"a": 2, "b": 100})
+}
+
+func TestValueGuardWritePaths(t *testing.T) {
+	errNegative := errors.New("negative")
+	sl := New(WithValueGuard[string, int](func(old, new int) error {
+		if new < 0 {
+			return errNegative
+		}
+		return nil
+	}))
+	sl.Set("a", 1)
+
+	if sl.Set("b", -1) {
+		t.Errorf("Set should not insert a rejected value")
+	}
+	if _, err := sl.SetChecked("a", -1); !errors.Is(err, errNegative) {
+		t.Errorf("expected the guard error, got %v", err)
+	}
+	if _, err := sl.TrySet("a", -1, 0); !errors.Is(err, ErrValueRejected) {
+		t.Errorf("expected ErrValueRejected, got %v", err)
+	}
+	if sl.SetIfVersion("a", -1, 1) {
+		t.Errorf("SetIfVersion should reject the value")
+	}
+	if sl.UpdateByValue("a", 1, -1) {
+		t.Errorf("UpdateByValue should reject the value")
+	}
+	if inserted, updated := sl.SetBatch([]Entry[string, int]{{"c", 3}, {"d", -4}}); inserted != 1 || updated != 0 {
+		t.Errorf("expected one insert and no update, got %d and %d", inserted, updated)
+	}
+	sl.LoadMap(map[string]int{"e": 5, "f": -6}, false)
+	requireModel(t, sl, map[string]int{"a": 1, "c": 3, "e": 5})
+}
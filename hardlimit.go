@@ -0,0 +1,78 @@
+package ranklist
+
+// WithHardLimit 将跳表的成员数硬性限制为 n，与淘汰成员的 WithMaxSize 不同，超出限制的插入直接被拒绝，已有成员不受影响，
+// 用于防止上游缺陷写入大量新键时耗尽内存。
+// 已有键的更新总是允许的；插入新键时如果跳表已经有 n 个成员，写入不做任何修改：
+// SetChecked、TrySet、IncrByChecked 和 SetIfVersionChecked 返回属于 ErrRejected 的 ErrFull，Set、IncrBy、IncrByClamped、Reserve 和 Resume 不做修改，
+// SetBatch、AddAll、LoadMap 和 HydrateFrom 跳过超出限制的新键，SetBatchReport 在 Rejected 中报告被拒绝的条目数，
+// Txn 中会使成员数超出限制的事务整体返回 ErrFull。检查与插入在同一把写锁内完成。
+// 每次拒绝都计入 WithExpvar 的 rejected 计数器。从快照整体恢复的 Restore、Load 和 UnmarshalJSON 不受限制
+// WithHardLimit caps the skip list at n members. Unlike WithMaxSize, which evicts members,
+// inserts past the limit are refused outright and existing members are left alone,
+// protecting the process from running out of memory when an upstream bug floods new keys.
+// Updates of existing keys are always allowed; inserting a new key while the list already holds n members changes nothing:
+// SetChecked, TrySet, IncrByChecked and SetIfVersionChecked return ErrFull, which is an ErrRejected,
+// Set, IncrBy, IncrByClamped, Reserve and Resume leave the list untouched,
+// SetBatch, AddAll, LoadMap and HydrateFrom skip the new keys past the limit, SetBatchReport reports the number of
+// rejected entries in Rejected, and a Txn that would take the size past the limit fails as a whole with ErrFull.
+// The check happens under the same write lock as the insert.
+// Every rejection is counted by the rejected counter of WithExpvar.
+// Whole-content restores from snapshots, Restore, Load and UnmarshalJSON, are not limited
+func WithHardLimit[K Ordered, V Ordered](n int) Option[K, V] {
+	if n < 1 {
+		panic("ranklist: hard limit must be positive")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.hardLimit = n
+	}
+}
+
+// guardFull 在跳表已有 size 个成员时校验写入 key 是否会超出硬性限制，已有的键和删除键的零值写入总是允许，调用方需持有写锁
+// guardFull checks whether writing key would take a list of size members past the hard limit,
+// existing keys and zero-value writes that delete the key are always allowed. The caller must hold the write lock
+func (sl *RankList[K, V]) guardFull(key K, value V, size int) error {
+	if sl.hardLimit == 0 || size < sl.hardLimit {
+		return nil
+	}
+	if _, exists := sl.lookup(key); exists {
+		return nil
+	}
+	if sl.zeroDeletes && value == ZeroValue[V]() {
+		return nil
+	}
+	sl.vars.add(opRejected)
+	return ErrFull
+}
+
+// guardWrite 依次校验硬性限制和 WithValueGuard，用于单个键的写入，调用方需持有写锁
+// guardWrite checks the hard limit and then WithValueGuard for a single-key write, the caller must hold the write lock
+func (sl *RankList[K, V]) guardWrite(key K, value V) error {
+	if err := sl.guardFull(key, value, sl.length); err != nil {
+		return err
+	}
+	return sl.guardKey(key, value)
+}
+
+// limitEntries 按顺序保留 entries 中不会使成员数超出硬性限制的条目，size 是写入前的成员数，
+// exists 判断键是否已经是成员。重复出现的新键只计一次，调用方需持有写锁
+// limitEntries keeps, in order, the entries of entries that do not take the size past the hard limit,
+// size being the number of members before the write and exists telling whether a key is already a member.
+// A new key appearing more than once counts once. The caller must hold the write lock
+func (sl *RankList[K, V]) limitEntries(entries []Entry[K, V], size int, exists func(key K) bool) []Entry[K, V] {
+	if sl.hardLimit == 0 {
+		return entries
+	}
+	added := make(map[K]struct{})
+	kept := entries[:0]
+	for _, entry := range entries {
+		if _, ok := added[entry.Key]; !ok && !exists(entry.Key) {
+			if size+len(added) >= sl.hardLimit {
+				sl.vars.add(opRejected)
+				continue
+			}
+			added[entry.Key] = struct{}{}
+		}
+		kept = append(kept, entry)
+	}
+	return kept
+}
@@ -0,0 +1,118 @@
+package ranklist
+
+import (
+	"errors"
+	"expvar"
+	"testing"
+)
+
+func TestHardLimitFill(t *testing.T) {
+	sl := New(WithHardLimit[string, int](3), WithExpvar[string, int]("ranklist_test_hardlimit"))
+	for i, key := range []string{"a", "b", "c"} {
+		if _, err := sl.SetChecked(key, (i+1)*10); err != nil {
+			t.Fatalf("unexpected error filling %s: %v", key, err)
+		}
+	}
+
+	_, err := sl.SetChecked("d", 40)
+	if !errors.Is(err, ErrFull) || !errors.Is(err, ErrRejected) {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+	if sl.Set("e", 50) {
+		t.Fatal("Set should not insert past the hard limit")
+	}
+	if value := sl.IncrBy("f", 5); value != 0 {
+		t.Fatalf("IncrBy on a new key should be refused, got %d", value)
+	}
+	requireModel(t, sl, map[string]int{"a": 10, "b": 20, "c": 30})
+
+	// 更新已有的键不受限制 / Updates of existing keys are always allowed
+	if _, err := sl.SetChecked("a", 100); err != nil {
+		t.Fatalf("update at the limit failed: %v", err)
+	}
+	if value, err := sl.IncrByChecked("b", 5); err != nil || value != 25 {
+		t.Fatalf("expected increment to 25, got %d, %v", value, err)
+	}
+	requireModel(t, sl, map[string]int{"a": 100, "b": 25, "c": 30})
+
+	// 删除之后可以再插入 / A delete makes room again
+	sl.Del("c")
+	if !sl.Set("d", 40) {
+		t.Fatal("expected d to be inserted after a delete")
+	}
+	requireModel(t, sl, map[string]int{"a": 100, "b": 25, "d": 40})
+	requireSpans(t, sl)
+
+	m := expvar.Get("ranklist_test_hardlimit").(*expvar.Map)
+	if got := m.Get("rejected").String(); got != "3" {
+		t.Fatalf("expected 3 rejections, got %s", got)
+	}
+}
+
+func TestHardLimitBatch(t *testing.T) {
+	sl := New(WithHardLimit[string, int](3))
+	sl.Set("a", 1)
+
+	report := sl.SetBatchReport([]Entry[string, int]{
+		{"b", 2}, {"a", 10}, {"c", 3}, {"d", 4}, {"b", 20}, {"e", 5},
+	}, 0)
+	if report.Inserted != 2 || report.Updated != 2 || report.Rejected != 2 {
+		t.Fatalf("expected 2 inserted, 2 updated and 2 rejected, got %+v", report)
+	}
+	requireModel(t, sl, map[string]int{"a": 10, "b": 20, "c": 3})
+
+	inserted, updated := sl.SetBatch([]Entry[string, int]{{"x", 1}, {"c", 30}})
+	if inserted != 0 || updated != 1 {
+		t.Fatalf("expected 0 inserted and 1 updated, got %d and %d", inserted, updated)
+	}
+
+	sl.Del("c")
+	sl.LoadMap(map[string]int{"a": 11, "y": 1, "z": 2}, false)
+	if sl.Length() != 3 {
+		t.Fatalf("expected LoadMap to stop at the limit, got length %d", sl.Length())
+	}
+	if value, _ := sl.Get("a"); value != 11 {
+		t.Fatalf("expected a to be updated by LoadMap, got %d", value)
+	}
+}
+
+func TestHardLimitLoadMapOrder(t *testing.T) {
+	m := map[string]int{"e": 5, "c": 3, "a": 1, "d": 4, "b": 2}
+	for range 20 {
+		// 按键的顺序处理，超出上限的总是较大的新键
+		// Keys are handled in order, the larger new keys are always the ones past the limit
+		merged := New(WithHardLimit[string, int](3))
+		merged.Set("z", 26)
+		merged.LoadMap(m, false)
+		requireModel(t, merged, map[string]int{"a": 1, "b": 2, "z": 26})
+
+		replaced := New(WithHardLimit[string, int](3))
+		replaced.LoadMap(m, true)
+		requireModel(t, replaced, map[string]int{"a": 1, "b": 2, "c": 3})
+	}
+}
+
+func TestHardLimitTxn(t *testing.T) {
+	sl := New(WithHardLimit[string, int](2))
+	sl.Set("a", 1)
+
+	err := sl.Txn(func(tx *Tx[string, int]) error {
+		tx.Set("b", 2)
+		return tx.Set("c", 3)
+	})
+	if !errors.Is(err, ErrFull) {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+	requireModel(t, sl, map[string]int{"a": 1})
+
+	// 同一个事务里腾出空间 / Room made within the same transaction
+	err = sl.Txn(func(tx *Tx[string, int]) error {
+		tx.Del("a")
+		tx.Set("b", 2)
+		return tx.Set("c", 3)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	requireModel(t, sl, map[string]int{"b": 2, "c": 3})
+}
@@ -0,0 +1,91 @@
+package ranklist
+
+import (
+	"context"
+	"iter"
+)
+
+// hydrateCheckEvery 是 HydrateFrom 检查 ctx 的间隔条目数
+// hydrateCheckEvery is the number of entries between two checks of ctx in HydrateFrom
+const hydrateCheckEvery = 1024
+
+// HydrateFrom 在启动时从 seq 批量加载一个空跳表，通常 seq 遍历的是 WithStore 配置的外部存储。
+// 全部条目先在锁外读取，再在一把写锁内通过排序后的 O(n) 构建载入，相同的键以最后一次出现的值为准，
+// 被 WithValueGuard 拒绝的键和超出 WithHardLimit 的新键被跳过。加载的内容写入预写日志，但不会写回外部存储。
+// 跳表非空时不加载任何内容并返回 ErrNotEmpty，跳表已关闭时返回 ErrClosed。
+// 读取期间每隔一段条目检查一次 ctx，ctx 被取消时停止读取，载入已经读到的条目后返回 ctx.Err()，
+// 此时跳表只包含一部分内容但可以正常使用
+// HydrateFrom bulk-loads an empty list from seq at startup, typically iterating the external store configured by WithStore.
+// All entries are read outside the lock first and then loaded under one write lock through the sorted O(n) build;
+// the last value wins for a key seen more than once, and keys rejected by WithValueGuard or past WithHardLimit are skipped.
+// The loaded content is journaled to the write-ahead log but never written back to the external store.
+// A non-empty list loads nothing and returns ErrNotEmpty, a closed list returns ErrClosed.
+// ctx is checked periodically while reading; once it is cancelled reading stops, the entries read so far are loaded
+// and ctx.Err() is returned, leaving a partially loaded list that is otherwise fully usable
+func (sl *RankList[K, V]) HydrateFrom(ctx context.Context, seq iter.Seq2[K, V]) error {
+	return sl.HydrateFromProgress(ctx, seq, 0, nil)
+}
+
+// HydrateFromProgress 与 HydrateFrom 相同，并在每读取 every 个条目后以已读取的条目数调用 progress。
+// progress 在锁外调用，every 小于 1 或 progress 为 nil 时不报告进度
+// HydrateFromProgress is HydrateFrom that also calls progress with the number of entries read after every every entries.
+// progress is called outside the lock, and no progress is reported when every is below 1 or progress is nil
+func (sl *RankList[K, V]) HydrateFromProgress(ctx context.Context, seq iter.Seq2[K, V], every int, progress func(loaded int)) error {
+	if sl.Closed() {
+		return ErrClosed
+	}
+	if sl.Length() != 0 {
+		return ErrNotEmpty
+	}
+	if progress == nil {
+		every = 0
+	}
+
+	var entries []Entry[K, V]
+	err := ctx.Err()
+	if err == nil {
+		for key, value := range seq {
+			entries = append(entries, Entry[K, V]{Key: key, Value: value})
+			read := len(entries)
+			if every > 0 && read%every == 0 {
+				progress(read)
+			}
+			if read%hydrateCheckEvery == 0 {
+				if err = ctx.Err(); err != nil {
+					break
+				}
+			}
+		}
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return ErrClosed
+	}
+	// 读取期间其他写入可能已经填充了跳表
+	// Other writes may have filled the list while it was being read
+	if sl.length != 0 {
+		sl.Unlock()
+		return ErrNotEmpty
+	}
+	zones := sl.zoneKeys()
+	accepted := entries[:0]
+	for _, entry := range entries {
+		if sl.guardKey(entry.Key, entry.Value) == nil {
+			accepted = append(accepted, entry)
+		}
+	}
+	accepted = sl.limitEntries(accepted, 0, func(K) bool { return false })
+	sl.load(accepted)
+	sl.evictOverflow()
+	sl.journalContent()
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	fireThresholds(events)
+	return err
+}
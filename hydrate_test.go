@@ -0,0 +1,141 @@
+package ranklist
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// entrySeq 按顺序产出 entries 的键值
+// entrySeq yields the keys and values of entries in order
+func entrySeq(entries []Entry[int, int]) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for _, entry := range entries {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+func TestHydrateFrom(t *testing.T) {
+	entries := make([]Entry[int, int], 100000)
+	for i := range entries {
+		entries[i] = Entry[int, int]{Key: rand.IntN(60000), Value: rand.IntN(1000)}
+	}
+	direct := New[int, int]()
+	for _, entry := range entries {
+		direct.Set(entry.Key, entry.Value)
+	}
+
+	sl := New[int, int]()
+	var reported []int
+	if err := sl.HydrateFromProgress(context.Background(), entrySeq(entries), 10000, func(loaded int) {
+		reported = append(reported, loaded)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(sl.Entries(), direct.Entries()) {
+		t.Fatal("the hydrated list differs from the one built by Set")
+	}
+	if len(reported) != 10 || reported[9] != len(entries) {
+		t.Fatalf("expected progress every 10000 entries, got %v", reported)
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHydrateFromCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	seq := func(yield func(int, int) bool) {
+		for i := 0; i < 100000; i++ {
+			if i == 5000 {
+				cancel()
+			}
+			if !yield(i, i%100) {
+				return
+			}
+		}
+	}
+
+	sl := New[int, int]()
+	if err := sl.HydrateFrom(ctx, seq); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if n := sl.Length(); n < 5000 || n >= 5000+hydrateCheckEvery {
+		t.Fatalf("expected the entries read before the cancellation, got %d", n)
+	}
+
+	// 只加载了一部分的跳表可以正常使用
+	// The partially loaded list is fully usable
+	sl.Set(-1, 1000)
+	sl.Del(0)
+	if entries := sl.Top(1); entries[0].Key != -1 {
+		t.Fatalf("expected -1 at the top, got %v", entries)
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHydrateFromRejects(t *testing.T) {
+	sl := New[int, int]()
+	sl.Set(1, 1)
+	if err := sl.HydrateFrom(context.Background(), entrySeq([]Entry[int, int]{{2, 2}})); !errors.Is(err, ErrNotEmpty) {
+		t.Fatalf("expected ErrNotEmpty, got %v", err)
+	}
+	if !slices.Equal(sl.Entries(), []Entry[int, int]{{1, 1}}) {
+		t.Fatalf("a rejected hydration must not touch the list, got %v", sl.Entries())
+	}
+
+	// 读取期间被其他写入填充的跳表同样被拒绝
+	// A list filled by another write while reading is rejected the same way
+	racing := New[int, int]()
+	seq := func(yield func(int, int) bool) {
+		racing.Set(9, 9)
+		yield(2, 2)
+	}
+	if err := racing.HydrateFrom(context.Background(), seq); !errors.Is(err, ErrNotEmpty) {
+		t.Fatalf("expected ErrNotEmpty, got %v", err)
+	}
+	if !slices.Equal(racing.Entries(), []Entry[int, int]{{9, 9}}) {
+		t.Fatalf("expected only the racing write, got %v", racing.Entries())
+	}
+
+	closed := New[int, int]()
+	closed.Close()
+	if err := closed.HydrateFrom(context.Background(), entrySeq(nil)); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestHydrateFromStore(t *testing.T) {
+	store := NewMemStore[string, int]()
+	for i, key := range []string{"a", "b", "c", "d"} {
+		store.Put(key, i*10)
+	}
+
+	// 加载的内容不会写回外部存储
+	// The loaded content is not written back to the store
+	store.Fail = func(op string, key string) error { return errStoreDown }
+	var reported []error
+	sl := New(
+		WithStore[string, int](store, true),
+		WithStoreErrorHandler[string, int](func(err error) { reported = append(reported, err) }),
+	)
+	if err := sl.HydrateFrom(context.Background(), store.All()); err != nil {
+		t.Fatal(err)
+	}
+	if len(reported) != 0 {
+		t.Fatalf("hydration wrote back to the store: %v", reported)
+	}
+	requireMirrored(t, sl, store)
+
+	store.Fail = nil
+	sl.Set("e", 5)
+	requireMirrored(t, sl, store)
+}
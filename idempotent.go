@@ -0,0 +1,96 @@
+package ranklist
+
+import "container/list"
+
+// defaultTokenWindow 未设置 WithTokenWindow 时去重窗口记住的令牌数
+// defaultTokenWindow is the number of tokens the dedup window remembers without WithTokenWindow
+const defaultTokenWindow = 4096
+
+// tokenCache 记住最近使用的令牌及其增量结果，超出容量时淘汰最久未使用的令牌，调用方需持有写锁
+// tokenCache remembers recently used tokens with the result of their increment,
+// evicting the least recently used token when full. The caller must hold the write lock
+type tokenCache[V Ordered] struct {
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+type tokenCacheItem[V Ordered] struct {
+	token string
+	value V
+}
+
+// WithTokenWindow 设置 IncrByIdempotent 的去重窗口记住的令牌数，默认为 4096，size 小于 1 时 panic
+// WithTokenWindow sets how many tokens the dedup window of IncrByIdempotent remembers, 4096 by default.
+// It panics if size is below 1
+func WithTokenWindow[K Ordered, V Ordered](size int) Option[K, V] {
+	if size < 1 {
+		panic("ranklist: token window size must be positive")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.tokenLimit = size
+	}
+}
+
+// IncrByIdempotent 与 IncrBy 相同，但以 token 标识这次提交：token 在去重窗口中出现过时不再累加，
+// 直接返回第一次提交时返回的值，因此客户端重试同一次提交得到完全相同的结果。
+// 第二个返回值说明增量是否已经生效（本次或之前带同一个 token 的调用），增量被拒绝时返回当前值和 false，且不记住 token，
+// 之后带同一个 token 的重试会重新尝试。
+// 窗口按最近使用淘汰，记住的令牌数见 WithTokenWindow；令牌被淘汰之后同一个 token 会被当作新的提交再次累加，
+// 因此窗口需要覆盖客户端的重试时长。令牌在整个跳表范围内唯一，与键无关。
+// 检查与累加在同一把写锁内完成，并发的相同 token 只会累加一次。窗口只保存在内存中，不会写入快照或日志
+// IncrByIdempotent behaves like IncrBy, but token identifies the submission: when the token is in the dedup window
+// nothing is added and the value returned by the first submission is returned again,
+// so a client retrying the same submission gets exactly the same result.
+// The second result reports whether the increment is in effect, applied by this call or an earlier one with the same token.
+// A rejected delta returns the current value and false and leaves the token unremembered, so a retry tries again.
+// The window evicts the least recently used token, see WithTokenWindow for its size;
+// once a token is evicted the same token counts as a new submission and is applied again,
+// so the window must cover how long clients keep retrying. Tokens are scoped to the whole list, not to a key.
+// The lookup and the increment happen under one write lock, so concurrent identical tokens apply exactly once.
+// The window lives in memory only and is never written to snapshots or the log
+func (sl *RankList[K, V]) IncrByIdempotent(key K, delta V, token string) (V, bool) {
+	value, err := sl.incrBy(key, delta, &token)
+	return value, err == nil
+}
+
+// tokenWindow 返回去重窗口，第一次使用时创建，调用方需持有写锁
+// tokenWindow returns the dedup window, creating it on first use. The caller must hold the write lock
+func (sl *RankList[K, V]) tokenWindow() *tokenCache[V] {
+	if sl.tokens == nil {
+		size := sl.tokenLimit
+		if size == 0 {
+			size = defaultTokenWindow
+		}
+		sl.tokens = &tokenCache[V]{size: size, order: list.New(), items: make(map[string]*list.Element)}
+	}
+	return sl.tokens
+}
+
+// get 返回令牌记住的结果，并将它标记为最近使用
+// get returns the result remembered for token and marks it as the most recently used
+func (c *tokenCache[V]) get(token string) (V, bool) {
+	elem, ok := c.items[token]
+	if !ok {
+		return ZeroValue[V](), false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*tokenCacheItem[V]).value, true
+}
+
+// put 记住令牌的结果，超出容量时淘汰最久未使用的令牌
+// put remembers the result of token, evicting the least recently used token when full
+func (c *tokenCache[V]) put(token string, value V) {
+	if c.order.Len() >= c.size {
+		// 复用最久未使用的元素，避免每次淘汰都重新分配
+		// Reuse the least recently used element so evictions do not allocate
+		oldest := c.order.Back()
+		item := oldest.Value.(*tokenCacheItem[V])
+		delete(c.items, item.token)
+		item.token, item.value = token, value
+		c.order.MoveToFront(oldest)
+		c.items[token] = oldest
+		return
+	}
+	c.items[token] = c.order.PushFront(&tokenCacheItem[V]{token: token, value: value})
+}
@@ -0,0 +1,85 @@
+package ranklist
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestIncrByIdempotent(t *testing.T) {
+	sl := New[string, int]()
+	if value, ok := sl.IncrByIdempotent("a", 10, "t1"); !ok || value != 10 {
+		t.Fatalf("expected 10, got %d, %v", value, ok)
+	}
+	sl.IncrBy("a", 5)
+
+	// 重试返回第一次的结果且不再累加 / A retry returns the first result and adds nothing
+	if value, ok := sl.IncrByIdempotent("a", 10, "t1"); !ok || value != 10 {
+		t.Fatalf("expected the retry to return 10, got %d, %v", value, ok)
+	}
+	if value, _ := sl.Get("a"); value != 15 {
+		t.Fatalf("expected the retry to apply nothing, got %d", value)
+	}
+	if value, ok := sl.IncrByIdempotent("a", 10, "t2"); !ok || value != 25 {
+		t.Fatalf("expected a new token to apply, got %d, %v", value, ok)
+	}
+}
+
+func TestIncrByIdempotentRejected(t *testing.T) {
+	sl := New(WithMaxDelta[string, int](5))
+	if value, ok := sl.IncrByIdempotent("a", 10, "t"); ok || value != 0 {
+		t.Fatalf("expected the delta to be rejected, got %d, %v", value, ok)
+	}
+	// 被拒绝的令牌不被记住 / A rejected token is not remembered
+	if value, ok := sl.IncrByIdempotent("a", 3, "t"); !ok || value != 3 {
+		t.Fatalf("expected the retry to apply, got %d, %v", value, ok)
+	}
+}
+
+func TestIncrByIdempotentWindow(t *testing.T) {
+	sl := New(WithTokenWindow[string, int](2))
+	sl.IncrByIdempotent("a", 1, "t1")
+	sl.IncrByIdempotent("a", 1, "t2")
+	sl.IncrByIdempotent("a", 1, "t1") // t1 成为最近使用的令牌 / t1 becomes the most recently used
+	sl.IncrByIdempotent("a", 1, "t3") // 淘汰 t2 / evicts t2
+
+	if value, _ := sl.IncrByIdempotent("a", 1, "t1"); value != 1 {
+		t.Fatalf("expected t1 to still be remembered at 1, got %d", value)
+	}
+	// 被淘汰的令牌会再次累加 / An evicted token is applied again
+	if value, _ := sl.IncrByIdempotent("a", 1, "t2"); value != 4 {
+		t.Fatalf("expected the evicted t2 to apply again, got %d", value)
+	}
+	if len(sl.tokens.items) != 2 || sl.tokens.order.Len() != 2 {
+		t.Fatalf("expected the window to hold 2 tokens, got %d", len(sl.tokens.items))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for an empty window")
+		}
+	}()
+	WithTokenWindow[string, int](0)
+}
+
+func TestIncrByIdempotentConcurrent(t *testing.T) {
+	sl := New[string, int]()
+	var wg sync.WaitGroup
+	results := make([]int, 64)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = sl.IncrByIdempotent("a", 7, "same")
+		}()
+	}
+	wg.Wait()
+
+	for i, value := range results {
+		if value != 7 {
+			t.Fatalf("goroutine %d: expected 7, got %d", i, value)
+		}
+	}
+	if value, _ := sl.Get("a"); value != 7 {
+		t.Fatalf("expected the token to apply exactly once, got %d", value)
+	}
+}
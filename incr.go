@@ -0,0 +1,223 @@
+package ranklist
+
+import (
+	"maps"
+	"slices"
+)
+
+// WithMaxDelta 限制单次增量的绝对值不能超过 max，超出时 IncrBy 不做修改，IncrByChecked 返回 ErrDeltaTooLarge
+// WithMaxDelta limits the absolute value of a single increment to max.
+// Larger deltas leave the entry untouched, IncrByChecked reports them with ErrDeltaTooLarge
+func WithMaxDelta[K Ordered, V Ordered](max V) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.maxDelta = max
+		sl.hasMaxDelta = true
+	}
+}
+
+// IncrBy 将键的值增加 delta 并返回新值，键不存在时从零值开始累加。
+// 增量被拒绝时不做任何修改，返回当前值
+// IncrBy increments the value of key by delta and returns the new value,
+// a missing key starts from the zero value.
+// When the delta is rejected nothing is changed and the current value is returned
+func (sl *RankList[K, V]) IncrBy(key K, delta V) V {
+	value, _ := sl.IncrByChecked(key, delta)
+	return value
+}
+
+// IncrByChecked 与 IncrBy 相同，但在增量被拒绝时返回错误
+// IncrByChecked behaves like IncrBy, but returns an error when the delta is rejected
+func (sl *RankList[K, V]) IncrByChecked(key K, delta V) (V, error) {
+	return sl.incrBy(key, delta, nil)
+}
+
+// incrBy 实现 IncrByChecked，token 不为 nil 时先在去重窗口中查找它，生效的增量结果记入窗口
+// incrBy implements IncrByChecked. With a non-nil token it first looks the token up in the dedup window,
+// and the result of an applied increment is remembered there
+func (sl *RankList[K, V]) incrBy(key K, delta V, token *string) (V, error) {
+	sl.Lock()
+	if token != nil {
+		if value, seen := sl.tokenWindow().get(*token); seen {
+			sl.Unlock()
+			return value, nil
+		}
+	}
+	var value V
+	node, exists := sl.lookup(key)
+	if exists {
+		value = node.data.Value
+	}
+	if sl.closed {
+		sl.Unlock()
+		return value, ErrClosed
+	}
+	if !sl.deltaAllowed(delta) {
+		sl.Unlock()
+		return value, ErrDeltaTooLarge
+	}
+	if err := sl.guardFull(key, value+delta, sl.length); err != nil {
+		sl.Unlock()
+		return value, err
+	}
+	if err := sl.guardValue(value, value+delta); err != nil {
+		sl.Unlock()
+		return value, err
+	}
+	if err := sl.persistWrite(key, value+delta); err != nil {
+		sl.Unlock()
+		return value, err
+	}
+
+	value += delta
+	probes := sl.probeThresholds(key)
+	gen := sl.gen
+	result := sl.store(key, value, keyspaceZincr)
+	if token != nil {
+		sl.tokens.put(*token, value)
+	}
+	events := sl.thresholdEvents(key, probes)
+	var trace TraceEvent[K]
+	if sl.tracer != nil {
+		trace = sl.traceSet(key, gen)
+	}
+	sl.Unlock()
+
+	result.count(sl.vars)
+	if sl.tracer != nil {
+		sl.tracer(trace)
+	}
+	fireThresholds(events)
+	return value, nil
+}
+
+// AddAll 在一把写锁内将 deltas 中每个键的值增加对应的增量，不存在的键以增量作为初始值创建，返回每个键的新值。
+// 每个键的处理与 IncrBy 相同：增量被 WithMaxDelta 拒绝或新值被 WithValueGuard 拒绝的键不做修改，结果中为它的当前值。
+// 键按从小到大的顺序处理，与 map 的遍历顺序无关，因此 WithHardLimit 拒绝哪些键、WithMaxSize 淘汰哪些条目是确定的
+// AddAll increments every key of deltas by its delta under one write lock, missing keys are created at the delta,
+// and returns the new value of every key.
+// Each key is handled like IncrBy: keys whose delta is rejected by WithMaxDelta or whose new value is rejected
+// by WithValueGuard are left untouched and reported at their current value.
+// Keys are applied in ascending order rather than map iteration order,
+// so which keys WithHardLimit rejects and which entries WithMaxSize evicts is deterministic
+func (sl *RankList[K, V]) AddAll(deltas map[K]V) map[K]V {
+	values := make(map[K]V, len(deltas))
+	sl.Lock()
+	if sl.closed {
+		defer sl.Unlock()
+		for key := range deltas {
+			if node, exists := sl.lookup(key); exists {
+				values[key] = node.data.Value
+			} else {
+				values[key] = ZeroValue[V]()
+			}
+		}
+		return values
+	}
+	zones := sl.zoneKeys()
+	results := make([]storeResult, 0, len(deltas))
+	var traces []TraceEvent[K]
+	for _, key := range slices.Sorted(maps.Keys(deltas)) {
+		delta := deltas[key]
+		var value V
+		node, exists := sl.lookup(key)
+		if exists {
+			value = node.data.Value
+		}
+		values[key] = value
+		if !sl.deltaAllowed(delta) || sl.guardFull(key, value+delta, sl.length) != nil ||
+			sl.guardValue(value, value+delta) != nil {
+			continue
+		}
+		if sl.persistWrite(key, value+delta) != nil {
+			continue
+		}
+
+		value += delta
+		values[key] = value
+		gen := sl.gen
+		results = append(results, sl.store(key, value, keyspaceZincr))
+		if sl.tracer != nil {
+			traces = append(traces, sl.traceSet(key, gen))
+		}
+	}
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	for _, result := range results {
+		result.count(sl.vars)
+	}
+	for _, trace := range traces {
+		sl.tracer(trace)
+	}
+	fireThresholds(events)
+	return values
+}
+
+// IncrByClamped 将键的值增加 delta 后限制在 [min, max] 之内并返回新值，键不存在时从零值开始累加。
+// 第二个返回值说明是否发生了截断：-1 表示截断到 min，1 表示截断到 max，0 表示没有截断。
+// 累加溢出类型范围时按方向截断到对应的边界，因此并发的增量永远不会让值越界。
+// 截断后的值与当前值相同时不做任何修改；增量被 WithMaxDelta 拒绝或新值被 WithValueGuard 拒绝时同样不做修改，返回当前值和 0。
+// min 大于 max 时 panic
+// IncrByClamped increments the value of key by delta, clamps the result into [min, max] and returns it,
+// a missing key starts from the zero value.
+// The second result tells whether clamping happened: -1 for clamped to min, 1 for clamped to max, 0 otherwise.
+// A sum overflowing the type is clamped to the bound in its direction, so concurrent increments never leave the range.
+// Nothing is changed when the clamped value equals the current one, nor when WithMaxDelta rejects the delta
+// or WithValueGuard rejects the new value, in which case the current value and 0 are returned. It panics when min is greater than max
+func (sl *RankList[K, V]) IncrByClamped(key K, delta V, min V, max V) (V, int) {
+	if min > max {
+		panic("ranklist: clamp min must not exceed max")
+	}
+
+	sl.Lock()
+	var value V
+	node, exists := sl.lookup(key)
+	if exists {
+		value = node.data.Value
+	}
+	if sl.closed || !sl.deltaAllowed(delta) {
+		sl.Unlock()
+		return value, 0
+	}
+
+	// 结果的变化方向与 delta 的符号相反即发生了溢出
+	// A sum that moves against the sign of delta has overflowed
+	zero := ZeroValue[V]()
+	sum, clamped := value+delta, 0
+	switch {
+	case delta > zero && sum < value:
+		sum, clamped = max, 1
+	case delta < zero && sum > value:
+		sum, clamped = min, -1
+	case sum > max:
+		sum, clamped = max, 1
+	case sum < min:
+		sum, clamped = min, -1
+	}
+	if exists && sum == value {
+		sl.Unlock()
+		return value, clamped
+	}
+	if sl.guardFull(key, sum, sl.length) != nil || sl.guardValue(value, sum) != nil || sl.persistWrite(key, sum) != nil {
+		sl.Unlock()
+		return value, 0
+	}
+	sl.setAndUnlock(key, sum, keyspaceZincr)
+	return sum, clamped
+}
+
+// deltaAllowed 判断增量是否在 WithMaxDelta 的限制之内。
+// 负增量通过 delta+max < 0 判断，避免对无符号或字符串类型取反
+// deltaAllowed reports whether delta is within the WithMaxDelta limit.
+// Negative deltas are checked with delta+max < 0, which avoids negating unsigned or string types
+func (sl *RankList[K, V]) deltaAllowed(delta V) bool {
+	if !sl.hasMaxDelta {
+		return true
+	}
+	zero := ZeroValue[V]()
+	if delta > sl.maxDelta || (delta < zero && delta+sl.maxDelta < zero) {
+		sl.vars.add(opRejected)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,223 @@
+package ranklist
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"testing"
+)
+
+func TestIncrBy(t *testing.T) {
+	sl := New[string, int]()
+
+	if value := sl.IncrBy("a", 5); value != 5 {
+		t.Errorf("expected 5 for a missing key, got %d", value)
+	}
+	if value := sl.IncrBy("a", -2); value != 3 {
+		t.Errorf("expected 3 after decrement, got %d", value)
+	}
+
+	sl.Set("b", 4)
+	if rank, _ := sl.Rank("a"); rank != 1 {
+		t.Errorf("expected rank 1, got %d", rank)
+	}
+	sl.IncrBy("a", 2)
+	if rank, _ := sl.Rank("a"); rank != 2 {
+		t.Errorf("expected rank 2 after increment, got %d", rank)
+	}
+}
+
+func TestIncrByMaxDelta(t *testing.T) {
+	sl := New(WithMaxDelta[string, int](10))
+	sl.Set("a", 100)
+	sl.Set("b", 105)
+
+	testCases := []struct {
+		delta    int
+		expected int
+		err      error
+	}{
+		{10, 110, nil},
+		{11, 110, ErrDeltaTooLarge},
+		{-10, 100, nil},
+		{-11, 100, ErrDeltaTooLarge},
+		{0, 100, nil},
+	}
+
+	for _, tc := range testCases {
+		value, err := sl.IncrByChecked("a", tc.delta)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("delta %d: expected error %v, got %v", tc.delta, tc.err, err)
+		}
+		if value != tc.expected {
+			t.Errorf("delta %d: expected value %d, got %d", tc.delta, tc.expected, value)
+		}
+	}
+
+	if value := sl.IncrBy("a", 100); value != 100 {
+		t.Errorf("rejected IncrBy should return the current value 100, got %d", value)
+	}
+	if rank, _ := sl.Rank("a"); rank != 1 {
+		t.Errorf("rank should be unchanged after rejection, got %d", rank)
+	}
+}
+
+func TestIncrByMaxDeltaUnsigned(t *testing.T) {
+	sl := New(WithMaxDelta[string, uint](5))
+
+	if _, err := sl.IncrByChecked("a", 5); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := sl.IncrByChecked("a", 6); !errors.Is(err, ErrDeltaTooLarge) {
+		t.Errorf("expected ErrDeltaTooLarge, got %v", err)
+	}
+	if _, err := sl.IncrByChecked("b", 6); !errors.Is(err, ErrDeltaTooLarge) {
+		t.Errorf("expected ErrDeltaTooLarge, got %v", err)
+	}
+	if _, exists := sl.Get("b"); exists {
+		t.Errorf("rejected increment should not create the key")
+	}
+}
+
+func TestIncrByClamped(t *testing.T) {
+	sl := New[string, int]()
+	cases := []struct {
+		delta   int
+		value   int
+		clamped int
+	}{
+		{500, 500, 0},
+		{999999, 999999, 1},
+		{1, 999999, 1},
+		{-5, 999994, 0},
+		{-2000000, 0, -1},
+		{-1, 0, -1},
+	}
+	for i, c := range cases {
+		if value, clamped := sl.IncrByClamped("a", c.delta, 0, 999999); value != c.value || clamped != c.clamped {
+			t.Errorf("step %d: expected %d, %d, got %d, %d", i, c.value, c.clamped, value, clamped)
+		}
+	}
+
+	// 截断到当前值时不做任何修改
+	// Clamping to the current value changes nothing
+	version := sl.Version()
+	sl.IncrByClamped("a", -1, 0, 999999)
+	if sl.Version() != version {
+		t.Errorf("clamping to the current value should not write")
+	}
+}
+
+func TestIncrByClampedOverflow(t *testing.T) {
+	sl := New[string, int8]()
+	sl.Set("a", 120)
+	if value, clamped := sl.IncrByClamped("a", 100, -100, 125); value != 125 || clamped != 1 {
+		t.Errorf("expected 125, 1, got %d, %d", value, clamped)
+	}
+	sl.Set("b", -120)
+	if value, clamped := sl.IncrByClamped("b", -100, -128, 0); value != -128 || clamped != -1 {
+		t.Errorf("expected -128, -1, got %d, %d", value, clamped)
+	}
+
+	unsigned := New[string, uint64]()
+	unsigned.Set("a", math.MaxUint64-1)
+	if value, clamped := unsigned.IncrByClamped("a", 10, 0, math.MaxUint64); value != math.MaxUint64 || clamped != 1 {
+		t.Errorf("expected the maximum, got %d, %d", value, clamped)
+	}
+}
+
+func TestIncrByClampedConcurrent(t *testing.T) {
+	sl := New[string, int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if value, _ := sl.IncrByClamped("a", 7, 0, 1000); value > 1000 {
+					t.Errorf("value %d escaped the bound", value)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if value, _ := sl.Get("a"); value != 1000 {
+		t.Fatalf("expected saturation at 1000, got %d", value)
+	}
+}
+
+func TestIncrByClampedInvalidBounds(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic")
+		}
+	}()
+	New[string, int]().IncrByClamped("a", 1, 10, 0)
+}
+
+func TestAddAll(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		batched := New[int, int](WithMaxDelta[int, int](90))
+		looped := New[int, int](WithMaxDelta[int, int](90))
+		for i := 0; i < 50; i++ {
+			key, value := rand.IntN(100), rand.IntN(1000)
+			batched.Set(key, value)
+			looped.Set(key, value)
+		}
+
+		deltas := make(map[int]int)
+		for i := 0; i < 60; i++ {
+			deltas[rand.IntN(100)] = rand.IntN(201) - 100
+		}
+		values := batched.AddAll(deltas)
+
+		expected := make(map[int]int, len(deltas))
+		for key, delta := range deltas {
+			expected[key] = looped.IncrBy(key, delta)
+		}
+		if !maps.Equal(values, expected) {
+			t.Fatalf("round %d: expected %v, got %v", round, expected, values)
+		}
+		if !batched.Equal(looped) {
+			t.Fatalf("round %d: AddAll and the IncrBy loop diverged", round)
+		}
+	}
+}
+
+func TestAddAllGuard(t *testing.T) {
+	sl := New(WithValueGuard[string, int](func(old, new int) error {
+		if new < 0 {
+			return errors.New("negative")
+		}
+		return nil
+	}))
+	sl.Set("a", 5)
+
+	values := sl.AddAll(map[string]int{"a": -10, "b": 3, "c": -1})
+	if !maps.Equal(values, map[string]int{"a": 5, "b": 3, "c": 0}) {
+		t.Fatalf("unexpected values %v", values)
+	}
+	requireModel(t, sl, map[string]int{"a": 5, "b": 3})
+}
+
+func TestAddAllOrder(t *testing.T) {
+	deltas := map[string]int{"d": 1, "b": 1, "c": 1, "a": 1}
+	for range 20 {
+		// 按键的顺序处理，超出硬上限的总是较大的键
+		// Keys are applied in order, the larger keys are always the ones past the hard limit
+		limited := New(WithHardLimit[string, int](2))
+		values := limited.AddAll(deltas)
+		if !maps.Equal(values, map[string]int{"a": 1, "b": 1, "c": 0, "d": 0}) {
+			t.Fatalf("unexpected values under the hard limit %v", values)
+		}
+		requireModel(t, limited, map[string]int{"a": 1, "b": 1})
+
+		// 值相同时淘汰排在最前的键，按顺序插入后留下的总是最后两个键
+		// Ties evict the first key, inserting in order always keeps the last two keys
+		bounded := New(WithMaxSize[string, int](2))
+		bounded.AddAll(deltas)
+		requireModel(t, bounded, map[string]int{"c": 1, "d": 1})
+	}
+}
@@ -0,0 +1,73 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"testing"
+)
+
+func TestUpdateInPlace(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   int
+		inPlace bool
+	}{
+		{"leader grows", "x", 61, true},
+		{"last drops", "a", 5, true},
+		{"middle between neighbors", "b", 25, true},
+		{"equal to predecessor, key sorts after", "b", 10, true},
+		{"equal to successor, key sorts before", "b", 30, true},
+		{"passes successor", "b", 31, false},
+		{"passes predecessor", "b", 9, false},
+		{"equal to successor, key sorts after", "z", 50, false},
+		{"equal to predecessor, key sorts before", "x", 50, false},
+		{"unchanged", "b", 20, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// z 的键排在后继 y 之后，x 的键排在前驱 y 之前，用于检查相同值时按键翻转顺序的情况
+			// z's key sorts after its successor y and x's key before its predecessor y,
+			// covering ties where the key flips the order
+			model := map[string]int{"a": 10, "b": 20, "c": 30, "z": 40, "y": 50, "x": 60}
+			sl := New[string, int]()
+			for key, value := range model {
+				sl.Set(key, value)
+			}
+
+			// 重新插入会记录 finger，原地更新不会，借此判断走了哪条路径
+			// A reinsert records the finger while an in-place update does not, which tells the paths apart
+			sl.finger.invalidate()
+			sl.Set(tt.key, tt.value)
+			model[tt.key] = tt.value
+
+			if inPlace := !sl.finger.valid; inPlace != tt.inPlace {
+				t.Errorf("expected in place %v, got %v", tt.inPlace, inPlace)
+			}
+			requireSpans(t, sl)
+			requireBackward(t, sl)
+			requireModel(t, sl, model)
+		})
+	}
+}
+
+func TestUpdateInPlaceChurn(t *testing.T) {
+	sl := New[string, int]()
+	model := make(map[string]int)
+	for i := 0; i < 300; i++ {
+		key := "key" + strconv.Itoa(i)
+		sl.Set(key, i*10)
+		model[key] = i * 10
+	}
+
+	for i := 0; i < 20000; i++ {
+		key := "key" + strconv.Itoa(rand.IntN(300))
+		delta := rand.IntN(25) - 12
+		model[key] += delta
+		sl.IncrBy(key, delta)
+	}
+	requireSpans(t, sl)
+	requireBackward(t, sl)
+	requireModel(t, sl, model)
+}
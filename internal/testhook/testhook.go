@@ -0,0 +1,11 @@
+// Package testhook 连接 ranklist 与 ranklisttest，让测试辅助代码可以触及跳表的内部结构而不必将其导出
+// Package testhook connects ranklist with ranklisttest, letting test helpers reach the internal structure
+// of a skip list without exporting it
+package testhook
+
+// CorruptSpan 由 ranklist 在初始化时设置，将 list 中排名为 rank 的节点在第 level 层（从 1 开始）的跨度加一，
+// list 不是 *ranklist.RankList、排名不存在或节点没有这一层时返回 false
+// CorruptSpan is set by ranklist during initialization and adds one to the span of the node ranked rank in list
+// on level level, counted from 1. It returns false when list is not a *ranklist.RankList,
+// the rank does not exist or the node is not that tall
+var CorruptSpan func(list any, rank, level int) bool
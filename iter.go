@@ -0,0 +1,115 @@
+package ranklist
+
+import "iter"
+
+// iterChunkSize 迭代器每次持有读锁读取的条目数
+// iterChunkSize is how many entries the iterators read per read lock acquisition
+const iterChunkSize = 256
+
+// All 返回按排名顺序（值从小到大）产出全部键值对的迭代器，可以提前 break。
+// 遍历按块进行，每块在读锁内读取，产出时不持有锁，因此循环体可以调用跳表的任何方法，包括写方法。
+// 一致性模型与 EncodeJSON 相同：每一块内部是一致的，块与块之间可能发生写入，
+// 遍历期间移动到游标另一侧的键可能被跳过或重复产出，未被修改的键恰好产出一次
+// All returns an iterator yielding every key-value pair in rank order, from the smallest value up,
+// and honors an early break.
+// The walk proceeds in chunks, each read under the read lock which is not held while yielding,
+// so the loop body may call any method of the list, writes included.
+// The consistency model is the one of EncodeJSON: each chunk is internally consistent but writes may land between chunks,
+// keys that move across the cursor during the walk may be skipped or yielded twice,
+// keys that are not modified are yielded exactly once
+func (sl *RankList[K, V]) All() iter.Seq2[K, V] {
+	return sl.ascend(func() *Node[K, V] {
+		return sl.header.forward[0]
+	})
+}
+
+// IterFromScore 返回从第一个值大于等于 value 的条目开始、按排名顺序向值更大的方向产出键值对的迭代器，可以提前 break。
+// 起点通过一次跨度下降定位，不会访问值小于 value 的条目，因此产出 k 个条目耗时 O(log n + k)。
+// 加锁方式和一致性模型与 All 相同
+// IterFromScore returns an iterator starting at the first entry whose value is at least value
+// and yielding key-value pairs in rank order towards larger values, honoring an early break.
+// The starting point is located with one span descent and entries below value are never visited,
+// so yielding k entries costs O(log n + k). Locking and the consistency model are the ones of All
+func (sl *RankList[K, V]) IterFromScore(value V) iter.Seq2[K, V] {
+	return sl.ascend(func() *Node[K, V] {
+		curr, _ := sl.descendBefore(value, false)
+		return curr.forward[0]
+	})
+}
+
+// ascend 返回从 first 返回的节点开始按排名顺序分块产出键值对的迭代器，first 在读锁内调用
+// ascend returns an iterator yielding key-value pairs in rank order in chunks,
+// starting at the node returned by first, which is called under the read lock
+func (sl *RankList[K, V]) ascend(first func() *Node[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		chunk := make([]Entry[K, V], 0, iterChunkSize)
+		for start := true; start || len(chunk) == iterChunkSize; start = false {
+			sl.RLock()
+			var curr *Node[K, V]
+			if start {
+				curr = first()
+			} else {
+				curr = sl.seekAfter(chunk[len(chunk)-1])
+			}
+			chunk = chunk[:0]
+			for ; curr != nil && len(chunk) < iterChunkSize; curr = curr.forward[0] {
+				chunk = append(chunk, curr.data)
+			}
+			sl.RUnlock()
+
+			for _, entry := range chunk {
+				if !yield(entry.Key, entry.Value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Backward 返回按值从大到小产出全部键值对的迭代器，顺序与 All 完全相反，值相同的条目也按相反的顺序产出，可以提前 break。
+// 每块从上一块最后一个条目之前的节点开始沿后向指针读取，不需要复制整个跳表，额外内存只有一块的大小。
+// 加锁方式和一致性模型与 All 相同
+// Backward returns an iterator yielding every key-value pair from the largest value down,
+// in exactly the reverse order of All, tied entries included, and honors an early break.
+// Every chunk starts at the node preceding the last entry of the previous chunk and follows the backward pointers,
+// so the list is never copied as a whole and the extra memory is a single chunk.
+// Locking and the consistency model are the ones of All
+func (sl *RankList[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		chunk := make([]Entry[K, V], 0, iterChunkSize)
+		for first := true; first || len(chunk) == iterChunkSize; first = false {
+			sl.RLock()
+			curr := sl.tail
+			if !first {
+				curr = sl.seekBefore(chunk[len(chunk)-1])
+			}
+			chunk = chunk[:0]
+			for ; curr != nil && len(chunk) < iterChunkSize; curr = curr.backward {
+				chunk = append(chunk, curr.data)
+			}
+			sl.RUnlock()
+
+			for _, entry := range chunk {
+				if !yield(entry.Key, entry.Value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// seekBefore 返回排在 entry 之前的最后一个节点，entry 本身不必在跳表中，没有这样的节点时返回 nil，调用方需持有锁
+// seekBefore returns the last node sorting before entry, which does not need to be in the list itself,
+// or nil when there is none. The caller must hold the lock
+func (sl *RankList[K, V]) seekBefore(entry Entry[K, V]) *Node[K, V] {
+	curr := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for curr.forward[i] != nil && sl.compare(curr.forward[i].data, entry) < 0 {
+			curr = curr.forward[i]
+		}
+	}
+	if curr == sl.header {
+		return nil
+	}
+	return curr
+}
@@ -0,0 +1,141 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// collect 将迭代器产出的键值对收集为条目切片
+// collect gathers the pairs yielded by an iterator into entries
+func collect[K Ordered, V Ordered](seq func(yield func(K, V) bool)) []Entry[K, V] {
+	entries := make([]Entry[K, V], 0)
+	for key, value := range seq {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+	}
+	return entries
+}
+
+func TestAllAndBackward(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 3*iterChunkSize+17; i++ {
+		sl.Set(i, rand.IntN(50))
+	}
+	forward := sl.Range(1, sl.Length()+1)
+
+	if entries := collect(sl.All()); !slices.Equal(entries, forward) {
+		t.Fatalf("All differs from Range")
+	}
+	reversed := slices.Clone(forward)
+	slices.Reverse(reversed)
+	if entries := collect(sl.Backward()); !slices.Equal(entries, reversed) {
+		t.Fatalf("Backward differs from the reversed Range")
+	}
+}
+
+func TestIteratorEarlyBreak(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 20)
+	sl.Set("d", 30)
+
+	var keys []string
+	for key := range sl.Backward() {
+		keys = append(keys, key)
+		if key == "c" {
+			break
+		}
+	}
+	if !slices.Equal(keys, []string{"d", "c"}) {
+		t.Fatalf("expected [d c], got %v", keys)
+	}
+
+	keys = keys[:0]
+	for key := range sl.All() {
+		keys = append(keys, key)
+		if len(keys) == 2 {
+			break
+		}
+	}
+	if !slices.Equal(keys, []string{"a", "b"}) {
+		t.Fatalf("expected [a b], got %v", keys)
+	}
+}
+
+func TestIteratorEmpty(t *testing.T) {
+	sl := New[string, int]()
+	if len(collect(sl.All())) != 0 || len(collect(sl.Backward())) != 0 {
+		t.Fatalf("expected no entries from an empty list")
+	}
+}
+
+func TestIteratorWritesInBody(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 2*iterChunkSize; i++ {
+		sl.Set(i, i)
+	}
+
+	// 循环体内的写入不会死锁，未修改的键恰好产出一次
+	// Writes from the loop body do not deadlock, and unmodified keys are yielded exactly once
+	seen := make(map[int]int)
+	for key := range sl.Backward() {
+		seen[key]++
+		if key%2 == 0 {
+			sl.Del(key)
+		}
+	}
+	for key, count := range seen {
+		if count != 1 {
+			t.Fatalf("key %d yielded %d times", key, count)
+		}
+	}
+	if len(seen) != 2*iterChunkSize || sl.Length() != iterChunkSize {
+		t.Fatalf("expected every key once and half of them deleted, got %d and %d", len(seen), sl.Length())
+	}
+}
+
+func TestIterFromScore(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 20)
+	sl.Set("d", 20)
+	sl.Set("e", 30)
+	all := sl.Range(1, 6)
+
+	if entries := collect(sl.IterFromScore(-5)); !slices.Equal(entries, all) {
+		t.Fatalf("a threshold below the minimum should yield everything, got %v", entries)
+	}
+	if entries := collect(sl.IterFromScore(31)); len(entries) != 0 {
+		t.Fatalf("a threshold above the maximum should yield nothing, got %v", entries)
+	}
+	if entries := collect(sl.IterFromScore(20)); !slices.Equal(entries, all[1:]) {
+		t.Fatalf("a threshold on a tie block should start at its first member, got %v", entries)
+	}
+	if entries := collect(sl.IterFromScore(21)); !slices.Equal(entries, all[4:]) {
+		t.Fatalf("expected only e, got %v", entries)
+	}
+
+	var keys []string
+	for key := range sl.IterFromScore(15) {
+		keys = append(keys, key)
+		if len(keys) == 2 {
+			break
+		}
+	}
+	if !slices.Equal(keys, []string{"b", "c"}) {
+		t.Fatalf("expected [b c], got %v", keys)
+	}
+}
+
+func TestIterFromScoreChunks(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 3*iterChunkSize; i++ {
+		sl.Set(i, i/3)
+	}
+	expected := sl.RangeByScore(100, 1<<30)
+	if entries := collect(sl.IterFromScore(100)); !slices.Equal(entries, expected) {
+		t.Fatalf("expected %d entries across chunks, got %d", len(expected), len(entries))
+	}
+}
@@ -0,0 +1,118 @@
+package ranklist
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// jsonChunkSize 流式编码 JSON 时每次持有读锁读取的条目数
+// jsonChunkSize is how many entries are read per read lock acquisition when streaming JSON
+const jsonChunkSize = 1024
+
+// MarshalJSON 将跳表按排名顺序编码为 {"key":..,"value":..} 对象数组，
+// 设置了 WithKeyFormatter 或 WithValueFormatter 时对应的字段编码为格式化后的字符串，
+// 设置了 WithJSONFieldNames 或 WithEntryMarshaler 时条目按它们决定的形式编码，Reserve 插入的占位条目不编码
+// MarshalJSON encodes the skip list as an array of {"key":..,"value":..} objects in rank order,
+// a field is encoded as its formatted string when WithKeyFormatter or WithValueFormatter is set,
+// and entries take the shape decided by WithJSONFieldNames or WithEntryMarshaler when set.
+// Placeholders inserted by Reserve are left out
+func (sl *RankList[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(sl.codec.jsonEntries(sl.savedEntries()))
+}
+
+// UnmarshalJSON 从对象数组解码并重建跳表，原有内容会被整体替换，重复的键以最后一次出现的值为准。
+// 设置了 WithKeyParser 或 WithValueParser 时对应的字段必须是字符串，由解析函数转换；
+// 设置了 WithJSONFieldNames 时按其字段名读取
+// UnmarshalJSON decodes an array of objects and rebuilds the skip list.
+// Existing contents are replaced, duplicate keys keep the value of their last occurrence.
+// With WithKeyParser or WithValueParser set the corresponding field must be a string, converted by the parser,
+// and with WithJSONFieldNames set the fields are read under its names
+func (sl *RankList[K, V]) UnmarshalJSON(data []byte) error {
+	entries, err := sl.codec.unmarshalEntries(data)
+	if err != nil {
+		return err
+	}
+
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return ErrClosed
+	}
+	zones := sl.zoneKeys()
+	sl.load(entries)
+	sl.evictOverflow()
+	sl.journalReset()
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	fireThresholds(events)
+	return nil
+}
+
+// EncodeJSON 将跳表以与 MarshalJSON 相同的格式流式写入 w，不会在内存中构建完整的字节切片。
+// 遍历按块进行，每块在读锁内读取，写入时释放读锁，下一块从上一块最后一个条目之后继续，因此写操作不会被整个导出阻塞。
+// 一致性模型：每一块内部是一致的，但块与块之间可能发生写入；
+// 导出期间移动到游标另一侧的键可能被跳过或重复输出，未被修改的键恰好输出一次。与 MarshalJSON 相同不输出占位条目
+// EncodeJSON streams the skip list to w in the same format as MarshalJSON without building the whole byte slice.
+// The walk proceeds in chunks, each read under the read lock which is released while writing,
+// and every chunk resumes right after the last entry of the previous one, so writers are not starved for the whole export.
+// Consistency model: each chunk is internally consistent but writes may land between chunks,
+// keys that move across the cursor during the export may be skipped or emitted twice,
+// keys that are not modified are emitted exactly once. Placeholders are left out like in MarshalJSON
+func (sl *RankList[K, V]) EncodeJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if err := bw.WriteByte('['); err != nil {
+		return err
+	}
+
+	chunk := make([]Entry[K, V], 0, jsonChunkSize)
+	var cursor Entry[K, V]
+	started := false
+	first := true
+	rank := 0
+	for {
+		sl.RLock()
+		curr := sl.header.forward[0]
+		if started {
+			curr = sl.seekAfter(cursor)
+		}
+		chunk = chunk[:0]
+		scanned := 0
+		for ; curr != nil && scanned < jsonChunkSize; curr = curr.forward[0] {
+			cursor = curr.data
+			scanned++
+			if !curr.placeholder {
+				chunk = append(chunk, curr.data)
+			}
+		}
+		sl.RUnlock()
+		started = true
+
+		for _, entry := range chunk {
+			if !first {
+				if err := bw.WriteByte(','); err != nil {
+					return err
+				}
+			}
+			first = false
+
+			rank++
+			data, err := json.Marshal(sl.codec.jsonEntry(entry, rank))
+			if err != nil {
+				return err
+			}
+			if _, err := bw.Write(data); err != nil {
+				return err
+			}
+		}
+		if scanned < jsonChunkSize {
+			break
+		}
+	}
+
+	if err := bw.WriteByte(']'); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,200 @@
+package ranklist
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestEntryMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(Entry[string, int]{Key: "a", Value: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"key":"a","value":1}` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("b", 2)
+	sl.Set("a", 1)
+
+	data, err := json.Marshal(sl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `[{"key":"a","value":1},{"key":"b","value":2}]` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+
+	data, err = json.Marshal(New[string, int]())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `[]` {
+		t.Errorf("unexpected JSON %s for an empty list", data)
+	}
+}
+
+func testJSONRoundTrip[K Ordered, V Ordered](t *testing.T, sl *RankList[K, V]) {
+	data, err := json.Marshal(sl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded := New[K, V]()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := sl.Range(1, sl.Length()+1)
+	result := decoded.Range(1, decoded.Length()+1)
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(result))
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Fatalf("at index %d: expected %v, got %v", i, expected[i], result[i])
+		}
+		if rank, _ := decoded.Rank(expected[i].Key); rank != i+1 {
+			t.Fatalf("Key %v: expected rank %d, got %d", expected[i].Key, i+1, rank)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	strInt := New[string, int]()
+	intStr := New[int, string]()
+	intFloat := New[int, float64]()
+	strStr := New[string, string]()
+	for i := 0; i < 1000; i++ {
+		strInt.Set(strconv.Itoa(i), rand.IntN(100))
+		intStr.Set(i, strconv.Itoa(rand.IntN(100)))
+		intFloat.Set(i, rand.Float64())
+		strStr.Set(strconv.Itoa(i), "value "+strconv.Itoa(rand.IntN(100)))
+	}
+
+	testJSONRoundTrip(t, strInt)
+	testJSONRoundTrip(t, intStr)
+	testJSONRoundTrip(t, intFloat)
+	testJSONRoundTrip(t, strStr)
+}
+
+func TestUnmarshalJSONReplaces(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("x", 100)
+	sl.Set("a", 100)
+
+	data := `[{"key":"b","value":2},{"key":"a","value":1},{"key":"b","value":3}]`
+	if err := json.Unmarshal([]byte(data), sl); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sl.Length() != 2 {
+		t.Fatalf("expected 2 entries after unmarshal, got %d", sl.Length())
+	}
+	if _, exists := sl.Get("x"); exists {
+		t.Errorf("Key x should be replaced by unmarshal")
+	}
+	if value, _ := sl.Get("b"); value != 3 {
+		t.Errorf("duplicate key b should keep the last value 3, got %d", value)
+	}
+	if rank, _ := sl.Rank("a"); rank != 1 {
+		t.Errorf("expected rank 1 for a, got %d", rank)
+	}
+
+	sl.Set("c", 2)
+	if rank, _ := sl.Rank("b"); rank != 3 {
+		t.Errorf("expected rank 3 for b after insert, got %d", rank)
+	}
+}
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+
+	if err := json.Unmarshal([]byte(`{"key":"a"}`), sl); err == nil {
+		t.Fatalf("expected an error for invalid JSON")
+	}
+	if sl.Length() != 1 {
+		t.Errorf("list should be untouched after a failed unmarshal")
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	sl := New[string, int]()
+	for i := 0; i < 5000; i++ {
+		sl.Set(strconv.Itoa(i), rand.IntN(100))
+	}
+
+	var buf bytes.Buffer
+	if err := sl.EncodeJSON(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, _ := json.Marshal(sl)
+	if buf.String() != string(expected) {
+		t.Errorf("EncodeJSON output should match MarshalJSON")
+	}
+
+	buf.Reset()
+	if err := New[string, int]().EncodeJSON(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "[]" {
+		t.Errorf("unexpected JSON %s for an empty list", buf.String())
+	}
+}
+
+func TestEncodeJSONConcurrentWriter(t *testing.T) {
+	sl := New[string, int]()
+	for i := 0; i < 10000; i++ {
+		sl.Set("stable"+strconv.Itoa(i), rand.IntN(1000))
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			key := "churn" + strconv.Itoa(i%500)
+			if i%3 == 0 {
+				sl.Del(key)
+			} else {
+				sl.Set(key, rand.IntN(1000))
+			}
+		}
+	}()
+
+	var buf bytes.Buffer
+	err := sl.EncodeJSON(&buf)
+	close(done)
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var entries []Entry[string, int]
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	seen := make(map[string]int)
+	for _, entry := range entries {
+		seen[entry.Key]++
+	}
+	for i := 0; i < 10000; i++ {
+		if seen["stable"+strconv.Itoa(i)] != 1 {
+			t.Fatalf("Key stable%d should appear exactly once", i)
+		}
+	}
+}
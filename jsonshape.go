@@ -0,0 +1,132 @@
+package ranklist
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonNames 是 WithJSONFieldNames 设置的字段名，rank 为空时不输出排名
+// jsonNames holds the field names set by WithJSONFieldNames, the rank is omitted when rank is empty
+type jsonNames struct {
+	member string
+	score  string
+	rank   string
+}
+
+// WithJSONFieldNames 设置 JSON 输出中条目的字段名，例如 ("member", "score", "rank") 输出 {"member":..,"score":..,"rank":..}。
+// 作用于 MarshalJSON、EncodeJSON 以及通过 JSONEntry 编码条目的 ranklisthttp 处理器，三者输出相同的形式；
+// 整体导出时排名是条目在输出中的位置，从 1 开始。rank 为空时不输出排名，member 或 score 为空时 panic。
+// WithKeyFormatter 和 WithValueFormatter 仍然生效，UnmarshalJSON 按同样的字段名读取
+// WithJSONFieldNames sets the field names of entries in JSON output,
+// e.g. ("member", "score", "rank") produces {"member":..,"score":..,"rank":..}.
+// It applies to MarshalJSON, EncodeJSON and the ranklisthttp handler, which encodes entries through JSONEntry,
+// so all three produce the same shape; in whole-list exports the rank is the 1-based position within the output.
+// An empty rank omits the rank, an empty member or score panics.
+// WithKeyFormatter and WithValueFormatter still apply, and UnmarshalJSON reads the same field names
+func WithJSONFieldNames[K Ordered, V Ordered](member, score, rank string) Option[K, V] {
+	if member == "" || score == "" {
+		panic("ranklist: JSON member and score field names must not be empty")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.codec.jsonNames = &jsonNames{member: member, score: score, rank: rank}
+	}
+}
+
+// WithEntryMarshaler 使用 fn 决定 JSON 输出中每个条目的形式，fn 的返回值由 encoding/json 编码，优先于 WithJSONFieldNames。
+// 作用范围与 WithJSONFieldNames 相同。fn 的输出无法被解读，UnmarshalJSON 仍然读取默认的或 WithJSONFieldNames 设置的形式
+// WithEntryMarshaler lets fn decide the shape of every entry in JSON output, its result is encoded by encoding/json
+// and takes precedence over WithJSONFieldNames. It covers the same outputs as WithJSONFieldNames.
+// The output of fn cannot be interpreted, so UnmarshalJSON still reads the default shape or the one of WithJSONFieldNames
+func WithEntryMarshaler[K Ordered, V Ordered](fn func(entry RankedEntry[K, V]) any) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.codec.marshalEntry = fn
+	}
+}
+
+// JSONEntry 返回 JSON 输出中 entry 应当编码的值，用于让其他编码器与 MarshalJSON 保持一致。
+// 设置了 WithEntryMarshaler 或 WithJSONFieldNames 时返回对应的值和 true，否则返回 nil 和 false，由调用方使用自己的默认形式
+// JSONEntry returns the value entry is encoded as in JSON output, letting other encoders stay consistent with MarshalJSON.
+// With WithEntryMarshaler or WithJSONFieldNames set it returns that value and true,
+// otherwise nil and false, leaving the caller to its own default shape
+func (sl *RankList[K, V]) JSONEntry(entry RankedEntry[K, V]) (any, bool) {
+	if !sl.codec.customJSON() {
+		return nil, false
+	}
+	return sl.codec.rankedJSON(entry), true
+}
+
+// customJSON 判断是否设置了自定义的 JSON 条目形式
+// customJSON reports whether a custom JSON shape of entries is set
+func (c textCodec[K, V]) customJSON() bool {
+	return c.marshalEntry != nil || c.jsonNames != nil
+}
+
+// rankedJSON 返回以自定义形式编码 entry 的 JSON 值，调用方需确认 customJSON 为 true
+// rankedJSON returns the value to JSON-encode entry with in the custom shape, the caller checks customJSON first
+func (c textCodec[K, V]) rankedJSON(entry RankedEntry[K, V]) any {
+	if c.marshalEntry != nil {
+		return c.marshalEntry(entry)
+	}
+	key, value := c.display(entry.Entry)
+	return namedEntry{names: c.jsonNames, key: key, value: value, rank: entry.Rank}
+}
+
+// namedEntry 按 WithJSONFieldNames 的字段名和顺序编码的条目
+// namedEntry is an entry encoded with the field names of WithJSONFieldNames, in their order
+type namedEntry struct {
+	names *jsonNames
+	key   any
+	value any
+	rank  int
+}
+
+func (e namedEntry) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	fields := []struct {
+		name  string
+		value any
+	}{{e.names.member, e.key}, {e.names.score, e.value}, {e.names.rank, e.rank}}
+	for _, field := range fields {
+		if field.name == "" {
+			continue
+		}
+		name, err := json.Marshal(field.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unmarshalNamed 解码以 WithJSONFieldNames 的字段名编码的对象数组，排名字段被忽略
+// unmarshalNamed decodes an array of objects using the field names of WithJSONFieldNames, the rank field is ignored
+func (c textCodec[K, V]) unmarshalNamed(data []byte) ([]Entry[K, V], error) {
+	var raw []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	entries := make([]Entry[K, V], len(raw))
+	for i, r := range raw {
+		var err error
+		if entries[i].Key, err = parseJSONField(r[c.jsonNames.member], c.parseKey); err != nil {
+			return nil, fmt.Errorf("ranklist: json entry %d: %s: %w", i, c.jsonNames.member, err)
+		}
+		if entries[i].Value, err = parseJSONField(r[c.jsonNames.score], c.parseValue); err != nil {
+			return nil, fmt.Errorf("ranklist: json entry %d: %s: %w", i, c.jsonNames.score, err)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,96 @@
+package ranklist
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func TestJSONFieldNames(t *testing.T) {
+	sl := New(WithJSONFieldNames[string, int]("member", "score", "rank"))
+	sl.Set("b", 20)
+	sl.Set("a", 10)
+
+	expected := `[{"member":"a","score":10,"rank":1},{"member":"b","score":20,"rank":2}]`
+	data, err := json.Marshal(sl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != expected {
+		t.Fatalf("unexpected MarshalJSON output %s", data)
+	}
+
+	var buf bytes.Buffer
+	if err := sl.EncodeJSON(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != expected {
+		t.Fatalf("EncodeJSON %s differs from MarshalJSON %s", buf.String(), expected)
+	}
+
+	shaped, ok := sl.JSONEntry(RankedEntry[string, int]{Entry: Entry[string, int]{Key: "b", Value: 20}, Rank: 2})
+	if !ok {
+		t.Fatal("expected JSONEntry to report the custom shape")
+	}
+	if data, _ := json.Marshal(shaped); string(data) != `{"member":"b","score":20,"rank":2}` {
+		t.Fatalf("unexpected JSONEntry output %s", data)
+	}
+
+	restored := New(WithJSONFieldNames[string, int]("member", "score", "rank"))
+	if err := json.Unmarshal([]byte(expected), restored); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	requireModel(t, restored, map[string]int{"a": 10, "b": 20})
+}
+
+func TestJSONFieldNamesWithoutRank(t *testing.T) {
+	sl := New(
+		WithJSONFieldNames[string, int]("name", "points", ""),
+		WithValueFormatter[string, int](func(value int) string { return strconv.Itoa(value) + "pt" }),
+	)
+	sl.Set("a", 1)
+
+	data, err := json.Marshal(sl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `[{"name":"a","points":"1pt"}]` {
+		t.Fatalf("unexpected JSON %s", data)
+	}
+}
+
+func TestEntryMarshaler(t *testing.T) {
+	sl := New(
+		WithJSONFieldNames[string, int]("ignored", "ignored_too", ""),
+		WithEntryMarshaler(func(entry RankedEntry[string, int]) any {
+			return []any{entry.Rank, entry.Key, entry.Value}
+		}),
+	)
+	for i := range 3 {
+		sl.Set(strconv.Itoa(i), i*10)
+	}
+
+	expected := `[[1,"0",0],[2,"1",10],[3,"2",20]]`
+	data, err := json.Marshal(sl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != expected {
+		t.Fatalf("unexpected MarshalJSON output %s", data)
+	}
+	var buf bytes.Buffer
+	if err := sl.EncodeJSON(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != expected {
+		t.Fatalf("EncodeJSON %s differs from MarshalJSON %s", buf.String(), expected)
+	}
+}
+
+func TestJSONEntryDefault(t *testing.T) {
+	sl := New[string, int]()
+	if shaped, ok := sl.JSONEntry(RankedEntry[string, int]{}); ok || shaped != nil {
+		t.Fatalf("expected no custom shape by default, got %v", shaped)
+	}
+}
@@ -0,0 +1,93 @@
+package ranklist
+
+import "slices"
+
+// WithKeyCompare 用 fn 替换值相同时比较键的规则，值的排序不变。
+// Set、Del、Rank、Range 以及所有按排名读写的操作都一致地使用 fn，Restore、Load 等整体加载的内容也按 fn 排序。
+// fn 必须是键上的严格全序：a 排在 b 之前时返回负数，之后时返回正数，只有 a == b 时才返回 0。
+// 只能在创建时设置，跳表中已有条目后不能更换。fn 为 nil 时 panic
+// WithKeyCompare replaces the rule breaking ties between equal values with fn, leaving the value order unchanged.
+// Set, Del, Rank, Range and every rank-based read or write use fn consistently,
+// and whole-content loads such as Restore and Load are sorted by it as well.
+// fn must be a strict total order on keys: negative when a sorts before b, positive when after, and 0 only when a == b.
+// It can only be set at construction and cannot change once the list holds entries. It panics if fn is nil
+func WithKeyCompare[K Ordered, V Ordered](fn func(a, b K) int) Option[K, V] {
+	if fn == nil {
+		panic("ranklist: key compare function must not be nil")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.keyCompare = fn
+	}
+}
+
+// WithValueCompare 用 fn 替换值的排序规则，例如按数字感知或忽略大小写的方式排列字符串值。
+// Set、Del、Rank、Range 以及 RangeByScore、CountByScore、TieCount 等按值查询的操作都一致地使用 fn；
+// fn 返回 0 的两个值视为相同，它们之间按 WithKeyCompare 或键的自然顺序排列，TieCount 和 DenseRank 也将它们计为并列。
+// fn 必须是值上的全序：a 排在 b 之前时返回负数，之后时返回正数。只能在创建时设置，跳表中已有条目后不能更换。
+// 字符串值可以通过 WithCollation 直接选用 NumericStringLess 或 FoldCaseLess。
+// 不能与 WithValueBuckets 同时使用，否则 New panic。fn 为 nil 时 panic
+// WithValueCompare replaces the order of values with fn, e.g. to sort string values numerically or ignoring case.
+// Set, Del, Rank, Range and the value queries such as RangeByScore, CountByScore and TieCount all use fn consistently;
+// two values for which fn returns 0 count as equal, sorted by WithKeyCompare or the natural order of keys among themselves
+// and counted as ties by TieCount and DenseRank.
+// fn must be a total order on values: negative when a sorts before b and positive when after.
+// It can only be set at construction and cannot change once the list holds entries.
+// String values can pick NumericStringLess or FoldCaseLess directly through WithCollation.
+// It cannot be combined with WithValueBuckets, New panics if both are given. It panics if fn is nil
+func WithValueCompare[K Ordered, V Ordered](fn func(a, b V) int) Option[K, V] {
+	if fn == nil {
+		panic("ranklist: value compare function must not be nil")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.valueCompare = fn
+	}
+}
+
+// compare 按照跳表的排序规则比较两个条目：先按 WithValueCompare 或值的自然顺序比较值，
+// 值相同时按 WithKeyCompare 或键的自然顺序比较
+// compare compares two entries in skip list order: by WithValueCompare or the natural order of values first,
+// then by WithKeyCompare or the natural order of keys for equal values
+func (sl *RankList[K, V]) compare(a, b Entry[K, V]) int {
+	if sl.keyCompare == nil && sl.valueCompare == nil {
+		return compareEntries(a, b)
+	}
+	switch {
+	case sl.valueLess(a.Value, b.Value):
+		return -1
+	case sl.valueLess(b.Value, a.Value):
+		return 1
+	case sl.keyCompare != nil:
+		return sl.keyCompare(a.Key, b.Key)
+	case a.Key < b.Key:
+		return -1
+	case a.Key > b.Key:
+		return 1
+	}
+	return 0
+}
+
+// valueLess 按照跳表的值排序规则判断 a 是否排在 b 之前
+// valueLess reports whether a sorts before b in the value order of the skip list
+func (sl *RankList[K, V]) valueLess(a, b V) bool {
+	if sl.valueCompare == nil {
+		return a < b
+	}
+	return sl.valueCompare(a, b) < 0
+}
+
+// sameValue 按照跳表的值排序规则判断 a 和 b 是否相同
+// sameValue reports whether a and b are equal in the value order of the skip list
+func (sl *RankList[K, V]) sameValue(a, b V) bool {
+	if sl.valueCompare == nil {
+		return a == b
+	}
+	return sl.valueCompare(a, b) == 0
+}
+
+// sortEntries 按照跳表的排序规则对条目去重并排序，重复的键以最后一次出现的值为准
+// sortEntries deduplicates and sorts entries in skip list order, duplicate keys keep the value of their last occurrence
+func (sl *RankList[K, V]) sortEntries(entries []Entry[K, V]) []Entry[K, V] {
+	sorted := dedupEntries(entries)
+	slices.SortFunc(sorted, sl.compare)
+	return sorted
+}
@@ -0,0 +1,156 @@
+package ranklist
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// reversed 按键的逆序打破值相同的平局
+// reversed breaks ties between equal values in reverse key order
+func reversed(a, b string) int {
+	return cmp.Compare(b, a)
+}
+
+// reversedOrder 返回 entries 按值升序、值相同时按键逆序排列的副本
+// reversedOrder returns a copy of entries sorted by ascending value, ties in reverse key order
+func reversedOrder(entries []Entry[string, int]) []Entry[string, int] {
+	sorted := slices.Clone(entries)
+	slices.SortFunc(sorted, func(a, b Entry[string, int]) int {
+		if c := cmp.Compare(a.Value, b.Value); c != 0 {
+			return c
+		}
+		return reversed(a.Key, b.Key)
+	})
+	return sorted
+}
+
+func TestKeyCompare(t *testing.T) {
+	sl := New(WithKeyCompare[string, int](reversed))
+	model := make(map[string]int)
+	for i := range 2000 {
+		key := fmt.Sprintf("k%03d", rand.IntN(500))
+		switch rand.IntN(4) {
+		case 0:
+			sl.Del(key)
+			delete(model, key)
+		case 1:
+			model[key] = sl.IncrBy(key, 1)
+		default:
+			sl.Set(key, i%5)
+			model[key] = i % 5
+		}
+	}
+
+	var want []Entry[string, int]
+	for key, value := range model {
+		want = append(want, Entry[string, int]{Key: key, Value: value})
+	}
+	want = reversedOrder(want)
+
+	if got := sl.Range(1, sl.Length()+1); !slices.Equal(got, want) {
+		t.Fatal("Range does not follow the reversed key order")
+	}
+	if got := sl.Entries(); !slices.Equal(got, want) {
+		t.Fatal("Entries does not follow the reversed key order")
+	}
+	var iterated []Entry[string, int]
+	for key, value := range sl.All() {
+		iterated = append(iterated, Entry[string, int]{Key: key, Value: value})
+	}
+	if !slices.Equal(iterated, want) {
+		t.Fatal("All does not follow the reversed key order")
+	}
+	if top := sl.Top(10); top[0] != want[len(want)-1] {
+		t.Fatalf("expected %v at the top, got %v", want[len(want)-1], top[0])
+	}
+
+	view := sl.SnapshotView()
+	for i, entry := range want {
+		if rank, ok := sl.Rank(entry.Key); !ok || rank != i+1 {
+			t.Fatalf("Rank(%s): expected %d, got %d, %v", entry.Key, i+1, rank, ok)
+		}
+		if rank, ok := sl.RankByValue(entry.Key, entry.Value); !ok || rank != i+1 {
+			t.Fatalf("RankByValue(%s): expected %d, got %d, %v", entry.Key, i+1, rank, ok)
+		}
+		if rank, ok := view.Rank(entry.Key); !ok || rank != i+1 {
+			t.Fatalf("View.Rank(%s): expected %d, got %d, %v", entry.Key, i+1, rank, ok)
+		}
+	}
+
+	// 删除值相同的条目时按比较函数找到正确的节点
+	// Deleting tied entries finds the right node through the compare function
+	for i, entry := range want[:len(want)/2] {
+		deleted := i%2 == 0 && sl.Del(entry.Key) || i%2 == 1 && sl.DelByValue(entry.Key, entry.Value)
+		if !deleted {
+			t.Fatalf("deleting %s failed", entry.Key)
+		}
+	}
+	if got := sl.Range(1, sl.Length()+1); !slices.Equal(got, want[len(want)/2:]) {
+		t.Fatal("deleting tied entries removed the wrong nodes")
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 整体加载的内容同样按比较函数排序
+	// Whole-content loads are sorted by the compare function as well
+	restored := New(WithKeyCompare[string, int](reversed))
+	restored.Restore(want)
+	if got := restored.Entries(); !slices.Equal(got, want) {
+		t.Fatal("Restore does not follow the reversed key order")
+	}
+	natural := New[string, int]()
+	natural.Restore(want)
+	data, err := natural.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := New(WithKeyCompare[string, int](reversed))
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Entries(); !slices.Equal(got, want) {
+		t.Fatal("a snapshot in natural order is not re-sorted on load")
+	}
+	if err := loaded.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeyCompareTies(t *testing.T) {
+	sl := New(WithKeyCompare[string, int](reversed), WithCachedTop[string, int](2))
+	for _, key := range []string{"a", "b", "c", "d"} {
+		sl.Set(key, 1)
+	}
+	if got := sl.Range(1, 5); !slices.Equal(got, []Entry[string, int]{{"d", 1}, {"c", 1}, {"b", 1}, {"a", 1}}) {
+		t.Fatalf("expected d c b a, got %v", got)
+	}
+	if got := sl.Top(2); !slices.Equal(got, []Entry[string, int]{{"a", 1}, {"b", 1}}) {
+		t.Fatalf("expected a b at the top, got %v", got)
+	}
+
+	// a 仍在缓存的前 2 名中，删除它必须让缓存失效
+	// a is in the cached top 2, deleting it must invalidate the cache
+	sl.Del("a")
+	if got := sl.Top(2); !slices.Equal(got, []Entry[string, int]{{"b", 1}, {"c", 1}}) {
+		t.Fatalf("expected b c at the top, got %v", got)
+	}
+	if rank, _ := sl.Rank("d"); rank != 1 {
+		t.Fatalf("expected d at rank 1, got %d", rank)
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeyCompareNilPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for a nil compare function")
+		}
+	}()
+	WithKeyCompare[string, int](nil)
+}
@@ -0,0 +1,95 @@
+package ranklist
+
+// Redis 有序集合的键空间事件名
+// Keyspace event names of Redis sorted sets
+const (
+	keyspaceZadd    = "zadd"
+	keyspaceZincr   = "zincr"
+	keyspaceZrem    = "zrem"
+	keyspaceDel     = "del"
+	keyspaceEvicted = "evicted"
+)
+
+// keyspace 保存键空间事件的配置和尚未投递的事件
+// keyspace holds the keyspace event settings and the events not delivered yet
+type keyspace[K Ordered] struct {
+	board   string
+	sink    func(event, member string)
+	format  func(key K) string
+	pending []keyspaceEvent[K]
+}
+
+// keyspaceEvent 一条尚未投递的键空间事件
+// keyspaceEvent is one keyspace event not delivered yet
+type keyspaceEvent[K Ordered] struct {
+	event string
+	key   K
+	board bool
+}
+
+// WithKeyspaceEvents 按 Redis 键空间通知的词汇报告每一次修改，便于从 Redis ZSET 迁移时保持下游消费者不变：
+// 写入新成员或更新已有成员为 zadd，IncrBy 系列和 AddAll 为 zincr，Del、DelByValue 和 Suspend 为 zrem，
+// WithMaxSize 的淘汰为 evicted，成员事件的 member 是经 format 格式化的键。
+// Clear 报告一次 del，member 为 boardName，即 Redis 中整个键被删除；
+// Restore、Load 等整体替换内容的操作报告一次 del，随后为替换后的每个成员报告一次 zadd。
+// format 为 nil 时使用与 WriteCSV 相同的格式。sink 在释放锁之后按修改顺序调用，
+// 插入引起的淘汰与预写日志中一样排在该次插入的 zadd 之前
+// WithKeyspaceEvents reports every mutation in the vocabulary of Redis keyspace notifications,
+// so downstream consumers stay unchanged while migrating from a Redis ZSET:
+// writing a new member or updating one is zadd, the IncrBy family and AddAll are zincr,
+// Del, DelByValue and Suspend are zrem, and evictions by WithMaxSize are evicted,
+// the member of these events being the key rendered by format.
+// Clear reports one del whose member is boardName, as Redis deletes the whole key;
+// whole-content replacements such as Restore and Load report one del followed by one zadd per resulting member.
+// A nil format uses the same rendering as WriteCSV. sink is called after the lock is released, in mutation order,
+// an eviction caused by an insert coming before the zadd of that insert, as in the write-ahead log
+func WithKeyspaceEvents[K Ordered, V Ordered](boardName string, sink func(event, member string), format func(key K) string) Option[K, V] {
+	if format == nil {
+		format = formatOrdered[K]
+	}
+	return func(sl *RankList[K, V]) {
+		sl.keyspace = &keyspace[K]{board: boardName, sink: sink, format: format}
+	}
+}
+
+// notify 记录一条成员事件，未开启时不做任何事，调用方需持有写锁
+// notify records one member event, a no-op when disabled. The caller must hold the write lock
+func (ks *keyspace[K]) notify(event string, key K) {
+	if ks != nil {
+		ks.pending = append(ks.pending, keyspaceEvent[K]{event: event, key: key})
+	}
+}
+
+// notifyBoard 记录一条整个榜单的事件，未开启时不做任何事，调用方需持有写锁
+// notifyBoard records one event about the whole board, a no-op when disabled. The caller must hold the write lock
+func (ks *keyspace[K]) notifyBoard(event string) {
+	if ks != nil {
+		ks.pending = append(ks.pending, keyspaceEvent[K]{event: event, board: true})
+	}
+}
+
+// keyspaceEvents 取出尚未投递的键空间事件，转换为在释放锁之后执行的回调事件，调用方需持有写锁
+// keyspaceEvents takes the keyspace events not delivered yet and turns them into events fired after the lock is released.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) keyspaceEvents() []thresholdEvent[K] {
+	ks := sl.keyspace
+	if ks == nil || len(ks.pending) == 0 {
+		return nil
+	}
+
+	events := make([]thresholdEvent[K], 0, len(ks.pending))
+	sink, format, board := ks.sink, ks.format, ks.board
+	for _, pending := range ks.pending {
+		event := pending.event
+		if pending.board {
+			events = append(events, thresholdEvent[K]{fn: func(K, bool) { sink(event, board) }})
+			continue
+		}
+		events = append(events, thresholdEvent[K]{
+			fn:  func(key K, _ bool) { sink(event, format(key)) },
+			key: pending.key,
+		})
+	}
+	ks.pending = ks.pending[:0]
+	return events
+}
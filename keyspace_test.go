@@ -0,0 +1,68 @@
+package ranklist
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestKeyspaceEvents(t *testing.T) {
+	var events []string
+	sl := New(
+		WithKeyspaceEvents[int, int]("board", func(event, member string) {
+			events = append(events, event+" "+member)
+		}, func(key int) string { return "player:" + strconv.Itoa(key) }),
+		WithMaxSize[int, int](3),
+	)
+	expect := func(what string, expected ...string) {
+		t.Helper()
+		if !slices.Equal(events, expected) {
+			t.Fatalf("%s: expected %q, got %q", what, expected, events)
+		}
+		events = events[:0]
+	}
+
+	sl.Set(1, 10)
+	expect("set new", "zadd player:1")
+	sl.Set(1, 20)
+	expect("set update", "zadd player:1")
+	sl.IncrBy(1, 5)
+	expect("incr", "zincr player:1")
+	sl.IncrByClamped(1, 5, 0, 100)
+	expect("clamped incr", "zincr player:1")
+	sl.AddAll(map[int]int{2: 1})
+	expect("add all", "zincr player:2")
+	sl.Del(2)
+	expect("del", "zrem player:2")
+	sl.Del(2)
+	expect("missed del")
+
+	sl.Set(2, 40)
+	sl.Set(3, 50)
+	events = events[:0]
+	sl.Set(4, 60)
+	// 淘汰发生在插入的写锁内、插入记录之后
+	// The eviction happens within the insert, after the insert is recorded
+	expect("evict", "zadd player:4", "evicted player:1")
+	sl.Set(7, 1)
+	// 新插入的键自己被淘汰时订阅者先看到写入再看到淘汰
+	// Subscribers see the write before the eviction when the new key is the one evicted
+	expect("evict self", "zadd player:7", "evicted player:7")
+
+	sl.Clear()
+	expect("clear", "del board")
+	sl.Restore([]Entry[int, int]{{5, 1}, {6, 2}})
+	expect("restore", "del board", "zadd player:5", "zadd player:6")
+}
+
+func TestKeyspaceEventsDefaultFormat(t *testing.T) {
+	var members []string
+	sl := New(WithKeyspaceEvents[int, int]("board", func(event, member string) {
+		members = append(members, member)
+	}, nil))
+	sl.Set(42, 1)
+
+	if !slices.Equal(members, []string{"42"}) {
+		t.Fatalf("expected the default format, got %q", members)
+	}
+}
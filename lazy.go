@@ -0,0 +1,56 @@
+package ranklist
+
+import (
+	crand "crypto/rand"
+	"math/rand/v2"
+)
+
+// lazyInit 初始化跳表的头节点、字典、随机数源等内部状态，只执行一次。
+// New 在应用选项之后直接调用；零值跳表在第一次加锁时调用，因此零值与不带选项的 New 行为相同
+// lazyInit initializes the header, dictionary, random source and the rest of the internal state, exactly once.
+// New calls it right after applying the options; a zero value list calls it on the first lock,
+// so the zero value behaves like New without options
+func (sl *RankList[K, V]) lazyInit() {
+	sl.initOnce.Do(func() {
+		sl.header = NewNode[K, V](ZeroValue[K](), ZeroValue[V](), MaxLevel)
+		sl.level = 1
+		sl.done = make(chan struct{})
+		if sl.rng == nil {
+			var seed [32]byte
+			crand.Read(seed[:])
+			sl.rng = rand.New(rand.NewChaCha8(seed))
+		}
+		sl.initEviction()
+		sl.dict = sl.newDict(0)
+		sl.startWriteThrough()
+		sl.startAuditor()
+	})
+}
+
+// Lock 获取写锁，零值跳表在此完成初始化
+// Lock acquires the write lock, a zero value list is initialized here
+func (sl *RankList[K, V]) Lock() {
+	sl.lazyInit()
+	sl.RWMutex.Lock()
+}
+
+// RLock 获取读锁，零值跳表在此完成初始化
+// RLock acquires the read lock, a zero value list is initialized here
+func (sl *RankList[K, V]) RLock() {
+	sl.lazyInit()
+	sl.RWMutex.RLock()
+}
+
+// TryLock 尝试获取写锁，零值跳表在此完成初始化
+// TryLock tries to acquire the write lock, a zero value list is initialized here
+func (sl *RankList[K, V]) TryLock() bool {
+	sl.lazyInit()
+	return sl.RWMutex.TryLock()
+}
+
+// TryRLock 尝试获取读锁，零值跳表在此完成初始化
+// TryRLock tries to acquire the read lock, a zero value list is initialized here
+func (sl *RankList[K, V]) TryRLock() bool {
+	sl.lazyInit()
+	return sl.RWMutex.TryRLock()
+}
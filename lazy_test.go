@@ -0,0 +1,179 @@
+package ranklist
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// zeroArgs 为方法构造参数：上下文、读写器和跳表使用可用的实例，函数返回零值，其余参数为零值
+// zeroArgs builds the arguments of a method: contexts, readers, writers and lists get usable instances,
+// functions return zero values and every other argument is the zero value
+func zeroArgs(m reflect.Method) []reflect.Value {
+	args := make([]reflect.Value, 0, m.Type.NumIn()-1)
+	for i := 1; i < m.Type.NumIn(); i++ {
+		typ := m.Type.In(i)
+		var arg reflect.Value
+		switch {
+		case typ == reflect.TypeFor[context.Context]():
+			arg = reflect.ValueOf(context.Background())
+		case typ == reflect.TypeFor[io.Reader]():
+			arg = reflect.ValueOf(strings.NewReader(""))
+		case typ == reflect.TypeFor[io.Writer]():
+			arg = reflect.ValueOf(&bytes.Buffer{})
+		case typ == reflect.TypeFor[*RankList[string, int]]():
+			arg = reflect.ValueOf(New[string, int]())
+		case typ.Kind() == reflect.Func:
+			arg = reflect.MakeFunc(typ, func([]reflect.Value) []reflect.Value {
+				out := make([]reflect.Value, typ.NumOut())
+				for j := range out {
+					out[j] = reflect.Zero(typ.Out(j))
+				}
+				return out
+			})
+		default:
+			arg = reflect.Zero(typ)
+		}
+		args = append(args, arg.Convert(typ))
+	}
+	return args
+}
+
+// callResult 调用方法的可比较结果：指针、函数、通道、接口和迭代器只比较是否为 nil，错误比较其文本
+// callResult is the comparable outcome of a method call: pointers, functions, channels, interfaces and iterators
+// only compare their nil-ness and errors compare their text
+func callResult(sl *RankList[string, int], m reflect.Method) (out []any, panicked string) {
+	defer func() {
+		if p := recover(); p != nil {
+			panicked = fmt.Sprint(p)
+		}
+	}()
+	results := reflect.ValueOf(sl).Method(m.Index).Call(zeroArgs(m))
+	for _, r := range results {
+		switch {
+		case r.Type() == reflect.TypeFor[error]():
+			if err, _ := r.Interface().(error); err != nil {
+				out = append(out, err.Error())
+			} else {
+				out = append(out, nil)
+			}
+		case r.Kind() == reflect.Pointer || r.Kind() == reflect.Func || r.Kind() == reflect.Chan || r.Kind() == reflect.Interface:
+			out = append(out, r.IsNil())
+		default:
+			out = append(out, r.Interface())
+		}
+	}
+	return out, ""
+}
+
+func TestZeroValueMatchesNew(t *testing.T) {
+	skip := map[string]bool{
+		"Lock": true, "Unlock": true, "RLock": true, "RUnlock": true,
+		"TryLock": true, "TryRLock": true, "RLocker": true,
+	}
+	typ := reflect.TypeFor[*RankList[string, int]]()
+	for i := range typ.NumMethod() {
+		m := typ.Method(i)
+		if skip[m.Name] {
+			continue
+		}
+		t.Run(m.Name, func(t *testing.T) {
+			fresh := New[string, int]()
+			zero := &RankList[string, int]{}
+			defer fresh.Close()
+			defer zero.Close()
+
+			want, wantPanic := callResult(fresh, m)
+			got, gotPanic := callResult(zero, m)
+			if gotPanic != wantPanic {
+				t.Fatalf("zero value panicked with %q, New with %q", gotPanic, wantPanic)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("zero value returned %v, New returned %v", got, want)
+			}
+
+			// 调用之后零值跳表仍然可用
+			// The zero value list is still usable after the call
+			if zero.Closed() {
+				return
+			}
+			zero.Set("a", 1)
+			if value, ok := zero.Get("a"); !ok || value != 1 {
+				t.Fatalf("after %s: Get(a) = %d, %v", m.Name, value, ok)
+			}
+			if err := zero.Check(); err != nil {
+				t.Fatalf("after %s: %v", m.Name, err)
+			}
+		})
+	}
+}
+
+func TestZeroValueEmbedded(t *testing.T) {
+	type board struct {
+		name     string
+		ranklist RankList[string, int]
+	}
+
+	var b board
+	if _, ok := b.ranklist.Get("a"); ok || b.ranklist.Length() != 0 {
+		t.Fatal("empty zero value list reports members")
+	}
+	b.ranklist.Set("a", 3)
+	b.ranklist.Set("b", 1)
+	b.ranklist.IncrBy("c", 2)
+	if got := b.ranklist.Range(1, 4); !reflect.DeepEqual(got, []Entry[string, int]{{"b", 1}, {"c", 2}, {"a", 3}}) {
+		t.Fatalf("Range = %v", got)
+	}
+	if rank, ok := b.ranklist.Rank("a"); !ok || rank != 3 {
+		t.Fatalf("Rank(a) = %d, %v", rank, ok)
+	}
+	if !b.ranklist.Del("b") || b.ranklist.Length() != 2 {
+		t.Fatal("Del(b) failed")
+	}
+	if err := b.ranklist.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.ranklist.Close(); err != nil || !b.ranklist.Closed() {
+		t.Fatalf("Close = %v", err)
+	}
+	if _, err := b.ranklist.SetChecked("d", 1); !errors.Is(err, ErrClosed) {
+		t.Fatalf("SetChecked after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestZeroValueConcurrentFirstUse(t *testing.T) {
+	var sl RankList[int, int]
+	done := make(chan struct{})
+	for g := range 8 {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for i := range 100 {
+				if g%2 == 0 {
+					sl.Set(g*100+i, i)
+				} else {
+					sl.Top(3)
+				}
+			}
+		}()
+	}
+	timeout := time.After(time.Minute)
+	for range 8 {
+		select {
+		case <-done:
+		case <-timeout:
+			t.Fatal("concurrent first use did not finish")
+		}
+	}
+	if sl.Length() != 400 {
+		t.Fatalf("Length = %d, want 400", sl.Length())
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,26 @@
+package ranklist
+
+// MapValues 用 fn 转换 src 的每个值，返回一个值类型为 V2 的新跳表，例如从数值排行榜得到用于展示的字符串排行榜。
+// src 在一把读锁内按排名顺序读取，fn 在锁外调用，因此 fn 可以访问 src。
+// fn 保持顺序时（v1 < v1' 推出 fn(v1) < fn(v1')，值相同时结果也相同），新跳表以 O(n) 直接构建而无需重新排序；
+// 构建前会逐一检查相邻的结果，发现顺序被打乱时退回到排序后构建，因此任意 fn 都能得到正确的跳表，只是排名可能与 src 不同
+// MapValues converts every value of src with fn and returns a new list with values of type V2,
+// e.g. a display board of formatted strings derived from a numeric board.
+// src is read in rank order under one read lock and fn is called outside the lock, so fn may access src.
+// When fn preserves order (v1 < v1' implies fn(v1) < fn(v1'), equal values map to equal results)
+// the new list is built directly in O(n) without sorting again;
+// adjacent results are checked before building and the entries are sorted first when the order is broken,
+// so any fn yields a correct list, only its ranks may then differ from src
+func MapValues[K Ordered, V1 Ordered, V2 Ordered](src *RankList[K, V1], fn func(key K, value V1) V2) *RankList[K, V2] {
+	entries := src.Entries()
+	mapped := make([]Entry[K, V2], len(entries))
+	for i, entry := range entries {
+		mapped[i] = Entry[K, V2]{Key: entry.Key, Value: fn(entry.Key, entry.Value)}
+	}
+
+	dst := New[K, V2]()
+	dst.Lock()
+	dst.loadSorted(mapped)
+	dst.Unlock()
+	return dst
+}
@@ -0,0 +1,66 @@
+package ranklist
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestMapValuesOrderPreserving(t *testing.T) {
+	src := New[string, int]()
+	for i := 0; i < 1000; i++ {
+		src.Set("key"+strconv.Itoa(i), i%50)
+	}
+
+	// 补零的字符串与数值的顺序相同
+	// Zero-padded strings sort the same way as the numbers
+	dst := MapValues(src, func(key string, value int) string {
+		return fmt.Sprintf("%06d pts", value)
+	})
+	if err := dst.Check(); err != nil {
+		t.Fatalf("corrupted list: %v", err)
+	}
+	if dst.Length() != src.Length() {
+		t.Fatalf("expected %d entries, got %d", src.Length(), dst.Length())
+	}
+	for i, entry := range src.Entries() {
+		got := dst.byRank(i + 1).data
+		if got.Key != entry.Key || got.Value != fmt.Sprintf("%06d pts", entry.Value) {
+			t.Fatalf("rank %d: expected %v, got %v", i+1, entry, got)
+		}
+	}
+	if value, ok := dst.Get("key7"); !ok || value != "000007 pts" {
+		t.Fatalf("unexpected Get result %q, %v", value, ok)
+	}
+
+	if empty := MapValues(New[string, int](), func(string, int) float64 { return 0 }); empty.Length() != 0 {
+		t.Fatalf("expected an empty list")
+	}
+}
+
+func TestMapValuesNotMonotonic(t *testing.T) {
+	src := New[int, int]()
+	for i := 0; i < 500; i++ {
+		src.Set(i, i)
+	}
+
+	// 未补零的字符串按字典序排列，"10" 排在 "9" 之前
+	// Unpadded strings sort lexicographically, "10" before "9"
+	dst := MapValues(src, func(key int, value int) string { return strconv.Itoa(value) })
+	if err := dst.Check(); err != nil {
+		t.Fatalf("corrupted list: %v", err)
+	}
+
+	expected := make([]Entry[int, string], 0, 500)
+	for i := 0; i < 500; i++ {
+		expected = append(expected, Entry[int, string]{Key: i, Value: strconv.Itoa(i)})
+	}
+	slices.SortFunc(expected, compareEntries[int, string])
+	if entries := dst.Entries(); !slices.Equal(entries, expected) {
+		t.Fatalf("expected the entries to be re-sorted by the new values")
+	}
+	if rank, ok := dst.Rank(10); !ok || rank != 3 {
+		t.Fatalf("expected \"10\" at rank 3 after \"0\" and \"1\", got %d, %v", rank, ok)
+	}
+}
@@ -0,0 +1,75 @@
+package ranklist
+
+import "maps"
+
+// MetaEntry 表示附带元数据的键值对
+// MetaEntry represents a key-value pair together with its metadata
+type MetaEntry[K Ordered, V Ordered] struct {
+	Entry[K, V]
+	Meta map[string]string
+}
+
+// SetMeta 为已存在的键设置元数据，键不存在时返回 false。
+// 元数据在值更新后保留，在键被删除或跳表被清空时自动清理
+// SetMeta sets the metadata of an existing key, returns false if the key does not exist.
+// Metadata survives value updates and is cleaned up automatically when the key is deleted or the list is cleared
+func (sl *RankList[K, V]) SetMeta(key K, meta map[string]string) bool {
+	sl.Lock()
+	defer sl.Unlock()
+
+	if _, exists := sl.lookup(key); !exists || sl.closed {
+		return false
+	}
+	if sl.meta == nil {
+		sl.meta = make(map[K]map[string]string)
+	}
+	sl.meta[key] = maps.Clone(meta)
+	return true
+}
+
+// GetMeta 获取键的元数据副本，键不存在时返回 false
+// GetMeta returns a copy of the metadata of a key, returns false if the key does not exist
+func (sl *RankList[K, V]) GetMeta(key K) (map[string]string, bool) {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	if _, exists := sl.lookup(key); !exists {
+		return nil, false
+	}
+	return maps.Clone(sl.meta[key]), true
+}
+
+// RangeWithMeta 与 Range 相同，但在同一把读锁内同时返回每个条目的元数据
+// RangeWithMeta behaves like Range, but joins the metadata of every entry under the same read lock
+func (sl *RankList[K, V]) RangeWithMeta(start int, end int) []MetaEntry[K, V] {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	entries := make([]MetaEntry[K, V], 0)
+	sl.walkRange(start, end, func(node *Node[K, V]) {
+		if sl.hidden(node) {
+			return
+		}
+		entries = append(entries, MetaEntry[K, V]{
+			Entry: node.data,
+			Meta:  maps.Clone(sl.meta[node.data.Key]),
+		})
+	})
+	return entries
+}
+
+// TopWithMeta 与 Top 相同，但在同一把读锁内同时返回每个条目的元数据
+// TopWithMeta behaves like Top, but joins the metadata of every entry under the same read lock
+func (sl *RankList[K, V]) TopWithMeta(n int) []MetaEntry[K, V] {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	entries := make([]MetaEntry[K, V], 0)
+	sl.walkTop(n, func(node *Node[K, V]) {
+		entries = append(entries, MetaEntry[K, V]{
+			Entry: node.data,
+			Meta:  maps.Clone(sl.meta[node.data.Key]),
+		})
+	})
+	return entries
+}
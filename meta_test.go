@@ -0,0 +1,122 @@
+package ranklist
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSetMetaMissingKey(t *testing.T) {
+	sl := New[string, int]()
+
+	if sl.SetMeta("a", map[string]string{"name": "alice"}) {
+		t.Errorf("SetMeta should return false for a missing key")
+	}
+	if _, exists := sl.GetMeta("a"); exists {
+		t.Errorf("GetMeta should return false for a missing key")
+	}
+}
+
+func TestMetaSurvivesUpdate(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	sl.SetMeta("a", map[string]string{"name": "alice"})
+
+	sl.Set("a", 2)
+	sl.IncrBy("a", 3)
+
+	meta, exists := sl.GetMeta("a")
+	if !exists || meta["name"] != "alice" {
+		t.Errorf("metadata should survive value updates, got %v, %v", meta, exists)
+	}
+}
+
+func TestMetaRemovedOnDel(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	sl.SetMeta("a", map[string]string{"name": "alice"})
+
+	sl.Del("a")
+	sl.Set("a", 1)
+
+	meta, exists := sl.GetMeta("a")
+	if !exists || len(meta) != 0 {
+		t.Errorf("metadata should be removed with the key, got %v", meta)
+	}
+
+	sl.SetMeta("a", map[string]string{"name": "alice"})
+	sl.Clear()
+	sl.Set("a", 1)
+	if meta, _ := sl.GetMeta("a"); len(meta) != 0 {
+		t.Errorf("metadata should be removed on clear, got %v", meta)
+	}
+}
+
+func TestMetaIsCopied(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+
+	meta := map[string]string{"name": "alice"}
+	sl.SetMeta("a", meta)
+	meta["name"] = "bob"
+
+	got, _ := sl.GetMeta("a")
+	got["name"] = "carol"
+
+	if got, _ := sl.GetMeta("a"); got["name"] != "alice" {
+		t.Errorf("stored metadata should not be shared with callers, got %v", got)
+	}
+}
+
+func TestRangeWithMetaConcurrent(t *testing.T) {
+	sl := New[string, int]()
+	for i := 0; i < 100; i++ {
+		key := strconv.Itoa(i)
+		sl.Set(key, i)
+		sl.SetMeta(key, map[string]string{"name": key})
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				sl.Set(strconv.Itoa(i%100), i)
+			}
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		entries := sl.RangeWithMeta(1, 101)
+		if len(entries) != 100 {
+			t.Fatalf("expected 100 entries, got %d", len(entries))
+		}
+		for _, entry := range entries {
+			if entry.Meta["name"] != entry.Key {
+				t.Fatalf("Key %s: unexpected metadata %v", entry.Key, entry.Meta)
+			}
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestTopWithMeta(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	sl.SetMeta("b", map[string]string{"name": "bob"})
+
+	top := sl.TopWithMeta(5)
+	if len(top) != 2 || top[0].Key != "b" || top[1].Key != "a" {
+		t.Fatalf("unexpected top %v", top)
+	}
+	if top[0].Meta["name"] != "bob" || top[1].Meta != nil {
+		t.Errorf("unexpected metadata %v, %v", top[0].Meta, top[1].Meta)
+	}
+}
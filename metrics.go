@@ -0,0 +1,132 @@
+package ranklist
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics 接收每次操作的耗时和涉及的条目数，用于统计延迟分位数等指标
+// Metrics receives the duration and the number of entries touched by every operation,
+// e.g. to track latency percentiles
+type Metrics interface {
+	// ObserveOp 报告一次操作，op 是操作名，dur 是耗时，n 是涉及的条目数
+	// ObserveOp reports one operation, op being its name, dur its duration and n the number of entries touched
+	ObserveOp(op string, dur time.Duration, n int)
+}
+
+// WithMetrics 注册 Metrics，以下操作每执行一次调用一次 ObserveOp：
+// set（Set、SetChecked）、del、get（Get、GetEntry）、rank、range、set_batch、mget、mrank（MRank、RankedEntries）。
+// 耗时从获取锁之前开始到操作返回为止，因此包含等待锁的时间，写操作还包含阈值回调的执行时间；
+// n 是涉及的条目数：单键操作命中为 1、未命中为 0，Range 为返回的条目数，批量操作为输入的条目或键数。
+// ObserveOp 在锁外同步调用，应当足够快。未注册时为 nil，热路径上只多一次 nil 判断，不会读取时钟
+// WithMetrics registers m, ObserveOp is called once per execution of the following operations:
+// set (Set, SetChecked), del, get (Get, GetEntry), rank, range, set_batch, mget and mrank (MRank, RankedEntries).
+// The duration runs from before the lock is acquired until the operation returns, so waiting for the lock is included,
+// and for writes so are the threshold callbacks;
+// n is the number of entries touched: 1 for a single-key hit and 0 for a miss,
+// the number of entries returned for range, and the number of input entries or keys for batch operations.
+// ObserveOp is called synchronously outside the lock and should be fast.
+// Without metrics the hot path only pays a nil check and the clock is never read
+func WithMetrics[K Ordered, V Ordered](m Metrics) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.metrics = m
+	}
+}
+
+// metricsStart 返回操作的开始时间，未注册 Metrics 时不读取时钟
+// metricsStart returns the start time of an operation without reading the clock when no Metrics is registered
+func (sl *RankList[K, V]) metricsStart() time.Time {
+	if sl.metrics == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// observe 向 Metrics 报告从 start 开始的一次操作，未注册时不做任何事
+// observe reports one operation started at start to the Metrics, it does nothing when none is registered
+func (sl *RankList[K, V]) observe(op string, start time.Time, n int) {
+	if sl.metrics != nil {
+		sl.metrics.ObserveOp(op, time.Since(start), n)
+	}
+}
+
+// hits 将单键操作是否命中转换为涉及的条目数
+// hits converts whether a single-key operation hit into the number of entries touched
+func hits(ok bool) int {
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+// MetricSummary 某个操作的累计统计
+// MetricSummary holds the accumulated statistics of one operation
+type MetricSummary struct {
+	// 操作次数
+	// Number of operations
+	Count int64
+
+	// 涉及的条目总数
+	// Total number of entries touched
+	Entries int64
+
+	// 总耗时和最大耗时
+	// Total and maximum duration
+	Total time.Duration
+	Max   time.Duration
+}
+
+// AtomicMetrics 一个基于原子计数器的简单 Metrics 实现，按操作名累计次数、条目数、总耗时和最大耗时，
+// 适合测试和小规模部署；需要分位数时请接入完整的指标库。零值即可使用，可以被多个跳表共享
+// AtomicMetrics is a simple Metrics implementation built on atomic counters, accumulating per operation name
+// the count, entries, total duration and maximum duration. It suits tests and small deployments,
+// plug in a full metrics library when percentiles are needed. The zero value is ready to use
+// and it may be shared by several lists
+type AtomicMetrics struct {
+	ops sync.Map
+}
+
+// atomicSummary 一个操作的原子计数器
+// atomicSummary holds the atomic counters of one operation
+type atomicSummary struct {
+	count   atomic.Int64
+	entries atomic.Int64
+	total   atomic.Int64
+	max     atomic.Int64
+}
+
+// ObserveOp 实现 Metrics
+// ObserveOp implements Metrics
+func (m *AtomicMetrics) ObserveOp(op string, dur time.Duration, n int) {
+	v, ok := m.ops.Load(op)
+	if !ok {
+		v, _ = m.ops.LoadOrStore(op, &atomicSummary{})
+	}
+	s := v.(*atomicSummary)
+	s.count.Add(1)
+	s.entries.Add(int64(n))
+	s.total.Add(int64(dur))
+	for {
+		prev := s.max.Load()
+		if int64(dur) <= prev || s.max.CompareAndSwap(prev, int64(dur)) {
+			break
+		}
+	}
+}
+
+// Summary 返回操作 op 当前的累计统计，从未观察到的操作返回零值
+// Summary returns the current statistics of op, the zero value for operations never observed
+func (m *AtomicMetrics) Summary(op string) MetricSummary {
+	v, ok := m.ops.Load(op)
+	if !ok {
+		return MetricSummary{}
+	}
+	s := v.(*atomicSummary)
+	return MetricSummary{
+		Count:   s.count.Load(),
+		Entries: s.entries.Load(),
+		Total:   time.Duration(s.total.Load()),
+		Max:     time.Duration(s.max.Load()),
+	}
+}
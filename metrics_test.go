@@ -0,0 +1,82 @@
+package ranklist
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	m := &AtomicMetrics{}
+	sl := New(WithMetrics[string, int](m))
+
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Get("a")
+	sl.Get("missing")
+	sl.Rank("b")
+	sl.Range(1, 10)
+	sl.SetBatch([]Entry[string, int]{{"c", 30}, {"d", 40}, {"e", 50}})
+	sl.MGet([]string{"a", "b"})
+	sl.MRank([]string{"a", "b", "missing"})
+	sl.Del("a")
+	sl.Del("a")
+
+	for op, expected := range map[string]MetricSummary{
+		"set":       {Count: 2, Entries: 2},
+		"get":       {Count: 2, Entries: 1},
+		"rank":      {Count: 1, Entries: 1},
+		"range":     {Count: 1, Entries: 2},
+		"set_batch": {Count: 1, Entries: 3},
+		"mget":      {Count: 1, Entries: 2},
+		"mrank":     {Count: 1, Entries: 3},
+		"del":       {Count: 2, Entries: 1},
+	} {
+		got := m.Summary(op)
+		if got.Count != expected.Count || got.Entries != expected.Entries {
+			t.Fatalf("%s: expected %d operations touching %d entries, got %d and %d",
+				op, expected.Count, expected.Entries, got.Count, got.Entries)
+		}
+		if got.Total < got.Max || got.Max < 0 {
+			t.Fatalf("%s: inconsistent durations %+v", op, got)
+		}
+	}
+	if summary := m.Summary("unknown"); summary != (MetricSummary{}) {
+		t.Fatalf("expected a zero summary, got %+v", summary)
+	}
+}
+
+func TestMetricsRejectedSet(t *testing.T) {
+	m := &AtomicMetrics{}
+	sl := New(WithMetrics[string, int](m), WithValueGuard[string, int](func(old, new int) error {
+		if new < 0 {
+			return ErrValueRejected
+		}
+		return nil
+	}))
+	sl.SetChecked("a", -1)
+
+	if summary := m.Summary("set"); summary.Count != 1 || summary.Entries != 0 {
+		t.Fatalf("expected a rejected set touching nothing, got %+v", summary)
+	}
+}
+
+func TestAtomicMetricsConcurrent(t *testing.T) {
+	m := &AtomicMetrics{}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				m.ObserveOp("set", time.Duration(i*1000+j), 1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	summary := m.Summary("set")
+	if summary.Count != 8000 || summary.Entries != 8000 || summary.Max != 7999 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+}
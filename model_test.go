@@ -0,0 +1,259 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// sliceModel 是用有序切片实现的朴素榜单，作为随机测试的参照
+// sliceModel is a naive leaderboard backed by a sorted slice, the reference for randomized tests
+type sliceModel[K Ordered, V Ordered] struct {
+	entries []Entry[K, V]
+}
+
+func (m *sliceModel[K, V]) index(key K) int {
+	return slices.IndexFunc(m.entries, func(entry Entry[K, V]) bool { return entry.Key == key })
+}
+
+func (m *sliceModel[K, V]) get(key K) (V, bool) {
+	if i := m.index(key); i >= 0 {
+		return m.entries[i].Value, true
+	}
+	return ZeroValue[V](), false
+}
+
+func (m *sliceModel[K, V]) set(key K, value V) {
+	m.del(key)
+	entry := Entry[K, V]{Key: key, Value: value}
+	i, _ := slices.BinarySearchFunc(m.entries, entry, compareEntries[K, V])
+	m.entries = slices.Insert(m.entries, i, entry)
+}
+
+func (m *sliceModel[K, V]) del(key K) bool {
+	if i := m.index(key); i >= 0 {
+		m.entries = slices.Delete(m.entries, i, i+1)
+		return true
+	}
+	return false
+}
+
+// requireSliceModel 断言 Length、Get、Rank、Range 和 Check 都与参照模型一致
+// requireSliceModel asserts that Length, Get, Rank, Range and Check all agree with the reference model
+func requireSliceModel[K Ordered, V Ordered](t *testing.T, sl *RankList[K, V], m *sliceModel[K, V], step int) {
+	t.Helper()
+
+	if err := sl.Check(); err != nil {
+		t.Fatalf("step %d: %v", step, err)
+	}
+	if sl.Length() != len(m.entries) {
+		t.Fatalf("step %d: expected length %d, got %d", step, len(m.entries), sl.Length())
+	}
+	if got := sl.Range(1, len(m.entries)+1); !slices.Equal(got, m.entries) {
+		t.Fatalf("step %d: Range disagrees with the model\nexpected %v\ngot      %v", step, m.entries, got)
+	}
+	for i, entry := range m.entries {
+		if rank, ok := sl.Rank(entry.Key); !ok || rank != i+1 {
+			t.Fatalf("step %d: key %v expected rank %d, got %d, %v", step, entry.Key, i+1, rank, ok)
+		}
+		if value, ok := sl.Get(entry.Key); !ok || value != entry.Value {
+			t.Fatalf("step %d: key %v expected value %v, got %v, %v", step, entry.Key, entry.Value, value, ok)
+		}
+	}
+}
+
+func TestRandomizedModel(t *testing.T) {
+	variants := []struct {
+		name string
+		opts []Option[int, int]
+	}{
+		{"default", nil},
+		{"nodict", []Option[int, int]{WithNoDict[int, int]()}},
+		{"arena", []Option[int, int]{WithArena[int, int](16)}},
+		{"rankcache", []Option[int, int]{WithRankCache[int, int](8)}},
+	}
+
+	for _, variant := range variants {
+		t.Run(variant.name, func(t *testing.T) {
+			sl := New(variant.opts...)
+			m := &sliceModel[int, int]{}
+
+			// 值取自很小的区间，制造大量并列
+			// Values come from a tiny range to force heavy ties
+			for step := 0; step < 4000; step++ {
+				key := rand.IntN(64)
+				switch rand.IntN(5) {
+				case 0:
+					if sl.Del(key) != m.del(key) {
+						t.Fatalf("step %d: Del(%d) disagrees with the model", step, key)
+					}
+				case 1:
+					delta := rand.IntN(5) - 2
+					value, _ := m.get(key)
+					m.set(key, value+delta)
+					if got := sl.IncrBy(key, delta); got != value+delta {
+						t.Fatalf("step %d: IncrBy(%d, %d) expected %d, got %d", step, key, delta, value+delta, got)
+					}
+				default:
+					value := rand.IntN(4)
+					sl.Set(key, value)
+					m.set(key, value)
+				}
+				requireSliceModel(t, sl, m, step)
+			}
+		})
+	}
+}
+
+// FuzzModel 将模糊输入解释为操作序列，每个操作占三个字节：操作码、键和值
+// FuzzModel interprets the fuzz input as a sequence of operations, three bytes each: opcode, key and value
+func FuzzModel(f *testing.F) {
+	f.Add([]byte{0, 1, 1, 0, 2, 1, 1, 1, 0, 2, 3, 2})
+	f.Add([]byte{0, 5, 0, 0, 6, 0, 0, 7, 0, 1, 6, 0, 2, 7, 1})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		sl := New[uint8, int8]()
+		m := &sliceModel[uint8, int8]{}
+		for step := 0; step+2 < len(ops); step += 3 {
+			key, value := ops[step+1]%16, int8(ops[step+2]%4)
+			switch ops[step] % 3 {
+			case 0:
+				sl.Set(key, value)
+				m.set(key, value)
+			case 1:
+				sl.Del(key)
+				m.del(key)
+			case 2:
+				old, _ := m.get(key)
+				m.set(key, old+value)
+				sl.IncrBy(key, value)
+			}
+			requireSliceModel(t, sl, m, step/3)
+		}
+	})
+}
+
+// 以下回归测试固定了随机测试所覆盖的两个跨度边界：插入比前驱矮的节点，以及删除高层没有后继的节点
+// The regression tests below pin the two span edge cases the randomized test covers:
+// inserting a node shorter than its predecessors, and deleting nodes that have no successor on higher levels
+
+func TestSpanAboveNewNode(t *testing.T) {
+	sl := New[string, int]()
+	sl.setLevel("a", 1, 3)
+	sl.setLevel("d", 4, 3)
+
+	// c 只有一层，a 在第 1、2 层的后继 d 的跨度需要加一；e 之后这些层没有后继
+	// c is one level tall, so d's spans on levels 1 and 2 grow by one; after e those levels have no successor
+	sl.setLevel("c", 3, 1)
+	sl.setLevel("e", 5, 1)
+	sl.setLevel("b", 2, 2)
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+	m := &sliceModel[string, int]{}
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		m.set(key, i+1)
+	}
+	requireSliceModel(t, sl, m, 0)
+}
+
+func TestDelWithoutSuccessor(t *testing.T) {
+	sl := New[string, int]()
+	sl.setLevel("a", 1, 3)
+	sl.setLevel("b", 2, 1)
+	sl.setLevel("c", 3, 2)
+	m := &sliceModel[string, int]{}
+	for i, key := range []string{"a", "b", "c"} {
+		m.set(key, i+1)
+	}
+
+	// 依次删除尾节点、中间节点和最高的节点，跳表层级随之下降
+	// Delete the tail, the middle node and the tallest node in turn, the list level shrinks along the way
+	for step, key := range []string{"c", "b", "a"} {
+		sl.Del(key)
+		m.del(key)
+		requireSliceModel(t, sl, m, step)
+	}
+	if sl.level != 1 || sl.tail != nil {
+		t.Fatalf("expected an empty list of level 1, got level %d", sl.level)
+	}
+}
+
+func TestTallNodeRaisesLevel(t *testing.T) {
+	for _, position := range []int{0, 1, 500, 999, 1000} {
+		sl := New[int, int]()
+		m := &sliceModel[int, int]{}
+		for i := 0; i < 1000; i++ {
+			sl.setLevel(i*2, i*2, 1)
+			m.set(i*2, i*2)
+		}
+		if sl.level != 1 {
+			t.Fatalf("expected a flat list, got level %d", sl.level)
+		}
+
+		// 在已有 1000 个一层节点的跳表中插入第一个高节点，跳表层级从 1 升到 MaxLevel
+		// Insert the first tall node into a list of 1000 level-1 nodes, raising the list level from 1 to MaxLevel
+		key := position*2 - 1
+		sl.setLevel(key, key, MaxLevel)
+		m.set(key, key)
+		requireSliceModel(t, sl, m, position)
+
+		// 之后的普通插入和删除继续经过新增的层
+		// Later ordinary inserts and deletes keep going through the new levels
+		for i := 0; i < 200; i++ {
+			k := rand.IntN(3000)
+			if i%3 == 0 {
+				sl.Del(k)
+				m.del(k)
+			} else {
+				sl.Set(k, k)
+				m.set(k, k)
+			}
+		}
+		requireSliceModel(t, sl, m, position)
+	}
+}
+
+func TestDelTailAndOnlyTallNode(t *testing.T) {
+	sl := New[int, int]()
+	m := &sliceModel[int, int]{}
+	for i := 0; i < 50; i++ {
+		sl.setLevel(i, i, 1+i%3)
+		m.set(i, i)
+	}
+	sl.setLevel(25, 25, MaxLevel)
+	m.set(25, 25)
+
+	// 反复删除尾节点并重新插入，检查尾部附近的窗口
+	// Repeatedly delete the tail and reinsert it, checking the windows near the tail
+	for round := 0; round < 20; round++ {
+		tail := m.entries[len(m.entries)-1]
+		sl.Del(tail.Key)
+		m.del(tail.Key)
+		requireSliceModel(t, sl, m, round)
+
+		if round%2 == 0 {
+			sl.Set(tail.Key, tail.Value)
+			m.set(tail.Key, tail.Value)
+		}
+		n := len(m.entries)
+		if got := sl.Range(n-2, n+1); !slices.Equal(got, m.entries[n-3:]) {
+			t.Fatalf("round %d: tail window expected %v, got %v", round, m.entries[n-3:], got)
+		}
+	}
+
+	// 删除唯一的高节点，跳表层级随之下降
+	// Delete the only tall node, the list level drops with it
+	sl.Del(25)
+	m.del(25)
+	requireSliceModel(t, sl, m, 0)
+	if sl.level >= MaxLevel {
+		t.Fatalf("expected the list level to drop after deleting the only tall node, got %d", sl.level)
+	}
+
+	for len(m.entries) > 0 {
+		tail := m.entries[len(m.entries)-1]
+		sl.Del(tail.Key)
+		m.del(tail.Key)
+		requireSliceModel(t, sl, m, len(m.entries))
+	}
+}
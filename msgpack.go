@@ -0,0 +1,349 @@
+package ranklist
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// EncodeMsgpack 将跳表以 MessagePack 格式写入 w：一个按排名顺序排列的数组，每个元素是 [key, value] 两元素数组。
+// 整数使用能容纳其值的最短编码，浮点数保持原有精度，字符串使用 str 类型。
+// 条目在读锁内复制，编码和写入在锁外进行，Reserve 插入的占位条目不写入
+// EncodeMsgpack writes the skip list to w in MessagePack format: one array in rank order
+// whose elements are two-element [key, value] arrays.
+// Integers use the shortest encoding that holds them, floats keep their precision and strings use the str type.
+// Entries are copied under the read lock and encoded outside of it, placeholders inserted by Reserve are left out
+func (sl *RankList[K, V]) EncodeMsgpack(w io.Writer) error {
+	entries := sl.savedEntries()
+
+	bw := bufio.NewWriter(w)
+	buf := appendMsgpackArray(make([]byte, 0, 64), len(entries))
+	for _, entry := range entries {
+		buf = appendMsgpackArray(buf, 2)
+		buf = appendMsgpack(buf, entry.Key)
+		buf = appendMsgpack(buf, entry.Value)
+		if len(buf) >= 4096 {
+			if _, err := bw.Write(buf); err != nil {
+				return err
+			}
+			buf = buf[:0]
+		}
+	}
+	if _, err := bw.Write(buf); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// DecodeMsgpack 从 r 读取 EncodeMsgpack 写入的 MessagePack 数据并整体替换跳表的内容。
+// 整数可以使用任意宽度的整数编码，只要值能放入目标类型；浮点类型也接受整数编码。
+// 按排名排序且键唯一的数据以 O(n) 直接构建，否则按 UnmarshalJSON 的规则排序去重。
+// 数据格式不正确或数值溢出时返回 ErrInvalidMsgpack，跳表保持不变
+// DecodeMsgpack reads MessagePack data written by EncodeMsgpack from r and replaces the whole content of the list.
+// Integers may use any integer encoding as long as the value fits the target type; float types accept integers too.
+// Data in rank order with unique keys is built directly in O(n), anything else is sorted and deduplicated
+// following the rules of UnmarshalJSON.
+// Malformed data or an overflowing number returns ErrInvalidMsgpack and leaves the list untouched
+func (sl *RankList[K, V]) DecodeMsgpack(r io.Reader) error {
+	entries, err := readMsgpackEntries[K, V](bufio.NewReader(r))
+	if err != nil {
+		return err
+	}
+
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return ErrClosed
+	}
+	zones := sl.zoneKeys()
+	sl.loadSorted(entries)
+	sl.evictOverflow()
+	sl.journalReset()
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	fireThresholds(events)
+	return nil
+}
+
+// readMsgpackEntries 解码条目数组，数组长度只用于有上限的预分配，避免恶意的长度耗尽内存
+// readMsgpackEntries decodes the array of entries,
+// the array length only drives a bounded preallocation so a hostile length cannot exhaust memory
+func readMsgpackEntries[K Ordered, V Ordered](r *bufio.Reader) ([]Entry[K, V], error) {
+	count, err := readMsgpackArray(r)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry[K, V], 0, min(count, 1<<16))
+	for i := 0; i < count; i++ {
+		size, err := readMsgpackArray(r)
+		if err != nil {
+			return nil, err
+		}
+		if size != 2 {
+			return nil, fmt.Errorf("%w: entry %d has %d elements", ErrInvalidMsgpack, i, size)
+		}
+		var entry Entry[K, V]
+		if entry.Key, err = readMsgpack[K](r); err != nil {
+			return nil, err
+		}
+		if entry.Value, err = readMsgpack[V](r); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// appendMsgpackArray 追加长度为 n 的数组头
+// appendMsgpackArray appends the header of an array of n elements
+func appendMsgpackArray(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xdc), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(buf, 0xdd), uint32(n))
+	}
+}
+
+// appendMsgpack 将 v 按其底层种类编码为 MessagePack 并追加到 buf
+// appendMsgpack appends the MessagePack encoding of v to buf according to its underlying kind
+func appendMsgpack[T Ordered](buf []byte, v T) []byte {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x := rv.Int()
+		switch {
+		case x >= 0:
+			return appendMsgpackUint(buf, uint64(x))
+		case x >= -32:
+			return append(buf, byte(x))
+		case x >= math.MinInt8:
+			return append(buf, 0xd0, byte(x))
+		case x >= math.MinInt16:
+			return binary.BigEndian.AppendUint16(append(buf, 0xd1), uint16(x))
+		case x >= math.MinInt32:
+			return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(x))
+		default:
+			return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(x))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendMsgpackUint(buf, rv.Uint())
+	case reflect.Float32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xca), math.Float32bits(float32(rv.Float())))
+	case reflect.Float64:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcb), math.Float64bits(rv.Float()))
+	default:
+		s := rv.String()
+		switch n := len(s); {
+		case n < 32:
+			buf = append(buf, 0xa0|byte(n))
+		case n <= math.MaxUint8:
+			buf = append(buf, 0xd9, byte(n))
+		case n <= math.MaxUint16:
+			buf = binary.BigEndian.AppendUint16(append(buf, 0xda), uint16(n))
+		default:
+			buf = binary.BigEndian.AppendUint32(append(buf, 0xdb), uint32(n))
+		}
+		return append(buf, s...)
+	}
+}
+
+// appendMsgpackUint 使用能容纳 x 的最短无符号编码
+// appendMsgpackUint uses the shortest unsigned encoding that holds x
+func appendMsgpackUint(buf []byte, x uint64) []byte {
+	switch {
+	case x <= 0x7f:
+		return append(buf, byte(x))
+	case x <= math.MaxUint8:
+		return append(buf, 0xcc, byte(x))
+	case x <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xcd), uint16(x))
+	case x <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xce), uint32(x))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcf), x)
+	}
+}
+
+// msgpackTruncated 表示数据在一个值的中间结束
+// msgpackTruncated reports data that ends in the middle of a value
+var msgpackTruncated = fmt.Errorf("%w: truncated data", ErrInvalidMsgpack)
+
+// readMsgpackBytes 读取恰好 n 个字节
+// readMsgpackBytes reads exactly n bytes
+func readMsgpackBytes(r *bufio.Reader, n int) ([]byte, error) {
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, msgpackTruncated
+	}
+	return data, nil
+}
+
+// readMsgpackArray 读取数组头并返回元素数量
+// readMsgpackArray reads an array header and returns the number of elements
+func readMsgpackArray(r *bufio.Reader) (int, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return 0, msgpackTruncated
+	}
+	switch {
+	case tag&0xf0 == 0x90:
+		return int(tag & 0x0f), nil
+	case tag == 0xdc:
+		data, err := readMsgpackBytes(r, 2)
+		if err != nil {
+			return 0, err
+		}
+		return int(binary.BigEndian.Uint16(data)), nil
+	case tag == 0xdd:
+		data, err := readMsgpackBytes(r, 4)
+		if err != nil {
+			return 0, err
+		}
+		return int(binary.BigEndian.Uint32(data)), nil
+	default:
+		return 0, fmt.Errorf("%w: expected an array, got tag 0x%02x", ErrInvalidMsgpack, tag)
+	}
+}
+
+// readMsgpack 解码一个值并转换为 T 的底层种类，类型不匹配或数值溢出时返回 ErrInvalidMsgpack
+// readMsgpack decodes one value and converts it to the underlying kind of T,
+// returning ErrInvalidMsgpack on a type mismatch or an overflowing number
+func readMsgpack[T Ordered](r *bufio.Reader) (T, error) {
+	var v T
+	tag, err := r.ReadByte()
+	if err != nil {
+		return v, msgpackTruncated
+	}
+
+	rv := reflect.ValueOf(&v).Elem()
+	if rv.Kind() == reflect.String {
+		var size int
+		switch {
+		case tag&0xe0 == 0xa0:
+			size = int(tag & 0x1f)
+		case tag >= 0xd9 && tag <= 0xdb:
+			data, err := readMsgpackBytes(r, 1<<(tag-0xd9))
+			if err != nil {
+				return v, err
+			}
+			size = int(readBigEndian(data))
+		default:
+			return v, fmt.Errorf("%w: expected a string, got tag 0x%02x", ErrInvalidMsgpack, tag)
+		}
+		data, err := readMsgpackBytes(r, size)
+		if err != nil {
+			return v, err
+		}
+		rv.SetString(string(data))
+		return v, nil
+	}
+
+	var (
+		signed   int64
+		unsigned uint64
+		float    float64
+		kind     reflect.Kind
+	)
+	switch {
+	case tag <= 0x7f:
+		unsigned, kind = uint64(tag), reflect.Uint64
+	case tag >= 0xe0:
+		signed, kind = int64(int8(tag)), reflect.Int64
+	case tag >= 0xcc && tag <= 0xcf:
+		data, err := readMsgpackBytes(r, 1<<(tag-0xcc))
+		if err != nil {
+			return v, err
+		}
+		unsigned, kind = readBigEndian(data), reflect.Uint64
+	case tag >= 0xd0 && tag <= 0xd3:
+		data, err := readMsgpackBytes(r, 1<<(tag-0xd0))
+		if err != nil {
+			return v, err
+		}
+		x := readBigEndian(data)
+		switch len(data) {
+		case 1:
+			signed = int64(int8(x))
+		case 2:
+			signed = int64(int16(x))
+		case 4:
+			signed = int64(int32(x))
+		default:
+			signed = int64(x)
+		}
+		kind = reflect.Int64
+	case tag == 0xca:
+		data, err := readMsgpackBytes(r, 4)
+		if err != nil {
+			return v, err
+		}
+		float, kind = float64(math.Float32frombits(binary.BigEndian.Uint32(data))), reflect.Float64
+	case tag == 0xcb:
+		data, err := readMsgpackBytes(r, 8)
+		if err != nil {
+			return v, err
+		}
+		float, kind = math.Float64frombits(binary.BigEndian.Uint64(data)), reflect.Float64
+	default:
+		return v, fmt.Errorf("%w: expected a number, got tag 0x%02x", ErrInvalidMsgpack, tag)
+	}
+
+	overflow := fmt.Errorf("%w: number out of range for %s", ErrInvalidMsgpack, rv.Type())
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch kind {
+		case reflect.Uint64:
+			if unsigned > math.MaxInt64 {
+				return v, overflow
+			}
+			signed = int64(unsigned)
+		case reflect.Float64:
+			return v, fmt.Errorf("%w: expected an integer, got a float", ErrInvalidMsgpack)
+		}
+		if rv.OverflowInt(signed) {
+			return v, overflow
+		}
+		rv.SetInt(signed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch kind {
+		case reflect.Int64:
+			if signed < 0 {
+				return v, overflow
+			}
+			unsigned = uint64(signed)
+		case reflect.Float64:
+			return v, fmt.Errorf("%w: expected an integer, got a float", ErrInvalidMsgpack)
+		}
+		if rv.OverflowUint(unsigned) {
+			return v, overflow
+		}
+		rv.SetUint(unsigned)
+	default:
+		switch kind {
+		case reflect.Int64:
+			float = float64(signed)
+		case reflect.Uint64:
+			float = float64(unsigned)
+		}
+		rv.SetFloat(float)
+	}
+	return v, nil
+}
+
+// readBigEndian 将 1、2、4 或 8 字节的大端数据读为无符号整数
+// readBigEndian reads 1, 2, 4 or 8 bytes of big-endian data as an unsigned integer
+func readBigEndian(data []byte) uint64 {
+	var x uint64
+	for _, b := range data {
+		x = x<<8 | uint64(b)
+	}
+	return x
+}
@@ -0,0 +1,139 @@
+package ranklist
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func testMsgpackRoundTrip[K Ordered, V Ordered](t *testing.T, sl *RankList[K, V]) {
+	var buf bytes.Buffer
+	if err := sl.EncodeMsgpack(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded := New[K, V]()
+	decoded.Set(ZeroValue[K](), ZeroValue[V]())
+	if err := decoded.DecodeMsgpack(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected, result := sl.Entries(), decoded.Entries(); !slices.Equal(result, expected) {
+		t.Fatalf("round trip mismatch:\nexpected %v\ngot      %v", expected, result)
+	}
+	if err := decoded.Check(); err != nil {
+		t.Fatalf("decoded list is inconsistent: %v", err)
+	}
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	ints := New[int64, int64]()
+	for _, x := range []int64{0, 1, -1, 127, 128, -32, -33, 255, 256, -128, -129, 65535, 65536, -32768, -32769,
+		math.MaxInt32, math.MaxInt32 + 1, math.MinInt32, math.MinInt32 - 1, math.MaxInt64, math.MinInt64} {
+		ints.Set(x, -x)
+	}
+	for i := 0; i < 1000; i++ {
+		ints.Set(rand.Int64(), rand.Int64N(1<<40)-1<<39)
+	}
+	testMsgpackRoundTrip(t, ints)
+
+	floats := New[int, float64]()
+	for i, x := range []float64{0, -0.5, 1.25, math.Pi, math.MaxFloat64, math.SmallestNonzeroFloat64, math.Inf(-1)} {
+		floats.Set(i, x)
+	}
+	for i := 10; i < 1000; i++ {
+		floats.Set(i, rand.NormFloat64()*1e6)
+	}
+	testMsgpackRoundTrip(t, floats)
+
+	strs := New[string, string]()
+	for _, size := range []int{0, 1, 31, 32, 255, 256, 65535, 65536} {
+		strs.Set(strconv.Itoa(size), string(bytes.Repeat([]byte{'x'}, size)))
+	}
+	strs.Set("unicode", "排行榜")
+	testMsgpackRoundTrip(t, strs)
+
+	large := New[string, uint32]()
+	for i := 0; i < 70000; i++ {
+		large.Set(strconv.Itoa(i), rand.Uint32())
+	}
+	testMsgpackRoundTrip(t, large)
+
+	testMsgpackRoundTrip(t, New[string, int]())
+}
+
+func TestMsgpackForeignEncodings(t *testing.T) {
+	// 其他编码器可能为小整数选择更宽的编码，为浮点数字段写入整数
+	// Other encoders may pick wider encodings for small integers and write integers into float fields
+	data := []byte{
+		0x93,
+		0x92, 0xd3, 0, 0, 0, 0, 0, 0, 0, 1, 0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0,
+		0x92, 0xcd, 0, 2, 0x05,
+		0x92, 0xd0, 0xff, 0xca, 0xc0, 0x20, 0, 0,
+	}
+	sl := New[int8, float32]()
+	if err := sl.DecodeMsgpack(bytes.NewReader(data)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Entry[int8, float32]{{-1, -2.5}, {1, 1.5}, {2, 5}}
+	if entries := sl.Entries(); !slices.Equal(entries, expected) {
+		t.Fatalf("expected %v, got %v", expected, entries)
+	}
+}
+
+func TestMsgpackInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	source := New[string, int8]()
+	source.Set("a", 1)
+	source.Set("b", -2)
+	source.EncodeMsgpack(&buf)
+	data := buf.Bytes()
+
+	cases := map[string][]byte{
+		"empty":      {},
+		"not array":  {0xa1, 'a'},
+		"entry size": {0x91, 0x93, 0xa1, 'a', 0x01, 0x01},
+		"truncated":  data[:len(data)-1],
+		"overflow":   {0x91, 0x92, 0xa1, 'a', 0xcc, 0xff},
+		"negative":   {0x91, 0x92, 0xa1, 'a', 0xd1, 0x80, 0x00},
+		"float":      {0x91, 0x92, 0xa1, 'a', 0xca, 0, 0, 0, 0},
+		"wrong key":  {0x91, 0x92, 0x01, 0x01},
+	}
+	for name, input := range cases {
+		sl := New[string, int8]()
+		sl.Set("kept", 7)
+		if err := sl.DecodeMsgpack(bytes.NewReader(input)); !errors.Is(err, ErrInvalidMsgpack) {
+			t.Errorf("%s: expected ErrInvalidMsgpack, got %v", name, err)
+		}
+		if value, _ := sl.Get("kept"); value != 7 || sl.Length() != 1 {
+			t.Errorf("%s: a failed decode should leave the list untouched", name)
+		}
+	}
+
+	unsigned := New[string, uint8]()
+	if err := unsigned.DecodeMsgpack(bytes.NewReader([]byte{0x91, 0x92, 0xa1, 'a', 0xff})); !errors.Is(err, ErrInvalidMsgpack) {
+		t.Errorf("a negative value for an unsigned type should fail, got %v", err)
+	}
+}
+
+func TestMsgpackUnsortedInput(t *testing.T) {
+	// 乱序且包含重复键的输入按 UnmarshalJSON 的规则排序去重
+	// Unsorted input with duplicate keys is sorted and deduplicated following the rules of UnmarshalJSON
+	data := []byte{
+		0x93,
+		0x92, 0xa1, 'a', 0x09,
+		0x92, 0xa1, 'b', 0x02,
+		0x92, 0xa1, 'a', 0x05,
+	}
+	sl := New[string, int]()
+	if err := sl.DecodeMsgpack(bytes.NewReader(data)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Entry[string, int]{{"b", 2}, {"a", 5}}
+	if entries := sl.Entries(); !slices.Equal(entries, expected) {
+		t.Fatalf("expected %v, got %v", expected, entries)
+	}
+}
@@ -0,0 +1,217 @@
+package ranklist
+
+import "slices"
+
+// MGet 返回多个键的值，不存在的键不会出现在结果中。
+// 与 Get 一样只获取字典的读锁，所有值来自同一时刻
+// MGet returns the values of several keys, missing keys are absent from the result.
+// Like Get it only takes the dictionary's read lock, and all values come from the same moment
+func (sl *RankList[K, V]) MGet(keys []K) map[K]V {
+	defer sl.observe("mget", sl.metricsStart(), len(keys))
+	sl.vars.add(opGet)
+	if sl.noDict {
+		sl.RLock()
+		defer sl.RUnlock()
+	} else {
+		sl.dictMu.RLock()
+		defer sl.dictMu.RUnlock()
+	}
+
+	values := make(map[K]V, len(keys))
+	for _, node := range sl.lookupAll(keys) {
+		values[node.data.Key] = node.data.Value
+	}
+	return values
+}
+
+// MRank 在一把读锁内返回多个键的排名，不存在的键不会出现在结果中。
+// 请求的键按存储的值排序后从左到右一次扫过跳表，每次下降都从上一个键停下的位置继续，
+// 总开销相当于一次遍历加上 k 次查找，结果与逐个调用 Rank 完全相同
+// MRank returns the ranks of several keys under one read lock, missing keys are absent from the result.
+// The requested keys are sorted by their stored values and resolved in a single left-to-right sweep,
+// every descent resuming where the previous key stopped,
+// so the total work is one traversal plus k lookups, and the results match individual Rank calls exactly
+func (sl *RankList[K, V]) MRank(keys []K) map[K]int {
+	defer sl.observe("mrank", sl.metricsStart(), len(keys))
+	sl.vars.add(opRank)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	nodes := sl.lookupAll(keys)
+	ranks := make(map[K]int, len(nodes))
+	for i, rank := range sl.sweepRanks(nodes) {
+		ranks[nodes[i].data.Key] = rank
+	}
+	return ranks
+}
+
+// RankedEntry 表示附带排名的键值对，排名从 1 开始，为 0 表示键不存在
+// RankedEntry represents a key-value pair together with its 1-based rank, 0 when the key does not exist
+type RankedEntry[K Ordered, V Ordered] struct {
+	Entry[K, V]
+	Rank int `json:"rank"`
+}
+
+// RankedEntries 在一把读锁内解析多个键的排名和值，按排名从前到后排列，不存在的键不会出现在结果中，
+// 重复的键只出现一次。与 MRank 一样在一次从左到右的扫描中完成，适合把排名拼接到其他来源的查询结果上
+// RankedEntries resolves the ranks and values of several keys under one read lock and returns them in rank order,
+// missing keys are absent from the result and duplicated keys appear once.
+// Like MRank it resolves them in a single left-to-right sweep, suited to joining ranks onto query results from elsewhere
+func (sl *RankList[K, V]) RankedEntries(keys []K) []RankedEntry[K, V] {
+	return sl.rankedEntries(keys, false)
+}
+
+// RankedEntriesWithMissing 与 RankedEntries 相同，但不存在的键也会以排名 0 和零值出现在结果末尾，按请求中的顺序排列
+// RankedEntriesWithMissing behaves like RankedEntries, but missing keys are included too,
+// with rank 0 and the zero value at the end of the result in request order
+func (sl *RankList[K, V]) RankedEntriesWithMissing(keys []K) []RankedEntry[K, V] {
+	return sl.rankedEntries(keys, true)
+}
+
+// rankedEntries 实现 RankedEntries 和 RankedEntriesWithMissing，missing 为 true 时保留不存在的键
+// rankedEntries implements RankedEntries and RankedEntriesWithMissing, keeping missing keys when missing is true
+func (sl *RankList[K, V]) rankedEntries(keys []K, missing bool) []RankedEntry[K, V] {
+	defer sl.observe("mrank", sl.metricsStart(), len(keys))
+	sl.vars.add(opRank)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	seen := make(map[K]struct{}, len(keys))
+	nodes := make([]*Node[K, V], 0, len(keys))
+	var absent []K
+	for _, key := range keys {
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		if node, exists := sl.lookup(key); exists {
+			nodes = append(nodes, node)
+		} else if missing {
+			absent = append(absent, key)
+		}
+	}
+
+	entries := make([]RankedEntry[K, V], len(nodes), len(nodes)+len(absent))
+	for i, rank := range sl.sweepRanks(nodes) {
+		entries[i] = RankedEntry[K, V]{Entry: nodes[i].data, Rank: rank}
+	}
+	slices.SortFunc(entries, func(a, b RankedEntry[K, V]) int {
+		return a.Rank - b.Rank
+	})
+	for _, key := range absent {
+		entries = append(entries, RankedEntry[K, V]{Entry: Entry[K, V]{Key: key}})
+	}
+	return entries
+}
+
+// RankDistance 在一把读锁内返回 rank(b) - rank(a)，b 排在 a 之后时为正数，任一键不存在时返回 false。
+// 两个键在同一次从左到右的扫描中定位，结果与同一时刻分别调用 Rank 再相减完全相同
+// RankDistance returns rank(b) - rank(a) under one read lock, positive when b ranks after a,
+// and false when either key is missing.
+// Both keys are resolved in the same left-to-right sweep, and the result equals subtracting two Rank calls taken at the same moment
+func (sl *RankList[K, V]) RankDistance(a, b K) (int, bool) {
+	sl.vars.add(opRank)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	nodeA, existsA := sl.lookup(a)
+	nodeB, existsB := sl.lookup(b)
+	if !existsA || !existsB {
+		return 0, false
+	}
+	ranks := sl.sweepRanks([]*Node[K, V]{nodeA, nodeB})
+	return ranks[1] - ranks[0], true
+}
+
+// RangeBetweenKeys 在一把读锁内返回排在 a 和 b 之间的条目，按排名顺序排列，a 和 b 的先后顺序无关紧要。
+// inclusive 为 true 时结果包含 a 和 b 本身，否则只包含严格位于两者之间的条目；任一键不存在时返回空切片和 false。
+// 从排名靠前的键所在的节点沿第 0 层直接走到另一个键，耗时 O(k)，k 为两者之间的条目数
+// RangeBetweenKeys returns the entries ranked between a and b in rank order under one read lock,
+// regardless of which of the two ranks first.
+// With inclusive the result contains a and b themselves, otherwise only the entries strictly between them;
+// an empty slice and false are returned when either key is missing.
+// It walks level 0 straight from the node of the higher ranked key to the other one in O(k),
+// k being the number of entries between them
+func (sl *RankList[K, V]) RangeBetweenKeys(a, b K, inclusive bool) ([]Entry[K, V], bool) {
+	sl.vars.add(opRange)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	entries := make([]Entry[K, V], 0)
+	from, existsA := sl.lookup(a)
+	to, existsB := sl.lookup(b)
+	if !existsA || !existsB {
+		return entries, false
+	}
+	if sl.compare(from.data, to.data) > 0 {
+		from, to = to, from
+	}
+
+	if inclusive {
+		entries = append(entries, from.data)
+	}
+	if from == to {
+		return entries, true
+	}
+	for curr := from.forward[0]; curr != to; curr = curr.forward[0] {
+		entries = append(entries, curr.data)
+	}
+	if inclusive {
+		entries = append(entries, to.data)
+	}
+	return entries, true
+}
+
+// sweepRanks 按存储的值的顺序从左到右一次扫过跳表，返回每个节点的排名，顺序与 nodes 相同，
+// 每次下降都从上一个节点停下的位置继续，调用方需持有读锁
+// sweepRanks resolves the nodes in a single left-to-right sweep in order of their stored values
+// and returns their ranks in the order of nodes, every descent resuming where the previous node stopped.
+// The caller must hold the read lock
+func (sl *RankList[K, V]) sweepRanks(nodes []*Node[K, V]) []int {
+	order := make([]int, len(nodes))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return sl.compare(nodes[a].data, nodes[b].data)
+	})
+
+	// prev[i] 和 rank[i] 是上一个节点在第 i 层停下的位置及其排名，后面的节点排在它之后，可以从这里继续
+	// prev[i] and rank[i] are where the previous node stopped at level i and its rank,
+	// later nodes sort after it and can resume from there
+	var prev [MaxLevel]*Node[K, V]
+	var rank [MaxLevel]int
+	for i := range prev {
+		prev[i] = sl.header
+	}
+
+	ranks := make([]int, len(nodes))
+	for _, j := range order {
+		node := nodes[j]
+		curr, sum := sl.header, 0
+		for i := sl.level - 1; i >= 0; i-- {
+			if rank[i] > sum {
+				curr, sum = prev[i], rank[i]
+			}
+			for curr.forward[i] != nil && sl.compare(curr.forward[i].data, node.data) <= 0 {
+				sum += curr.forward[i].span[i]
+				curr = curr.forward[i]
+			}
+			prev[i], rank[i] = curr, sum
+		}
+		ranks[j] = sum
+	}
+	return ranks
+}
+
+// lookupAll 查找多个键对应的节点，跳过不存在的键，调用方需持有锁
+// lookupAll finds the nodes holding several keys, skipping missing ones. The caller must hold the lock
+func (sl *RankList[K, V]) lookupAll(keys []K) []*Node[K, V] {
+	nodes := make([]*Node[K, V], 0, len(keys))
+	for _, key := range keys {
+		if node, exists := sl.lookup(key); exists {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,231 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestMRank(t *testing.T) {
+	sl := New[string, int]()
+	for i := 0; i < 5000; i++ {
+		sl.Set(strconv.Itoa(i), rand.IntN(200))
+	}
+
+	for round := 0; round < 20; round++ {
+		keys := make([]string, 0, 60)
+		for i := 0; i < 50; i++ {
+			keys = append(keys, strconv.Itoa(rand.IntN(5000)))
+		}
+		keys = append(keys, "missing", keys[0])
+
+		ranks := sl.MRank(keys)
+		for _, key := range keys {
+			expected, exists := sl.Rank(key)
+			rank, ok := ranks[key]
+			if ok != exists || rank != expected {
+				t.Fatalf("Key %s: expected %d, %v, got %d, %v", key, expected, exists, rank, ok)
+			}
+		}
+	}
+
+	if ranks := sl.MRank(nil); len(ranks) != 0 {
+		t.Errorf("expected an empty result, got %v", ranks)
+	}
+}
+
+func TestRankedEntries(t *testing.T) {
+	sl := New[string, int]()
+	for i := 0; i < 2000; i++ {
+		// 只有 20 种值，大量条目并列
+		// Only 20 distinct values, so many entries tie
+		sl.Set(strconv.Itoa(i), rand.IntN(20))
+	}
+
+	for round := 0; round < 20; round++ {
+		keys := make([]string, 0, 60)
+		for i := 0; i < 50; i++ {
+			keys = append(keys, strconv.Itoa(rand.IntN(2000)))
+		}
+		keys = append(keys, "missing", keys[0], keys[1])
+
+		entries := sl.RankedEntries(keys)
+		seen := make(map[string]bool)
+		for i, entry := range entries {
+			if seen[entry.Key] {
+				t.Fatalf("key %s appears twice", entry.Key)
+			}
+			seen[entry.Key] = true
+			rank, _ := sl.Rank(entry.Key)
+			value, _ := sl.Get(entry.Key)
+			if entry.Rank != rank || entry.Value != value {
+				t.Fatalf("key %s: expected rank %d value %d, got %+v", entry.Key, rank, value, entry)
+			}
+			if i > 0 && entries[i-1].Rank >= entry.Rank {
+				t.Fatalf("entries out of rank order at %d: %v", i, entries)
+			}
+		}
+		for _, key := range keys {
+			if _, exists := sl.Get(key); exists != seen[key] {
+				t.Fatalf("key %s: present %v, in result %v", key, exists, seen[key])
+			}
+		}
+
+		withMissing := sl.RankedEntriesWithMissing(keys)
+		if len(withMissing) != len(entries)+1 || !slices.Equal(withMissing[:len(entries)], entries) {
+			t.Fatalf("RankedEntriesWithMissing should extend RankedEntries by the missing key, got %v", withMissing)
+		}
+		if last := withMissing[len(entries)]; last != (RankedEntry[string, int]{Entry: Entry[string, int]{Key: "missing"}}) {
+			t.Fatalf("expected the missing key with rank 0, got %+v", last)
+		}
+	}
+
+	if entries := sl.RankedEntries([]string{"x", "y", "x"}); len(entries) != 0 {
+		t.Errorf("expected an empty result for missing keys, got %v", entries)
+	}
+	missing := sl.RankedEntriesWithMissing([]string{"y", "x", "y"})
+	if len(missing) != 2 || missing[0].Key != "y" || missing[1].Key != "x" || missing[0].Rank != 0 || missing[1].Rank != 0 {
+		t.Errorf("expected y and x with rank 0 in request order, got %v", missing)
+	}
+	if entries := sl.RankedEntries(nil); len(entries) != 0 {
+		t.Errorf("expected an empty result, got %v", entries)
+	}
+}
+
+func TestMGet(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("", 0)
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+
+	values := sl.MGet([]string{"a", "missing", "", "b", "a"})
+	if len(values) != 3 || values["a"] != 1 || values["b"] != 2 {
+		t.Fatalf("unexpected values %v", values)
+	}
+	if value, ok := values[""]; !ok || value != 0 {
+		t.Fatalf("the zero key should be present with value 0, got %v, %v", value, ok)
+	}
+	if _, ok := values["missing"]; ok {
+		t.Fatalf("missing keys should be absent")
+	}
+
+	noDict := New(WithNoDict[string, int]())
+	noDict.Set("a", 1)
+	if values := noDict.MGet([]string{"a", "b"}); len(values) != 1 || values["a"] != 1 {
+		t.Fatalf("without a dictionary: unexpected values %v", values)
+	}
+}
+
+func TestRankDistance(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	sl.Set("d", 30)
+
+	cases := []struct {
+		a, b     string
+		distance int
+	}{
+		{"a", "c", 2},
+		{"c", "a", -2},
+		{"c", "d", 1},
+		{"b", "b", 0},
+	}
+	for _, c := range cases {
+		if distance, ok := sl.RankDistance(c.a, c.b); !ok || distance != c.distance {
+			t.Errorf("RankDistance(%s, %s): expected %d, got %d, %v", c.a, c.b, c.distance, distance, ok)
+		}
+	}
+	if _, ok := sl.RankDistance("a", "x"); ok {
+		t.Errorf("RankDistance should fail for a missing key")
+	}
+	if _, ok := sl.RankDistance("x", "a"); ok {
+		t.Errorf("RankDistance should fail for a missing key")
+	}
+}
+
+func TestRangeBetweenKeys(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	sl.Set("d", 30)
+	sl.Set("e", 40)
+
+	cases := []struct {
+		a, b      string
+		inclusive bool
+		expected  []Entry[string, int]
+	}{
+		{"a", "d", true, []Entry[string, int]{{"a", 10}, {"b", 20}, {"c", 30}, {"d", 30}}},
+		{"d", "a", true, []Entry[string, int]{{"a", 10}, {"b", 20}, {"c", 30}, {"d", 30}}},
+		{"a", "d", false, []Entry[string, int]{{"b", 20}, {"c", 30}}},
+		{"e", "b", false, []Entry[string, int]{{"c", 30}, {"d", 30}}},
+		{"c", "d", true, []Entry[string, int]{{"c", 30}, {"d", 30}}},
+		{"c", "d", false, []Entry[string, int]{}},
+		{"b", "b", true, []Entry[string, int]{{"b", 20}}},
+		{"b", "b", false, []Entry[string, int]{}},
+	}
+	for _, c := range cases {
+		entries, ok := sl.RangeBetweenKeys(c.a, c.b, c.inclusive)
+		if !ok || !slices.Equal(entries, c.expected) {
+			t.Errorf("RangeBetweenKeys(%s, %s, %v): expected %v, got %v, %v", c.a, c.b, c.inclusive, c.expected, entries, ok)
+		}
+	}
+	for _, keys := range [][2]string{{"a", "x"}, {"x", "a"}} {
+		if entries, ok := sl.RangeBetweenKeys(keys[0], keys[1], true); ok || entries == nil || len(entries) != 0 {
+			t.Errorf("RangeBetweenKeys(%s, %s) should return an empty slice and false, got %v, %v", keys[0], keys[1], entries, ok)
+		}
+	}
+}
+
+func TestRankDistanceConcurrent(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("low", 0)
+	sl.Set("high", 1000)
+	for i := 0; i < 100; i++ {
+		sl.Set(strconv.Itoa(i), 500)
+	}
+
+	// 无关的键一直在两个键之间和外侧来回移动，两者之间的成员数因此在 [0, 100] 之间变化
+	// Unrelated keys keep moving between and around the two keys, so the members between them vary within [0, 100]
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				sl.Set(strconv.Itoa(rand.IntN(100)), rand.IntN(1500)-250)
+			}
+		}
+	}()
+
+	for i := 0; i < 2000; i++ {
+		distance, ok := sl.RankDistance("low", "high")
+		if !ok || distance < 1 || distance > 101 {
+			close(done)
+			wg.Wait()
+			t.Fatalf("inconsistent distance %d, %v", distance, ok)
+		}
+		if reverse, _ := sl.RankDistance("high", "low"); reverse > -1 {
+			close(done)
+			wg.Wait()
+			t.Fatalf("expected a negative distance, got %d", reverse)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	lowRank, _ := sl.Rank("low")
+	highRank, _ := sl.Rank("high")
+	if distance, _ := sl.RankDistance("low", "high"); distance != highRank-lowRank {
+		t.Fatalf("expected %d, got %d", highRank-lowRank, distance)
+	}
+}
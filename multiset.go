@@ -0,0 +1,191 @@
+package ranklist
+
+import (
+	"slices"
+	"sync"
+)
+
+// MultiRankList 允许同一个键多次上榜的排行榜，例如每个玩家保留多次成绩。
+// Set 追加一条新的成绩而不是替换已有的成绩，每条成绩单独占据一个排名。
+// 排序与 RankList 相同按值升序，排名 1 是值最小的成绩；值相同的成绩按提交顺序排列，先提交的在前，
+// 同一个键的多条成绩之间和不同键的成绩之间都遵循这一规则，因此顺序总是确定的。
+// 键的最好成绩是它排名最靠前的那一条，Get 和 Rank 返回最好成绩，RanksOf 返回全部成绩的排名
+// MultiRankList is a leaderboard where the same key may appear several times, e.g. keeping several runs per player.
+// Set appends a new entry rather than replacing the existing ones, and every entry occupies a rank of its own.
+// Entries are ordered ascending by value like in RankList, rank 1 being the smallest value;
+// entries with equal values are ordered by submission, the earlier one first,
+// both among the entries of one key and across keys, so the order is always deterministic.
+// The best entry of a key is its highest ranked one, Get and Rank report the best entry and RanksOf all of them
+type MultiRankList[K Ordered, V Ordered] struct {
+	mu sync.RWMutex
+
+	// 按 (值, 提交序号) 排序的全部成绩，键为提交序号
+	// Every entry ordered by (value, submission id), keyed by submission id
+	list *RankList[uint64, V]
+
+	// 提交序号所属的键
+	// Key owning each submission id
+	owners map[uint64]K
+
+	// 每个键的成绩，按 (值, 提交序号) 排序，第一条即最好成绩
+	// Entries of every key ordered by (value, submission id), the first one being the best
+	entries map[K][]Entry[uint64, V]
+
+	// 下一个提交序号
+	// Next submission id
+	seq uint64
+}
+
+// NewMulti 创建一个新的 MultiRankList
+// NewMulti creates a new MultiRankList
+func NewMulti[K Ordered, V Ordered]() *MultiRankList[K, V] {
+	return &MultiRankList[K, V]{
+		list:    New[uint64, V](),
+		owners:  make(map[uint64]K),
+		entries: make(map[K][]Entry[uint64, V]),
+	}
+}
+
+// Set 为键追加一条成绩，已有的成绩保持不变
+// Set appends an entry for key, leaving its existing entries untouched
+func (m *MultiRankList[K, V]) Set(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := Entry[uint64, V]{Key: m.seq, Value: value}
+	m.seq++
+	m.list.Set(entry.Key, value)
+	m.owners[entry.Key] = key
+
+	entries := m.entries[key]
+	i, _ := slices.BinarySearchFunc(entries, entry, compareEntries[uint64, V])
+	m.entries[key] = slices.Insert(entries, i, entry)
+}
+
+// DelEntry 删除键的一条值为 value 的成绩，有多条时删除最后提交的一条，没有匹配的成绩时返回 false
+// DelEntry removes one entry of key whose value is value, the latest submitted one when several match,
+// and returns false when none does
+func (m *MultiRankList[K, V]) DelEntry(key K, value V) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := m.entries[key]
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Value == value {
+			m.list.Del(entries[i].Key)
+			delete(m.owners, entries[i].Key)
+			if len(entries) == 1 {
+				delete(m.entries, key)
+			} else {
+				m.entries[key] = slices.Delete(entries, i, i+1)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Del 删除键的全部成绩，返回删除的条数
+// Del removes every entry of key and returns how many were removed
+func (m *MultiRankList[K, V]) Del(key K) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := m.entries[key]
+	for _, entry := range entries {
+		m.list.Del(entry.Key)
+		delete(m.owners, entry.Key)
+	}
+	delete(m.entries, key)
+	return len(entries)
+}
+
+// Get 返回键的最好成绩，键没有成绩时返回 false
+// Get returns the best value of key, false when the key has no entries
+func (m *MultiRankList[K, V]) Get(key K) (V, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if entries := m.entries[key]; len(entries) > 0 {
+		return entries[0].Value, true
+	}
+	return ZeroValue[V](), false
+}
+
+// Values 按排名顺序返回键的全部成绩
+// Values returns every value of key in rank order
+func (m *MultiRankList[K, V]) Values(key K) []V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries := m.entries[key]
+	values := make([]V, len(entries))
+	for i, entry := range entries {
+		values[i] = entry.Value
+	}
+	return values
+}
+
+// Rank 返回键的最好成绩的排名，键没有成绩时返回 false
+// Rank returns the rank of the best entry of key, false when the key has no entries
+func (m *MultiRankList[K, V]) Rank(key K) (int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if entries := m.entries[key]; len(entries) > 0 {
+		return m.list.Rank(entries[0].Key)
+	}
+	return 0, false
+}
+
+// RanksOf 按升序返回键的全部成绩的排名，在一次扫描中完成，键没有成绩时返回空切片
+// RanksOf returns the ranks of every entry of key in ascending order, resolved in a single sweep,
+// and an empty slice when the key has no entries
+func (m *MultiRankList[K, V]) RanksOf(key K) []int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries := m.entries[key]
+	ids := make([]uint64, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.Key
+	}
+	ranks := m.list.MRank(ids)
+
+	result := make([]int, len(ids))
+	for i, id := range ids {
+		result[i] = ranks[id]
+	}
+	return result
+}
+
+// Count 返回键的成绩条数
+// Count returns the number of entries of key
+func (m *MultiRankList[K, V]) Count(key K) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.entries[key])
+}
+
+// Length 返回全部成绩的条数
+// Length returns the total number of entries
+func (m *MultiRankList[K, V]) Length() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.owners)
+}
+
+// Range 获取排名区间 [start, end) 内的成绩，规则与 RankList.Range 相同，同一个键可能出现多次
+// Range retrieves the entries within the rank range [start, end) following the rules of RankList.Range,
+// the same key may appear several times
+func (m *MultiRankList[K, V]) Range(start int, end int) []Entry[K, V] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	window := m.list.Range(start, end)
+	entries := make([]Entry[K, V], len(window))
+	for i, entry := range window {
+		entries[i] = Entry[K, V]{Key: m.owners[entry.Key], Value: entry.Value}
+	}
+	return entries
+}
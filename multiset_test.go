@@ -0,0 +1,108 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestMultiRankList(t *testing.T) {
+	m := NewMulti[string, int]()
+	m.Set("alice", 30)
+	m.Set("bob", 20)
+	m.Set("alice", 10)
+	m.Set("bob", 30)
+	m.Set("alice", 30)
+
+	// 值相同的成绩按提交顺序排列：alice 的第一个 30、bob 的 30、alice 的第二个 30
+	// Equal values follow submission order: alice's first 30, bob's 30, then alice's second 30
+	expected := []Entry[string, int]{
+		{"alice", 10}, {"bob", 20}, {"alice", 30}, {"bob", 30}, {"alice", 30},
+	}
+	if entries := m.Range(1, 6); !slices.Equal(entries, expected) {
+		t.Fatalf("expected %v, got %v", expected, entries)
+	}
+
+	if value, ok := m.Get("alice"); !ok || value != 10 {
+		t.Fatalf("expected the best value 10, got %d, %v", value, ok)
+	}
+	if rank, ok := m.Rank("bob"); !ok || rank != 2 {
+		t.Fatalf("expected bob's best rank 2, got %d, %v", rank, ok)
+	}
+	if ranks := m.RanksOf("alice"); !slices.Equal(ranks, []int{1, 3, 5}) {
+		t.Fatalf("expected ranks [1 3 5], got %v", ranks)
+	}
+	if values := m.Values("alice"); !slices.Equal(values, []int{10, 30, 30}) {
+		t.Fatalf("expected values [10 30 30], got %v", values)
+	}
+	if m.Count("alice") != 3 || m.Length() != 5 {
+		t.Fatalf("expected 3 entries for alice and 5 in total, got %d and %d", m.Count("alice"), m.Length())
+	}
+
+	// 删除最后提交的那条 30
+	// The latest submitted 30 is removed
+	if !m.DelEntry("alice", 30) || m.DelEntry("alice", 99) {
+		t.Fatalf("DelEntry should remove exactly one matching entry")
+	}
+	if ranks := m.RanksOf("alice"); !slices.Equal(ranks, []int{1, 3}) {
+		t.Fatalf("expected ranks [1 3], got %v", ranks)
+	}
+
+	if removed := m.Del("alice"); removed != 2 {
+		t.Fatalf("expected 2 entries removed, got %d", removed)
+	}
+	if _, ok := m.Rank("alice"); ok || len(m.RanksOf("alice")) != 0 {
+		t.Fatalf("alice should have no entries left")
+	}
+	if entries := m.Range(1, 10); !slices.Equal(entries, []Entry[string, int]{{"bob", 20}, {"bob", 30}}) {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+}
+
+func TestMultiRankListModel(t *testing.T) {
+	m := NewMulti[int, int]()
+	var model []Entry[int, int]
+	for step := 0; step < 2000; step++ {
+		key := rand.IntN(20)
+		switch rand.IntN(4) {
+		case 0:
+			value := rand.IntN(10)
+			removed := m.DelEntry(key, value)
+			i := len(model) - 1
+			for ; i >= 0; i-- {
+				if model[i].Key == key && model[i].Value == value {
+					break
+				}
+			}
+			if removed != (i >= 0) {
+				t.Fatalf("step %d: DelEntry(%d, %d) disagrees with the model", step, key, value)
+			}
+			if i >= 0 {
+				model = slices.Delete(model, i, i+1)
+			}
+		default:
+			value := rand.IntN(10)
+			m.Set(key, value)
+			model = append(model, Entry[int, int]{Key: key, Value: value})
+		}
+	}
+
+	// 稳定排序保留提交顺序，与 MultiRankList 的并列规则一致
+	// A stable sort keeps submission order, matching the tie rule of MultiRankList
+	sorted := slices.Clone(model)
+	slices.SortStableFunc(sorted, func(a, b Entry[int, int]) int { return a.Value - b.Value })
+	if entries := m.Range(1, len(sorted)+1); !slices.Equal(entries, sorted) {
+		t.Fatalf("Range mismatch:\nexpected %v\ngot      %v", sorted, entries)
+	}
+	for key := 0; key < 20; key++ {
+		var ranks []int
+		for i, entry := range sorted {
+			if entry.Key == key {
+				ranks = append(ranks, i+1)
+			}
+		}
+		if got := m.RanksOf(key); !slices.Equal(got, ranks) {
+			t.Fatalf("key %d: expected ranks %v, got %v", key, ranks, got)
+		}
+	}
+}
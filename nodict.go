@@ -0,0 +1,177 @@
+package ranklist
+
+// WithNoDict 创建不维护键到节点字典的跳表，适合 Get 很少使用的超大榜单。
+// 取舍：节省字典占用的内存（键不再存两份），每次修改也少一次字典写入；
+// 但按键查找的操作（Get、Rank、Del、Set 和 IncrBy 判断键是否已存在、元数据操作）都退化为第 0 层的 O(n) 扫描。
+// 已知键当前值的调用方应使用 RankByValue、DelByValue 和 UpdateByValue，它们在 O(log n) 内完成。
+// 批量构建（Restore、FromMap 等）以及 Range、Top 不受影响
+// WithNoDict creates a skip list that does not maintain the dictionary from keys to nodes,
+// meant for enormous boards where Get is rare.
+// Tradeoffs: the memory of the dictionary is saved (keys are no longer stored twice)
+// and every mutation skips a dictionary write, but every lookup by key
+// (Get, Rank, Del, the existence check of Set and IncrBy, metadata operations) degrades to an O(n) scan of level 0.
+// Callers that know a key's current value should use RankByValue, DelByValue and UpdateByValue,
+// which run in O(log n). Bulk builds (Restore, FromMap and friends), Range and Top are unaffected
+func WithNoDict[K Ordered, V Ordered]() Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.noDict = true
+	}
+}
+
+// lookup 查找键对应的节点，没有字典时扫描第 0 层，调用方需持有锁
+// lookup finds the node holding key, scanning level 0 when there is no dictionary. The caller must hold the lock
+func (sl *RankList[K, V]) lookup(key K) (*Node[K, V], bool) {
+	if sl.noDict {
+		for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+			if curr.data.Key == key {
+				return curr, true
+			}
+		}
+		return nil, false
+	}
+	node, exists := sl.dict[key]
+	return node, exists
+}
+
+// seek 按值和键定位节点，耗时 O(log n)，调用方需持有锁
+// seek locates the node holding key/value in O(log n). The caller must hold the lock
+func (sl *RankList[K, V]) seek(key K, value V) (*Node[K, V], bool) {
+	entry := Entry[K, V]{Key: key, Value: value}
+	curr := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for curr.forward[i] != nil && sl.compare(curr.forward[i].data, entry) < 0 {
+			curr = curr.forward[i]
+		}
+	}
+	if node := curr.forward[0]; node != nil && node.data == entry {
+		return node, true
+	}
+	return nil, false
+}
+
+// RankByValue 返回当前值为 value 的键的排名，值不匹配或键不存在时返回 false。
+// 不依赖字典，耗时 O(log n)
+// RankByValue returns the rank of key given its current value,
+// returns false if the value does not match or the key does not exist.
+// It does not depend on the dictionary and runs in O(log n)
+func (sl *RankList[K, V]) RankByValue(key K, value V) (int, bool) {
+	sl.vars.add(opRank)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	rank := sl.countLess(Entry[K, V]{Key: key, Value: value}) + 1
+	if node := sl.byRank(rank); node != nil && node.data.Key == key && node.data.Value == value {
+		return rank, true
+	}
+	return 0, false
+}
+
+// DelByValue 删除当前值为 value 的键，值不匹配或键不存在时返回 false。不依赖字典，耗时 O(log n)
+// DelByValue removes key given its current value, returns false if the value does not match or the key does not exist.
+// It does not depend on the dictionary and runs in O(log n)
+func (sl *RankList[K, V]) DelByValue(key K, value V) bool {
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return false
+	}
+	probes := sl.probeThresholds(key)
+	node, ok := sl.seek(key, value)
+	ok = ok && sl.persist(walOpDel, key, ZeroValue[V]()) == nil
+	var nodeLevel int
+	if ok {
+		nodeLevel = node.level
+		ok, _ = sl.delNode(key, node)
+	}
+	if ok {
+		delete(sl.meta, key)
+		sl.journal(walOpDel, key, ZeroValue[V]())
+	}
+	var trace TraceEvent[K]
+	if sl.tracer != nil {
+		trace = sl.traceEvent(TraceDel, key, ok, nodeLevel)
+	}
+	events := sl.thresholdEvents(key, probes)
+	sl.Unlock()
+
+	sl.vars.add(opDel)
+	if sl.tracer != nil {
+		sl.tracer(trace)
+	}
+	fireThresholds(events)
+	return ok
+}
+
+// UpdateByValue 将当前值为 old 的键更新为 value，值不匹配或键不存在时不做修改并返回 false。
+// 写入与 Set 相同：开启 WithZeroMeansDelete 时零值删除键，键空间事件、追踪回调和计数也与 Set 一致。
+// 不依赖字典，耗时 O(log n)
+// UpdateByValue changes key from its current value old to value,
+// nothing is changed and false is returned if old does not match or the key does not exist.
+// The write behaves like Set: with WithZeroMeansDelete a zero value deletes the key,
+// and keyspace events, the tracer and the counters see it the way they see Set.
+// It does not depend on the dictionary and runs in O(log n)
+func (sl *RankList[K, V]) UpdateByValue(key K, old V, value V) bool {
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return false
+	}
+	probes := sl.probeThresholds(key)
+	node, ok := sl.seek(key, old)
+	op := walOpSet
+	if sl.zeroDeletes && value == ZeroValue[V]() {
+		op = walOpDel
+	}
+	ok = ok && sl.guardValue(old, value) == nil && sl.persist(op, key, value) == nil
+	result := storeSkipped
+	var trace TraceEvent[K]
+	if ok {
+		gen := sl.gen
+		result = sl.storeNode(node, true, key, value, keyspaceZadd)
+		if sl.tracer != nil {
+			trace = sl.traceSet(key, gen)
+		}
+	}
+	events := sl.thresholdEvents(key, probes)
+	sl.Unlock()
+
+	result.count(sl.vars)
+	if ok && sl.tracer != nil {
+		sl.tracer(trace)
+	}
+	fireThresholds(events)
+	return ok
+}
+
+// newDict 创建容量为 size 的字典，没有字典时返回 nil
+// newDict creates a dictionary with room for size keys, nil without a dictionary
+func (sl *RankList[K, V]) newDict(size int) map[K]*Node[K, V] {
+	if sl.noDict {
+		return nil
+	}
+	return make(map[K]*Node[K, V], size)
+}
+
+// dictPut 在字典中记录键对应的节点，没有字典时不做任何事，调用方需持有写锁
+// dictPut records the node of key in the dictionary, it does nothing without a dictionary.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) dictPut(key K, node *Node[K, V]) {
+	if sl.noDict {
+		return
+	}
+	sl.dictMu.Lock()
+	sl.dict[key] = node
+	sl.dictMu.Unlock()
+}
+
+// dictDelete 从字典中删除键，没有字典时不做任何事，调用方需持有写锁
+// dictDelete removes key from the dictionary, it does nothing without a dictionary.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) dictDelete(key K) {
+	if sl.noDict {
+		return
+	}
+	sl.dictMu.Lock()
+	delete(sl.dict, key)
+	sl.dictMu.Unlock()
+}
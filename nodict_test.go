@@ -0,0 +1,179 @@
+package ranklist
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestNoDictModel(t *testing.T) {
+	sl := New(WithNoDict[int, int]())
+	model := make(map[int]int)
+	for i := 0; i < 3000; i++ {
+		key := rand.IntN(300)
+		switch rand.IntN(6) {
+		case 0:
+			sl.Del(key)
+			delete(model, key)
+		case 1:
+			if value, exists := model[key]; exists {
+				if !sl.DelByValue(key, value) {
+					t.Fatalf("Key %d: DelByValue with the current value failed", key)
+				}
+				delete(model, key)
+			}
+		case 2:
+			if value, exists := model[key]; exists {
+				if !sl.UpdateByValue(key, value, value+rand.IntN(5)-2) {
+					t.Fatalf("Key %d: UpdateByValue with the current value failed", key)
+				}
+				model[key], _ = sl.Get(key)
+			}
+		case 3:
+			model[key] = sl.IncrBy(key, rand.IntN(5))
+		default:
+			value := rand.IntN(100)
+			sl.Set(key, value)
+			model[key] = value
+		}
+	}
+
+	if sl.dict != nil {
+		t.Fatalf("expected no dictionary")
+	}
+	requireSpans(t, sl)
+	requireBackward(t, sl)
+	requireModel(t, sl, model)
+}
+
+func TestNoDictByValue(t *testing.T) {
+	sl := New(WithNoDict[string, int]())
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+
+	if rank, ok := sl.RankByValue("b", 20); !ok || rank != 2 {
+		t.Fatalf("expected rank 2, got %d, %v", rank, ok)
+	}
+	if _, ok := sl.RankByValue("b", 21); ok {
+		t.Fatalf("RankByValue should fail for a stale value")
+	}
+	if _, ok := sl.RankByValue("x", 20); ok {
+		t.Fatalf("RankByValue should fail for a missing key")
+	}
+
+	if sl.UpdateByValue("b", 21, 40) {
+		t.Fatalf("UpdateByValue should fail for a stale value")
+	}
+	if !sl.UpdateByValue("b", 20, 40) {
+		t.Fatalf("UpdateByValue should succeed for the current value")
+	}
+	if rank, ok := sl.RankByValue("b", 40); !ok || rank != 3 {
+		t.Fatalf("expected rank 3, got %d, %v", rank, ok)
+	}
+
+	if sl.DelByValue("a", 11) {
+		t.Fatalf("DelByValue should fail for a stale value")
+	}
+	if !sl.DelByValue("a", 10) {
+		t.Fatalf("DelByValue should succeed for the current value")
+	}
+	if _, exists := sl.Get("a"); exists {
+		t.Fatalf("Key a should be gone")
+	}
+	if sl.Length() != 2 {
+		t.Fatalf("expected length 2, got %d", sl.Length())
+	}
+}
+
+func TestByValueWithDict(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+
+	if !sl.UpdateByValue("a", 10, 30) {
+		t.Fatalf("UpdateByValue should succeed for the current value")
+	}
+	if value, _ := sl.Get("a"); value != 30 {
+		t.Fatalf("expected 30, got %d", value)
+	}
+	if !sl.DelByValue("b", 20) {
+		t.Fatalf("DelByValue should succeed for the current value")
+	}
+	requireModel(t, sl, map[string]int{"a": 30})
+}
+
+func TestByValueZeroDeleteAndTracer(t *testing.T) {
+	for _, noDict := range []bool{false, true} {
+		var traces []TraceEvent[string]
+		var events []string
+		opts := []Option[string, int]{
+			WithZeroMeansDelete[string, int](),
+			WithTracer[string, int](func(event TraceEvent[string]) {
+				traces = append(traces, event)
+			}),
+			WithKeyspaceEvents[string, int]("board", func(event, member string) {
+				events = append(events, event+" "+member)
+			}, nil),
+		}
+		if noDict {
+			opts = append(opts, WithNoDict[string, int]())
+		}
+		sl := New(opts...)
+		sl.Set("a", 10)
+		sl.Set("b", 20)
+		sl.Set("c", 30)
+		traces, events = nil, nil
+
+		if !sl.UpdateByValue("a", 10, 15) {
+			t.Fatalf("noDict=%v: UpdateByValue should succeed for the current value", noDict)
+		}
+		// 零值与 Set 一样删除键 / A zero value deletes the key as Set does
+		if !sl.UpdateByValue("b", 20, 0) {
+			t.Fatalf("noDict=%v: UpdateByValue to zero should succeed", noDict)
+		}
+		if !sl.DelByValue("c", 30) {
+			t.Fatalf("noDict=%v: DelByValue should succeed for the current value", noDict)
+		}
+		expected := []string{"zadd a", "zrem b", "zrem c"}
+		if !slices.Equal(events, expected) {
+			t.Fatalf("noDict=%v: expected events %q, got %q", noDict, expected, events)
+		}
+		if len(traces) != 3 || traces[0].Op != TraceSet || traces[1].Op != TraceSet || traces[2].Op != TraceDel {
+			t.Fatalf("noDict=%v: unexpected traces %+v", noDict, traces)
+		}
+		if !traces[1].Structural || !traces[2].Structural || traces[2].NodeLevel < 1 || traces[2].Length != 1 {
+			t.Fatalf("noDict=%v: expected the deletes to be structural, got %+v", noDict, traces)
+		}
+		requireModel(t, sl, map[string]int{"a": 15})
+	}
+}
+
+func TestNoDictBulk(t *testing.T) {
+	model := make(map[int]int)
+	for i := 0; i < 1000; i++ {
+		model[i] = rand.IntN(100)
+	}
+	sl := FromMap(model, WithNoDict[int, int]())
+	requireModel(t, sl, model)
+
+	var buf bytes.Buffer
+	if err := sl.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded := New(WithNoDict[int, int]())
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	requireModel(t, loaded, model)
+
+	if stats := loaded.Stats(); stats.DictBytes != 0 {
+		t.Fatalf("expected no dictionary bytes, got %d", stats.DictBytes)
+	}
+
+	loaded.Clear()
+	if loaded.Length() != 0 || loaded.dict != nil {
+		t.Fatalf("expected an empty list without a dictionary")
+	}
+}
@@ -0,0 +1,84 @@
+package ranklist
+
+// OpStats 是操作计数器的快照，各项的含义与 WithExpvar 发布的同名计数器相同
+// OpStats is a snapshot of the operation counters, every field means the same as the counter WithExpvar publishes under that name
+type OpStats struct {
+	// Set 的次数，其中覆盖已有键的次数
+	// Number of Sets, and how many of them replaced an existing key
+	Sets    int64
+	Updates int64
+
+	// Del 的次数
+	// Number of Dels
+	Deletes int64
+
+	// Get、Rank 和 Range 的次数
+	// Number of Gets, Ranks and Ranges
+	Gets   int64
+	Ranks  int64
+	Ranges int64
+
+	// 被 WithValueGuard、WithMaxDelta 或 WithHardLimit 拒绝的写入数
+	// Number of writes refused by WithValueGuard, WithMaxDelta or WithHardLimit
+	Rejected int64
+
+	// WithMaxSize 淘汰的成员数
+	// Number of members evicted by WithMaxSize
+	Evictions int64
+
+	// 修复的字典不一致数
+	// Number of repaired dictionary inconsistencies
+	Divergences int64
+}
+
+// WithOpStats 开启操作计数器，供 StatsSnapshot 和 ResetStats 使用，不需要通过 expvar 发布。
+// 计数器在热路径上以原子操作累加，未开启时只多一次 nil 判断
+// WithOpStats enables the operation counters behind StatsSnapshot and ResetStats without publishing them through expvar.
+// The counters are bumped with atomics on the hot path, without them it only pays a nil check
+func WithOpStats[K Ordered, V Ordered]() Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		if sl.vars == nil {
+			sl.vars = &opVars{}
+		}
+	}
+}
+
+// StatsSnapshot 返回自创建或上一次 ResetStats 以来的操作计数，只读取原子计数器，不获取跳表的锁。
+// 需要开启 WithOpStats 或 WithExpvar，否则返回零值
+// StatsSnapshot returns the operation counts since creation or the last ResetStats,
+// reading only the atomic counters without taking the list's lock.
+// It requires WithOpStats or WithExpvar and returns the zero value otherwise
+func (sl *RankList[K, V]) StatsSnapshot() OpStats {
+	v := sl.vars
+	if v == nil {
+		return OpStats{}
+	}
+	count := func(op int) int64 {
+		return v.counters[op].Value() - v.base[op].Load()
+	}
+	return OpStats{
+		Sets:        count(opSet),
+		Updates:     count(opUpdate),
+		Deletes:     count(opDel),
+		Gets:        count(opGet),
+		Ranks:       count(opRank),
+		Ranges:      count(opRange),
+		Rejected:    count(opRejected),
+		Evictions:   count(opEvict),
+		Divergences: count(opDivergence),
+	}
+}
+
+// ResetStats 将 StatsSnapshot 返回的计数归零，不修改跳表的内容，也不获取跳表的锁。
+// WithExpvar 发布的计数器不受影响，保持单调递增
+// ResetStats zeroes the counts StatsSnapshot returns without touching the content of the list or taking its lock.
+// The counters published by WithExpvar are unaffected and stay monotonic
+func (sl *RankList[K, V]) ResetStats() {
+	v := sl.vars
+	if v == nil {
+		return
+	}
+	for op := range v.counters {
+		v.base[op].Store(v.counters[op].Value())
+	}
+}
@@ -0,0 +1,65 @@
+package ranklist
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStatsSnapshotAndReset(t *testing.T) {
+	sl := New(
+		WithOpStats[string, int](),
+		WithMaxSize[string, int](3),
+		WithValueGuard[string, int](func(old, new int) error {
+			if new < 0 {
+				return errors.New("negative")
+			}
+			return nil
+		}),
+	)
+
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	sl.Set("a", 3)
+	sl.Set("c", 4)
+	sl.Set("d", 5) // 淘汰 b / evicts b
+	sl.Set("e", -1)
+	sl.Get("a")
+	sl.Rank("c")
+	sl.Range(1, 3)
+	sl.Del("d")
+
+	first := sl.StatsSnapshot()
+	expected := OpStats{Sets: 5, Updates: 1, Deletes: 1, Gets: 1, Ranks: 1, Ranges: 1, Rejected: 1, Evictions: 1}
+	if first != expected {
+		t.Fatalf("expected %+v, got %+v", expected, first)
+	}
+
+	before := sl.Entries()
+	sl.ResetStats()
+	if stats := sl.StatsSnapshot(); stats != (OpStats{}) {
+		t.Fatalf("expected zeroed stats after reset, got %+v", stats)
+	}
+	if after := sl.Entries(); len(after) != len(before) {
+		t.Fatalf("ResetStats must not touch the content, got %v", after)
+	}
+
+	sl.Set("f", 6)
+	sl.Get("f")
+	sl.Get("missing")
+	second := sl.StatsSnapshot()
+	if second != (OpStats{Sets: 1, Gets: 2}) {
+		t.Fatalf("expected only the operations after the reset, got %+v", second)
+	}
+	if first.Sets != 5 {
+		t.Fatalf("an earlier snapshot must not change, got %+v", first)
+	}
+}
+
+func TestStatsSnapshotDisabled(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	sl.ResetStats()
+	if stats := sl.StatsSnapshot(); stats != (OpStats{}) {
+		t.Fatalf("expected zero stats without WithOpStats, got %+v", stats)
+	}
+}
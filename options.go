@@ -0,0 +1,5 @@
+package ranklist
+
+// Option 定义创建跳表时的可选配置
+// Option configures optional behaviors of a RankList when it is created
+type Option[K Ordered, V Ordered] func(*RankList[K, V])
@@ -0,0 +1,148 @@
+package ranklist
+
+// WithVisiblePlaceholders 让 Reserve 插入的占位条目出现在 Range、RevRange 和 Top 的结果中，默认它们被跳过
+// WithVisiblePlaceholders makes the placeholders inserted by Reserve show up in Range, RevRange and Top,
+// which skip them by default
+func WithVisiblePlaceholders[K Ordered, V Ordered]() Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.showPlaceholders = true
+	}
+}
+
+// Reserve 以 value 插入一个占位条目，为之后的真实条目预留位置，例如赛事分组时预留的种子位，
+// 键已存在或占位条目插入后立即被 WithMaxSize 淘汰时返回 false。
+// 占位条目与普通条目一样占据排名，Rank、Get、Length 和 Entries 都能看到它，Del 可以将它删除，Set 修改它的值时保留占位标记；
+// 但 Range、RevRange、RangeByScore、CountByScore 和 Top 默认跳过它：Range 和 RevRange 的结果在区间覆盖占位条目时相应变少，
+// Top(n) 返回 n 个非占位条目。
+// 预写日志单独记录占位条目，重放后仍是占位条目；快照无法表示占位标记，Save、MarshalJSON、EncodeJSON 和 EncodeMsgpack 不写入占位条目。
+// WithStore 的外部存储无法表示占位标记，占位条目及对它的写入不会写穿，直到 Claim 将它变为普通条目
+// Reserve inserts a placeholder at value that reserves a slot for a real entry to claim later,
+// e.g. a seeding slot in a tournament. Returns false if the key already exists
+// or the placeholder was evicted by WithMaxSize right after being inserted.
+// A placeholder holds its rank like any other entry: Rank, Get, Length and Entries see it, Del removes it,
+// and Set changes its value while keeping it a placeholder.
+// Range, RevRange, RangeByScore, CountByScore and Top skip it by default though: Range and RevRange return fewer entries
+// when their window covers a placeholder, and Top(n) returns n entries that are not placeholders.
+// The write-ahead log records placeholders as such and replays them as placeholders,
+// while snapshots cannot hold the mark, so Save, MarshalJSON, EncodeJSON and EncodeMsgpack leave placeholders out.
+// The external store of WithStore cannot hold the mark, so placeholders and writes to them are not written through
+// until Claim turns them into ordinary entries
+func (sl *RankList[K, V]) Reserve(key K, value V) bool {
+	sl.Lock()
+	if _, exists := sl.lookup(key); exists || sl.closed || sl.guardWrite(key, value) != nil ||
+		sl.persist(walOpReserve, key, value) != nil {
+		sl.Unlock()
+		return false
+	}
+
+	probes := sl.probeThresholds(key)
+	sl.set(key, value)
+	if node, exists := sl.lookup(key); exists {
+		node.placeholder = true
+		sl.hasPlaceholders = true
+	}
+	sl.journal(walOpReserve, key, value)
+	sl.evictOverflow()
+	_, kept := sl.lookup(key)
+	events := sl.thresholdEvents(key, probes)
+	sl.Unlock()
+
+	sl.vars.add(opSet)
+	fireThresholds(events)
+	return kept
+}
+
+// Claim 在一把写锁内将占位条目 placeholderKey 改名为 realKey 并清除占位标记，值和元数据保持不变。
+// placeholderKey 不存在或不是占位条目、或 realKey 已存在时返回 false，因此同一个占位条目只能被认领一次
+// Claim renames the placeholder placeholderKey to realKey and clears its placeholder mark under one write lock,
+// keeping its value and metadata. Returns false if placeholderKey does not exist or is not a placeholder,
+// or if realKey already exists, so a placeholder can only be claimed once
+func (sl *RankList[K, V]) Claim(placeholderKey K, realKey K) bool {
+	sl.Lock()
+	node, exists := sl.lookup(placeholderKey)
+	if !exists || !node.placeholder || sl.closed {
+		sl.Unlock()
+		return false
+	}
+	value := node.data.Value
+	if _, taken := sl.lookup(realKey); taken ||
+		sl.persist(walOpDel, placeholderKey, ZeroValue[V]()) != nil || sl.persist(walOpSet, realKey, value) != nil {
+		sl.Unlock()
+		return false
+	}
+
+	zones := sl.zoneKeys()
+	sl.del(placeholderKey)
+	sl.journal(walOpDel, placeholderKey, ZeroValue[V]())
+	sl.set(realKey, value)
+	sl.journal(walOpSet, realKey, value)
+	if meta, ok := sl.meta[placeholderKey]; ok {
+		delete(sl.meta, placeholderKey)
+		sl.meta[realKey] = meta
+	}
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	sl.vars.add(opDel)
+	sl.vars.add(opSet)
+	fireThresholds(events)
+	return true
+}
+
+// IsPlaceholder 返回键是否是尚未被认领的占位条目
+// IsPlaceholder reports whether key is a placeholder that has not been claimed yet
+func (sl *RankList[K, V]) IsPlaceholder(key K) bool {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	node, exists := sl.lookup(key)
+	return exists && node.placeholder
+}
+
+// savedEntries 在一把读锁内按排名顺序复制除占位条目外的全部条目，快照无法表示占位标记，因此总是不写入占位条目
+// savedEntries copies every entry except the placeholders in rank order under one read lock.
+// Snapshots cannot hold the placeholder mark, so they always leave placeholders out
+func (sl *RankList[K, V]) savedEntries() []Entry[K, V] {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	if !sl.hasPlaceholders {
+		return sl.entries()
+	}
+	entries := make([]Entry[K, V], 0, sl.length)
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		if !curr.placeholder {
+			entries = append(entries, curr.data)
+		}
+	}
+	return entries
+}
+
+// hidden 返回节点是否应被 Range、RevRange 和 Top 跳过，调用方需持有锁
+// hidden reports whether Range, RevRange and Top skip the node, the caller must hold the lock
+func (sl *RankList[K, V]) hidden(node *Node[K, V]) bool {
+	return node.placeholder && !sl.showPlaceholders
+}
+
+// mayHide 返回跳表中是否可能有被跳过的占位条目，为 false 时可以不逐个检查节点，调用方需持有锁
+// mayHide reports whether the list may hold placeholders that are skipped,
+// nodes need no check one by one when it is false. The caller must hold the lock
+func (sl *RankList[K, V]) mayHide() bool {
+	return sl.hasPlaceholders && !sl.showPlaceholders
+}
+
+// countVisible 从 first 开始沿第 0 层数出 n 个节点中不被跳过的节点数，调用方需持有锁
+// countVisible counts the nodes that are not skipped among the n nodes from first along level 0.
+// The caller must hold the lock
+func (sl *RankList[K, V]) countVisible(first *Node[K, V], n int) int {
+	if !sl.mayHide() {
+		return n
+	}
+	visible := 0
+	for curr := first; n > 0 && curr != nil; curr, n = curr.forward[0], n-1 {
+		if !sl.hidden(curr) {
+			visible++
+		}
+	}
+	return visible
+}
@@ -0,0 +1,278 @@
+package ranklist
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestReserveHidden(t *testing.T) {
+	sl := New(WithCachedTop[string, int](3))
+	sl.Set("a", 10)
+	sl.Set("b", 30)
+	sl.Set("c", 50)
+
+	if !sl.Reserve("seed1", 40) || !sl.Reserve("seed2", 20) {
+		t.Fatalf("expected the reservations to succeed")
+	}
+	if sl.Reserve("a", 5) || sl.Reserve("seed1", 45) {
+		t.Fatalf("reserving an existing key should fail")
+	}
+	if !sl.IsPlaceholder("seed1") || sl.IsPlaceholder("a") || sl.IsPlaceholder("missing") {
+		t.Fatalf("unexpected placeholder flags")
+	}
+
+	// 占位条目占据排名，但被 Range、RevRange 和 Top 跳过
+	// Placeholders hold their ranks but Range, RevRange and Top skip them
+	if rank, _ := sl.Rank("b"); rank != 3 || sl.Length() != 5 {
+		t.Fatalf("placeholders should hold their ranks, got rank %d and length %d", rank, sl.Length())
+	}
+	if entries := sl.Range(1, 6); !slices.Equal(entries, []Entry[string, int]{{"a", 10}, {"b", 30}, {"c", 50}}) {
+		t.Fatalf("unexpected Range result %v", entries)
+	}
+	if entries := sl.RevRange(1, 3); !slices.Equal(entries, []Entry[string, int]{{"c", 50}}) {
+		t.Fatalf("unexpected RevRange result %v", entries)
+	}
+	for _, n := range []int{2, 4} {
+		if top := sl.Top(n); !slices.Equal(top, []Entry[string, int]{{"c", 50}, {"b", 30}, {"a", 10}}[:min(n, 3)]) {
+			t.Fatalf("unexpected Top(%d) result %v", n, top)
+		}
+	}
+	if entries := sl.RangeWithMeta(1, 6); len(entries) != 3 {
+		t.Fatalf("RangeWithMeta should skip placeholders, got %v", entries)
+	}
+	if value, ok := sl.Get("seed1"); !ok || value != 40 {
+		t.Fatalf("Get should see the placeholder, got %d, %v", value, ok)
+	}
+
+	// 修改值和重建都保留占位标记
+	// Changing the value and rebuilding both keep the placeholder mark
+	sl.Set("seed1", 60)
+	sl.Compact()
+	sl.LoadMap(map[string]int{"d": 70}, false)
+	if !sl.IsPlaceholder("seed1") || !sl.IsPlaceholder("seed2") {
+		t.Fatalf("placeholders should survive Set, Compact and LoadMap")
+	}
+	if top := sl.Top(1); !slices.Equal(top, []Entry[string, int]{{"d", 70}}) {
+		t.Fatalf("unexpected Top result %v", top)
+	}
+}
+
+func TestReserveVisible(t *testing.T) {
+	sl := New(WithVisiblePlaceholders[string, int]())
+	sl.Set("a", 10)
+	sl.Reserve("seed", 20)
+
+	expected := []Entry[string, int]{{"a", 10}, {"seed", 20}}
+	if entries := sl.Range(1, 3); !slices.Equal(entries, expected) {
+		t.Fatalf("unexpected Range result %v", entries)
+	}
+	if top := sl.Top(1); !slices.Equal(top, expected[1:]) {
+		t.Fatalf("unexpected Top result %v", top)
+	}
+}
+
+func TestClaim(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("taken", 5)
+	sl.Reserve("seed", 20)
+	sl.SetMeta("seed", map[string]string{"bracket": "A"})
+
+	if sl.Claim("taken", "x") || sl.Claim("missing", "x") || sl.Claim("seed", "taken") {
+		t.Fatalf("claiming a non-placeholder, a missing key or onto an existing key should fail")
+	}
+	if !sl.Claim("seed", "player") {
+		t.Fatalf("expected the claim to succeed")
+	}
+	if sl.Claim("seed", "other") {
+		t.Fatalf("claiming twice should fail")
+	}
+
+	if _, ok := sl.Get("seed"); ok {
+		t.Fatalf("the placeholder key should be gone")
+	}
+	if value, ok := sl.Get("player"); !ok || value != 20 || sl.IsPlaceholder("player") {
+		t.Fatalf("expected a real entry at 20, got %d, %v", value, ok)
+	}
+	if meta, _ := sl.GetMeta("player"); meta["bracket"] != "A" {
+		t.Fatalf("the metadata should move to the real key, got %v", meta)
+	}
+	if entries := sl.Range(1, 3); !slices.Equal(entries, []Entry[string, int]{{"taken", 5}, {"player", 20}}) {
+		t.Fatalf("unexpected Range result %v", entries)
+	}
+}
+
+func TestDelPlaceholder(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 10)
+	sl.Reserve("seed", 20)
+
+	if !sl.Del("seed") {
+		t.Fatalf("expected the placeholder to be deleted")
+	}
+	if _, ok := sl.Get("seed"); ok || sl.Length() != 1 || sl.Claim("seed", "b") {
+		t.Fatalf("a deleted placeholder should be gone")
+	}
+
+	// 被回收的节点不会把占位标记带给之后的条目
+	// A recycled node does not carry the placeholder mark over to later entries
+	for i := 0; i < 100; i++ {
+		sl.Reserve("tmp", i)
+		sl.Del("tmp")
+		sl.Set("b", i)
+		if sl.IsPlaceholder("b") {
+			t.Fatalf("an ordinary entry should never be a placeholder")
+		}
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatalf("corrupted list: %v", err)
+	}
+}
+
+func TestReserveEvicted(t *testing.T) {
+	sl := New(WithMaxSize[string, int](1))
+	sl.Set("a", 10)
+
+	// 值更小的占位条目插入后立即被淘汰 / A placeholder with a smaller value is evicted right away
+	if sl.Reserve("low", 5) {
+		t.Fatalf("an evicted placeholder should not be reported as reserved")
+	}
+	if _, ok := sl.Get("low"); ok || sl.Claim("low", "b") {
+		t.Fatalf("the evicted placeholder should be gone")
+	}
+
+	// 淘汰其他条目的占位条目保留下来 / A placeholder evicting another entry is kept
+	if !sl.Reserve("high", 20) || !sl.IsPlaceholder("high") {
+		t.Fatalf("expected the placeholder to be reserved")
+	}
+	if _, ok := sl.Get("a"); ok {
+		t.Fatalf("expected a to be evicted")
+	}
+}
+
+func TestPlaceholderScoreHelpers(t *testing.T) {
+	for _, buckets := range []bool{false, true} {
+		for _, visible := range []bool{false, true} {
+			var opts []Option[string, int]
+			if buckets {
+				opts = append(opts, WithValueBuckets[string, int](100))
+			}
+			if visible {
+				opts = append(opts, WithVisiblePlaceholders[string, int]())
+			}
+			sl := New(opts...)
+			sl.Set("a", 10)
+			sl.Set("b", 20)
+			sl.Set("c", 30)
+			sl.Set("d", 50)
+			sl.Reserve("seed", 20)
+			sl.Reserve("lone", 40)
+
+			// 与 CountByScore 和 RangeByScore 采用相同的可见性规则
+			// The same visibility rule as CountByScore and RangeByScore
+			if ties, _ := sl.TieCount("b"); ties != sl.CountByScore(20, 20) {
+				t.Fatalf("buckets %v, visible %v: TieCount %d differs from CountByScore %d", buckets, visible, ties, sl.CountByScore(20, 20))
+			}
+			if ties, _ := sl.TieCount("seed"); ties != 2 {
+				t.Fatalf("buckets %v, visible %v: a placeholder should count itself, got %d", buckets, visible, ties)
+			}
+			var keys []string
+			for _, entry := range sl.RangeByScore(20, 20) {
+				keys = append(keys, entry.Key)
+			}
+			if got := sl.KeysWithValue(20); !slices.Equal(got, keys) {
+				t.Fatalf("buckets %v, visible %v: KeysWithValue returned %v, RangeByScore %v", buckets, visible, got, keys)
+			}
+
+			values := map[int]int{}
+			sl.ValueCounts(func(value int, count int) bool {
+				values[value] = count
+				return true
+			})
+			expected := map[int]int{10: 1, 20: 1, 30: 1, 50: 1}
+			dense, nearest := 4, "c"
+			if visible {
+				expected, dense, nearest = map[int]int{10: 1, 20: 2, 30: 1, 40: 1, 50: 1}, 5, "lone"
+			}
+			if len(values) != len(expected) || sl.DistinctValues() != len(expected) {
+				t.Fatalf("buckets %v, visible %v: unexpected value counts %v, %d distinct", buckets, visible, values, sl.DistinctValues())
+			}
+			for value, count := range expected {
+				if values[value] != count {
+					t.Fatalf("buckets %v, visible %v: unexpected value counts %v", buckets, visible, values)
+				}
+			}
+			if rank, _ := sl.DenseRank("d"); rank != dense {
+				t.Fatalf("buckets %v, visible %v: expected dense rank %d, got %d", buckets, visible, dense, rank)
+			}
+			if entry, ok := sl.Nearest(40); !ok || entry.Key != nearest {
+				t.Fatalf("buckets %v, visible %v: expected %s nearest to 40, got %v, %v", buckets, visible, nearest, entry, ok)
+			}
+		}
+	}
+
+	sl := New[string, int]()
+	sl.Reserve("seed", 10)
+	if _, ok := sl.Nearest(10); ok {
+		t.Fatalf("a list of hidden placeholders only should have no nearest entry")
+	}
+}
+
+func TestPlaceholderSnapshots(t *testing.T) {
+	sl := New(WithVisiblePlaceholders[string, int]())
+	sl.Set("a", 10)
+	sl.Set("b", 30)
+	sl.Reserve("seed", 20)
+	expected := []Entry[string, int]{{"a", 10}, {"b", 30}}
+
+	var buf bytes.Buffer
+	if err := sl.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded := New[string, int]()
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if entries := loaded.Entries(); !slices.Equal(entries, expected) {
+		t.Fatalf("Save should leave placeholders out, loaded %v", entries)
+	}
+
+	data, err := sl.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	loaded = New[string, int]()
+	if err := loaded.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+	if entries := loaded.Entries(); !slices.Equal(entries, expected) {
+		t.Fatalf("MarshalJSON should leave placeholders out, loaded %v", entries)
+	}
+
+	buf.Reset()
+	if err := sl.EncodeMsgpack(&buf); err != nil {
+		t.Fatalf("EncodeMsgpack failed: %v", err)
+	}
+	loaded = New[string, int]()
+	if err := loaded.DecodeMsgpack(&buf); err != nil {
+		t.Fatalf("DecodeMsgpack failed: %v", err)
+	}
+	if entries := loaded.Entries(); !slices.Equal(entries, expected) {
+		t.Fatalf("EncodeMsgpack should leave placeholders out, loaded %v", entries)
+	}
+
+	// EncodeJSON 跨越多块时仍与 MarshalJSON 的输出相同
+	// EncodeJSON matches the output of MarshalJSON even across several chunks
+	for i := 0; i < 2*jsonChunkSize; i++ {
+		sl.Reserve(fmt.Sprintf("seed%05d", i), 20)
+	}
+	data, _ = sl.MarshalJSON()
+	buf.Reset()
+	if err := sl.EncodeJSON(&buf); err != nil {
+		t.Fatalf("EncodeJSON failed: %v", err)
+	}
+	if buf.String() != string(data) {
+		t.Fatalf("EncodeJSON should match MarshalJSON, got %s and %s", buf.String(), data)
+	}
+}
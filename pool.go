@@ -0,0 +1,36 @@
+package ranklist
+
+// newNode 从对应层级的对象池中取出一个节点并初始化，池为空时分配新节点；开启 arena 时从 arena 中分配
+// newNode takes a node from the pool of its level and initializes it, a new node is allocated when the pool is empty.
+// With an arena enabled the node comes from the arena instead
+func (sl *RankList[K, V]) newNode(key K, value V, level int) *Node[K, V] {
+	if sl.arena != nil {
+		return sl.arena.alloc(key, value, level)
+	}
+	if node, ok := sl.pool[level-1].Get().(*Node[K, V]); ok {
+		node.data = Entry[K, V]{Key: key, Value: value}
+		return node
+	}
+	return NewNode(key, value, level)
+}
+
+// freeNode 清空已从跳表中摘除的节点并放回对应层级的对象池，开启 arena 时放回 arena 的空闲链表。
+// 前向指针在放回前被置空，节点不会再引用其他节点，键和值也会被清零以免延长它们的生命周期
+// freeNode clears a node that has been unlinked from the skip list and returns it to the pool of its level,
+// or to the arena's free list when an arena is enabled.
+// Forward pointers are nilled before pooling so the node cannot keep other nodes alive,
+// the key and value are zeroed as well so their lifetime is not extended
+func (sl *RankList[K, V]) freeNode(node *Node[K, V]) {
+	clear(node.forward)
+	clear(node.span)
+	node.backward = nil
+	node.data = Entry[K, V]{}
+	node.version = 0
+	node.times = nil
+	node.placeholder = false
+	if sl.arena != nil {
+		sl.arena.release(node)
+		return
+	}
+	sl.pool[node.level-1].Put(node)
+}
@@ -0,0 +1,63 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"testing"
+)
+
+func TestFreeNodeClearsReferences(t *testing.T) {
+	sl := New[string, int]()
+	sl.setLevel("a", 1, 3)
+	sl.setLevel("b", 2, 3)
+	sl.setLevel("c", 3, 3)
+
+	node := sl.header.forward[0].forward[0]
+	if node.data.Key != "b" {
+		t.Fatalf("expected b, got %v", node.data.Key)
+	}
+	sl.Del("b")
+
+	for i := 0; i < node.level; i++ {
+		if node.forward[i] != nil || node.span[i] != 0 {
+			t.Errorf("level %d of a freed node still holds a pointer or span", i)
+		}
+	}
+	if node.data != (Entry[string, int]{}) {
+		t.Errorf("freed node still holds its entry %v", node.data)
+	}
+}
+
+func TestPooledNodesChurn(t *testing.T) {
+	sl := New[string, int]()
+	model := make(map[string]int)
+
+	for i := 0; i < 20000; i++ {
+		key := "key" + strconv.Itoa(rand.IntN(500))
+		switch rand.IntN(4) {
+		case 0:
+			sl.Del(key)
+			delete(model, key)
+		case 1:
+			delta := rand.IntN(10)
+			sl.IncrBy(key, delta)
+			model[key] += delta
+		default:
+			value := rand.IntN(100)
+			sl.Set(key, value)
+			model[key] = value
+		}
+	}
+	requireModel(t, sl, model)
+
+	expected := 0
+	for _, entry := range sl.Range(1, sl.Length()+1) {
+		if entry.Value != model[entry.Key] {
+			t.Fatalf("Key %v: expected value %v, got %v", entry.Key, model[entry.Key], entry.Value)
+		}
+		expected++
+	}
+	if expected != len(model) {
+		t.Errorf("expected %d entries from Range, got %d", len(model), expected)
+	}
+}
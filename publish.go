@@ -0,0 +1,70 @@
+package ranklist
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// PublishTop 在后台每隔 interval 获取一次值最大的 n 个条目，只有与上一次发布的结果不同时才调用 fn，
+// 因此没有变化的榜单不会产生任何推送。内容版本未变时直接跳过，不会获取锁。
+// 第一次发布发生在第一个周期，空榜单不会被发布。fn 在后台 goroutine 中依次调用，调用期间到期的周期会被合并。
+// ctx 被取消或跳表被 Close 关闭后后台 goroutine 立即退出，不会再调用 fn
+// PublishTop takes the n entries with the largest values every interval in the background
+// and calls fn only when they differ from the last published ones, so an idle board generates no traffic.
+// Ticks where the content version is unchanged are skipped without taking the lock.
+// The first publication happens on the first tick, and an empty board is never published.
+// fn is called sequentially from the background goroutine, ticks firing while it runs are coalesced.
+// Once ctx is cancelled or the list is closed the goroutine exits promptly and fn is not called again
+func (sl *RankList[K, V]) PublishTop(ctx context.Context, n int, interval time.Duration, fn func(top []Entry[K, V])) {
+	ticker := time.NewTicker(interval)
+	done := sl.publishTop(ctx, n, ticker.C, fn)
+	go func() {
+		<-done
+		ticker.Stop()
+	}()
+}
+
+// publishTop 每次从 tick 收到信号时检查一次 Top，便于测试时注入触发器，返回的通道在后台 goroutine 退出时关闭
+// publishTop checks Top every time tick fires, so tests can inject the trigger.
+// The returned channel is closed once the background goroutine exits
+func (sl *RankList[K, V]) publishTop(ctx context.Context, n int, tick <-chan time.Time, fn func(top []Entry[K, V])) <-chan struct{} {
+	done := make(chan struct{})
+	if !sl.startBackground() {
+		close(done)
+		return done
+	}
+	go func() {
+		defer sl.background.Done()
+		defer close(done)
+
+		var last []Entry[K, V]
+		var version uint64
+		checked := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sl.done:
+				return
+			case <-tick:
+			}
+
+			// 版本在 Top 之前读取，期间的写入只会让下一个周期多检查一次
+			// The version is read before Top, a write in between only costs one more check on the next tick
+			v := sl.Version()
+			if checked && v == version {
+				continue
+			}
+			checked, version = true, v
+
+			top := sl.Top(n)
+			if slices.Equal(top, last) || ctx.Err() != nil {
+				continue
+			}
+			last = top
+			fn(top)
+		}
+	}()
+	return done
+}
@@ -0,0 +1,82 @@
+package ranklist
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPublishTop(t *testing.T) {
+	sl := New[string, int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	tick := make(chan time.Time)
+	published := make(chan []Entry[string, int], 10)
+	done := sl.publishTop(ctx, 2, tick, func(top []Entry[string, int]) {
+		published <- top
+	})
+
+	// 发送下一个信号会阻塞到上一个周期处理完毕，因此此时已发布的结果都已在通道中
+	// Sending the next tick blocks until the previous one was handled, so every publication is in the channel by then
+	step := func(expected ...[]Entry[string, int]) {
+		t.Helper()
+		tick <- time.Now()
+		tick <- time.Now()
+		var got [][]Entry[string, int]
+		for len(published) > 0 {
+			got = append(got, <-published)
+		}
+		if !slices.EqualFunc(got, expected, slices.Equal) {
+			t.Fatalf("expected publications %v, got %v", expected, got)
+		}
+	}
+
+	step()
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	step([]Entry[string, int]{{"b", 20}, {"a", 10}})
+	step()
+
+	// 榜单外的变化不会改变前 2 名，不会发布
+	// A change outside the top 2 leaves it unchanged and publishes nothing
+	sl.Set("c", 5)
+	step()
+	sl.Set("c", 30)
+	step([]Entry[string, int]{{"c", 30}, {"b", 20}})
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("the publisher did not stop after cancellation")
+	}
+	sl.Set("d", 40)
+	select {
+	case tick <- time.Now():
+		t.Fatalf("the publisher still consumes ticks after cancellation")
+	default:
+	}
+	if len(published) != 0 {
+		t.Fatalf("nothing should be published after cancellation")
+	}
+}
+
+func TestPublishTopTicker(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	published := make(chan []Entry[string, int], 1)
+	sl.PublishTop(ctx, 1, time.Millisecond, func(top []Entry[string, int]) {
+		published <- top
+	})
+	select {
+	case top := <-published:
+		if !slices.Equal(top, []Entry[string, int]{{"a", 1}}) {
+			t.Fatalf("unexpected top %v", top)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected a publication")
+	}
+}
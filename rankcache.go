@@ -0,0 +1,93 @@
+package ranklist
+
+import (
+	"container/list"
+	"sync"
+)
+
+// rankCache 缓存最近查询过的键的排名。任何结构性修改都会递增跳表的代数，
+// 代数变化后整个缓存失效，因为一次插入可能改变任意成员的排名
+// rankCache memoizes the ranks of recently queried keys. Every structural mutation bumps the list's generation
+// and a new generation invalidates the whole cache, since any insert can shift arbitrary ranks
+type rankCache[K Ordered] struct {
+	mu    sync.Mutex
+	size  int
+	gen   uint64
+	order *list.List
+	items map[K]*list.Element
+}
+
+type rankCacheItem[K Ordered] struct {
+	key  K
+	rank int
+}
+
+// WithRankCache 为最近查询过的 size 个键缓存排名，适合少数热门成员被频繁查询而榜单很少变化的场景
+// WithRankCache caches the ranks of the size most recently queried keys,
+// which suits boards where a few hot members are queried constantly while the board barely changes
+func WithRankCache[K Ordered, V Ordered](size int) Option[K, V] {
+	if size < 1 {
+		panic("ranklist: rank cache size must be positive")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.rankCache = &rankCache[K]{
+			size:  size,
+			order: list.New(),
+			items: make(map[K]*list.Element, size),
+		}
+	}
+}
+
+// get 返回 key 在代数 gen 下缓存的排名
+// get returns the rank cached for key under generation gen
+func (c *rankCache[K]) get(key K, gen uint64) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gen != gen {
+		c.reset(gen)
+		return 0, false
+	}
+	elem, ok := c.items[key]
+	if !ok {
+		return 0, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*rankCacheItem[K]).rank, true
+}
+
+// put 缓存 key 在代数 gen 下的排名，超出容量时淘汰最久未查询的键
+// put caches the rank of key under generation gen, evicting the least recently queried key when full
+func (c *rankCache[K]) put(key K, rank int, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gen != gen {
+		c.reset(gen)
+	}
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*rankCacheItem[K]).rank = rank
+		c.order.MoveToFront(elem)
+		return
+	}
+	if c.order.Len() >= c.size {
+		// 复用最久未查询的元素，避免每次淘汰都重新分配
+		// Reuse the least recently queried element so evictions do not allocate
+		oldest := c.order.Back()
+		item := oldest.Value.(*rankCacheItem[K])
+		delete(c.items, item.key)
+		item.key, item.rank = key, rank
+		c.order.MoveToFront(oldest)
+		c.items[key] = oldest
+		return
+	}
+	c.items[key] = c.order.PushFront(&rankCacheItem[K]{key: key, rank: rank})
+}
+
+// reset 清空缓存并切换到代数 gen
+// reset empties the cache and switches it to generation gen
+func (c *rankCache[K]) reset(gen uint64) {
+	c.gen = gen
+	c.order.Init()
+	clear(c.items)
+}
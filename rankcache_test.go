@@ -0,0 +1,77 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestRankCacheMatchesControl(t *testing.T) {
+	cached := New(WithRankCache[int, int](8))
+	control := New[int, int]()
+
+	for i := 0; i < 20000; i++ {
+		key := rand.IntN(50)
+		switch rand.IntN(10) {
+		case 0:
+			cached.Del(key)
+			control.Del(key)
+		case 1:
+			value := rand.IntN(100)
+			cached.Set(key, value)
+			control.Set(key, value)
+		case 2:
+			delta := rand.IntN(3)
+			cached.IncrBy(key, delta)
+			control.IncrBy(key, delta)
+		case 3:
+			if rand.IntN(100) == 0 {
+				snapshot := control.Snapshot()
+				cached.Restore(snapshot)
+				control.Restore(snapshot)
+			}
+		default:
+			// 热门键被反复查询
+			// Hot keys are queried over and over
+			key = rand.IntN(5)
+			rank, ok := cached.Rank(key)
+			expectedRank, expectedOK := control.Rank(key)
+			if rank != expectedRank || ok != expectedOK {
+				t.Fatalf("step %d, key %d: expected rank %d, %v, got %d, %v", i, key, expectedRank, expectedOK, rank, ok)
+			}
+		}
+	}
+}
+
+func TestRankCacheEviction(t *testing.T) {
+	sl := New(WithRankCache[int, int](2))
+	for i := 0; i < 10; i++ {
+		sl.Set(i, i)
+	}
+	for i := 0; i < 10; i++ {
+		sl.Rank(i)
+	}
+	if n := len(sl.rankCache.items); n != 2 || sl.rankCache.order.Len() != 2 {
+		t.Fatalf("cache should hold at most 2 keys, got %d", n)
+	}
+	if _, ok := sl.rankCache.items[9]; !ok {
+		t.Errorf("the most recently queried key should be cached")
+	}
+
+	sl.Set(100, 100)
+	if rank, ok := sl.Rank(9); !ok || rank != 10 {
+		t.Errorf("expected rank 10, got %d, %v", rank, ok)
+	}
+	if len(sl.rankCache.items) != 1 {
+		t.Errorf("a structural change should invalidate the whole cache")
+	}
+}
+
+func TestRankCacheMissingKey(t *testing.T) {
+	sl := New(WithRankCache[int, int](4))
+	if _, ok := sl.Rank(1); ok {
+		t.Fatalf("missing key should not have a rank")
+	}
+	if len(sl.rankCache.items) != 0 {
+		t.Errorf("missing keys should not be cached")
+	}
+}
@@ -0,0 +1,68 @@
+package ranklist
+
+// rankChange 记录一次写入前后被写入的键的排名
+// rankChange records the ranks of the written key before and after one write
+type rankChange[K Ordered] struct {
+	key              K
+	oldRank, newRank int
+}
+
+// WithRankChangeHook 注册排名变化回调，每次写入或删除一个键之后以该键写入前后的排名调用一次 fn，
+// 插入时 oldRank 为 0，删除（包括淘汰）时 newRank 为 0，值改变但位置不变时两者相等。
+// 只报告被写入的键本身，不报告因此移动了一位的其他成员；两个排名与写入在同一个临界区内计算，
+// 写入前的排名多一次 O(log n) 查找，写入后的排名来自插入时已经累计的跨度。
+// 批量写入和事务按顺序报告每个写入的键；Restore、Load、Clear、ApplyToAll 等整体替换内容的操作不报告。
+// fn 在释放锁之后调用，可以调用跳表的任何方法
+// WithRankChangeHook registers a rank change callback, fn is called once after every write or delete of a key
+// with the ranks of that key before and after it: oldRank is 0 for inserts, newRank is 0 for deletes including evictions,
+// and both are equal when the value changed without moving the key.
+// Only the written key itself is reported, not the members shifted by one position as a consequence;
+// both ranks are computed in the same critical section as the write, the old one costing one extra O(log n) descent
+// and the new one coming from the spans the insert accumulates anyway.
+// Batch writes and transactions report every written key in order; whole-content replacements
+// such as Restore, Load, Clear and ApplyToAll report nothing.
+// fn runs after the lock is released and may call any method of the list
+func WithRankChangeHook[K Ordered, V Ordered](fn func(key K, oldRank, newRank int)) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.onRankChange = fn
+	}
+}
+
+// rankBefore 返回写入之前节点的排名，未注册排名变化回调时不查找并返回 0，调用方需持有锁
+// rankBefore returns the rank of node before a write, without looking it up when no rank change hook is registered.
+// The caller must hold the lock
+func (sl *RankList[K, V]) rankBefore(node *Node[K, V]) int {
+	if sl.onRankChange == nil {
+		return 0
+	}
+	return sl.countLess(node.data) + 1
+}
+
+// recordRankChange 记录一次排名变化，未注册排名变化回调时不做任何事，调用方需持有写锁
+// recordRankChange records one rank change, a no-op without a rank change hook. The caller must hold the write lock
+func (sl *RankList[K, V]) recordRankChange(key K, oldRank, newRank int) {
+	if sl.onRankChange != nil {
+		sl.rankChanges = append(sl.rankChanges, rankChange[K]{key: key, oldRank: oldRank, newRank: newRank})
+	}
+}
+
+// rankEvents 取出尚未报告的排名变化，转换为在释放锁之后执行的回调事件，调用方需持有写锁
+// rankEvents takes the rank changes not yet reported and turns them into events fired after the lock is released.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) rankEvents() []thresholdEvent[K] {
+	if len(sl.rankChanges) == 0 {
+		return nil
+	}
+
+	events := make([]thresholdEvent[K], 0, len(sl.rankChanges))
+	fn := sl.onRankChange
+	for _, change := range sl.rankChanges {
+		oldRank, newRank := change.oldRank, change.newRank
+		events = append(events, thresholdEvent[K]{
+			fn:  func(key K, _ bool) { fn(key, oldRank, newRank) },
+			key: change.key,
+		})
+	}
+	sl.rankChanges = sl.rankChanges[:0]
+	return events
+}
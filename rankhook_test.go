@@ -0,0 +1,97 @@
+package ranklist
+
+import (
+	"slices"
+	"testing"
+)
+
+// rankRecorder 记录 WithRankChangeHook 报告的排名变化
+// rankRecorder records the rank changes reported through WithRankChangeHook
+type rankRecorder struct {
+	changes []rankChange[string]
+}
+
+func (r *rankRecorder) hook(key string, oldRank, newRank int) {
+	r.changes = append(r.changes, rankChange[string]{key: key, oldRank: oldRank, newRank: newRank})
+}
+
+// take 返回并清空已记录的排名变化
+// take returns and clears the recorded rank changes
+func (r *rankRecorder) take() []rankChange[string] {
+	changes := r.changes
+	r.changes = nil
+	return changes
+}
+
+func TestRankChangeHook(t *testing.T) {
+	r := &rankRecorder{}
+	sl := New(WithRankChangeHook[string, int](r.hook))
+	sl.SetBatch([]Entry[string, int]{{"a", 10}, {"b", 20}, {"c", 30}})
+	if want := []rankChange[string]{{"a", 0, 1}, {"b", 0, 2}, {"c", 0, 3}}; !slices.Equal(r.take(), want) {
+		t.Fatalf("expected the batch inserts %v", want)
+	}
+
+	steps := []struct {
+		name string
+		fn   func()
+		want rankChange[string]
+	}{
+		{"insert", func() { sl.Set("d", 15) }, rankChange[string]{"d", 0, 2}},
+		{"move up", func() { sl.Set("a", 40) }, rankChange[string]{"a", 1, 4}},
+		{"stay", func() { sl.IncrBy("b", 1) }, rankChange[string]{"b", 2, 2}},
+		{"move down", func() { sl.UpdateByValue("c", 30, 1) }, rankChange[string]{"c", 3, 1}},
+		{"delete", func() { sl.Del("d") }, rankChange[string]{"d", 2, 0}},
+		{"delete by value", func() { sl.DelByValue("c", 1) }, rankChange[string]{"c", 1, 0}},
+	}
+	for _, step := range steps {
+		step.fn()
+		if got := r.take(); len(got) != 1 || got[0] != step.want {
+			t.Fatalf("%s: expected %v, got %v", step.name, step.want, got)
+		}
+		if step.want.newRank > 0 {
+			if rank, _ := sl.Rank(step.want.key); rank != step.want.newRank {
+				t.Fatalf("%s: the reported rank %d disagrees with Rank %d", step.name, step.want.newRank, rank)
+			}
+		}
+	}
+
+	// 未命中的删除和整体替换不报告
+	// Missed deletes and whole-content replacements report nothing
+	sl.Del("missing")
+	sl.Restore([]Entry[string, int]{{"x", 1}})
+	sl.Clear()
+	if got := r.take(); len(got) != 0 {
+		t.Fatalf("expected no reports, got %v", got)
+	}
+}
+
+func TestRankChangeHookEvict(t *testing.T) {
+	r := &rankRecorder{}
+	sl := New(WithRankChangeHook[string, int](r.hook), WithMaxSize[string, int](2))
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	r.take()
+
+	// 插入 c 之后最低的 a 被淘汰，c 的排名在淘汰之前计算
+	// Inserting c evicts the lowest a, c's rank being computed before the eviction
+	sl.Set("c", 30)
+	if want := []rankChange[string]{{"c", 0, 3}, {"a", 1, 0}}; !slices.Equal(r.take(), want) {
+		t.Fatalf("expected %v", want)
+	}
+}
+
+func TestRankChangeHookReentrant(t *testing.T) {
+	var sl *RankList[string, int]
+	var ranks []int
+	sl = New(WithRankChangeHook[string, int](func(key string, oldRank, newRank int) {
+		// 回调在锁外执行，可以读取跳表
+		// The hook runs outside the lock and may read the list
+		rank, _ := sl.Rank(key)
+		ranks = append(ranks, rank)
+	}))
+	sl.Set("a", 1)
+	sl.Set("b", 0)
+	if !slices.Equal(ranks, []int{1, 1}) {
+		t.Fatalf("expected the ranks read from the hook to be 1 and 1, got %v", ranks)
+	}
+}
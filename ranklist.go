@@ -1,8 +1,11 @@
 package ranklist
 
 import (
-	"math/rand"
+	"math/rand/v2"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -36,8 +39,8 @@ func ZeroValue[K Ordered]() K {
 
 // Entry  represents a key-value pair
 type Entry[K Ordered, V Ordered] struct {
-	Key   K
-	Value V
+	Key   K `json:"key"`
+	Value V `json:"value"`
 }
 
 // Node 定义跳表节点的结构
@@ -47,23 +50,46 @@ type Node[K Ordered, V Ordered] struct {
 	// Key-value pair of the node
 	data Entry[K, V]
 
-	// 每一层对应的前向指针数组
-	// Array of forward pointers for each level
-	forward [MaxLevel]*Node[K, V]
+	// 每一层对应的前向指针，长度等于节点的层级
+	// Forward pointers for each level, as long as the node's level
+	forward []*Node[K, V]
 
-	// 每一层对应的跨度数组，记录到下一个节点的距离
-	// Array of spans for each level, recording distance to next node
-	span [MaxLevel]int
+	// 每一层对应的跨度，记录到下一个节点的距离，长度等于节点的层级
+	// Spans for each level, recording distance to next node, as long as the node's level
+	span []int
+
+	// 第 0 层的后向指针，第一个节点的后向指针为 nil
+	// Backward pointer at level 0, nil for the first node
+	backward *Node[K, V]
 
 	// 当前节点的层级
 	// Current level of the node
 	level int
+
+	// 条目的版本，键创建时为 1，值每改变一次递增 1
+	// Version of the entry, 1 when the key is created and bumped by one on every value change
+	version uint64
+
+	// 条目的创建和更新时间，未开启时间戳时为 nil
+	// Creation and update times of the entry, nil when timestamps are disabled
+	times *entryTimes
+
+	// 是否是 Reserve 插入的占位条目
+	// Whether the entry is a placeholder inserted by Reserve
+	placeholder bool
+
+	// 节点在更新顺序链表中的链接，未开启 WithUpdateOrder 时为 nil
+	// Links of the node within the update order list, nil without WithUpdateOrder
+	recency *recencyLink[K, V]
 }
 
 // RankList 定义跳表的核心结构
-// 提供线程安全的节点管理，支持插入、删除、查找、排名等功能
+// 提供线程安全的节点管理，支持插入、删除、查找、排名等功能。
+// 零值是一个不带任何选项的可用空跳表，可以直接嵌入其他结构体，首次使用时才完成初始化
 // RankList defines the core structure of the skip list
-// Provides thread-safe node management and supports insertion, deletion, retrieval, and ranking functionalities
+// Provides thread-safe node management and supports insertion, deletion, retrieval, and ranking functionalities.
+// The zero value is a usable empty list without options, so it can be embedded in other structs,
+// it is initialized on first use
 type RankList[K Ordered, V Ordered] struct {
 	sync.RWMutex
 
@@ -71,9 +97,27 @@ type RankList[K Ordered, V Ordered] struct {
 	// Header node of the skip list
 	header *Node[K, V]
 
-	// 用于快速查找的键值对字典
-	// Dictionary for fast key-value lookup
-	dict map[K]V
+	// 跳表的最后一个节点，跳表为空时为 nil
+	// Last node of the skip list, nil when the list is empty
+	tail *Node[K, V]
+
+	// 键到节点的字典，用于快速查找值和直接定位节点
+	// Dictionary from keys to nodes, for fast value lookup and direct node access
+	dict map[K]*Node[K, V]
+
+	// 保护字典的锁。字典只在持有写锁时修改，修改时还需持有 dictMu，
+	// 因此 Get 只需获取 dictMu 的读锁，不会被跳表的结构性修改阻塞
+	// Lock guarding the dictionary. The dictionary is only modified under the write lock and dictMu together,
+	// so Get only needs dictMu's read lock and never waits behind structural changes of the list
+	dictMu sync.RWMutex
+
+	// 字典与跳表不一致时的调试回调，未开启时为 nil
+	// Debug callback for divergences between the dictionary and the list, nil when disabled
+	onDivergence func(key K, err error)
+
+	// 是否不维护字典，为 true 时 dict 为 nil，按键查找退化为扫描第 0 层
+	// Whether the dictionary is disabled, dict is nil then and lookups by key scan level 0
+	noDict bool
 
 	// 当前跳表的最大层级
 	// Current maximum level of the skip list
@@ -82,52 +126,336 @@ type RankList[K Ordered, V Ordered] struct {
 	// 跳表中的节点总数
 	// Total number of nodes in the skip list
 	length int
+
+	// 自上一次整体重建以来的最大长度，以及触发自动重建的收缩倍数，为 0 时不自动重建
+	// Largest length since the last whole rebuild, and the shrink factor triggering an automatic rebuild, 0 when disabled
+	peak          int
+	compactFactor float64
+
+	// 排名阈值回调列表
+	// Registered rank threshold callbacks
+	thresholds []threshold[K]
+
+	// 每个键的元数据，随键的删除一起清理
+	// Per-key metadata, cleaned up together with the key
+	meta map[K]map[string]string
+
+	// 单次增量允许的最大绝对值
+	// Maximum absolute delta allowed for a single increment
+	maxDelta    V
+	hasMaxDelta bool
+
+	// 预写日志，未开启时为 nil
+	// Write-ahead log, nil when disabled
+	wal *wal
+
+	// expvar 计数器，未开启时为 nil
+	// expvar counters, nil when disabled
+	vars *opVars
+
+	// 按层级划分的节点对象池，被删除的节点在这里回收复用
+	// Node pools bucketed by level, deleted nodes are recycled here
+	pool [MaxLevel]sync.Pool
+
+	// 最近一次插入的位置，用于加速按顺序递增的插入
+	// Position of the last insertion, used to speed up monotonically increasing inserts
+	finger finger[K, V]
+
+	// 节点的 arena 分配器，未开启时为 nil
+	// Arena allocator for nodes, nil when disabled
+	arena *arena[K, V]
+
+	// 结构代数，每次可能改变排名的修改都会递增
+	// Structural generation, bumped by every mutation that may shift ranks
+	gen uint64
+
+	// 时间戳使用的时钟，未开启时间戳时为 nil
+	// Clock used for timestamps, nil when timestamps are disabled
+	clock func() time.Time
+
+	// 值相同时比较键的函数，为 nil 时按键的自然顺序比较
+	// Key comparison breaking ties between equal values, nil for the natural order of keys
+	keyCompare func(a, b K) int
+
+	// 值的比较函数，为 nil 时按值的自然顺序比较
+	// Value comparison, nil for the natural order of values
+	valueCompare func(a, b V) int
+
+	// 写入前的值校验函数，未开启时为 nil
+	// Validation function consulted before values are stored, nil when disabled
+	valueGuard func(old, new V) error
+
+	// 容量上限，为 0 时不限制
+	// Capacity limit, 0 when unlimited
+	maxSize int
+
+	// 拒绝插入新键的硬性成员数上限，为 0 时不限制
+	// Hard limit on the number of members past which new keys are refused, 0 when unlimited
+	hardLimit int
+
+	// 超出容量时的淘汰策略和淘汰回调
+	// Eviction policy applied past the capacity and the eviction callback
+	evictPolicy EvictPolicy
+	onEvict     func(entry Entry[K, V], policy EvictPolicy)
+
+	// 按更新时间排序的堆，仅在 EvictStalest 时使用
+	// Heap ordered by update time, only used by EvictStalest
+	stale *staleHeap[K]
+
+	// 已淘汰但尚未报告的条目
+	// Entries evicted but not reported yet
+	evicted []Entry[K, V]
+
+	// IncrByIdempotent 的去重窗口，第一次使用时创建
+	// Dedup window of IncrByIdempotent, created on first use
+	tokens     *tokenCache[V]
+	tokenLimit int
+
+	// 被 Suspend 暂停的条目
+	// Entries parked by Suspend
+	suspended map[K]suspendedEntry[V]
+
+	// 按暂停顺序排列的待清理队列，以及最近一次暂停的序号，Resume 和 Del 留下的过期项在 Sweep 时跳过
+	// Sweep queue in suspension order and the sequence number of the latest suspension,
+	// stale items left behind by Resume and Del are skipped by Sweep
+	sweepQueue []sweepItem[K]
+	parkSeq    uint64
+
+	// 触发自动清理的暂停条目比例，为 0 时不自动清理
+	// Ratio of parked entries triggering automatic sweeping, 0 when disabled
+	sweepRatio float64
+
+	// 内容版本，每次成功改变内容的修改都会递增，通过 Version 无锁读取
+	// Content version, bumped by every mutation that actually changes the content, read lock-free by Version
+	version atomic.Uint64
+
+	// 排名缓存，未开启时为 nil
+	// Rank cache, nil when disabled
+	rankCache *rankCache[K]
+
+	// 前 n 名缓存，未开启时为 nil
+	// Top-n cache, nil when disabled
+	topCache *topCache[K, V]
+
+	// Range、RevRange 和 Top 是否包含占位条目
+	// Whether Range, RevRange and Top include placeholders
+	showPlaceholders bool
+
+	// 是否曾经插入过占位条目，为 false 时跳表中一定没有占位条目，只增不减
+	// Whether a placeholder was ever inserted, false guarantees the list holds none. It never goes back to false
+	hasPlaceholders bool
+
+	// 文本导出和导入使用的格式化与解析函数
+	// Formatters and parsers used by textual exports and imports
+	codec textCodec[K, V]
+
+	// 写入零值时是否删除键
+	// Whether writing the zero value deletes the key
+	zeroDeletes bool
+
+	// 外部存储的写穿适配器，未开启时为 nil
+	// Write-through adapter of the external store, nil when disabled
+	writeThrough *writeThrough[K, V]
+
+	// 后台一致性审计的周期和回调，未开启时为 nil
+	// Interval and callback of the background consistency auditor, nil when disabled
+	auditor *auditor
+
+	// 追踪回调，未开启时为 nil
+	// Trace callback, nil when disabled
+	tracer func(event TraceEvent[K])
+
+	// 按值索引的辅助结构，未开启时为 nil
+	// Auxiliary index by value, nil when disabled
+	buckets *valueBuckets[K, V]
+
+	// 按更新时间排列的链表，未开启时为 nil
+	// List in update order, nil when disabled
+	recency *recencyList[K, V]
+
+	// 排名变化回调和尚未报告的排名变化，未开启时为 nil
+	// Rank change callback and the rank changes not reported yet, nil when disabled
+	onRankChange func(key K, oldRank, newRank int)
+	rankChanges  []rankChange[K]
+
+	// 操作耗时指标，未开启时为 nil
+	// Operation latency metrics, nil when disabled
+	metrics Metrics
+
+	// 键空间事件，未开启时为 nil
+	// Keyspace events, nil when disabled
+	keyspace *keyspace[K]
+
+	// 排名草图，未开启时为 nil
+	// Rank sketch, nil when disabled
+	sketch *rankSketch[V]
+
+	// 是否已经被 Close 关闭
+	// Whether the list has been shut down by Close
+	closed bool
+
+	// Close 时关闭，通知后台 goroutine 退出
+	// Closed by Close to tell background goroutines to exit
+	done chan struct{}
+
+	// 尚未退出的后台 goroutine
+	// Background goroutines that have not exited yet
+	background sync.WaitGroup
+
+	// 生成节点层级的随机数源，只在写锁内使用
+	// Random source for node levels, only used under the write lock
+	rng *rand.Rand
+
+	// 保证零值跳表只初始化一次
+	// Makes sure a zero value list is initialized only once
+	initOnce sync.Once
 }
 
-// NewNode 创建一个新的跳表节点
-// NewNode creates a new skip list node
+// NewNode 创建一个新的跳表节点，前向指针和跨度按层级分配
+// NewNode creates a new skip list node, forward pointers and spans are sized to its level
 func NewNode[K Ordered, V Ordered](key K, value V, level int) *Node[K, V] {
 	return &Node[K, V]{
-		data:  Entry[K, V]{Key: key, Value: value},
-		level: level,
+		data:    Entry[K, V]{Key: key, Value: value},
+		forward: make([]*Node[K, V], level),
+		span:    make([]int, level),
+		level:   level,
 	}
 }
 
 // New 创建一个新的跳表
+// 可以传入若干 Option 来开启可选功能
 // New creates a new skip list
-func New[K Ordered, V Ordered]() *RankList[K, V] {
-	return &RankList[K, V]{
-		header: NewNode[K, V](ZeroValue[K](), ZeroValue[V](), MaxLevel),
-		dict:   make(map[K]V),
-		level:  1,
+// Optional behaviors can be enabled by passing Options
+func New[K Ordered, V Ordered](opts ...Option[K, V]) *RankList[K, V] {
+	sl := &RankList[K, V]{}
+	for _, opt := range opts {
+		opt(sl)
+	}
+	if sl.buckets != nil && sl.valueCompare != nil {
+		panic("ranklist: value buckets cannot be combined with a custom value order")
 	}
+	sl.lazyInit()
+	return sl
 }
 
-// randomLevel 随机生成节点的层级
+// WithRandSource 使用 src 作为生成节点层级的随机数源，传入固定种子的源可以让跳表结构可复现。
+// 默认每个跳表使用一个以 crypto/rand 熵作为种子的 ChaCha8 源，不经过任何全局锁。
+// src 只在写锁内使用，但不能与其他跳表或代码共享，src 为 nil 时 panic
+// WithRandSource uses src as the random source for node levels, a seeded source makes the list structure reproducible.
+// By default every list has its own ChaCha8 source seeded from crypto/rand, without any global lock.
+// src is only used under the write lock but must not be shared with other lists or code. It panics if src is nil
+func WithRandSource[K Ordered, V Ordered](src rand.Source) Option[K, V] {
+	if src == nil {
+		panic("ranklist: nil random source")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.rng = rand.New(src)
+	}
+}
+
+// WithSeed 使用以 seed 为种子的 PCG 源生成节点层级，相同的种子和相同的操作序列得到相同的跳表结构
+// WithSeed generates node levels from a PCG source seeded with seed,
+// the same seed and the same sequence of operations produce the same list structure
+func WithSeed[K Ordered, V Ordered](seed uint64) Option[K, V] {
+	return WithRandSource[K, V](rand.NewPCG(seed, seed))
+}
+
+// randomLevel 随机生成节点的层级，必须在写锁内调用
 // 使用概率Probability来决定是否增加层级，最高不超过MaxLevel
-// randomLevel generates a random level for a new node
+// randomLevel generates a random level for a new node and must be called under the write lock
 // Uses Probability to decide level increment, not exceeding MaxLevel
-func randomLevel() int {
+func (sl *RankList[K, V]) randomLevel() int {
 	level := 1
-	for rand.Float64() < Probability && level < MaxLevel {
+	for sl.rng.Float64() < Probability && level < MaxLevel {
 		level++
 	}
 	return level
 }
 
 // Set 向跳表中插入数据
-// 如果键已存在，则先删除旧节点再插入新节点
+// 如果键已存在，则先删除旧节点再插入新节点。
+// 键是新插入的返回 true，更新已有的键返回 false，判断与写入在同一把写锁内完成
 // Set inserts or updates a key-value pair
-// If the key exists, removes the old node before inserting the new one
-func (sl *RankList[K, V]) Set(key K, value V) {
-	sl.Lock()
-	defer sl.Unlock()
+// If the key exists, removes the old node before inserting the new one.
+// Returns true if the key was newly inserted and false if an existing key was updated,
+// decided under the same write lock as the write itself
+func (sl *RankList[K, V]) Set(key K, value V) bool {
+	inserted, _ := sl.SetChecked(key, value)
+	return inserted
+}
+
+// setAndUnlock 完成一次已获取写锁的 Set，并在触发回调之前释放写锁，event 是这次写入的键空间事件名，键是新插入的返回 true
+// setAndUnlock completes a Set whose write lock is already held, releasing it before callbacks fire,
+// event being the keyspace event name of the write. Returns true if the key was newly inserted
+func (sl *RankList[K, V]) setAndUnlock(key K, value V, event string) bool {
+	probes := sl.probeThresholds(key)
+	gen := sl.gen
+	result := sl.store(key, value, event)
+	events := sl.thresholdEvents(key, probes)
+	var trace TraceEvent[K]
+	if sl.tracer != nil {
+		trace = sl.traceSet(key, gen)
+	}
+	sl.Unlock()
 
-	// 如果节点已存在，先删除旧节点
-	// If node exists, remove old node first
-	if _, exists := sl.dict[key]; exists {
-		sl.del(key)
+	result.count(sl.vars)
+	if sl.tracer != nil {
+		sl.tracer(trace)
 	}
+	fireThresholds(events)
+	return result == storeInserted
+}
+
+// set 在已持有写锁的情况下插入或更新数据，键是新插入的返回 true。
+// set 不淘汰超出容量的成员，调用方在记录这次写入之后调用 evictOverflow，日志和键空间事件因此先写入后淘汰
+// set inserts or updates a key-value pair, the caller must hold the write lock. Returns true if the key was newly inserted.
+// set never evicts past the capacity, the caller runs evictOverflow after journaling the write
+// so the log and the keyspace events see the write before the evictions
+func (sl *RankList[K, V]) set(key K, value V) bool {
+	return sl.setLevel(key, value, sl.randomLevel())
+}
+
+// setLevel 以指定的层级插入或更新数据，键是新插入的返回 true，调用方需持有写锁
+// setLevel inserts or updates a key-value pair with the given level, returns true if the key was newly inserted.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) setLevel(key K, value V, level int) bool {
+	// 如果节点已存在，先摘除旧节点。字典继续指向旧节点直到新节点写入，Get 不会看到键短暂消失，
+	// 旧节点在字典切换之后才回收
+	// If node exists, unlink the old node first. The dictionary keeps pointing at it until the new node is written,
+	// so Get never sees the key disappear, and the old node is only recycled after the switch
+	old, exists := sl.lookup(key)
+	return sl.setNode(old, exists, key, value, level)
+}
+
+// setNode 以指定的层级写入键值对，exists 为 true 时 old 是键当前所在的节点。
+// 键是新插入的返回 true，调用方需持有写锁
+// setNode writes a key-value pair with the given level, old is the node currently holding key when exists is true.
+// Returns true if the key was newly inserted. The caller must hold the write lock
+func (sl *RankList[K, V]) setNode(old *Node[K, V], exists bool, key K, value V, level int) bool {
+	placeholder := exists && old.placeholder
+	oldRank := 0
+	if exists {
+		oldRank = sl.rankBefore(old)
+		changed := old.data.Value != value
+		if sl.updateInPlace(old, value) {
+			if changed {
+				sl.version.Add(1)
+				sl.trackStale(old)
+				sl.recency.touch(old)
+			}
+			sl.recordRankChange(key, oldRank, oldRank)
+			return false
+		}
+		// 字典指向不在跳表中的节点时按新键插入，字典条目随后被覆盖
+		// A dictionary entry pointing outside the list is treated as a new key and overwritten below
+		if exists = sl.unlink(old); !exists {
+			oldRank = 0
+		}
+	}
+	entry := Entry[K, V]{Key: key, Value: value}
+	sl.topCache.touch(sl, entry)
+	sl.version.Add(1)
+	sl.gen++
 
 	// 用于记录每层的前驱节点
 	// Records predecessor nodes at each level
@@ -139,9 +467,11 @@ func (sl *RankList[K, V]) Set(key K, value V) {
 
 	curr := sl.header
 
-	// 生成新节点的随机层级
-	// Generate random level for new node
-	level := randomLevel()
+	// 新增的层上前驱是头节点、排名为 0，新节点在这些层上的跨度等于它在第 0 层的位置 rank[0]+1。
+	// 跨度记录在目标节点上，头节点的跨度从不被读取，因此无需像记录在前驱上的实现那样用长度初始化
+	// On the new levels the predecessor is the header at rank 0, so the new node's span there is its level-0 position rank[0]+1.
+	// Spans live on the target node and the header's spans are never read,
+	// so unlike layouts that store spans on the predecessor there is nothing to seed with the length
 	if level > sl.level {
 		for i := sl.level; i < level; i++ {
 			prev[i] = sl.header
@@ -153,10 +483,10 @@ func (sl *RankList[K, V]) Set(key K, value V) {
 	// Find insertion position and update rank information
 	sum := 0
 	for i := sl.level - 1; i >= 0; i-- {
+		curr, sum = sl.finger.resume(sl, i, curr, sum, entry)
 		for curr.forward[i] != nil {
 
-			if curr.forward[i].data.Value > value ||
-				(curr.forward[i].data.Value == value && curr.forward[i].data.Key > key) {
+			if sl.compare(curr.forward[i].data, entry) > 0 {
 				break
 			}
 			sum += curr.forward[i].span[i]
@@ -169,8 +499,18 @@ func (sl *RankList[K, V]) Set(key K, value V) {
 
 	// 创建并插入新节点
 	// Create and insert new node
-	newNode := NewNode(key, value, level)
-	sl.dict[key] = value
+	newNode := sl.newNode(key, value, level)
+	newNode.version = 1
+	newNode.placeholder = placeholder
+	newNode.times = sl.stamp()
+	if exists {
+		newNode.version = old.version + 1
+		newNode.times = newNode.times.keepCreated(old.times)
+	}
+	sl.dictPut(key, newNode)
+	if exists {
+		sl.freeNode(old)
+	}
 	for i := 0; i < level; i++ {
 		newNode.forward[i] = prev[i].forward[i]
 		prev[i].forward[i] = newNode
@@ -185,6 +525,17 @@ func (sl *RankList[K, V]) Set(key K, value V) {
 		}
 	}
 
+	// 维护第 0 层的后向指针和尾节点
+	// Maintain the level-0 backward pointers and the tail
+	if prev[0] != sl.header {
+		newNode.backward = prev[0]
+	}
+	if newNode.forward[0] != nil {
+		newNode.forward[0].backward = newNode
+	} else {
+		sl.tail = newNode
+	}
+
 	// 更新高于新节点的层级的跨度
 	// Update spans for levels above new node
 	for i := level; i < sl.level; i++ {
@@ -193,6 +544,221 @@ func (sl *RankList[K, V]) Set(key K, value V) {
 		}
 	}
 	sl.length++
+	sl.peak = max(sl.peak, sl.length)
+	sl.buckets.add(sl, newNode)
+	sl.finger.record(sl, newNode, &prev, &rank)
+	sl.trackStale(newNode)
+	sl.recency.touch(newNode)
+	sl.recordRankChange(key, oldRank, rank[0]+1)
+	return !exists
+}
+
+// updateInPlace 当新值不改变节点的位置时直接覆盖节点中的值，指针和跨度都无需修改。
+// 新条目必须仍然严格位于第 0 层的前驱和后继之间，与邻居值相同时按键决定先后，顺序改变时返回 false。
+// 调用方需持有写锁
+// updateInPlace overwrites the value in the node when the new value keeps the node's position,
+// no pointers or spans need to change.
+// The new entry must still sort strictly between its level-0 predecessor and successor,
+// ties with a neighbor's value are broken by key, and false is returned when the order would change.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) updateInPlace(node *Node[K, V], value V) bool {
+	// 节点的两端必须与跳表相连，否则它可能是字典中不在跳表里的节点
+	// Both ends of the node must connect to the list, otherwise it may be a dictionary entry outside the list
+	if (node.backward == nil && sl.header.forward[0] != node) || (node.forward[0] == nil && sl.tail != node) {
+		return false
+	}
+
+	entry := Entry[K, V]{Key: node.data.Key, Value: value}
+	if prev := node.backward; prev != nil && sl.compare(prev.data, entry) >= 0 {
+		return false
+	}
+	if next := node.forward[0]; next != nil && sl.compare(entry, next.data) >= 0 {
+		return false
+	}
+
+	sl.topCache.touch(sl, node.data)
+	sl.topCache.touch(sl, entry)
+
+	// Get 在字典锁内读取节点的值，因此修改值时也需持有字典锁
+	// Get reads the node's value under the dictionary lock, so the value is written under it too
+	sl.buckets.remove(node)
+	sl.dictMu.Lock()
+	if node.data.Value != value {
+		node.version++
+		node.times = sl.stamp().keepCreated(node.times)
+	}
+	node.data.Value = value
+	sl.dictMu.Unlock()
+	sl.buckets.add(sl, node)
+	return true
+}
+
+// Clear 清空跳表中的所有元素
+// Clear removes all elements from the skip list
+func (sl *RankList[K, V]) Clear() {
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return
+	}
+	zones := sl.zoneKeys()
+	sl.reset()
+	sl.version.Add(1)
+	sl.journal(walOpClear, ZeroValue[K](), ZeroValue[V]())
+	sl.mirror(walOpClear, ZeroValue[K](), ZeroValue[V]())
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	fireThresholds(events)
+}
+
+// reset 将跳表恢复为刚创建时的状态，调用方需持有写锁
+// reset restores the skip list to its freshly created state, the caller must hold the write lock
+func (sl *RankList[K, V]) reset() {
+	sl.resetNodes()
+	sl.swapDict(sl.newDict(0))
+	sl.rebuildStale()
+	sl.suspended = nil
+	sl.sweepQueue = nil
+}
+
+// resetNodes 将跳表结构恢复为空，字典保持不变，调用方需持有写锁
+// resetNodes empties the list structure and leaves the dictionary untouched, the caller must hold the write lock
+func (sl *RankList[K, V]) resetNodes() {
+	sl.header = NewNode[K, V](ZeroValue[K](), ZeroValue[V](), MaxLevel)
+	sl.tail = nil
+	sl.finger.invalidate()
+	sl.topCache.invalidate()
+	sl.gen++
+	if sl.arena != nil {
+		sl.arena = &arena[K, V]{size: sl.arena.size}
+	}
+	sl.level = 1
+	sl.length = 0
+	sl.peak = 0
+	sl.meta = nil
+	sl.buckets.rebuild(sl)
+	sl.recency.rebuild(sl)
+}
+
+// swapDict 整体替换字典，调用方需持有写锁
+// swapDict replaces the whole dictionary, the caller must hold the write lock
+func (sl *RankList[K, V]) swapDict(dict map[K]*Node[K, V]) {
+	sl.dictMu.Lock()
+	sl.dict = dict
+	sl.dictMu.Unlock()
+}
+
+// ResetAndSnapshot 在同一把写锁内获取完整的有序榜单并清空跳表，
+// 因此不会有写入落在快照和清空之间而丢失
+// ResetAndSnapshot captures the full ordered standings and resets the skip list under one write lock,
+// so no write can land between the snapshot and the reset and be lost
+func (sl *RankList[K, V]) ResetAndSnapshot() []Entry[K, V] {
+	return sl.ResetAndSnapshotWith(nil)
+}
+
+// ResetAndSnapshotWith 与 ResetAndSnapshot 相同，但会对快照中的每个条目调用 carry，
+// carry 返回 true 时该键会以返回的值重新写入新赛季
+// ResetAndSnapshotWith behaves like ResetAndSnapshot, but calls carry for every entry of the snapshot,
+// when carry returns true the key is reseeded into the new season with the returned value
+func (sl *RankList[K, V]) ResetAndSnapshotWith(carry func(rank int, entry Entry[K, V]) (V, bool)) []Entry[K, V] {
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return nil
+	}
+	zones := sl.zoneKeys()
+
+	entries := sl.entries()
+	carried := make([]Entry[K, V], 0)
+	if carry != nil {
+		for i, entry := range entries {
+			if value, ok := carry(i+1, entry); ok {
+				carried = append(carried, Entry[K, V]{Key: entry.Key, Value: value})
+			}
+		}
+	}
+	sl.build(sl.sortEntries(carried))
+	sl.version.Add(1)
+	sl.journalReset()
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	fireThresholds(events)
+	return entries
+}
+
+// Entries 在一把读锁内按排名顺序复制跳表的全部条目，切片按读锁内的长度一次性分配。
+// 与先调用 Length 再调用 Range 不同，长度和内容来自同一时刻，结果总是完整且一致的。
+// Snapshot 和 Freeze 都基于它
+// Entries copies every entry of the skip list in rank order under one read lock,
+// the slice being allocated once from the length read under that lock.
+// Unlike calling Length and then Range, length and content come from the same moment,
+// so the result is always complete and consistent. Snapshot and Freeze are built on it
+func (sl *RankList[K, V]) Entries() []Entry[K, V] {
+	sl.RLock()
+	defer sl.RUnlock()
+	return sl.entries()
+}
+
+// Snapshot 在一把读锁内按排名顺序返回跳表的全部条目，与 Entries 相同
+// Snapshot returns all entries of the skip list in rank order under one read lock, the same as Entries
+func (sl *RankList[K, V]) Snapshot() []Entry[K, V] {
+	return sl.Entries()
+}
+
+// Restore 在一把写锁内用 entries 原子地替换跳表的全部内容，读操作不会看到恢复了一半的跳表。
+// entries 可以无序，重复的键以最后一次出现的值为准
+// Restore atomically replaces the whole content of the skip list with entries under one write lock,
+// readers never observe a half-restored list.
+// entries may be unordered, duplicate keys keep the value of their last occurrence
+func (sl *RankList[K, V]) Restore(entries []Entry[K, V]) {
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return
+	}
+	zones := sl.zoneKeys()
+	sl.load(entries)
+	sl.evictOverflow()
+	sl.journalReset()
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	fireThresholds(events)
+}
+
+// entries 按排名顺序返回跳表的全部条目，调用方需持有锁
+// entries returns all entries of the skip list in rank order, the caller must hold the lock
+func (sl *RankList[K, V]) entries() []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, sl.length)
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		entries = append(entries, curr.data)
+	}
+	return entries
+}
+
+// walk 按排名顺序产出跳表的全部条目，调用方需在遍历期间持有锁
+// walk yields all entries of the skip list in rank order, the caller must hold the lock throughout the iteration
+func (sl *RankList[K, V]) walk(yield func(Entry[K, V]) bool) {
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		if !yield(curr.data) {
+			return
+		}
+	}
+}
+
+// Version 返回跳表的内容版本，不获取任何锁。
+// 每次成功改变内容的修改都会使版本递增：实际改变了值的写入、删除、清空，以及批量写入中每个改变的键各递增一次；
+// Restore、LoadMap、Load 等整体替换内容的操作递增一次。读操作、值未改变的写入和 Compact 不改变版本，
+// 因此版本相同即表示内容未变，可以用来判断缓存是否过期
+// Version returns the content version of the skip list without taking any lock.
+// Every mutation that actually changes the content bumps it: writes that change a value, deletes and clears,
+// once per changed key within batch writes, and once for whole-content replacements such as Restore, LoadMap and Load.
+// Reads, writes of an unchanged value and Compact leave it alone,
+// so an equal version means unchanged content and can tell whether a cache is stale
+func (sl *RankList[K, V]) Version() uint64 {
+	return sl.version.Load()
 }
 
 // Length 返回跳表中当前元素的数量。
@@ -205,23 +771,132 @@ func (sl *RankList[K, V]) Length() int {
 
 // Del 从跳表中删除指定键的节点。
 // 如果键存在并且节点被删除，返回true；如果键不存在，返回false。
+// 被 Suspend 暂停的键也会被彻底删除，此时同样返回 true
 // Del removes the node with the specified key from the skip list.
 // Returns true if the key exists and the node is deleted, false if the key does not exist.
+// A key parked by Suspend is dropped for good as well, which also returns true
 func (sl *RankList[K, V]) Del(key K) bool {
+	return sl.DelChecked(key) == nil
+}
+
+// DelChecked 与 Del 相同，但以错误说明失败的原因：键不存在时返回 ErrNotFound，跳表已关闭时返回 ErrClosed
+// DelChecked behaves like Del, but reports why it failed:
+// ErrNotFound when the key does not exist and ErrClosed once the list is closed
+func (sl *RankList[K, V]) DelChecked(key K) error {
+	start := sl.metricsStart()
 	sl.Lock()
-	defer sl.Unlock()
-	return sl.del(key)
+	if sl.closed {
+		sl.Unlock()
+		sl.observe("del", start, 0)
+		return ErrClosed
+	}
+	var nodeLevel int
+	node, exists := sl.lookup(key)
+	if exists {
+		nodeLevel = node.level
+	}
+	_, suspended := sl.suspended[key]
+	if exists || suspended {
+		if err := sl.persist(walOpDel, key, ZeroValue[V]()); err != nil {
+			sl.Unlock()
+			sl.observe("del", start, 0)
+			return err
+		}
+	}
+	probes := sl.probeThresholds(key)
+	ok, divergence := sl.del(key)
+	if ok {
+		delete(sl.meta, key)
+		sl.journal(walOpDel, key, ZeroValue[V]())
+	}
+	var trace TraceEvent[K]
+	if sl.tracer != nil {
+		trace = sl.traceEvent(TraceDel, key, ok, nodeLevel)
+	}
+	if suspended {
+		delete(sl.suspended, key)
+		ok = true
+	}
+	events := sl.thresholdEvents(key, probes)
+	sl.Unlock()
+
+	sl.vars.add(opDel)
+	if sl.tracer != nil {
+		sl.tracer(trace)
+	}
+	sl.reportDivergence(key, divergence)
+	fireThresholds(events)
+	sl.observe("del", start, hits(ok))
+	if !ok {
+		return ErrNotFound
+	}
+	return nil
 }
 
 // 删除操作实际执行跳表节点的删除。
 // 它搜索指定的节点，更新前向指针，并相应地调整跨度值。
 // del performs the actual deletion of a node from the skip list.
 // It searches for the node, updates the forward pointers, and adjusts the span values accordingly.
-func (sl *RankList[K, V]) del(key K) bool {
-	value, exists := sl.dict[key]
+// 字典与跳表不一致时就地修复，并返回描述修复内容的错误
+// A divergence between the dictionary and the list is repaired on the spot and described by the returned error
+func (sl *RankList[K, V]) del(key K) (bool, error) {
+	var divergence error
+	node, exists := sl.lookup(key)
+	if !exists && sl.onDivergence != nil && !sl.noDict {
+		if node, exists = sl.scan(key); exists {
+			divergence = sl.listOrphan(key)
+		}
+	}
 	if !exists {
-		return false
+		return false, nil
 	}
+	if ok, err := sl.delNode(key, node); err != nil || !ok {
+		return ok, err
+	}
+	return true, divergence
+}
+
+// delNode 删除键 key 所在的节点 node，调用方已经通过字典或按值下降找到了它。
+// 字典与跳表不一致时就地修复并返回描述修复内容的错误，调用方需持有写锁
+// delNode removes node, the node holding key that the caller already found through the dictionary or a descent by value.
+// A divergence between the dictionary and the list is repaired on the spot and described by the returned error.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) delNode(key K, node *Node[K, V]) (bool, error) {
+	var divergence error
+	oldRank := sl.rankBefore(node)
+	if !sl.unlink(node) {
+		sl.dictDelete(key)
+
+		// 按字典节点的值下降没有找到它：可能只是字典残留，也可能跳表中键的节点与字典给出的值不一致，
+		// 删除以键为准，按键扫描第 0 层确认
+		// Descending by the value of the dictionary's node did not find it: either the dictionary entry is a leftover,
+		// or the list's node for key disagrees with the value the dictionary gives. Deletion goes by the key,
+		// so level 0 is scanned for it to decide
+		stale, found := sl.unlinkKey(key)
+		if !found {
+			return false, sl.dictOrphan(key)
+		}
+		node, divergence = stale, sl.misplaced(key)
+		oldRank = 0
+	}
+	if oldRank > 0 {
+		sl.recordRankChange(key, oldRank, 0)
+	}
+	sl.dictDelete(key)
+	sl.freeNode(node)
+	sl.untrackStale(key)
+	sl.version.Add(1)
+	sl.autoCompact()
+	return true, divergence
+}
+
+// unlink 将节点从跳表结构中摘除，不修改字典也不回收节点。
+// 节点不在跳表中时（字典与跳表不一致）不做任何修改并返回 false，调用方需持有写锁
+// unlink removes the node from the list structure without touching the dictionary or recycling the node.
+// When the node is not in the list (the dictionary disagrees with it) nothing is changed and false is returned.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) unlink(node *Node[K, V]) bool {
+	entry := node.data
 
 	// 记录每层的前驱节点
 	// Record predecessor nodes at each level
@@ -231,20 +906,63 @@ func (sl *RankList[K, V]) del(key K) bool {
 	// 查找要删除的节点
 	// Find the node to be deleted
 	for i := sl.level - 1; i >= 0; i-- {
-		for curr.forward[i] != nil &&
-			(curr.forward[i].data.Value < value ||
-				(curr.forward[i].data.Value == value && curr.forward[i].data.Key < key)) {
+		for curr.forward[i] != nil && sl.compare(curr.forward[i].data, entry) < 0 {
 			curr = curr.forward[i]
 		}
 		prev[i] = curr
 	}
 
+	// 要删除的节点，字典与跳表不一致时可能找不到
+	// The node to be deleted, it may be missing when the dictionary disagrees with the list
+	if prev[0].forward[0] != node {
+		return false
+	}
+	sl.unlinkAt(node, &prev)
+	return true
+}
+
+// unlinkKey 沿第 0 层按键查找节点并将其摘除，不依赖节点的值与其位置一致，耗时 O(n)，
+// 用于修复字典与跳表不一致的情况，返回被摘除的节点，调用方需持有写锁
+// unlinkKey looks for the node holding key along level 0 and removes it, without relying on the node's value
+// agreeing with its position, in O(n). It repairs divergences between the dictionary and the list
+// and returns the removed node. The caller must hold the write lock
+func (sl *RankList[K, V]) unlinkKey(key K) (*Node[K, V], bool) {
+	// 每层最近经过的、高度覆盖该层的节点，到达目标时就是它在每层的前驱
+	// The latest node passed whose height covers each level, the predecessors of the target once it is reached
+	var prev [MaxLevel]*Node[K, V]
+	for i := range sl.level {
+		prev[i] = sl.header
+	}
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		if curr.data.Key == key {
+			sl.unlinkAt(curr, &prev)
+
+			// 节点的值可能与位置不一致，前 n 名缓存无法据此判断是否受影响
+			// The node's value may disagree with its position, so the top-n cache cannot tell whether it is affected
+			sl.topCache.invalidate()
+			return curr, true
+		}
+		for i := range curr.level {
+			prev[i] = curr
+		}
+	}
+	return nil, false
+}
+
+// unlinkAt 根据每层的前驱将 target 从跳表结构中摘除，调用方需持有写锁
+// unlinkAt removes target from the list structure given its predecessor on every level.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) unlinkAt(target *Node[K, V], prev *[MaxLevel]*Node[K, V]) {
+	sl.gen++
+	sl.buckets.remove(target)
+	sl.recency.remove(target)
+
 	// 更新前向指针和跨度
 	// Update forward pointers and spans
 	for i := 0; i < sl.level; i++ {
-		curr = prev[i].forward[i]
+		curr := prev[i].forward[i]
 
-		if curr != nil && curr.data.Key == key && curr.data.Value == value {
+		if curr != nil && curr == target {
 			// 如果这一层找到了删除的节点，那么将删除节点清除，并将删除节点的 span 甩给后面的节点
 			// If the node to be deleted is found at this level, remove the node and pass its span to the next node
 			prev[i].forward[i] = curr.forward[i]
@@ -258,6 +976,16 @@ func (sl *RankList[K, V]) del(key K) bool {
 			// so the span of these higher-level nodes needs to be decremented by 1
 			curr.span[i]--
 		}
+
+		// curr 为 nil 时这一层在删除位置之后没有节点，跨度记录在目标节点上，没有需要调整的跨度
+		// When curr is nil no node follows the deleted position on this level,
+		// and since spans live on the target node there is no span to adjust
+	}
+
+	if target.forward[0] != nil {
+		target.forward[0].backward = target.backward
+	} else {
+		sl.tail = target.backward
 	}
 
 	// 更新跳表的最大层级
@@ -266,37 +994,129 @@ func (sl *RankList[K, V]) del(key K) bool {
 		sl.level--
 	}
 
-	delete(sl.dict, key)
 	sl.length--
-	return true
+	sl.finger.invalidate()
+	sl.topCache.touch(sl, target.data)
 }
 
 // Get 根据键获取节点的值
-// 如果键存在并且节点被删除，返回true；如果键不存在，返回false。
+// 如果键存在，返回其值和true；如果键不存在，返回零值和false。
+// 只获取字典的读锁，不会被跳表的结构性修改阻塞
 // Get retrieves the value associated with the key
-// Returns true if the key exists and the node is deleted, false if the key does not exist.
+// Returns the value and true if the key exists, the zero value and false if the key does not exist.
+// Only the dictionary's read lock is taken, so it never waits behind structural changes of the list
 func (sl *RankList[K, V]) Get(key K) (V, bool) {
-	sl.RLock()
-	defer sl.RUnlock()
+	entry, ok := sl.GetEntry(key)
+	return entry.Value, ok
+}
 
-	if value, exists := sl.dict[key]; exists {
-		return value, true
+// GetChecked 与 Get 相同，但键不存在时返回 ErrNotFound
+// GetChecked behaves like Get, but returns ErrNotFound when the key does not exist
+func (sl *RankList[K, V]) GetChecked(key K) (V, error) {
+	value, ok := sl.Get(key)
+	if !ok {
+		return value, ErrNotFound
 	}
-	return ZeroValue[V](), false
+	return value, nil
+}
+
+// GetEntry 根据键获取完整的条目，键不存在时返回零值条目和 false，加锁方式与 Get 相同
+// GetEntry retrieves the complete entry stored for key, a zero entry and false are returned if the key does not exist.
+// It locks the same way Get does
+func (sl *RankList[K, V]) GetEntry(key K) (Entry[K, V], bool) {
+	start := sl.metricsStart()
+	entry, ok := sl.getEntry(key)
+	sl.observe("get", start, hits(ok))
+	return entry, ok
+}
+
+// getEntry 根据键获取完整的条目，加锁方式与 Get 相同
+// getEntry retrieves the complete entry stored for key, it locks the same way Get does
+func (sl *RankList[K, V]) getEntry(key K) (Entry[K, V], bool) {
+	sl.vars.add(opGet)
+	if sl.noDict {
+		// 没有字典时需要扫描跳表，因此获取跳表的读锁
+		// Without a dictionary the list itself is scanned, so the list's read lock is taken
+		sl.RLock()
+		defer sl.RUnlock()
+	} else {
+		sl.dictMu.RLock()
+		defer sl.dictMu.RUnlock()
+	}
+
+	if node, exists := sl.lookup(key); exists {
+		return node.data, true
+	}
+	return Entry[K, V]{}, false
 }
 
 // Rank 获取节点的排名
-// 如果键存在并且节点被删除，返回true；如果键不存在，返回false。
+// 如果键存在，返回从 1 开始的排名和true；如果键不存在，返回0和false。
 // Rank gets the rank of a node
-// Returns true if the key exists and the node is deleted, false if the key does not exist.
+// Returns the 1-based rank and true if the key exists, 0 and false if the key does not exist.
 func (sl *RankList[K, V]) Rank(key K) (int, bool) {
+	start := sl.metricsStart()
+	sl.vars.add(opRank)
+	rank, ok, diverged := sl.cachedRank(key)
+	if diverged {
+		// 字典与跳表不一致，在写锁下修复后重新计算
+		// The dictionary and the list disagree, repair under the write lock and compute again
+		sl.repair(key)
+		rank, ok, _ = sl.cachedRank(key)
+	}
+	sl.observe("rank", start, hits(ok))
+	if sl.tracer != nil {
+		sl.traceRead(TraceRank, key)
+	}
+	return rank, ok
+}
+
+// RankChecked 与 Rank 相同，但键不存在时返回 ErrNotFound
+// RankChecked behaves like Rank, but returns ErrNotFound when the key does not exist
+func (sl *RankList[K, V]) RankChecked(key K) (int, error) {
+	rank, ok := sl.Rank(key)
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return rank, nil
+}
+
+// cachedRank 在读锁内计算键的排名，开启 WithRankCache 时优先使用缓存，
+// 找不到键时还会报告字典与跳表是否不一致
+// cachedRank computes the rank of key under the read lock, consulting the cache when WithRankCache is enabled,
+// and reports whether the dictionary and the list disagree when the key is not found
+func (sl *RankList[K, V]) cachedRank(key K) (int, bool, bool) {
 	sl.RLock()
 	defer sl.RUnlock()
+	return sl.cachedRankLocked(key)
+}
 
-	value, exists := sl.dict[key]
+// cachedRankLocked 与 cachedRank 相同，但调用方需持有锁
+// cachedRankLocked behaves like cachedRank, but the caller must hold the lock
+func (sl *RankList[K, V]) cachedRankLocked(key K) (int, bool, bool) {
+	if sl.rankCache != nil {
+		if rank, ok := sl.rankCache.get(key, sl.gen); ok {
+			return rank, true, false
+		}
+	}
+	rank, ok := sl.rank(key)
+	if !ok {
+		return 0, false, sl.diverged(key)
+	}
+	if sl.rankCache != nil {
+		sl.rankCache.put(key, rank, sl.gen)
+	}
+	return rank, true, false
+}
+
+// rank 计算节点的排名，调用方需持有锁
+// rank calculates the rank of a node, the caller must hold the lock
+func (sl *RankList[K, V]) rank(key K) (int, bool) {
+	node, exists := sl.lookup(key)
 	if !exists {
 		return 0, false
 	}
+	entry := node.data
 
 	// 计算节点的排名
 	// Calculate node's rank
@@ -306,13 +1126,12 @@ func (sl *RankList[K, V]) Rank(key K) (int, bool) {
 	for i := sl.level - 1; i >= 0; i-- {
 		for curr.forward[i] != nil {
 
-			if curr.forward[i].data.Value == value && curr.forward[i].data.Key == key {
+			if curr.forward[i].data == entry {
 				rank += curr.forward[i].span[i]
 				return rank, true
 			}
 
-			if curr.forward[i].data.Value > value ||
-				(curr.forward[i].data.Value == value && curr.forward[i].data.Key > key) {
+			if sl.compare(curr.forward[i].data, entry) > 0 {
 				break
 			}
 
@@ -323,17 +1142,109 @@ func (sl *RankList[K, V]) Rank(key K) (int, bool) {
 	return 0, false
 }
 
-// Range 获取指定排名区间内的榜单项（不包含END）
-// 返回指定范围内的条目列表。
-// Range retrieves the entries within the specified rank range (excluding END)
-// Returns a list of entries within the specified range.
+// byRank 根据排名查找节点，排名超出范围时返回 nil，调用方需持有锁
+// byRank finds the node at the given rank, returns nil if the rank is out of range.
+// The caller must hold the lock
+func (sl *RankList[K, V]) byRank(rank int) *Node[K, V] {
+	if rank < 1 || rank > sl.length {
+		return nil
+	}
+
+	traversed := 0
+	curr := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for curr.forward[i] != nil && traversed+curr.forward[i].span[i] <= rank {
+			traversed += curr.forward[i].span[i]
+			curr = curr.forward[i]
+		}
+		if traversed == rank {
+			return curr
+		}
+	}
+	return nil
+}
+
+// seekAfter 查找排序位置严格位于 entry 之后的第一个节点，不存在时返回 nil，调用方需持有锁
+// seekAfter finds the first node that sorts strictly after entry, returns nil if there is none.
+// The caller must hold the lock
+func (sl *RankList[K, V]) seekAfter(entry Entry[K, V]) *Node[K, V] {
+	curr := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for curr.forward[i] != nil && sl.compare(curr.forward[i].data, entry) <= 0 {
+			curr = curr.forward[i]
+		}
+	}
+	return curr.forward[0]
+}
+
+// Range 获取排名区间 [start, end) 内的榜单项，排名从 1 开始，与 Rank 的返回值一致。
+// start 小于 1 时按 1 处理，end 超过长度时截断到最后一名，区间为空时返回空切片。
+// 例如 Range(1, 3) 返回排名 1 和 2 的条目
+// Range retrieves the entries within the rank range [start, end), ranks are 1-based like the ones Rank returns.
+// A start below 1 is treated as 1, an end past the length is cut at the last rank,
+// and an empty window returns an empty slice.
+// For example Range(1, 3) returns the entries at ranks 1 and 2
 func (sl *RankList[K, V]) Range(start int, end int) []Entry[K, V] {
+	began := sl.metricsStart()
+	sl.vars.add(opRange)
+	sl.RLock()
+	entries := sl.appendRange(make([]Entry[K, V], 0, sl.rangeSize(start, end)), start, end)
+	sl.RUnlock()
+	sl.observe("range", began, len(entries))
+
+	if sl.tracer != nil {
+		sl.traceRead(TraceRange, ZeroValue[K]())
+	}
+	return entries
+}
+
+// RangeAppend 与 Range 相同，但将结果追加到 dst 并返回扩展后的切片，
+// 调用方可以用 dst[:0] 在多次调用之间复用同一个缓冲区
+// RangeAppend behaves like Range, but appends the entries to dst and returns the extended slice,
+// callers can reuse one buffer across calls with dst[:0]
+func (sl *RankList[K, V]) RangeAppend(dst []Entry[K, V], start int, end int) []Entry[K, V] {
+	sl.vars.add(opRange)
 	sl.RLock()
 	defer sl.RUnlock()
 
+	return sl.appendRange(dst, start, end)
+}
+
+// appendRange 将指定排名区间内的条目追加到 dst，追加前按区间大小一次性扩容，调用方需持有锁
+// appendRange appends the entries within the specified rank range to dst,
+// growing it once by the window size beforehand. The caller must hold the lock
+func (sl *RankList[K, V]) appendRange(dst []Entry[K, V], start int, end int) []Entry[K, V] {
+	n := sl.rangeSize(start, end)
+	if n == 0 {
+		return dst
+	}
+
+	dst = slices.Grow(dst, n)
+	sl.walkRange(start, end, func(node *Node[K, V]) {
+		if !sl.hidden(node) {
+			dst = append(dst, node.data)
+		}
+	})
+	return dst
+}
+
+// rangeSize 根据跳表长度计算排名区间内的条目数，即 min(end, length+1) - max(start, 1)，不小于 0，调用方需持有锁
+// rangeSize computes the number of entries within the rank range from the list length,
+// min(end, length+1) - max(start, 1) clamped at zero. The caller must hold the lock
+func (sl *RankList[K, V]) rangeSize(start int, end int) int {
+	return max(min(end, sl.length+1)-max(start, 1), 0)
+}
+
+// walkRange 按排名顺序对指定排名区间内的每个节点调用 fn，调用方需持有锁
+// walkRange calls fn for every node within the specified rank range in rank order.
+// The caller must hold the lock
+func (sl *RankList[K, V]) walkRange(start int, end int, fn func(node *Node[K, V])) {
+	start = max(start, 1)
+
+	// 下降到排名 start-1 的节点：下一个节点的排名达到 start 时停止
+	// Descend to the node at rank start-1: stop once the next node would reach rank start
 	rank := 0
 	curr := sl.header
-	entries := make([]Entry[K, V], 0)
 
 	for i := sl.level - 1; i >= 0; i-- {
 		for curr.forward[i] != nil {
@@ -346,28 +1257,11 @@ func (sl *RankList[K, V]) Range(start int, end int) []Entry[K, V] {
 		}
 	}
 
-	total := 0
-	for curr.forward[0] != nil && start+total < end {
-		entries = append(entries, curr.forward[0].data)
+	// rank 是 curr 的绝对排名，窗口只由绝对排名决定，与 start 是否被截断无关
+	// rank is the absolute rank of curr, so the window depends on absolute ranks only, however start was clamped
+	for curr.forward[0] != nil && rank+1 < end {
 		curr = curr.forward[0]
-		total++
+		rank++
+		fn(curr)
 	}
-	return entries
 }
-
-// Print for test
-// func (sl *RankList[K, V]) Print() {
-// 	fmt.Printf("SkipList Level: %d, Length: %d\n", sl.level, sl.length)
-// 	for i := sl.level - 1; i >= 0; i-- {
-// 		curr := sl.header
-// 		fmt.Printf("L%d -> ", i+1)
-// 		for curr != nil {
-// 			if curr != sl.header {
-// 				fmt.Printf("[%v:%v:%v] -> ", curr.data.Key, curr.data.Value, curr.span[i])
-// 			}
-// 			curr = curr.forward[i]
-// 		}
-// 		fmt.Println("NIL")
-// 	}
-// 	fmt.Println("===================================")
-// }
@@ -1,9 +1,16 @@
 package ranklist
 
 import (
+	"bytes"
+	"encoding/json"
+	randv1 "math/rand"
 	"math/rand/v2"
+	"runtime"
+	"slices"
 	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/liyiheng/zset"
 	skiplist "github.com/sean-public/fast-skiplist"
@@ -27,6 +34,49 @@ func BenchmarkRankListRandSet(b *testing.B) {
 	}
 }
 
+// BenchmarkRandomLevelParallel 比较并发写入不同跳表时，从全局锁保护的 math/rand 与从每个跳表自己的源生成层级的开销
+// BenchmarkRandomLevelParallel compares generating levels from the globally locked math/rand
+// with each list's own source while different lists are written concurrently
+func BenchmarkRandomLevelParallel(b *testing.B) {
+	b.Run("global", func(b *testing.B) {
+		r := randv1.New(randv1.NewSource(1))
+		var mu sync.Mutex
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				level := 1
+				for {
+					mu.Lock()
+					f := r.Float64()
+					mu.Unlock()
+					if f >= Probability || level >= MaxLevel {
+						break
+					}
+					level++
+				}
+			}
+		})
+	})
+	b.Run("perList", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			sl := New[int, int]()
+			for pb.Next() {
+				sl.randomLevel()
+			}
+		})
+	})
+}
+
+// BenchmarkRankListSetParallel 每个 goroutine 写入自己的跳表，生成层级不再经过共享的锁
+// BenchmarkRankListSetParallel has every goroutine write its own list, so generating levels no longer goes through a shared lock
+func BenchmarkRankListSetParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		sl := New[int, int]()
+		for i := 0; pb.Next(); i++ {
+			sl.Set(i, rand.Int())
+		}
+	})
+}
+
 func BenchmarkRankListGet(b *testing.B) {
 	sl := New[int, int]()
 	for i := 0; i < 1000000; i++ {
@@ -59,7 +109,36 @@ func BenchmarkRankListRange(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		sl.Range(0, 10)
+		sl.Range(1, 11)
+	}
+}
+
+// BenchmarkRankListTop 比较写入稀少时每次遍历与从 WithCachedTop 缓存读取 Top(10) 的开销
+// BenchmarkRankListTop compares walking on every call with serving Top(10) from the WithCachedTop cache while writes are rare
+func BenchmarkRankListTop(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		opts []Option[int, int]
+	}{
+		{"walk", nil},
+		{"cached", []Option[int, int]{WithCachedTop[int, int](10)}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			sl := New(bm.opts...)
+			for i := 0; i < 1000000; i++ {
+				sl.Set(i, rand.IntN(1000000))
+			}
+			b.ResetTimer()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					if i%1000 == 0 {
+						sl.Set(rand.IntN(1000000), rand.IntN(1000000))
+					}
+					sl.Top(10)
+				}
+			})
+		})
 	}
 }
 
@@ -108,3 +187,473 @@ func BenchmarkMapSet(b *testing.B) {
 		n[strconv.Itoa(i)] = score
 	}
 }
+
+func newBenchSnapshotList() *RankList[string, int] {
+	sl := New[string, int]()
+	for i := 0; i < 100000; i++ {
+		sl.Set(strconv.Itoa(i), rand.IntN(1000000))
+	}
+	return sl
+}
+
+func BenchmarkRankListSave(b *testing.B) {
+	sl := newBenchSnapshotList()
+	var buf bytes.Buffer
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		sl.Save(&buf)
+	}
+	b.ReportMetric(float64(buf.Len()), "bytes")
+}
+
+func BenchmarkRankListLoad(b *testing.B) {
+	var buf bytes.Buffer
+	newBenchSnapshotList().Save(&buf)
+	data := buf.Bytes()
+	sl := New[string, int]()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sl.Load(bytes.NewReader(data))
+	}
+}
+
+func BenchmarkRankListMarshalJSON(b *testing.B) {
+	sl := newBenchSnapshotList()
+	var data []byte
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		data, _ = json.Marshal(sl)
+	}
+	b.ReportMetric(float64(len(data)), "bytes")
+}
+
+func BenchmarkRankListUnmarshalJSON(b *testing.B) {
+	data, _ := json.Marshal(newBenchSnapshotList())
+	sl := New[string, int]()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		json.Unmarshal(data, sl)
+	}
+}
+
+// BenchmarkRankListMemory 测量一个 100 万条目的榜单每个条目占用的堆内存
+// BenchmarkRankListMemory measures the heap used per entry by a board of one million entries
+func BenchmarkRankListMemory(b *testing.B) {
+	const size = 1000000
+	b.ReportAllocs()
+
+	var before, after runtime.MemStats
+	var sl *RankList[int, int]
+	for i := 0; i < b.N; i++ {
+		sl = nil
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		sl = New[int, int]()
+		for j := 0; j < size; j++ {
+			sl.Set(j, rand.IntN(size))
+		}
+
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+	}
+	b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/size, "heap-bytes/entry")
+	runtime.KeepAlive(sl)
+}
+
+// BenchmarkFrozenMemory 测量冻结一个 100 万条目的榜单后每个条目占用的堆内存，可与 BenchmarkRankListMemory 对比
+// BenchmarkFrozenMemory measures the heap used per entry once a board of one million entries is frozen,
+// to be compared with BenchmarkRankListMemory
+func BenchmarkFrozenMemory(b *testing.B) {
+	const size = 1000000
+	sl := New[int, int]()
+	for j := 0; j < size; j++ {
+		sl.Set(j, rand.IntN(size))
+	}
+
+	var before, after runtime.MemStats
+	var frozen *FrozenRankList[int, int]
+	for i := 0; i < b.N; i++ {
+		frozen = nil
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		frozen = sl.Freeze()
+
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+	}
+	b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/size, "heap-bytes/entry")
+	runtime.KeepAlive(frozen)
+}
+
+// BenchmarkRankListMemoryNoDict 比较 1000 万个字符串键在维护字典和 WithNoDict 时的堆内存占用
+// BenchmarkRankListMemoryNoDict compares the heap used by 10M string keys with and without the dictionary
+func BenchmarkRankListMemoryNoDict(b *testing.B) {
+	const size = 10000000
+	entries := make([]Entry[string, int], size)
+	for i := range entries {
+		entries[i] = Entry[string, int]{Key: "player:" + strconv.Itoa(i), Value: rand.IntN(size)}
+	}
+
+	for _, bc := range []struct {
+		name string
+		opts []Option[string, int]
+	}{
+		{"dict", nil},
+		{"nodict", []Option[string, int]{WithNoDict[string, int]()}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			var before, after runtime.MemStats
+			var sl *RankList[string, int]
+			for i := 0; i < b.N; i++ {
+				sl = nil
+				runtime.GC()
+				runtime.ReadMemStats(&before)
+
+				sl = New(bc.opts...)
+				sl.Restore(entries)
+
+				runtime.GC()
+				runtime.ReadMemStats(&after)
+			}
+			b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/size, "heap-bytes/entry")
+			runtime.KeepAlive(sl)
+		})
+	}
+}
+
+// BenchmarkRankListUpdate 测量对已有键反复更新的负载，每次更新都会删除并重新插入节点
+// BenchmarkRankListUpdate measures an update-heavy workload, every update deletes and reinserts a node
+func BenchmarkRankListUpdate(b *testing.B) {
+	const size = 100000
+	sl := New[int, int]()
+	for i := 0; i < size; i++ {
+		sl.Set(i, rand.IntN(size))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sl.Set(rand.IntN(size), rand.IntN(size))
+	}
+}
+
+func BenchmarkRankListParallelSet(b *testing.B) {
+	sl := New[int, int]()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			sl.Set(rand.IntN(1000000), rand.IntN(1000000))
+		}
+	})
+}
+
+func BenchmarkShardedParallelSet(b *testing.B) {
+	sl := NewSharded[int, int](16)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			sl.Set(rand.IntN(1000000), rand.IntN(1000000))
+		}
+	})
+}
+
+func BenchmarkShardedTop(b *testing.B) {
+	sl := NewSharded[int, int](16)
+	for i := 0; i < 1000000; i++ {
+		sl.Set(i, rand.IntN(1000000))
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sl.Top(100)
+	}
+}
+
+// BenchmarkRankListGetUnderRebuild 测量后台反复重建跳表时 Get 的延迟，并报告 p99
+// BenchmarkRankListGetUnderRebuild measures Get latency while the list is rebuilt in the background and reports the p99
+func BenchmarkRankListGetUnderRebuild(b *testing.B) {
+	const size = 100000
+	sl := New[int, int]()
+	entries := make([]Entry[int, int], size)
+	for i := range entries {
+		entries[i] = Entry[int, int]{Key: i, Value: rand.IntN(size)}
+	}
+	sl.Restore(entries)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				sl.Restore(entries)
+			}
+		}
+	}()
+
+	latencies := make([]time.Duration, b.N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		sl.Get(rand.IntN(size))
+		latencies[i] = time.Since(start)
+	}
+	b.StopTimer()
+	close(done)
+	<-stopped
+
+	slices.Sort(latencies)
+	b.ReportMetric(float64(latencies[len(latencies)*99/100].Nanoseconds()), "p99-ns")
+}
+
+// BenchmarkRankListIncrSmall 测量小增量的负载，相对顺序很少改变，大多数更新可以原地完成
+// BenchmarkRankListIncrSmall measures small increments, relative order rarely changes so most updates happen in place
+func BenchmarkRankListIncrSmall(b *testing.B) {
+	const size = 100000
+	sl := New[int, int]()
+	for i := 0; i < size; i++ {
+		sl.Set(i, i*1000)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sl.IncrBy(rand.IntN(size), 1)
+	}
+}
+
+func BenchmarkRankListRangeAppend(b *testing.B) {
+	sl := New[int, int]()
+	for i := 0; i < 10000; i++ {
+		sl.Set(i, i)
+	}
+	buf := make([]Entry[int, int], 0, 100)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf = sl.RangeAppend(buf[:0], 1, 101)
+	}
+}
+
+func BenchmarkRankListRangeLarge(b *testing.B) {
+	sl := New[int, int]()
+	for i := 0; i < 100000; i++ {
+		sl.Set(i, i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sl.Range(1, 10001)
+	}
+}
+
+// BenchmarkRankListArena 对比默认分配和 arena 分配下一个批量加载的大榜单的堆内存以及 Rank/Range 延迟
+// BenchmarkRankListArena compares heap usage and Rank/Range latency of a bulk-loaded board
+// between default and arena allocation
+func BenchmarkRankListArena(b *testing.B) {
+	const size = 10000000
+	entries := make([]Entry[int, int], size)
+	for i := range entries {
+		entries[i] = Entry[int, int]{Key: i, Value: rand.IntN(size)}
+	}
+
+	modes := []struct {
+		name string
+		opts []Option[int, int]
+	}{
+		{"Default", nil},
+		{"Arena", []Option[int, int]{WithArena[int, int](65536)}},
+	}
+	for _, mode := range modes {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		sl := New(mode.opts...)
+		sl.Restore(entries)
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		heap := float64(after.HeapAlloc-before.HeapAlloc) / size
+
+		b.Run(mode.name+"/Rank", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sl.Rank(rand.IntN(size))
+			}
+			b.ReportMetric(heap, "heap-bytes/entry")
+		})
+		b.Run(mode.name+"/Range", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				start := rand.IntN(size)
+				sl.Range(start, start+100)
+			}
+		})
+		runtime.KeepAlive(sl)
+	}
+}
+
+// BenchmarkRankListRankZipf 以 Zipf 分布查询排名，少数热门键占据大部分查询，每 10000 次查询发生一次写入
+// BenchmarkRankListRankZipf queries ranks with a Zipfian key distribution where a few hot keys get most queries,
+// with one write per 10000 queries
+func BenchmarkRankListRankZipf(b *testing.B) {
+	const size = 100000
+	modes := []struct {
+		name string
+		opts []Option[int, int]
+	}{
+		{"Uncached", nil},
+		{"Cached", []Option[int, int]{WithRankCache[int, int](1024)}},
+	}
+	for _, mode := range modes {
+		b.Run(mode.name, func(b *testing.B) {
+			sl := New(mode.opts...)
+			for i := 0; i < size; i++ {
+				sl.Set(i, rand.IntN(size))
+			}
+			zipf := rand.NewZipf(rand.New(rand.NewPCG(1, 2)), 1.2, 1, size-1)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if i%10000 == 0 {
+					sl.Set(rand.IntN(size), rand.IntN(size))
+				}
+				sl.Rank(int(zipf.Uint64()))
+			}
+		})
+	}
+}
+
+// BenchmarkRankListMRank 比较一次 MRank 与 50 次 Rank 解析同一组成员的开销
+// BenchmarkRankListMRank compares one MRank with 50 Rank calls resolving the same members
+func BenchmarkRankListMRank(b *testing.B) {
+	const size = 1000000
+	sl := New[int, int]()
+	for i := 0; i < size; i++ {
+		sl.Set(i, rand.IntN(size))
+	}
+	keys := make([]int, 50)
+	for i := range keys {
+		keys[i] = rand.IntN(size)
+	}
+
+	b.Run("MRank", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sl.MRank(keys)
+		}
+	})
+	b.Run("Rank", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, key := range keys {
+				sl.Rank(key)
+			}
+		}
+	})
+}
+
+// BenchmarkRankListAddAll 比较用 AddAll 和逐个调用 IncrBy 应用 1000 个增量的开销
+// BenchmarkRankListAddAll compares applying 1000 deltas with AddAll and with a loop of IncrBy
+func BenchmarkRankListAddAll(b *testing.B) {
+	const size = 100000
+	deltas := make(map[int]int, 1000)
+	for len(deltas) < 1000 {
+		deltas[rand.IntN(size)] = rand.IntN(100)
+	}
+
+	b.Run("addall", func(b *testing.B) {
+		sl := New[int, int]()
+		for i := 0; i < size; i++ {
+			sl.Set(i, rand.IntN(size))
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			sl.AddAll(deltas)
+		}
+	})
+	b.Run("incrby", func(b *testing.B) {
+		sl := New[int, int]()
+		for i := 0; i < size; i++ {
+			sl.Set(i, rand.IntN(size))
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for key, delta := range deltas {
+				sl.IncrBy(key, delta)
+			}
+		}
+	})
+}
+
+// BenchmarkRankListEncodeMsgpack 编码一个数字榜单，并同时报告 MessagePack 和 JSON 的输出大小
+// BenchmarkRankListEncodeMsgpack encodes a numeric board and reports the output size of both MessagePack and JSON
+func BenchmarkRankListEncodeMsgpack(b *testing.B) {
+	sl := New[int64, int64]()
+	for i := 0; i < 100000; i++ {
+		sl.Set(rand.Int64N(1<<40), rand.Int64N(1000000))
+	}
+	jsonData, _ := json.Marshal(sl)
+	var buf bytes.Buffer
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		sl.EncodeMsgpack(&buf)
+	}
+	b.ReportMetric(float64(buf.Len()), "bytes")
+	b.ReportMetric(float64(len(jsonData)), "json-bytes")
+}
+
+func BenchmarkRankListDecodeMsgpack(b *testing.B) {
+	var buf bytes.Buffer
+	newBenchSnapshotList().EncodeMsgpack(&buf)
+	data := buf.Bytes()
+	sl := New[string, int]()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sl.DecodeMsgpack(bytes.NewReader(data))
+	}
+}
+
+// BenchmarkRankListRankAfterTrim 比较删除 99% 的尾部条目之后，不重建与 WithAutoCompact 自动重建的 Rank 开销
+// BenchmarkRankListRankAfterTrim compares Rank after deleting 99% of the entries from the tail,
+// without a rebuild and with the automatic rebuild of WithAutoCompact
+func BenchmarkRankListRankAfterTrim(b *testing.B) {
+	const size, kept = 1000000, 10000
+	modes := []struct {
+		name string
+		opts []Option[int, int]
+	}{
+		{"Plain", nil},
+		{"AutoCompact", []Option[int, int]{WithAutoCompact[int, int](4)}},
+	}
+	for _, mode := range modes {
+		b.Run(mode.name, func(b *testing.B) {
+			sl := New(append(mode.opts, WithSeed[int, int](1))...)
+			for i := 0; i < size; i++ {
+				sl.Set(i, i)
+			}
+			for i := size - 1; i >= kept; i-- {
+				sl.Del(i)
+			}
+			if err := sl.Check(); err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				sl.Rank(i % kept)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package ranklist
 
 import (
 	"math/rand/v2"
+	"slices"
 	"strconv"
+	"sync"
 	"testing"
 )
 
@@ -17,6 +19,48 @@ func TestNew(t *testing.T) {
 	}
 }
 
+// nodeLevels 按排名顺序返回每个节点的层级
+// nodeLevels returns the level of every node in rank order
+func nodeLevels[K Ordered, V Ordered](sl *RankList[K, V]) []int {
+	var levels []int
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		levels = append(levels, curr.level)
+	}
+	return levels
+}
+
+func TestSeedDeterministic(t *testing.T) {
+	build := func(opt Option[int, int]) []int {
+		sl := New(opt)
+		for i := 0; i < 1000; i++ {
+			sl.Set(i, i%37)
+		}
+		for i := 0; i < 1000; i += 3 {
+			sl.Del(i)
+		}
+		sl.LoadMap(map[int]int{1: 1, 2: 2, 3: 3}, false)
+		return nodeLevels(sl)
+	}
+
+	first := build(WithSeed[int, int](42))
+	if second := build(WithSeed[int, int](42)); !slices.Equal(first, second) {
+		t.Fatalf("the same seed should produce the same structure")
+	}
+	if other := build(WithSeed[int, int](43)); slices.Equal(first, other) {
+		t.Fatalf("different seeds should produce different structures")
+	}
+	if pcg := build(WithRandSource[int, int](rand.NewPCG(42, 42))); !slices.Equal(first, pcg) {
+		t.Fatalf("WithSeed should match a PCG source with the same seed")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic for a nil source")
+		}
+	}()
+	WithRandSource[int, int](nil)
+}
+
 func TestSet(t *testing.T) {
 	sl := New[string, int]()
 	for k := 0; k < 10000; k++ {
@@ -175,9 +219,26 @@ func TestMassiveRank(t *testing.T) {
 	}
 }
 
+func TestGetEntry(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+
+	if entry, ok := sl.GetEntry("b"); !ok || entry != (Entry[string, int]{Key: "b", Value: 20}) {
+		t.Errorf("expected {b 20}, got %v, %v", entry, ok)
+	}
+	sl.Set("b", 5)
+	if entry, ok := sl.GetEntry("b"); !ok || entry.Value != 5 {
+		t.Errorf("expected the updated value 5, got %v, %v", entry, ok)
+	}
+	if entry, ok := sl.GetEntry("missing"); ok || entry != (Entry[string, int]{}) {
+		t.Errorf("expected a zero entry and false, got %v, %v", entry, ok)
+	}
+}
+
 func TestRankKeyNotExist(t *testing.T) {
 	sl := New[string, int]()
-	sl.dict["x"] = 0
+	sl.dict["x"] = NewNode("x", 0, 1)
 
 	rank, exists := sl.Rank("x")
 	if exists {
@@ -267,8 +328,8 @@ func TestRankList_Range_FullRange(t *testing.T) {
 	rankList.Set(4, 180)
 	rankList.Set(5, 200)
 
-	start := 0
-	end := 5
+	start := 1
+	end := 6
 	expected := []Entry[int, int]{
 		{Key: 1, Value: 100},
 		{Key: 2, Value: 120},
@@ -357,3 +418,512 @@ func TestRankList_Range_OutOfBoundsEnd(t *testing.T) {
 		}
 	}
 }
+
+func TestRangeSemantics(t *testing.T) {
+	sl := New[string, int]()
+	for i, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		sl.Set(key, (i+1)*10)
+	}
+
+	// want[end] 是 Range(start, end) 返回的键按顺序拼接的结果，end 取 0 到 8
+	// want[end] is the concatenation of the keys Range(start, end) returns, for end from 0 to 8
+	tests := []struct {
+		start int
+		want  [9]string
+	}{
+		{-1, [9]string{"", "", "a", "ab", "abc", "abcd", "abcde", "abcdef", "abcdef"}},
+		{0, [9]string{"", "", "a", "ab", "abc", "abcd", "abcde", "abcdef", "abcdef"}},
+		{1, [9]string{"", "", "a", "ab", "abc", "abcd", "abcde", "abcdef", "abcdef"}},
+		{2, [9]string{"", "", "", "b", "bc", "bcd", "bcde", "bcdef", "bcdef"}},
+		{3, [9]string{"", "", "", "", "c", "cd", "cde", "cdef", "cdef"}},
+		{4, [9]string{"", "", "", "", "", "d", "de", "def", "def"}},
+		{5, [9]string{"", "", "", "", "", "", "e", "ef", "ef"}},
+		{6, [9]string{"", "", "", "", "", "", "", "f", "f"}},
+		{7, [9]string{"", "", "", "", "", "", "", "", ""}},
+		{8, [9]string{"", "", "", "", "", "", "", "", ""}},
+	}
+	for _, tt := range tests {
+		for end, want := range tt.want {
+			got := ""
+			for _, entry := range sl.Range(tt.start, end) {
+				got += entry.Key
+			}
+			if got != want {
+				t.Errorf("Range(%d, %d): expected %q, got %q", tt.start, end, want, got)
+			}
+		}
+	}
+}
+
+func TestClear(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 100; i++ {
+		sl.Set(i, i)
+	}
+
+	sl.Clear()
+	if sl.Length() != 0 {
+		t.Errorf("Length should be 0 after clear, got %d", sl.Length())
+	}
+	if _, exists := sl.Get(1); exists {
+		t.Errorf("Key should not exist after clear")
+	}
+
+	sl.Set(1, 1)
+	if rank, _ := sl.Rank(1); rank != 1 {
+		t.Errorf("expected rank 1 after clear and set, got %d", rank)
+	}
+}
+
+func TestResetAndSnapshot(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 3)
+	sl.Set("b", 1)
+	sl.Set("c", 2)
+
+	entries := sl.ResetAndSnapshot()
+	expected := []Entry[string, int]{
+		{Key: "b", Value: 1},
+		{Key: "c", Value: 2},
+		{Key: "a", Value: 3},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("at index %d: expected %v, got %v", i, expected[i], entries[i])
+		}
+	}
+	if sl.Length() != 0 {
+		t.Errorf("Length should be 0 after reset, got %d", sl.Length())
+	}
+}
+
+func TestResetAndSnapshotWithCarry(t *testing.T) {
+	sl := New[int, int]()
+	for i := 1; i <= 20; i++ {
+		sl.Set(i, i)
+	}
+
+	entries := sl.ResetAndSnapshotWith(func(rank int, entry Entry[int, int]) (int, bool) {
+		return 100, rank <= 10
+	})
+	if len(entries) != 20 {
+		t.Fatalf("expected 20 entries in snapshot, got %d", len(entries))
+	}
+	if sl.Length() != 10 {
+		t.Fatalf("expected 10 carried entries, got %d", sl.Length())
+	}
+	for i := 1; i <= 20; i++ {
+		value, exists := sl.Get(i)
+		if i <= 10 && (!exists || value != 100) {
+			t.Errorf("Key %d should be carried over with value 100, got %d, %v", i, value, exists)
+		}
+		if i > 10 && exists {
+			t.Errorf("Key %d should not be carried over", i)
+		}
+	}
+}
+
+func TestResetAndSnapshotConcurrent(t *testing.T) {
+	sl := New[int, int]()
+
+	var wg sync.WaitGroup
+	const writers = 4
+	const perWriter = 2000
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				sl.Set(w*perWriter+i, i)
+			}
+		}(w)
+	}
+
+	seen := make(map[int]int)
+	for i := 0; i < 10; i++ {
+		for _, entry := range sl.ResetAndSnapshot() {
+			seen[entry.Key]++
+		}
+	}
+	wg.Wait()
+	for _, entry := range sl.ResetAndSnapshot() {
+		seen[entry.Key]++
+	}
+
+	for k := 0; k < writers*perWriter; k++ {
+		if seen[k] != 1 {
+			t.Fatalf("Key %d should be seen exactly once, got %d", k, seen[k])
+		}
+	}
+}
+
+func TestSetReportsInsert(t *testing.T) {
+	sl := New[string, int]()
+	if !sl.Set("a", 1) {
+		t.Errorf("a fresh key should report an insert")
+	}
+	if sl.Set("a", 2) || sl.Set("a", 2) {
+		t.Errorf("repeated Sets of the same key should report updates")
+	}
+	if sl.Set("a", -5) {
+		t.Errorf("an update that moves the key should report an update")
+	}
+	sl.Del("a")
+	if !sl.Set("a", 3) {
+		t.Errorf("a key set again after Del should report an insert")
+	}
+	if !sl.Set("b", 3) || sl.Set("b", 4) {
+		t.Errorf("a second key should report an insert and then an update")
+	}
+}
+
+func TestSetBatch(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 10)
+
+	inserted, updated := sl.SetBatch([]Entry[string, int]{
+		{Key: "b", Value: 2},
+		{Key: "a", Value: 1},
+		{Key: "c", Value: 3},
+		{Key: "b", Value: 4},
+	})
+	if inserted != 2 || updated != 2 {
+		t.Errorf("expected 2 inserted keys and 2 updates, got %d and %d", inserted, updated)
+	}
+
+	expected := []Entry[string, int]{
+		{Key: "a", Value: 1},
+		{Key: "c", Value: 3},
+		{Key: "b", Value: 4},
+	}
+	result := sl.Range(1, 4)
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(result))
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("at index %d: expected %v, got %v", i, expected[i], result[i])
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	sl := New[string, int]()
+	if entries := sl.Snapshot(); len(entries) != 0 {
+		t.Errorf("expected an empty snapshot, got %v", entries)
+	}
+
+	sl.Set("b", 2)
+	sl.Set("a", 1)
+	entries := sl.Snapshot()
+	if len(entries) != 2 || entries[0].Key != "a" || entries[1].Key != "b" {
+		t.Errorf("unexpected snapshot %v", entries)
+	}
+}
+
+func TestEntriesConcurrent(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 200; i++ {
+		sl.Set(i, rand.IntN(100))
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				key := rand.IntN(300)
+				if rand.IntN(3) == 0 {
+					sl.Del(key)
+				} else {
+					sl.Set(key, rand.IntN(100))
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 500; i++ {
+		entries := sl.Entries()
+		if cap(entries) != len(entries) {
+			t.Fatalf("expected exact preallocation, got len %d and cap %d", len(entries), cap(entries))
+		}
+		seen := make(map[int]bool, len(entries))
+		for j, entry := range entries {
+			if seen[entry.Key] {
+				t.Fatalf("key %d appears twice", entry.Key)
+			}
+			seen[entry.Key] = true
+			if j > 0 && compareEntries(entries[j-1], entry) >= 0 {
+				t.Fatalf("entries out of order at %d: %v then %v", j, entries[j-1], entry)
+			}
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestRestore(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("x", 1)
+	sl.Set("a", 9)
+
+	sl.Restore([]Entry[string, int]{
+		{Key: "c", Value: 3},
+		{Key: "a", Value: 1},
+		{Key: "c", Value: 0},
+		{Key: "b", Value: 2},
+	})
+
+	expected := []Entry[string, int]{
+		{Key: "c", Value: 0},
+		{Key: "a", Value: 1},
+		{Key: "b", Value: 2},
+	}
+	result := sl.Snapshot()
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(result))
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("at index %d: expected %v, got %v", i, expected[i], result[i])
+		}
+	}
+	if _, exists := sl.Get("x"); exists {
+		t.Errorf("Key x should be replaced by Restore")
+	}
+
+	sl.Restore(nil)
+	if sl.Length() != 0 {
+		t.Errorf("expected an empty list after restoring an empty slice, got %d", sl.Length())
+	}
+}
+
+func TestRestoreConsistency(t *testing.T) {
+	sl := New[int, int]()
+	oldBoard := make([]Entry[int, int], 1000)
+	newBoard := make([]Entry[int, int], 1500)
+	for i := range oldBoard {
+		oldBoard[i] = Entry[int, int]{Key: i, Value: 1}
+	}
+	for i := range newBoard {
+		newBoard[i] = Entry[int, int]{Key: i, Value: 2}
+	}
+	sl.Restore(oldBoard)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if i%2 == 0 {
+				sl.Restore(newBoard)
+			} else {
+				sl.Restore(oldBoard)
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		entries := sl.Snapshot()
+		if len(entries) != len(oldBoard) && len(entries) != len(newBoard) {
+			t.Fatalf("observed a half-restored list with %d entries", len(entries))
+		}
+		for _, entry := range entries {
+			if entry.Value != entries[0].Value {
+				t.Fatalf("observed a mixture of old and new entries")
+			}
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestLoadSortedDuplicateKeys(t *testing.T) {
+	sl := New[string, int]()
+
+	sl.Lock()
+	sl.loadSorted([]Entry[string, int]{
+		{Key: "k", Value: 1},
+		{Key: "j", Value: 2},
+		{Key: "k", Value: 3},
+	})
+	sl.Unlock()
+
+	if sl.Length() != 2 {
+		t.Fatalf("expected 2 entries, got %d", sl.Length())
+	}
+	if value, _ := sl.Get("k"); value != 3 {
+		t.Errorf("expected the last value 3 for k, got %d", value)
+	}
+	if rank, _ := sl.Rank("k"); rank != 2 {
+		t.Errorf("expected rank 2 for k, got %d", rank)
+	}
+}
+
+func TestGetDoesNotWaitForWriteLock(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+
+	sl.Lock()
+	defer sl.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if value, ok := sl.Get("a"); !ok || value != 1 {
+			t.Errorf("expected a=1, got %d, %v", value, ok)
+		}
+	}()
+	<-done
+}
+
+func TestGetNeverMissesDuringUpdates(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 100; i++ {
+		sl.Set(i, i)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			sl.Set(i%100, i)
+			sl.IncrBy((i+1)%100, 1)
+			if i%500 == 0 {
+				sl.ResetAndSnapshotWith(func(rank int, entry Entry[int, int]) (int, bool) {
+					return entry.Value, true
+				})
+			}
+		}
+	}()
+
+	for i := 0; i < 20000; i++ {
+		if _, ok := sl.Get(i % 100); !ok {
+			t.Fatalf("key %d disappeared during an update", i%100)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestRangeAbsoluteRanks(t *testing.T) {
+	sl := New[string, int]()
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		sl.Set(key, i)
+	}
+
+	if !slices.Equal(sl.Range(0, 3), sl.Range(1, 3)) {
+		t.Errorf("Range(0, 3) should equal Range(1, 3), got %v and %v", sl.Range(0, 3), sl.Range(1, 3))
+	}
+	if got := sl.Range(-10, 2); !slices.Equal(got, []Entry[string, int]{{Key: "a", Value: 0}}) {
+		t.Errorf("Range(-10, 2): expected only rank 1, got %v", got)
+	}
+	if got := sl.Range(6, 10); len(got) != 0 {
+		t.Errorf("a start beyond the length should return nothing, got %v", got)
+	}
+	if got := sl.Range(5, 10); !slices.Equal(got, []Entry[string, int]{{Key: "e", Value: 4}}) {
+		t.Errorf("a start at the last rank should return the last entry, got %v", got)
+	}
+	if got := sl.Range(5, 6); !slices.Equal(got, []Entry[string, int]{{Key: "e", Value: 4}}) {
+		t.Errorf("Range(5, 6): expected the last entry, got %v", got)
+	}
+}
+
+func TestRangeAppend(t *testing.T) {
+	sl := New[string, int]()
+	for i := 1; i <= 5; i++ {
+		sl.Set("k"+strconv.Itoa(i), i)
+	}
+
+	dst := []Entry[string, int]{{Key: "existing", Value: 100}}
+	dst = sl.RangeAppend(dst, 2, 4)
+	expected := []Entry[string, int]{{Key: "existing", Value: 100}, {Key: "k2", Value: 2}, {Key: "k3", Value: 3}}
+	if !slices.Equal(dst, expected) {
+		t.Errorf("expected %v, got %v", expected, dst)
+	}
+
+	buf := make([]Entry[string, int], 0, 8)
+	for i := 1; i <= 5; i++ {
+		buf = sl.RangeAppend(buf[:0], 1, i+1)
+		if !slices.Equal(buf, sl.Range(1, i+1)) {
+			t.Errorf("RangeAppend(1, %d) disagrees with Range", i+1)
+		}
+	}
+	if got := sl.RangeAppend(nil, 6, 10); got != nil {
+		t.Errorf("an empty window should leave a nil dst untouched, got %v", got)
+	}
+}
+
+func TestRangePreallocates(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 100; i++ {
+		sl.Set(i, i)
+	}
+
+	tests := []struct{ start, end, size int }{
+		{1, 11, 10},
+		{95, 200, 6},
+		{101, 200, 0},
+		{5, 5, 0},
+		{10, 2, 0},
+	}
+	for _, tt := range tests {
+		entries := sl.Range(tt.start, tt.end)
+		if len(entries) != tt.size || cap(entries) != tt.size {
+			t.Errorf("Range(%d, %d): expected len and cap %d, got %d and %d", tt.start, tt.end, tt.size, len(entries), cap(entries))
+		}
+		if entries == nil {
+			t.Errorf("Range(%d, %d) should never return nil", tt.start, tt.end)
+		}
+	}
+}
+
+func TestSetBatchCounts(t *testing.T) {
+	sl := New[string, int]()
+	if inserted, updated := sl.SetBatch([]Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}); inserted != 3 || updated != 0 {
+		t.Errorf("all-new batch: expected 3 inserts and 0 updates, got %d and %d", inserted, updated)
+	}
+	if inserted, updated := sl.SetBatch([]Entry[string, int]{{"a", 4}, {"b", 5}}); inserted != 0 || updated != 2 {
+		t.Errorf("all-update batch: expected 0 inserts and 2 updates, got %d and %d", inserted, updated)
+	}
+
+	// 批内重复 3 次的新键计为一次插入和两次更新
+	// A new key repeated three times counts as one insert plus two updates
+	var reported []bool
+	inserted, updated := sl.SetBatchFunc([]Entry[string, int]{{"d", 1}, {"a", 7}, {"d", 2}, {"e", 3}, {"d", 3}},
+		func(entry Entry[string, int], inserted bool) {
+			reported = append(reported, inserted)
+		})
+	if inserted != 2 || updated != 3 {
+		t.Errorf("mixed batch: expected 2 inserts and 3 updates, got %d and %d", inserted, updated)
+	}
+	if expected := []bool{true, false, false, true, false}; !slices.Equal(reported, expected) {
+		t.Errorf("expected per-entry classification %v, got %v", expected, reported)
+	}
+	if value, _ := sl.Get("d"); value != 3 || sl.Length() != 5 {
+		t.Errorf("expected d to keep its last value 3 among 5 keys, got %d among %d", value, sl.Length())
+	}
+}
@@ -0,0 +1,486 @@
+// Package ranklistbench 提供按工作负载形态驱动 ranklist 排行榜的压测运行器，用于在部署前比较不同配置
+// Package ranklistbench provides a stress runner that drives a ranklist leaderboard with a given workload shape,
+// for comparing configurations before deploying
+package ranklistbench
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/werbenhu/ranklist"
+)
+
+// Target 被压测的排行榜，*ranklist.RankList[int, int] 和 *ranklist.Sharded[int, int] 都满足该接口
+// Target is the board under test, satisfied by both *ranklist.RankList[int, int] and *ranklist.Sharded[int, int]
+type Target interface {
+	Set(key int, value int) bool
+	Get(key int) (int, bool)
+	Del(key int) bool
+	IncrBy(key int, delta int) int
+	Rank(key int) (int, bool)
+	Range(start int, end int) []ranklist.Entry[int, int]
+	Top(n int) []ranklist.Entry[int, int]
+}
+
+// Op 表示一种操作类型
+// Op is a kind of operation
+type Op int
+
+const (
+	OpSet Op = iota
+	OpIncrBy
+	OpDel
+	OpGet
+	OpRank
+	OpRange
+	OpTop
+
+	// 操作类型的数量
+	// Number of operation kinds
+	numOps
+)
+
+// String 返回操作类型的名称
+// String returns the name of the operation kind
+func (op Op) String() string {
+	switch op {
+	case OpSet:
+		return "set"
+	case OpIncrBy:
+		return "incrby"
+	case OpDel:
+		return "del"
+	case OpGet:
+		return "get"
+	case OpRank:
+		return "rank"
+	case OpRange:
+		return "range"
+	case OpTop:
+		return "top"
+	default:
+		return fmt.Sprintf("op(%d)", int(op))
+	}
+}
+
+// Distribution 表示键或值的抽样分布
+// Distribution is the distribution keys or values are drawn from
+type Distribution int
+
+const (
+	// Uniform 在区间内均匀抽样
+	// Uniform draws evenly over the interval
+	Uniform Distribution = iota
+
+	// Zipf 按 Zipf 分布抽样，少数小数值出现得最频繁，适合模拟热点键
+	// Zipf draws from a Zipf distribution where a few small numbers dominate, modelling hot keys
+	Zipf
+
+	// Normal 按以区间中点为均值的正态分布抽样，结果截断到区间内
+	// Normal draws from a normal distribution centred on the middle of the interval, clamped to the interval
+	Normal
+)
+
+// Workload 描述一次压测的工作负载形态
+// Workload describes the shape of the load of one run
+type Workload struct {
+	// 工作负载名称，出现在报告中
+	// Name of the workload, shown in the report
+	Name string
+
+	// 各操作类型的相对权重，按 Op 索引，权重为 0 的操作不会执行
+	// Relative weight of each operation kind indexed by Op, kinds with a zero weight are never run
+	Mix [numOps]int
+
+	// 键的基数，键从 [0, Keys) 中抽样
+	// Key cardinality, keys are drawn from [0, Keys)
+	Keys int
+
+	// 键的分布
+	// Distribution of keys
+	KeyDist Distribution
+
+	// Set 写入的值从 [0, MaxValue) 中抽样，IncrBy 的增量从 [1, 100] 中均匀抽样
+	// Values written by Set are drawn from [0, MaxValue), IncrBy deltas evenly from [1, 100]
+	MaxValue int
+
+	// 值的分布
+	// Distribution of values
+	ValueDist Distribution
+
+	// Range 和 Top 每次读取的条目数
+	// Number of entries read by each Range and Top
+	PageSize int
+
+	// 并发执行操作的 goroutine 数量
+	// Number of goroutines running operations concurrently
+	Goroutines int
+
+	// 压测持续时间
+	// Duration of the run
+	Duration time.Duration
+
+	// 为 true 时在计时开始前写入全部 Keys 个键
+	// When true all Keys keys are written before timing starts
+	Preload bool
+}
+
+// ReadHeavy 返回读多写少的工作负载：约 90% 的操作是 Get、Rank、Range 和 Top，键均匀分布
+// ReadHeavy returns a read-mostly workload: about 90% of the operations are Get, Rank, Range and Top over uniform keys
+func ReadHeavy() Workload {
+	return Workload{
+		Name:       "read-heavy",
+		Mix:        [numOps]int{OpSet: 5, OpIncrBy: 5, OpGet: 40, OpRank: 30, OpRange: 10, OpTop: 10},
+		Keys:       100000,
+		KeyDist:    Uniform,
+		MaxValue:   1000000,
+		ValueDist:  Uniform,
+		PageSize:   10,
+		Goroutines: 8,
+		Duration:   5 * time.Second,
+		Preload:    true,
+	}
+}
+
+// WriteHeavy 返回写多读少的工作负载：约 80% 的操作是 Set、IncrBy 和 Del，键集中在 Zipf 分布的热点上
+// WriteHeavy returns a write-mostly workload: about 80% of the operations are Set, IncrBy and Del,
+// concentrated on Zipf distributed hot keys
+func WriteHeavy() Workload {
+	return Workload{
+		Name:       "write-heavy",
+		Mix:        [numOps]int{OpSet: 30, OpIncrBy: 45, OpDel: 5, OpGet: 10, OpRank: 5, OpTop: 5},
+		Keys:       100000,
+		KeyDist:    Zipf,
+		MaxValue:   1000000,
+		ValueDist:  Normal,
+		PageSize:   10,
+		Goroutines: 8,
+		Duration:   5 * time.Second,
+		Preload:    true,
+	}
+}
+
+// Runner 按工作负载驱动 Target 并统计吞吐量和延迟
+// Runner drives a Target with a workload and measures throughput and latency
+type Runner struct {
+	// 工作负载
+	// The workload
+	Workload Workload
+
+	// 随机数种子，相同的种子产生相同的操作序列
+	// Random seed, the same seed yields the same operation sequences
+	Seed uint64
+}
+
+// NewRunner 创建运行 w 的 Runner，w 的配置不合法时 panic
+// NewRunner creates a Runner running w, panics if w is invalid
+func NewRunner(w Workload) *Runner {
+	if w.Keys < 1 {
+		panic("ranklistbench: key cardinality must be positive")
+	}
+	if w.Goroutines < 1 {
+		panic("ranklistbench: goroutine count must be positive")
+	}
+	if w.Duration <= 0 {
+		panic("ranklistbench: duration must be positive")
+	}
+	if w.MaxValue < 1 {
+		panic("ranklistbench: max value must be positive")
+	}
+	total := 0
+	for _, weight := range w.Mix {
+		if weight < 0 {
+			panic("ranklistbench: operation weight must not be negative")
+		}
+		total += weight
+	}
+	if total == 0 {
+		panic("ranklistbench: operation mix must not be empty")
+	}
+	return &Runner{Workload: w, Seed: 1}
+}
+
+// OpStats 一种操作的统计结果
+// OpStats is the result for one operation kind
+type OpStats struct {
+	// 完成的操作次数
+	// Number of operations completed
+	Count int
+
+	// 每秒完成的操作次数
+	// Operations completed per second
+	OpsPerSec float64
+
+	// 延迟百分位数，精度约为 6%
+	// Latency percentiles, accurate to about 6%
+	P50, P90, P99, Max time.Duration
+}
+
+// Report 一次压测的结果
+// Report is the result of one run
+type Report struct {
+	// 工作负载名称
+	// Name of the workload
+	Workload string
+
+	// 实际计时的时长
+	// Time actually measured
+	Elapsed time.Duration
+
+	// 按操作类型统计的结果，按 Op 索引
+	// Results per operation kind indexed by Op
+	Ops [numOps]OpStats
+
+	// 全部操作的汇总结果
+	// Results over every operation
+	Total OpStats
+}
+
+// String 以表格形式返回报告，每种执行过的操作一行
+// String returns the report as a table with one row per operation kind that ran
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %v\n", r.Workload, r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(&b, "%-8s %10s %12s %10s %10s %10s %10s\n", "op", "count", "ops/sec", "p50", "p90", "p99", "max")
+	row := func(name string, s OpStats) {
+		fmt.Fprintf(&b, "%-8s %10d %12.0f %10v %10v %10v %10v\n", name, s.Count, s.OpsPerSec, s.P50, s.P90, s.P99, s.Max)
+	}
+	for op := Op(0); op < numOps; op++ {
+		if r.Ops[op].Count > 0 {
+			row(op.String(), r.Ops[op])
+		}
+	}
+	row("total", r.Total)
+	return b.String()
+}
+
+// Run 用工作负载驱动 target，直到 Duration 用完或 ctx 被取消，返回统计结果
+// Run drives target with the workload until Duration elapses or ctx is cancelled and returns the results
+func (r *Runner) Run(ctx context.Context, target Target) *Report {
+	w := r.Workload
+	if w.Preload {
+		gen := newGenerator(w, r.Seed, 0)
+		for key := range w.Keys {
+			target.Set(key, gen.value())
+		}
+	}
+
+	hists := make([][numOps]histogram, w.Goroutines)
+	var wg sync.WaitGroup
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, w.Duration)
+	defer cancel()
+	for i := range w.Goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gen := newGenerator(w, r.Seed, uint64(i)+1)
+			hist := &hists[i]
+			for n := 0; ; n++ {
+				// 每 64 次操作检查一次 ctx，避免检查本身成为瓶颈
+				// Check ctx every 64 operations so the check itself does not become the bottleneck
+				if n%64 == 0 && ctx.Err() != nil {
+					return
+				}
+				op := gen.op()
+				began := time.Now()
+				run(target, op, gen)
+				hist[op].record(time.Since(began))
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	report := &Report{Workload: w.Name, Elapsed: elapsed}
+	var total histogram
+	for op := Op(0); op < numOps; op++ {
+		var merged histogram
+		for i := range hists {
+			merged.merge(&hists[i][op])
+		}
+		report.Ops[op] = merged.stats(elapsed)
+		total.merge(&merged)
+	}
+	report.Total = total.stats(elapsed)
+	return report
+}
+
+// run 对 target 执行一次 op
+// run performs one op against target
+func run(target Target, op Op, gen *generator) {
+	switch op {
+	case OpSet:
+		target.Set(gen.key(), gen.value())
+	case OpIncrBy:
+		target.IncrBy(gen.key(), 1+gen.rng.IntN(100))
+	case OpDel:
+		target.Del(gen.key())
+	case OpGet:
+		target.Get(gen.key())
+	case OpRank:
+		target.Rank(gen.key())
+	case OpRange:
+		start := 1 + gen.rng.IntN(gen.w.Keys)
+		target.Range(start, start+gen.w.PageSize)
+	case OpTop:
+		target.Top(gen.w.PageSize)
+	}
+}
+
+// generator 每个 goroutine 私有的操作、键和值生成器
+// generator produces the operations, keys and values of one goroutine
+type generator struct {
+	w      Workload
+	rng    *rand.Rand
+	weight int
+	keyZ   *rand.Zipf
+	valueZ *rand.Zipf
+}
+
+// newGenerator 创建由 seed 和 stream 决定的生成器
+// newGenerator creates a generator determined by seed and stream
+func newGenerator(w Workload, seed uint64, stream uint64) *generator {
+	g := &generator{w: w, rng: rand.New(rand.NewPCG(seed, stream))}
+	for _, weight := range w.Mix {
+		g.weight += weight
+	}
+	if w.KeyDist == Zipf {
+		g.keyZ = rand.NewZipf(g.rng, 1.1, 1, uint64(w.Keys-1))
+	}
+	if w.ValueDist == Zipf {
+		g.valueZ = rand.NewZipf(g.rng, 1.1, 1, uint64(w.MaxValue-1))
+	}
+	return g
+}
+
+// op 按权重抽取一种操作
+// op draws an operation kind by weight
+func (g *generator) op() Op {
+	n := g.rng.IntN(g.weight)
+	for op, weight := range g.w.Mix {
+		if n < weight {
+			return Op(op)
+		}
+		n -= weight
+	}
+	return numOps - 1
+}
+
+// key 按 KeyDist 抽取一个键
+// key draws a key following KeyDist
+func (g *generator) key() int {
+	return g.draw(g.w.KeyDist, g.w.Keys, g.keyZ)
+}
+
+// value 按 ValueDist 抽取一个值
+// value draws a value following ValueDist
+func (g *generator) value() int {
+	return g.draw(g.w.ValueDist, g.w.MaxValue, g.valueZ)
+}
+
+// draw 按分布 dist 从 [0, n) 中抽取一个数
+// draw draws a number from [0, n) following dist
+func (g *generator) draw(dist Distribution, n int, z *rand.Zipf) int {
+	switch dist {
+	case Zipf:
+		return int(z.Uint64())
+	case Normal:
+		x := float64(n)/2 + g.rng.NormFloat64()*float64(n)/6
+		return min(max(int(x), 0), n-1)
+	default:
+		return g.rng.IntN(n)
+	}
+}
+
+// 每个 2 的幂区间再细分的子桶数量的对数，16 个子桶对应约 6% 的相对误差
+// Log2 of the number of sub-buckets per power of two, 16 sub-buckets bound the relative error to about 6%
+const subBucketBits = 4
+
+// histogram 以纳秒为单位的对数分桶延迟直方图，记录和合并都是 O(1) 内存
+// histogram is a log-bucketed latency histogram in nanoseconds, recording and merging take constant memory
+type histogram struct {
+	counts [64 << subBucketBits]uint64
+	count  int
+	max    time.Duration
+}
+
+// bucket 返回 ns 所在的桶
+// bucket returns the bucket holding ns
+func bucket(ns uint64) int {
+	if ns < 1<<subBucketBits {
+		return int(ns)
+	}
+	exp := bits.Len64(ns) - 1
+	sub := (ns >> (exp - subBucketBits)) & (1<<subBucketBits - 1)
+	return (exp-subBucketBits+1)<<subBucketBits | int(sub)
+}
+
+// bucketUpper 返回桶 b 中的最大值
+// bucketUpper returns the largest value held by bucket b
+func bucketUpper(b int) uint64 {
+	if b < 1<<subBucketBits {
+		return uint64(b)
+	}
+	exp := b>>subBucketBits + subBucketBits - 1
+	sub := uint64(b & (1<<subBucketBits - 1))
+	lower := uint64(1)<<exp | sub<<(exp-subBucketBits)
+	return lower + uint64(1)<<(exp-subBucketBits) - 1
+}
+
+// record 记录一次延迟
+// record records one latency
+func (h *histogram) record(d time.Duration) {
+	h.counts[bucket(uint64(max(d, 0)))]++
+	h.count++
+	h.max = max(h.max, d)
+}
+
+// merge 将 other 合并到 h 中
+// merge merges other into h
+func (h *histogram) merge(other *histogram) {
+	for i, c := range other.counts {
+		h.counts[i] += c
+	}
+	h.count += other.count
+	h.max = max(h.max, other.max)
+}
+
+// quantile 返回第 q 分位的延迟，不超过记录到的最大值
+// quantile returns the latency at quantile q, never above the largest recorded latency
+func (h *histogram) quantile(q float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	target := uint64(math.Ceil(q * float64(h.count)))
+	var seen uint64
+	for b, c := range h.counts {
+		seen += c
+		if c > 0 && seen >= target {
+			return min(time.Duration(bucketUpper(b)), h.max)
+		}
+	}
+	return h.max
+}
+
+// stats 返回 elapsed 时长内的统计结果
+// stats returns the results over elapsed
+func (h *histogram) stats(elapsed time.Duration) OpStats {
+	s := OpStats{
+		Count: h.count,
+		P50:   h.quantile(0.50),
+		P90:   h.quantile(0.90),
+		P99:   h.quantile(0.99),
+		Max:   h.max,
+	}
+	if elapsed > 0 {
+		s.OpsPerSec = float64(h.count) / elapsed.Seconds()
+	}
+	return s
+}
@@ -0,0 +1,120 @@
+package ranklistbench
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/werbenhu/ranklist"
+)
+
+// checkStats 检查一组统计结果是否合理
+// checkStats checks that one set of results is sane
+func checkStats(t *testing.T, name string, s OpStats) {
+	t.Helper()
+	if s.Count <= 0 || s.OpsPerSec <= 0 {
+		t.Fatalf("%s: count %d ops/sec %f, want positive", name, s.Count, s.OpsPerSec)
+	}
+	if s.P50 <= 0 || s.P50 > s.P90 || s.P90 > s.P99 || s.P99 > s.Max {
+		t.Fatalf("%s: percentiles p50 %v p90 %v p99 %v max %v not positive and ordered", name, s.P50, s.P90, s.P99, s.Max)
+	}
+}
+
+func TestRunnerPresets(t *testing.T) {
+	targets := map[string]func() Target{
+		"ranklist": func() Target { return ranklist.New[int, int]() },
+		"sharded":  func() Target { return ranklist.NewSharded[int, int](4) },
+	}
+	for _, w := range []Workload{ReadHeavy(), WriteHeavy()} {
+		w.Keys = 1000
+		w.Goroutines = 4
+		w.Duration = 100 * time.Millisecond
+		for name, newTarget := range targets {
+			report := NewRunner(w).Run(context.Background(), newTarget())
+			if report.Workload != w.Name || report.Elapsed < w.Duration {
+				t.Fatalf("%s/%s: workload %q elapsed %v", w.Name, name, report.Workload, report.Elapsed)
+			}
+			checkStats(t, w.Name+"/"+name+"/total", report.Total)
+
+			sum := 0
+			for op := Op(0); op < numOps; op++ {
+				s := report.Ops[op]
+				sum += s.Count
+				if w.Mix[op] == 0 {
+					if s.Count != 0 {
+						t.Fatalf("%s/%s: %v ran %d times with zero weight", w.Name, name, op, s.Count)
+					}
+					continue
+				}
+				checkStats(t, w.Name+"/"+name+"/"+op.String(), s)
+			}
+			if sum != report.Total.Count {
+				t.Fatalf("%s/%s: per-op counts sum to %d, total %d", w.Name, name, sum, report.Total.Count)
+			}
+			if !strings.Contains(report.String(), "total") {
+				t.Fatalf("%s/%s: report %q lacks the total row", w.Name, name, report.String())
+			}
+		}
+	}
+}
+
+func TestRunnerCancel(t *testing.T) {
+	w := ReadHeavy()
+	w.Keys = 100
+	w.Duration = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	report := NewRunner(w).Run(ctx, ranklist.New[int, int]())
+	if report.Elapsed > time.Minute || report.Total.Count == 0 {
+		t.Fatalf("elapsed %v count %d after cancel", report.Elapsed, report.Total.Count)
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	var h histogram
+	for i := 1; i <= 1000; i++ {
+		h.record(time.Duration(i) * time.Microsecond)
+	}
+	for _, tc := range []struct {
+		q    float64
+		want time.Duration
+	}{{0.5, 500 * time.Microsecond}, {0.9, 900 * time.Microsecond}, {0.99, 990 * time.Microsecond}} {
+		got := h.quantile(tc.q)
+		if got < tc.want || float64(got) > float64(tc.want)*1.07 {
+			t.Fatalf("quantile(%v) = %v, want within 7%% above %v", tc.q, got, tc.want)
+		}
+	}
+	if h.quantile(1) != time.Millisecond || h.max != time.Millisecond {
+		t.Fatalf("quantile(1) = %v max %v, want 1ms", h.quantile(1), h.max)
+	}
+
+	for ns := uint64(0); ns < 1<<20; ns += 7 {
+		if b := bucket(ns); bucketUpper(b) < ns || (b > 0 && bucketUpper(b-1) >= ns) {
+			t.Fatalf("bucket(%d) = %d with upper %d", ns, b, bucketUpper(b))
+		}
+	}
+}
+
+func TestNewRunnerInvalid(t *testing.T) {
+	for _, mutate := range []func(w *Workload){
+		func(w *Workload) { w.Keys = 0 },
+		func(w *Workload) { w.Goroutines = 0 },
+		func(w *Workload) { w.Duration = 0 },
+		func(w *Workload) { w.MaxValue = 0 },
+		func(w *Workload) { w.Mix = [numOps]int{} },
+		func(w *Workload) { w.Mix[OpGet] = -1 },
+	} {
+		w := ReadHeavy()
+		mutate(&w)
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("NewRunner(%+v) did not panic", w)
+				}
+			}()
+			NewRunner(w)
+		}()
+	}
+}
@@ -0,0 +1,259 @@
+// Package ranklisthttp 提供将 ranklist 排行榜以 JSON 形式暴露的 net/http 处理器
+// Package ranklisthttp provides a net/http handler exposing a ranklist leaderboard as JSON
+package ranklisthttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/werbenhu/ranklist"
+)
+
+const (
+	// 分页查询默认返回的条目数
+	// Default number of entries returned by a range query
+	DefaultLimit = 10
+
+	// 分页查询单次允许返回的最大条目数
+	// Maximum number of entries a single range query may return
+	MaxLimit = 1000
+)
+
+// RankedEntry 表示附带排名的键值对
+// RankedEntry represents a key-value pair together with its rank
+type RankedEntry[K ranklist.Ordered, V ranklist.Ordered] struct {
+	Rank  int `json:"rank"`
+	Key   K   `json:"key"`
+	Value V   `json:"value"`
+}
+
+// Handler 将 RankList 以 JSON 接口的形式暴露，路由如下：
+//
+//	GET    /members?key=&key=   批量查询成员的分数和排名，不存在的成员被跳过，最多 1000 个
+//	GET    /members/{key}       查询成员的分数和排名，不存在时返回 404
+//	PUT    /members/{key}       设置成员的分数，请求体为 {"value": ...}
+//	POST   /members/{key}/incr  增加成员的分数，请求体为 {"delta": ...}
+//	DELETE /members/{key}       删除成员，不存在时返回 404
+//	GET    /range?start=&limit= 从排名 start（默认 1）开始分页查询，limit 默认 10，最大 1000
+//
+// Handler exposes a RankList as a JSON API with the following routes:
+//
+//	GET    /members?key=&key=   scores and ranks of several members, missing ones are skipped, at most 1000
+//	GET    /members/{key}       score and rank of a member, 404 when missing
+//	PUT    /members/{key}       set the score of a member, body {"value": ...}
+//	POST   /members/{key}/incr  increment the score of a member, body {"delta": ...}
+//	DELETE /members/{key}       delete a member, 404 when missing
+//	GET    /range?start=&limit= page through ranks from start (default 1), limit defaults to 10, at most 1000
+type Handler[K ranklist.Ordered, V ranklist.Ordered] struct {
+	list     *ranklist.RankList[K, V]
+	parseKey func(string) (K, error)
+	mux      *http.ServeMux
+}
+
+// NewHandler 为 list 创建一个 Handler，parseKey 用于解析路径中的键，
+// 为 nil 时字符串类型的键直接使用路径，数字类型的键按 JSON 数字解析
+// NewHandler creates a Handler for list, parseKey parses keys from the path.
+// When it is nil, string keys use the path segment as is and numeric keys are parsed as JSON numbers
+func NewHandler[K ranklist.Ordered, V ranklist.Ordered](list *ranklist.RankList[K, V], parseKey func(string) (K, error)) *Handler[K, V] {
+	if parseKey == nil {
+		parseKey = defaultParseKey[K]
+	}
+
+	h := &Handler[K, V]{list: list, parseKey: parseKey, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /members", h.getMany)
+	h.mux.HandleFunc("GET /members/{key}", h.get)
+	h.mux.HandleFunc("PUT /members/{key}", h.set)
+	h.mux.HandleFunc("POST /members/{key}/incr", h.incr)
+	h.mux.HandleFunc("DELETE /members/{key}", h.del)
+	h.mux.HandleFunc("GET /range", h.rangeEntries)
+	return h
+}
+
+// ServeHTTP 实现 http.Handler
+// ServeHTTP implements http.Handler
+func (h *Handler[K, V]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler[K, V]) get(w http.ResponseWriter, r *http.Request) {
+	key, ok := h.key(w, r)
+	if !ok {
+		return
+	}
+
+	value, exists := h.list.Get(key)
+	rank, ranked := h.list.Rank(key)
+	if !exists || !ranked {
+		writeError(w, http.StatusNotFound, "member not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.entry(rank, key, value))
+}
+
+func (h *Handler[K, V]) getMany(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()["key"]
+	if len(params) > MaxLimit {
+		writeError(w, http.StatusBadRequest, "at most "+strconv.Itoa(MaxLimit)+" keys")
+		return
+	}
+	keys := make([]K, len(params))
+	for i, param := range params {
+		key, err := h.parseKey(param)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid key")
+			return
+		}
+		keys[i] = key
+	}
+
+	values := h.list.MGet(keys)
+	ranks := h.list.MRank(keys)
+	result := make([]any, 0, len(values))
+	for _, key := range keys {
+		value, exists := values[key]
+		rank, ranked := ranks[key]
+		if !exists || !ranked {
+			continue
+		}
+		result = append(result, h.entry(rank, key, value))
+		delete(values, key)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler[K, V]) set(w http.ResponseWriter, r *http.Request) {
+	key, ok := h.key(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Value *V `json:"value"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Value == nil {
+		writeError(w, http.StatusBadRequest, "invalid body, expected {\"value\": ...}")
+		return
+	}
+
+	h.list.Set(key, *body.Value)
+	h.writeMember(w, key)
+}
+
+func (h *Handler[K, V]) incr(w http.ResponseWriter, r *http.Request) {
+	key, ok := h.key(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Delta *V `json:"delta"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Delta == nil {
+		writeError(w, http.StatusBadRequest, "invalid body, expected {\"delta\": ...}")
+		return
+	}
+
+	if _, err := h.list.IncrByChecked(key, *body.Delta); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	h.writeMember(w, key)
+}
+
+func (h *Handler[K, V]) del(w http.ResponseWriter, r *http.Request) {
+	key, ok := h.key(w, r)
+	if !ok {
+		return
+	}
+
+	if !h.list.Del(key) {
+		writeError(w, http.StatusNotFound, "member not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler[K, V]) rangeEntries(w http.ResponseWriter, r *http.Request) {
+	start, err := intParam(r, "start", 1)
+	if err != nil || start < 1 {
+		writeError(w, http.StatusBadRequest, "start must be a positive integer")
+		return
+	}
+	limit, err := intParam(r, "limit", DefaultLimit)
+	if err != nil || limit < 1 || limit > MaxLimit {
+		writeError(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(MaxLimit))
+		return
+	}
+
+	entries := h.list.Range(start, start+limit)
+	result := make([]any, len(entries))
+	for i, entry := range entries {
+		result[i] = h.entry(start+i, entry.Key, entry.Value)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// writeMember 写出成员当前的分数和排名
+// writeMember writes the current score and rank of a member
+func (h *Handler[K, V]) writeMember(w http.ResponseWriter, key K) {
+	value, _ := h.list.Get(key)
+	rank, _ := h.list.Rank(key)
+	writeJSON(w, http.StatusOK, h.entry(rank, key, value))
+}
+
+// entry 返回成员在响应中的 JSON 形式，跳表设置了 WithJSONFieldNames 或 WithEntryMarshaler 时与 MarshalJSON 一致，
+// 否则为 RankedEntry
+// entry returns the JSON form of a member in responses, matching MarshalJSON when the list sets
+// WithJSONFieldNames or WithEntryMarshaler, and a RankedEntry otherwise
+func (h *Handler[K, V]) entry(rank int, key K, value V) any {
+	ranked := ranklist.RankedEntry[K, V]{Entry: ranklist.Entry[K, V]{Key: key, Value: value}, Rank: rank}
+	if shaped, ok := h.list.JSONEntry(ranked); ok {
+		return shaped
+	}
+	return RankedEntry[K, V]{Rank: rank, Key: key, Value: value}
+}
+
+// key 解析路径中的键，失败时写出 400 响应
+// key parses the key from the path and writes a 400 response on failure
+func (h *Handler[K, V]) key(w http.ResponseWriter, r *http.Request) (K, bool) {
+	key, err := h.parseKey(r.PathValue("key"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid key")
+		return key, false
+	}
+	return key, true
+}
+
+// defaultParseKey 字符串类型的键直接使用原始字符串，其他类型按 JSON 数字解析
+// defaultParseKey uses the raw string for string keys and parses other kinds as JSON numbers
+func defaultParseKey[K ranklist.Ordered](s string) (K, error) {
+	var key K
+	if reflect.TypeFor[K]().Kind() == reflect.String {
+		reflect.ValueOf(&key).Elem().SetString(s)
+		return key, nil
+	}
+	err := json.Unmarshal([]byte(s), &key)
+	return key, err
+}
+
+// intParam 读取整数查询参数，缺省时返回 def
+// intParam reads an integer query parameter, returning def when it is absent
+func intParam(r *http.Request, name string, def int) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return def, nil
+	}
+	return strconv.Atoi(s)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
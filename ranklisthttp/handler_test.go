@@ -0,0 +1,234 @@
+package ranklisthttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/werbenhu/ranklist"
+)
+
+func do(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decode %q: %v", rec.Body.String(), err)
+	}
+	return v
+}
+
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("expected status %d, got %d: %s", status, rec.Code, rec.Body.String())
+	}
+}
+
+func newTestHandler() (*ranklist.RankList[string, int], *Handler[string, int]) {
+	sl := ranklist.New[string, int]()
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	return sl, NewHandler(sl, nil)
+}
+
+func TestHandlerGet(t *testing.T) {
+	_, h := newTestHandler()
+
+	rec := do(h, http.MethodGet, "/members/b", "")
+	expectStatus(t, rec, http.StatusOK)
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+	if got := decode[RankedEntry[string, int]](t, rec); got != (RankedEntry[string, int]{Rank: 2, Key: "b", Value: 20}) {
+		t.Errorf("unexpected member %+v", got)
+	}
+
+	expectStatus(t, do(h, http.MethodGet, "/members/missing", ""), http.StatusNotFound)
+}
+
+func TestHandlerGetMany(t *testing.T) {
+	_, h := newTestHandler()
+
+	rec := do(h, http.MethodGet, "/members?key=c&key=missing&key=a&key=c", "")
+	expectStatus(t, rec, http.StatusOK)
+	got := decode[[]RankedEntry[string, int]](t, rec)
+	expected := []RankedEntry[string, int]{{Rank: 3, Key: "c", Value: 30}, {Rank: 1, Key: "a", Value: 10}}
+	if len(got) != len(expected) || got[0] != expected[0] || got[1] != expected[1] {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	if got := decode[[]RankedEntry[string, int]](t, do(h, http.MethodGet, "/members", "")); len(got) != 0 {
+		t.Errorf("expected an empty list without keys, got %+v", got)
+	}
+	expectStatus(t, do(h, http.MethodGet, "/members?key="+strings.Repeat("x&key=", MaxLimit)+"x", ""), http.StatusBadRequest)
+}
+
+func TestHandlerSet(t *testing.T) {
+	sl, h := newTestHandler()
+
+	rec := do(h, http.MethodPut, "/members/d", `{"value": 5}`)
+	expectStatus(t, rec, http.StatusOK)
+	if got := decode[RankedEntry[string, int]](t, rec); got != (RankedEntry[string, int]{Rank: 1, Key: "d", Value: 5}) {
+		t.Errorf("unexpected member %+v", got)
+	}
+	if value, ok := sl.Get("d"); !ok || value != 5 {
+		t.Errorf("expected d=5 in the list, got %d, %v", value, ok)
+	}
+
+	for _, body := range []string{`{"value": "x"}`, `{}`, `not json`} {
+		expectStatus(t, do(h, http.MethodPut, "/members/d", body), http.StatusBadRequest)
+	}
+}
+
+func TestHandlerIncr(t *testing.T) {
+	_, h := newTestHandler()
+
+	rec := do(h, http.MethodPost, "/members/a/incr", `{"delta": 25}`)
+	expectStatus(t, rec, http.StatusOK)
+	if got := decode[RankedEntry[string, int]](t, rec); got != (RankedEntry[string, int]{Rank: 3, Key: "a", Value: 35}) {
+		t.Errorf("unexpected member %+v", got)
+	}
+
+	rec = do(h, http.MethodPost, "/members/new/incr", `{"delta": 1}`)
+	expectStatus(t, rec, http.StatusOK)
+	if got := decode[RankedEntry[string, int]](t, rec); got != (RankedEntry[string, int]{Rank: 1, Key: "new", Value: 1}) {
+		t.Errorf("unexpected member %+v", got)
+	}
+
+	expectStatus(t, do(h, http.MethodPost, "/members/a/incr", `{}`), http.StatusBadRequest)
+
+	limited := ranklist.New(ranklist.WithMaxDelta[string, int](5))
+	expectStatus(t, do(NewHandler(limited, nil), http.MethodPost, "/members/a/incr", `{"delta": 6}`), http.StatusUnprocessableEntity)
+	if _, ok := limited.Get("a"); ok {
+		t.Errorf("rejected increment should not create the member")
+	}
+}
+
+func TestHandlerDelete(t *testing.T) {
+	sl, h := newTestHandler()
+
+	expectStatus(t, do(h, http.MethodDelete, "/members/a", ""), http.StatusNoContent)
+	if _, ok := sl.Get("a"); ok {
+		t.Errorf("expected a to be deleted")
+	}
+
+	expectStatus(t, do(h, http.MethodDelete, "/members/a", ""), http.StatusNotFound)
+}
+
+func TestHandlerRange(t *testing.T) {
+	sl := ranklist.New[string, int]()
+	for i := 1; i <= 25; i++ {
+		sl.Set("k"+strconv.Itoa(i), i)
+	}
+	h := NewHandler(sl, nil)
+
+	rec := do(h, http.MethodGet, "/range", "")
+	expectStatus(t, rec, http.StatusOK)
+	page := decode[[]RankedEntry[string, int]](t, rec)
+	if len(page) != DefaultLimit || page[0] != (RankedEntry[string, int]{Rank: 1, Key: "k1", Value: 1}) {
+		t.Errorf("unexpected default page %+v", page)
+	}
+
+	rec = do(h, http.MethodGet, "/range?start=21&limit=10", "")
+	expectStatus(t, rec, http.StatusOK)
+	page = decode[[]RankedEntry[string, int]](t, rec)
+	if len(page) != 5 || page[4] != (RankedEntry[string, int]{Rank: 25, Key: "k25", Value: 25}) {
+		t.Errorf("unexpected last page %+v", page)
+	}
+
+	rec = do(h, http.MethodGet, "/range?start=100", "")
+	expectStatus(t, rec, http.StatusOK)
+	if page = decode[[]RankedEntry[string, int]](t, rec); len(page) != 0 {
+		t.Errorf("expected an empty page past the end, got %+v", page)
+	}
+
+	for _, target := range []string{"/range?start=0", "/range?start=x", "/range?limit=0", "/range?limit=1001"} {
+		expectStatus(t, do(h, http.MethodGet, target, ""), http.StatusBadRequest)
+	}
+}
+
+func TestHandlerNumericKeys(t *testing.T) {
+	sl := ranklist.New[int, float64]()
+	sl.Set(7, 1.5)
+	h := NewHandler(sl, nil)
+
+	rec := do(h, http.MethodGet, "/members/7", "")
+	expectStatus(t, rec, http.StatusOK)
+	if got := decode[RankedEntry[int, float64]](t, rec); got != (RankedEntry[int, float64]{Rank: 1, Key: 7, Value: 1.5}) {
+		t.Errorf("unexpected member %+v", got)
+	}
+
+	expectStatus(t, do(h, http.MethodGet, "/members/seven", ""), http.StatusBadRequest)
+}
+
+func TestHandlerConcurrent(t *testing.T) {
+	sl := ranklist.New[int, int]()
+	h := NewHandler(sl, nil)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				key := strconv.Itoa(g*100 + i)
+				do(h, http.MethodPut, "/members/"+key, `{"value": `+key+`}`)
+				do(h, http.MethodPost, "/members/"+key+"/incr", `{"delta": 1}`)
+				do(h, http.MethodGet, "/members/"+key, "")
+				do(h, http.MethodGet, "/range?limit=5", "")
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if sl.Length() != 800 {
+		t.Fatalf("expected 800 members, got %d", sl.Length())
+	}
+	for key := 0; key < 800; key++ {
+		if value, ok := sl.Get(key); !ok || value != key+1 {
+			t.Errorf("expected %d=%d, got %d, %v", key, key+1, value, ok)
+		}
+	}
+}
+
+func TestHandlerJSONFieldNames(t *testing.T) {
+	sl := ranklist.New(ranklist.WithJSONFieldNames[string, int]("member", "score", "rank"))
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	h := NewHandler(sl, nil)
+
+	rec := do(h, http.MethodGet, "/members/b", "")
+	expectStatus(t, rec, http.StatusOK)
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"member":"b","score":20,"rank":2}` {
+		t.Errorf("unexpected member %s", got)
+	}
+
+	// 整页与 MarshalJSON 的输出一致 / A full page matches the output of MarshalJSON
+	marshaled, err := json.Marshal(sl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rec = do(h, http.MethodGet, "/range", "")
+	expectStatus(t, rec, http.StatusOK)
+	if got := strings.TrimSpace(rec.Body.String()); got != string(marshaled) {
+		t.Errorf("range %s differs from MarshalJSON %s", got, marshaled)
+	}
+
+	rec = do(h, http.MethodGet, "/members?key=a&key=missing", "")
+	expectStatus(t, rec, http.StatusOK)
+	if got := strings.TrimSpace(rec.Body.String()); got != `[{"member":"a","score":10,"rank":1}]` {
+		t.Errorf("unexpected members %s", got)
+	}
+}
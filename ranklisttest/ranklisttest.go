@@ -0,0 +1,153 @@
+// Package ranklisttest 提供测试 ranklist 及其上层代码时使用的断言和随机操作脚本，
+// 是“跳表处于有效状态”这一判断的唯一来源
+// Package ranklisttest provides assertions and random operation scripts for testing ranklist and code built on it,
+// and is the single source of truth for what a valid skip list means
+package ranklisttest
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/werbenhu/ranklist"
+	"github.com/werbenhu/ranklist/internal/testhook"
+)
+
+// RequireValid 校验跳表内部结构的全部不变量，不一致时以 Check 返回的错误终止测试
+// RequireValid validates every internal invariant of the skip list and fails the test with the error Check returns
+func RequireValid[K ranklist.Ordered, V ranklist.Ordered](tb testing.TB, sl *ranklist.RankList[K, V]) {
+	tb.Helper()
+	if err := sl.Check(); err != nil {
+		tb.Fatalf("invalid skip list: %v", err)
+	}
+}
+
+// RequireEqualModel 校验跳表与按暴力排序的模型一致：长度、每个键的 Get 和 Rank、每个排名的 GetByRank，
+// 以及完整的 Range 都必须与模型按 (值, 键) 升序排列的结果相同，同时校验内部结构的不变量
+// RequireEqualModel verifies the skip list against a brute-force sorted model: the length, Get and Rank of every key,
+// GetByRank of every rank and the full Range must all match the model sorted ascending by (value, key),
+// and the internal invariants are validated as well
+func RequireEqualModel[K ranklist.Ordered, V ranklist.Ordered](tb testing.TB, sl *ranklist.RankList[K, V], model map[K]V) {
+	tb.Helper()
+	RequireValid(tb, sl)
+
+	sorted := make([]ranklist.Entry[K, V], 0, len(model))
+	for key, value := range model {
+		sorted = append(sorted, ranklist.Entry[K, V]{Key: key, Value: value})
+	}
+	slices.SortFunc(sorted, func(a, b ranklist.Entry[K, V]) int {
+		return cmp.Or(cmp.Compare(a.Value, b.Value), cmp.Compare(a.Key, b.Key))
+	})
+
+	if length := sl.Length(); length != len(sorted) {
+		tb.Fatalf("expected length %d, got %d", len(sorted), length)
+	}
+	for i, expected := range sorted {
+		if value, ok := sl.Get(expected.Key); !ok || value != expected.Value {
+			tb.Fatalf("Get(%v): expected %v, got %v, %v", expected.Key, expected.Value, value, ok)
+		}
+		if rank, ok := sl.Rank(expected.Key); !ok || rank != i+1 {
+			tb.Fatalf("Rank(%v): expected %d, got %d, %v", expected.Key, i+1, rank, ok)
+		}
+		if entry, ok := sl.GetByRank(i + 1); !ok || entry != expected {
+			tb.Fatalf("GetByRank(%d): expected %v, got %v, %v", i+1, expected, entry, ok)
+		}
+	}
+	if entries := sl.Range(1, len(sorted)+1); !slices.Equal(entries, sorted) {
+		tb.Fatalf("Range mismatch:\nexpected %v\ngot      %v", sorted, entries)
+	}
+}
+
+// CorruptSpan 故意将排名为 rank 的节点在第 level 层（从 1 开始）的跨度加一，破坏跳表的内部结构，
+// 用于验证 Check 和 WithAuditor 能发现不一致。排名不存在或节点没有这一层时返回 false，此时跳表不变
+// CorruptSpan deliberately adds one to the span of the node ranked rank on level level, counted from 1,
+// breaking the internal structure of the list to verify that Check and WithAuditor notice.
+// It returns false and leaves the list alone when the rank does not exist or the node is not that tall
+func CorruptSpan[K ranklist.Ordered, V ranklist.Ordered](sl *ranklist.RankList[K, V], rank, level int) bool {
+	return testhook.CorruptSpan(sl, rank, level)
+}
+
+// Script 描述一段可复现的随机操作序列，相同的 Seed 总是产生相同的操作
+// Script describes a reproducible random sequence of operations, the same Seed always yields the same operations
+type Script struct {
+	// 随机数种子
+	// Seed of the random source
+	Seed uint64
+
+	// 操作的数量
+	// Number of operations
+	Steps int
+
+	// 键取自 [0, Keys)，值取自 [0, Values)，为 0 时分别使用 100 和 1000
+	// Keys are drawn from [0, Keys) and values from [0, Values), 100 and 1000 are used when zero
+	Keys, Values int
+
+	// 每执行多少个操作校验一次模型，为 0 时只在结束时校验
+	// How many operations run between model checks, zero checks only at the end
+	CheckEvery int
+}
+
+// Run 将脚本的操作依次应用到跳表和一个 map 模型上，包括 Set、Del、IncrBy、SetBatch 和偶尔的 Clear，
+// 按 CheckEvery 用 RequireEqualModel 校验两者，结束时返回模型。
+// 失败信息中包含种子和步数，使用同一个 Script 即可重现
+// Run applies the operations of the script to the skip list and to a map model,
+// covering Set, Del, IncrBy, SetBatch and the occasional Clear, checks both with RequireEqualModel every CheckEvery steps,
+// and returns the model at the end. Failures name the seed and step, so rerunning the same Script reproduces them
+func (s Script) Run(tb testing.TB, sl *ranklist.RankList[int, int]) map[int]int {
+	tb.Helper()
+	keys, values := cmp.Or(s.Keys, 100), cmp.Or(s.Values, 1000)
+	rng := rand.New(rand.NewPCG(s.Seed, s.Seed))
+	model := make(map[int]int)
+
+	for step := 1; step <= s.Steps; step++ {
+		key := rng.IntN(keys)
+		switch op := rng.IntN(100); {
+		case op < 45:
+			value := rng.IntN(values)
+			sl.Set(key, value)
+			model[key] = value
+		case op < 70:
+			_, exists := model[key]
+			if sl.Del(key) != exists {
+				tb.Fatalf("seed %d, step %d: Del(%d) disagrees with the model", s.Seed, step, key)
+			}
+			delete(model, key)
+		case op < 90:
+			delta := rng.IntN(21) - 10
+			model[key] += delta
+			if value := sl.IncrBy(key, delta); value != model[key] {
+				tb.Fatalf("seed %d, step %d: IncrBy(%d) expected %d, got %d", s.Seed, step, key, model[key], value)
+			}
+		case op < 99:
+			batch := make([]ranklist.Entry[int, int], rng.IntN(8))
+			for i := range batch {
+				batch[i] = ranklist.Entry[int, int]{Key: rng.IntN(keys), Value: rng.IntN(values)}
+				model[batch[i].Key] = batch[i].Value
+			}
+			sl.SetBatch(batch)
+		default:
+			sl.Clear()
+			clear(model)
+		}
+
+		if s.CheckEvery > 0 && step%s.CheckEvery == 0 {
+			RequireEqualModel(prefixed{tb, fmt.Sprintf("seed %d, step %d: ", s.Seed, step)}, sl, model)
+		}
+	}
+	RequireEqualModel(prefixed{tb, fmt.Sprintf("seed %d, step %d: ", s.Seed, s.Steps)}, sl, model)
+	return model
+}
+
+// prefixed 在失败信息前加上前缀的 testing.TB
+// prefixed is a testing.TB that prefixes failure messages
+type prefixed struct {
+	testing.TB
+	prefix string
+}
+
+func (p prefixed) Fatalf(format string, args ...any) {
+	p.TB.Helper()
+	p.TB.Fatalf(p.prefix+format, args...)
+}
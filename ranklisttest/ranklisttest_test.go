@@ -0,0 +1,103 @@
+package ranklisttest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/werbenhu/ranklist"
+)
+
+// recorder 记录失败而不终止测试的 testing.TB
+// recorder is a testing.TB recording failures without stopping the test
+type recorder struct {
+	testing.TB
+	failure string
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.failure = fmt.Sprintf(format, args...)
+	panic(r)
+}
+
+// failure 运行 fn 并返回它报告的失败信息，没有失败时返回空字符串
+// failure runs fn and returns the failure it reported, empty when it passed
+func failure(t *testing.T, fn func(tb testing.TB)) (msg string) {
+	r := &recorder{TB: t}
+	defer func() {
+		if p := recover(); p != nil && p != r {
+			panic(p)
+		}
+		msg = r.failure
+	}()
+	fn(r)
+	return ""
+}
+
+func TestRequireEqualModel(t *testing.T) {
+	sl := ranklist.New[string, int]()
+	sl.Set("a", 2)
+	sl.Set("b", 1)
+	sl.Set("c", 2)
+	RequireEqualModel(t, sl, map[string]int{"a": 2, "b": 1, "c": 2})
+
+	cases := []map[string]int{
+		{"a": 2, "b": 1},
+		{"a": 2, "b": 1, "c": 3},
+		{"a": 2, "b": 1, "d": 2},
+	}
+	for _, model := range cases {
+		if msg := failure(t, func(tb testing.TB) { RequireEqualModel(tb, sl, model) }); msg == "" {
+			t.Errorf("model %v should not match", model)
+		}
+	}
+}
+
+func TestScript(t *testing.T) {
+	for seed := uint64(1); seed <= 5; seed++ {
+		a := Script{Seed: seed, Steps: 2000, CheckEvery: 500}.Run(t, ranklist.New[int, int]())
+		b := Script{Seed: seed, Steps: 2000}.Run(t, ranklist.New(ranklist.WithNoDict[int, int]()))
+		if fmt.Sprint(a) != fmt.Sprint(b) {
+			t.Fatalf("seed %d: the same script should produce the same model", seed)
+		}
+	}
+}
+
+func TestCorruptSpan(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	errs := make(chan error, 1)
+	sl := ranklist.New(ranklist.WithAuditor[int, int](interval, func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}))
+	defer sl.Close()
+	for i := 0; i < 1000; i++ {
+		sl.Set(i, i%100)
+	}
+	RequireValid(t, sl)
+
+	if CorruptSpan(sl, 0, 1) || CorruptSpan(sl, 1001, 1) || CorruptSpan(sl, 1, ranklist.MaxLevel+1) {
+		t.Fatal("CorruptSpan should refuse ranks and levels that do not exist")
+	}
+	if !CorruptSpan(sl, 500, 1) {
+		t.Fatal("expected CorruptSpan to corrupt rank 500")
+	}
+	if msg := failure(t, func(tb testing.TB) { RequireValid(tb, sl) }); !strings.Contains(msg, "spans add up") {
+		t.Fatalf("expected RequireValid to notice the corrupted span, got %q", msg)
+	}
+
+	// 审计在下一个周期发现损坏，超时只是为繁忙的机器留出余量
+	// The auditor notices on the next tick, the timeout only leaves room for a busy machine
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ranklist.ErrCorrupted) {
+			t.Fatalf("expected ErrCorrupted, got %v", err)
+		}
+	case <-time.After(50 * interval):
+		t.Fatal("the auditor did not report the corrupted span")
+	}
+}
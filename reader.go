@@ -0,0 +1,53 @@
+package ranklist
+
+// Reader 是跳表的只读接口，只包含读取方法，*RankList、*View 和 *FrozenRankList 都实现了它。
+// 只需要读取榜单的代码（例如渲染）可以接受 Reader 而不是 *RankList，从类型上排除修改，测试时也可以传入替身实现。
+// 排名从 1 开始，规则与 RankList 的同名方法相同
+// Reader is the read-only interface of a skip list with only its read methods,
+// implemented by *RankList, *View and *FrozenRankList.
+// Code that only reads a board, such as rendering, can accept a Reader instead of a *RankList to rule out mutations
+// by type, and tests can pass a fake. Ranks are 1-based and follow the methods of the same name on RankList
+type Reader[K Ordered, V Ordered] interface {
+	// Length 返回条目数量
+	// Length returns the number of entries
+	Length() int
+
+	// Get 返回键的值，键不存在时返回 false
+	// Get returns the value of key, false if the key does not exist
+	Get(key K) (V, bool)
+
+	// Rank 返回键的排名，键不存在时返回 false
+	// Rank returns the rank of key, false if the key does not exist
+	Rank(key K) (int, bool)
+
+	// GetByRank 返回指定排名上的条目，超出 [1, Length()] 时返回 false
+	// GetByRank returns the entry at the given rank, false outside [1, Length()]
+	GetByRank(rank int) (Entry[K, V], bool)
+
+	// Range 返回排名区间 [start, end) 内的条目
+	// Range returns the entries within the rank range [start, end)
+	Range(start int, end int) []Entry[K, V]
+
+	// Top 返回值最大的 n 个条目，按值从高到低排列
+	// Top returns the n entries with the largest values from the highest value down
+	Top(n int) []Entry[K, V]
+
+	// Entries 按排名顺序返回全部条目
+	// Entries returns every entry in rank order
+	Entries() []Entry[K, V]
+}
+
+var (
+	_ Reader[string, int] = (*RankList[string, int])(nil)
+	_ Reader[string, int] = (*View[string, int])(nil)
+	_ Reader[string, int] = (*FrozenRankList[string, int])(nil)
+)
+
+// ReadOnly 返回以 Reader 接口表示的跳表本身，不复制任何内容，读取的始终是最新的数据。
+// 调用方仍然可以通过类型断言取回 *RankList，只读是类型上的约定而不是隔离；需要隔离时使用 SnapshotView 或 Freeze
+// ReadOnly returns the list itself typed as the Reader interface, copying nothing, so reads always see live data.
+// Callers can still type-assert the *RankList back, read-only is a contract by type rather than isolation;
+// use SnapshotView or Freeze when isolation is needed
+func (sl *RankList[K, V]) ReadOnly() Reader[K, V] {
+	return sl
+}
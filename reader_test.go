@@ -0,0 +1,62 @@
+package ranklist
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// printStandings 只依赖 Reader，打印前 n 名及其排名
+// printStandings depends on Reader alone and prints the top n with their ranks
+func printStandings(board Reader[string, int], n int) {
+	for _, entry := range board.Top(n) {
+		rank, _ := board.Rank(entry.Key)
+		fmt.Printf("%d/%d %s %d\n", rank, board.Length(), entry.Key, entry.Value)
+	}
+}
+
+func ExampleReader() {
+	sl := New[string, int]()
+	sl.SetBatch([]Entry[string, int]{{"alice", 30}, {"bob", 10}, {"carol", 20}})
+
+	printStandings(sl.ReadOnly(), 2)
+	printStandings(sl.SnapshotView(), 1)
+	printStandings(sl.Freeze(), 1)
+	// Output:
+	// 3/3 alice 30
+	// 2/3 carol 20
+	// 3/3 alice 30
+	// 3/3 alice 30
+}
+
+func TestReaderImplementations(t *testing.T) {
+	sl := New[string, int]()
+	for i := range 100 {
+		sl.Set(strconv.Itoa(i), i%7)
+	}
+	readers := map[string]Reader[string, int]{
+		"view":   sl.SnapshotView(),
+		"frozen": sl.Freeze(),
+	}
+	for name, r := range readers {
+		if r.Length() != sl.Length() || !slices.Equal(r.Entries(), sl.Entries()) {
+			t.Fatalf("%s: expected the entries of the list", name)
+		}
+		for _, n := range []int{-1, 0, 5, 100, 200} {
+			if !slices.Equal(r.Top(n), sl.Top(n)) {
+				t.Fatalf("%s: Top(%d) disagrees with the list", name, n)
+			}
+		}
+		for rank := -1; rank <= 101; rank++ {
+			a, aok := r.GetByRank(rank)
+			b, bok := sl.GetByRank(rank)
+			if a != b || aok != bok {
+				t.Fatalf("%s: GetByRank(%d) disagrees with the list", name, rank)
+			}
+		}
+	}
+	if r := sl.ReadOnly(); r != Reader[string, int](sl) {
+		t.Fatal("expected ReadOnly to return the list itself")
+	}
+}
@@ -0,0 +1,176 @@
+package ranklist
+
+import "fmt"
+
+// recencyList 按更新时间从新到旧串起全部节点的双向链表，链接挂在每个节点的 recency 上
+// recencyList is a doubly linked list threading every node from the most to the least recently updated,
+// with the links hanging off each node's recency field
+type recencyList[K Ordered, V Ordered] struct {
+	newest *Node[K, V]
+	oldest *Node[K, V]
+}
+
+// recencyLink 节点在更新顺序链表中的前后链接
+// recencyLink holds the links of a node within the update order list
+type recencyLink[K Ordered, V Ordered] struct {
+	newer *Node[K, V]
+	older *Node[K, V]
+}
+
+// WithUpdateOrder 在排名顺序之外，按更新时间从新到旧把全部节点串成一个双向链表，供 RecentlyUpdated 读取，
+// 例如用来展示最近的动态。每次改变值的写入（Set、IncrBy、批量写入等）将键移到最前面，删除时将其摘除，
+// 都是 O(1)；值未改变的写入不移动键。Restore、Load、ApplyToAll 等整体改写内容的操作重新开始计算顺序，
+// 等同于按排名从低到高依次写入，值最大的条目成为最近更新的条目。
+// 内存开销：未开启时每个节点只多一个为 nil 的指针，开启后每个节点再带一个含两个指针的链接
+// WithUpdateOrder threads every node into a doubly linked list from the most to the least recently updated,
+// alongside the rank order, for RecentlyUpdated to read, e.g. to show activity feeds.
+// Every write changing a value (Set, IncrBy, batch writes and so on) moves the key to the front
+// and deletes unlink it, both in O(1); writes of an unchanged value leave the key where it is.
+// Whole-content rewrites such as Restore, Load and ApplyToAll restart the order
+// as if the entries were written from the lowest rank up, making the largest value the most recently updated.
+// Memory cost: without it every node only carries one nil pointer, with it every node also holds a link of two pointers
+func WithUpdateOrder[K Ordered, V Ordered]() Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.recency = &recencyList[K, V]{}
+	}
+}
+
+// RecentlyUpdated 在读锁内返回最近更新的 n 个条目，从最近更新的开始，耗时 O(n)。
+// 未开启 WithUpdateOrder 或 n 小于 1 时返回空切片，占位条目与 Range 相同默认被跳过
+// RecentlyUpdated returns the n most recently updated entries under a read lock, the most recent first, in O(n).
+// It returns an empty slice without WithUpdateOrder or when n is below 1; placeholders are skipped like in Range
+func (sl *RankList[K, V]) RecentlyUpdated(n int) []Entry[K, V] {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	entries := make([]Entry[K, V], 0)
+	if sl.recency == nil {
+		return entries
+	}
+	for curr := sl.recency.newest; curr != nil && len(entries) < n; curr = curr.recency.older {
+		if !sl.hidden(curr) {
+			entries = append(entries, curr.data)
+		}
+	}
+	return entries
+}
+
+// touch 将节点移到链表的最前面，节点可以尚未在链表中，调用方需持有写锁
+// touch moves node to the front of the list, the node may not be in the list yet. The caller must hold the write lock
+func (r *recencyList[K, V]) touch(node *Node[K, V]) {
+	if r == nil {
+		return
+	}
+	if node.recency == nil {
+		node.recency = &recencyLink[K, V]{}
+	} else if r.newest == node {
+		return
+	} else {
+		r.remove(node)
+	}
+	node.recency.older = r.newest
+	if r.newest != nil {
+		r.newest.recency.newer = node
+	} else {
+		r.oldest = node
+	}
+	r.newest = node
+}
+
+// remove 将节点从链表中摘除，节点不在链表中时不做任何事，调用方需持有写锁
+// remove unlinks node from the list, a no-op for a node outside it. The caller must hold the write lock
+func (r *recencyList[K, V]) remove(node *Node[K, V]) {
+	if r == nil || node.recency == nil {
+		return
+	}
+	link := node.recency
+	if r.newest != node && link.newer == nil {
+		return
+	}
+	if link.newer != nil {
+		link.newer.recency.older = link.older
+	} else {
+		r.newest = link.older
+	}
+	if link.older != nil {
+		link.older.recency.newer = link.newer
+	} else {
+		r.oldest = link.newer
+	}
+	*link = recencyLink[K, V]{}
+}
+
+// rebuild 按第 0 层顺序重新串起全部节点，排名最后的节点成为最近更新的节点，用于整体重建之后，调用方需持有写锁
+// rebuild threads every node again in level-0 order, the last ranked node becoming the most recently updated,
+// after a whole rebuild. The caller must hold the write lock
+func (r *recencyList[K, V]) rebuild(sl *RankList[K, V]) {
+	if r == nil {
+		return
+	}
+	r.newest, r.oldest = nil, nil
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		if curr.recency != nil {
+			*curr.recency = recencyLink[K, V]{}
+		}
+		r.touch(curr)
+	}
+}
+
+// positions 按从旧到新的更新顺序返回每个节点在第 0 层的位置，未开启时返回 nil，调用方需持有锁
+// positions returns the level-0 position of every node from the least to the most recently updated,
+// nil when disabled. The caller must hold the lock
+func (r *recencyList[K, V]) positions(sl *RankList[K, V]) []int {
+	if r == nil {
+		return nil
+	}
+	index := make(map[*Node[K, V]]int, sl.length)
+	for curr, i := sl.header.forward[0], 0; curr != nil; curr, i = curr.forward[0], i+1 {
+		index[curr] = i
+	}
+	order := make([]int, 0, sl.length)
+	for curr := r.oldest; curr != nil; curr = curr.recency.newer {
+		order = append(order, index[curr])
+	}
+	return order
+}
+
+// replay 按 positions 返回的顺序重新串起顺序不变的重建之后的节点，恢复重建前的更新顺序，调用方需持有写锁
+// replay threads the nodes again in the order returned by positions after a rebuild that kept the rank order,
+// restoring the update order from before the rebuild. The caller must hold the write lock
+func (r *recencyList[K, V]) replay(sl *RankList[K, V], order []int) {
+	if r == nil {
+		return
+	}
+	nodes := make([]*Node[K, V], 0, sl.length)
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		nodes = append(nodes, curr)
+	}
+	for _, i := range order {
+		r.touch(nodes[i])
+	}
+}
+
+// check 校验链表的前后链接一致，且恰好包含 positions 中的每个节点，positions 是第 0 层每个节点的位置，调用方需持有锁
+// check validates that the list links agree both ways and hold exactly the nodes of positions,
+// the position of every node at level 0. The caller must hold the lock
+func (r *recencyList[K, V]) check(positions map[*Node[K, V]]int) error {
+	if r == nil {
+		return nil
+	}
+	var newer *Node[K, V]
+	seen := 0
+	for curr := r.newest; curr != nil; curr = curr.recency.older {
+		if _, ok := positions[curr]; !ok || seen == len(positions) {
+			return fmt.Errorf("%w: update order list holds key %v, which is not in the list", ErrCorrupted, curr.data.Key)
+		}
+		if curr.recency.newer != newer {
+			return fmt.Errorf("%w: update order list links key %v back to the wrong node", ErrCorrupted, curr.data.Key)
+		}
+		newer = curr
+		seen++
+	}
+	if newer != r.oldest || seen != len(positions) {
+		return fmt.Errorf("%w: update order list holds %d nodes, level 0 holds %d", ErrCorrupted, seen, len(positions))
+	}
+	return nil
+}
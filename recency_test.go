@@ -0,0 +1,124 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// recentKeys 返回 RecentlyUpdated 给出的全部键
+// recentKeys returns every key given by RecentlyUpdated
+func recentKeys(sl *RankList[string, int]) []string {
+	var keys []string
+	for _, entry := range sl.RecentlyUpdated(sl.Length()) {
+		keys = append(keys, entry.Key)
+	}
+	return keys
+}
+
+func TestUpdateOrder(t *testing.T) {
+	sl := New(WithUpdateOrder[string, int]())
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+
+	steps := []struct {
+		name string
+		fn   func()
+		want []string
+	}{
+		{"insert", func() {}, []string{"c", "b", "a"}},
+		{"update in place", func() { sl.IncrBy("a", 1) }, []string{"a", "c", "b"}},
+		{"update moving the node", func() { sl.Set("b", 40) }, []string{"b", "a", "c"}},
+		{"unchanged value", func() { sl.Set("c", 30) }, []string{"b", "a", "c"}},
+		{"delete the newest", func() { sl.Del("b") }, []string{"a", "c"}},
+		{"delete the oldest", func() { sl.Del("c") }, []string{"a"}},
+		{"delete the last", func() { sl.Del("a") }, nil},
+		{"insert after emptying", func() { sl.Set("d", 1) }, []string{"d"}},
+	}
+	for _, step := range steps {
+		step.fn()
+		if got := recentKeys(sl); !slices.Equal(got, step.want) {
+			t.Fatalf("%s: expected %v, got %v", step.name, step.want, got)
+		}
+		if err := sl.Check(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+	}
+
+	sl.SetBatch([]Entry[string, int]{{"e", 5}, {"f", 3}})
+	if got := sl.RecentlyUpdated(2); !slices.Equal(got, []Entry[string, int]{{"f", 3}, {"e", 5}}) {
+		t.Fatalf("expected the batch in write order, got %v", got)
+	}
+	if got := sl.RecentlyUpdated(0); len(got) != 0 {
+		t.Fatalf("expected nothing for n = 0, got %v", got)
+	}
+
+	// 整体重建之后按排名从低到高重新计算，值最大的条目最近
+	// A whole rebuild restarts the order from the lowest rank up, the largest value being the most recent
+	sl.Restore([]Entry[string, int]{{"x", 2}, {"y", 1}, {"z", 3}})
+	if got := recentKeys(sl); !slices.Equal(got, []string{"z", "x", "y"}) {
+		t.Fatalf("expected the rebuilt order, got %v", got)
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateOrderDisabled(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	if got := sl.RecentlyUpdated(1); got == nil || len(got) != 0 {
+		t.Fatalf("expected an empty slice without WithUpdateOrder, got %v", got)
+	}
+}
+
+func TestUpdateOrderRandom(t *testing.T) {
+	// 与一个不维护更新顺序的跳表并行写入，排名结构不受影响，顺序与模型一致
+	// Written alongside a list without update order, the rank structure is unaffected and the order follows a model
+	sl := New(WithUpdateOrder[string, int]())
+	plain := New[string, int]()
+	var model []string
+	touch := func(key string) {
+		model = slices.DeleteFunc(model, func(k string) bool { return k == key })
+		model = append([]string{key}, model...)
+	}
+
+	for i := range 3000 {
+		key := strconv.Itoa(rand.IntN(100))
+		value, exists := plain.Get(key)
+		switch op := rand.IntN(10); {
+		case op < 6:
+			next := rand.IntN(50)
+			sl.Set(key, next)
+			plain.Set(key, next)
+			if !exists || next != value {
+				touch(key)
+			}
+		case op < 8:
+			delta := rand.IntN(3) - 1
+			sl.IncrBy(key, delta)
+			plain.IncrBy(key, delta)
+			if !exists || delta != 0 {
+				touch(key)
+			}
+		default:
+			sl.Del(key)
+			plain.Del(key)
+			model = slices.DeleteFunc(model, func(k string) bool { return k == key })
+		}
+
+		if i%100 == 0 {
+			if err := sl.Check(); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(sl.Entries(), plain.Entries()) {
+				t.Fatalf("step %d: the rank structure diverged", i)
+			}
+			if got := recentKeys(sl); !slices.Equal(got, model) {
+				t.Fatalf("step %d: expected %v, got %v", i, model, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package ranklist
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+)
+
+// WriteRedisProto 按排名顺序将跳表写为 RESP 格式的 ZADD 命令，每个条目一条 `ZADD zsetKey score member`，
+// 可以直接通过 `redis-cli --pipe` 导入。RESP 使用长度前缀，因此成员中的空格和换行无需转义。
+// 分数按能够精确还原的最短形式格式化，设置了 WithValueFormatter 时按其格式化；Redis 的分数是双精度浮点数，超过 2^53 的整数会丢失精度。
+// 值类型不是数字时返回 ErrNonNumericValue。导出期间持有读锁
+// WriteRedisProto writes the skip list as RESP-encoded ZADD commands in rank order,
+// one `ZADD zsetKey score member` per entry, ready to be piped into `redis-cli --pipe`.
+// RESP is length-prefixed, so spaces and newlines in members need no escaping.
+// Scores use the shortest representation that round-trips, or WithValueFormatter when set; Redis scores are doubles,
+// so integers beyond 2^53 lose precision.
+// Returns ErrNonNumericValue when the value type is not numeric. The read lock is held during the export
+func (sl *RankList[K, V]) WriteRedisProto(w io.Writer, zsetKey string) error {
+	if kindOf[V]() == reflect.String {
+		return ErrNonNumericValue
+	}
+
+	sl.RLock()
+	defer sl.RUnlock()
+	return writeRedisProto(w, zsetKey, sl.codec, sl.walk)
+}
+
+// writeRedisProto 按 entries 的顺序将条目以 WriteRedisProto 的格式写入 w，调用方需已检查值类型是数字
+// writeRedisProto writes entries to w in the WriteRedisProto format, in the order entries yields them.
+// The caller must have checked that the value type is numeric
+func writeRedisProto[K Ordered, V Ordered](w io.Writer, zsetKey string, codec textCodec[K, V], entries iter.Seq[Entry[K, V]]) error {
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, 0, 256)
+	for entry := range entries {
+		buf = append(buf[:0], "*4\r\n"...)
+		buf = appendBulkString(buf, "ZADD")
+		buf = appendBulkString(buf, zsetKey)
+		buf = appendBulkString(buf, codec.valueText(entry.Value))
+		buf = appendBulkString(buf, codec.keyText(entry.Key))
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// appendBulkString 将 s 编码为 RESP 批量字符串追加到 buf
+// appendBulkString appends s to buf encoded as a RESP bulk string
+func appendBulkString(buf []byte, s string) []byte {
+	buf = append(buf, '$')
+	buf = strconv.AppendInt(buf, int64(len(s)), 10)
+	buf = append(buf, "\r\n"...)
+	buf = append(buf, s...)
+	return append(buf, "\r\n"...)
+}
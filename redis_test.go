@@ -0,0 +1,99 @@
+package ranklist
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+)
+
+// readRESPCommand 解析一条 RESP 数组命令
+// readRESPCommand parses one RESP array command
+func readRESPCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(line[1 : len(line)-2])
+	if err != nil || line[0] != '*' {
+		return nil, errors.New("invalid array header")
+	}
+
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(line[1 : len(line)-2])
+		if err != nil || line[0] != '$' {
+			return nil, errors.New("invalid bulk header")
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		args = append(args, string(data[:size]))
+	}
+	return args, nil
+}
+
+func TestWriteRedisProto(t *testing.T) {
+	sl := New[string, float64]()
+	expected := make(map[string]float64)
+	for i := 0; i < 1000; i++ {
+		key := "member " + strconv.Itoa(i)
+		if i%10 == 0 {
+			key += "\r\nwith newline"
+		}
+		value := rand.NormFloat64() * 1e6
+		sl.Set(key, value)
+		expected[key] = value
+	}
+
+	var buf bytes.Buffer
+	if err := sl.WriteRedisProto(&buf, "board:season 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := make(map[string]float64)
+	r := bufio.NewReader(&buf)
+	for {
+		args, err := readRESPCommand(r)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(args) != 4 || args[0] != "ZADD" || args[1] != "board:season 1" {
+			t.Fatalf("unexpected command %q", args)
+		}
+		score, err := strconv.ParseFloat(args[2], 64)
+		if err != nil {
+			t.Fatalf("unexpected score %q", args[2])
+		}
+		result[args[3]] = score
+	}
+
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d members, got %d", len(expected), len(result))
+	}
+	for key, value := range expected {
+		if result[key] != value {
+			t.Errorf("member %q: expected score %v, got %v", key, value, result[key])
+		}
+	}
+}
+
+func TestWriteRedisProtoNonNumeric(t *testing.T) {
+	sl := New[string, string]()
+	sl.Set("a", "b")
+
+	if err := sl.WriteRedisProto(io.Discard, "board"); !errors.Is(err, ErrNonNumericValue) {
+		t.Errorf("expected ErrNonNumericValue, got %v", err)
+	}
+}
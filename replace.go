@@ -0,0 +1,123 @@
+package ranklist
+
+// ReplaceFrom 用完整构建好的 other 原子地替换跳表的全部内容，用于蓝绿切换：在另一个跳表中从数据源重建榜单，
+// 再整体换入正在服务的跳表。在写锁内直接接管 other 的节点、字典和元数据，不复制任何条目，
+// 持有跳表指针的读操作要么看到完整的旧榜单，要么看到完整的新榜单，等待的时间与条目数量基本无关，
+// 不需要经历 Clear 再批量加载的整个过程。
+// other 的内容按原样接管，不再经过 WithValueGuard 和 WithZeroMeansDelete，超出容量的部分照常淘汰；
+// 时间戳按本跳表是否开启补齐或去掉。两个跳表值相同时的键顺序不同（例如 WithKeyCompare 不同）时，
+// 退回到用 other 的条目重建，结果相同，只是不再是 O(1) 的交换。
+// 替换按一次内容替换写入预写日志和外部存储，版本递增一次。
+// 之后 other 被清空并关闭，不能再使用：修改返回 ErrClosed 或不做任何修改，读取看到空跳表。
+// 任一跳表已经关闭时不做任何修改并返回 ErrClosed；other 与跳表相同时什么也不做。
+// 调用期间同时持有两个跳表的写锁，因此不能在两个跳表上同时以相反的方向调用
+// ReplaceFrom atomically replaces the whole content of the list with a fully built other, for blue/green swaps
+// where a board is rebuilt from its source of truth in a separate list and then swapped in for the live one.
+// The nodes, dictionary and metadata of other are taken over under the write lock without copying any entry,
+// so readers holding the list pointer see either the complete old board or the complete new one,
+// and the pause hardly depends on the number of entries, instead of going through a Clear and a bulk load.
+// The content of other is taken as is, without going through WithValueGuard and WithZeroMeansDelete,
+// while members past the capacity are evicted as usual; timestamps are filled in or dropped to match this list.
+// When the two lists order keys of equal values differently, e.g. through different WithKeyCompare functions,
+// it falls back to rebuilding from the entries of other, with the same result but no longer an O(1) swap.
+// The replacement is journaled and written through as one content replacement and bumps the version once.
+// Afterwards other is emptied and closed and must not be used anymore:
+// mutators return ErrClosed or change nothing, and reads see an empty list.
+// If either list is closed nothing changes and ErrClosed is returned; an other identical to the list is a no-op.
+// Both write locks are held during the call, so it must not run concurrently in opposite directions on the same two lists
+func (sl *RankList[K, V]) ReplaceFrom(other *RankList[K, V]) error {
+	if other == sl {
+		return nil
+	}
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return ErrClosed
+	}
+	other.Lock()
+	if other.closed {
+		other.Unlock()
+		sl.Unlock()
+		return ErrClosed
+	}
+	zones := sl.zoneKeys()
+
+	meta := other.meta
+	if sl.sameOrder(other) {
+		sl.adopt(other)
+		sl.hasPlaceholders = sl.hasPlaceholders || other.hasPlaceholders
+	} else {
+		sl.load(other.entries())
+	}
+	sl.meta = meta
+
+	// other 不再持有任何节点，关闭之后它的后台 goroutine 随之退出，预写日志的刷出错误与本跳表无关
+	// other no longer holds any node, closing it stops its background goroutines,
+	// and a flush error of its write-ahead log does not concern this list
+	other.reset()
+	_ = other.shutdown()
+	other.Unlock()
+
+	sl.evictOverflow()
+	sl.journalReset()
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	other.background.Wait()
+	fireThresholds(events)
+	return nil
+}
+
+// sameOrder 判断 other 的第 0 层在本跳表的排序规则下是否严格有序，调用方需持有两个跳表的锁
+// sameOrder reports whether level 0 of other is strictly ordered under the ordering of this list.
+// The caller must hold the locks of both lists
+func (sl *RankList[K, V]) sameOrder(other *RankList[K, V]) bool {
+	for curr := other.header.forward[0]; curr != nil && curr.forward[0] != nil; curr = curr.forward[0] {
+		if sl.compare(curr.data, curr.forward[0].data) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// adopt 接管 other 的跳表结构和字典，other 的排序必须与本跳表一致，调用方需持有两个跳表的写锁。
+// 节点在字典换入之前对 Get 不可见，因此时间戳和更新顺序的链接可以直接改写
+// adopt takes over the structure and dictionary of other, whose order must agree with this list.
+// The caller must hold the write locks of both lists.
+// Nodes are invisible to Get until the dictionary is swapped in, so their timestamps and update order links are written directly
+func (sl *RankList[K, V]) adopt(other *RankList[K, V]) {
+	times := sl.stamp()
+	for curr := other.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		if sl.recency == nil {
+			curr.recency = nil
+		}
+		switch {
+		case times == nil:
+			curr.times = nil
+		case curr.times == nil:
+			curr.times = times
+		}
+	}
+
+	dict := other.dict
+	switch {
+	case sl.noDict:
+		dict = nil
+	case dict == nil:
+		dict = sl.newDict(other.length)
+		for curr := other.header.forward[0]; curr != nil; curr = curr.forward[0] {
+			dict[curr.data.Key] = curr
+		}
+	}
+
+	sl.finger.invalidate()
+	sl.topCache.invalidate()
+	sl.gen++
+	sl.header, sl.tail = other.header, other.tail
+	sl.level, sl.length = other.level, other.length
+	sl.swapDict(dict)
+	sl.rebuildStale()
+	sl.buckets.rebuild(sl)
+	sl.recency.rebuild(sl)
+	sl.version.Add(1)
+}
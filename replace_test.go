@@ -0,0 +1,142 @@
+package ranklist
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// boardOf 创建包含 n 个键的跳表，所有键的值都是 value
+// boardOf creates a list of n keys that all hold value
+func boardOf(n int, value int, opts ...Option[string, int]) *RankList[string, int] {
+	sl := New(opts...)
+	for i := range n {
+		sl.Set(strconv.Itoa(i), value)
+	}
+	return sl
+}
+
+func TestReplaceFrom(t *testing.T) {
+	sl := boardOf(100, 1, WithCachedTop[string, int](3))
+	sl.Top(3)
+	next := boardOf(50, 2)
+	next.SetMeta("7", map[string]string{"team": "red"})
+	want := next.Entries()
+	version := sl.Version()
+
+	if err := sl.ReplaceFrom(next); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(sl.Entries(), want) {
+		t.Fatal("expected the content of the other list")
+	}
+	if value, ok := sl.Get("7"); !ok || value != 2 {
+		t.Fatalf("expected Get to see the new board, got %d, %v", value, ok)
+	}
+	if _, ok := sl.Get("70"); ok {
+		t.Fatal("expected keys of the old board to be gone")
+	}
+	if meta, _ := sl.GetMeta("7"); meta["team"] != "red" {
+		t.Fatal("expected the metadata of the other list to be taken over")
+	}
+	top := slices.Clone(want[len(want)-3:])
+	slices.Reverse(top)
+	if !slices.Equal(sl.Top(3), top) {
+		t.Fatalf("expected the cached top to be invalidated, got %v", sl.Top(3))
+	}
+	if sl.Version() != version+1 {
+		t.Fatalf("expected the version to be bumped once, got %d after %d", sl.Version(), version)
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 被接管的跳表为空且已关闭
+	// The list taken over is empty and closed
+	if !next.Closed() || next.Length() != 0 || next.Set("x", 1) {
+		t.Fatal("expected the other list to be emptied and closed")
+	}
+	if err := sl.ReplaceFrom(next); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed from a closed other list, got %v", err)
+	}
+	if err := sl.ReplaceFrom(sl); err != nil || sl.Length() != 50 {
+		t.Fatal("replacing a list with itself should change nothing")
+	}
+}
+
+func TestReplaceFromOptions(t *testing.T) {
+	// 键的顺序不同时退回到重建，时间戳按接收方补齐，超出容量的部分被淘汰
+	// A different key order falls back to a rebuild, timestamps follow the receiver and members past the capacity are evicted
+	reverse := func(a, b string) int { return strings.Compare(b, a) }
+	sl := New(
+		WithKeyCompare[string, int](reverse),
+		WithTimestamps[string, int](time.Now),
+		WithMaxSize[string, int](3),
+	)
+	next := New[string, int]()
+	next.SetBatch([]Entry[string, int]{{"a", 1}, {"b", 1}, {"c", 1}, {"d", 2}})
+
+	if err := sl.ReplaceFrom(next); err != nil {
+		t.Fatal(err)
+	}
+	if want := []Entry[string, int]{{"b", 1}, {"a", 1}, {"d", 2}}; !slices.Equal(sl.Entries(), want) {
+		t.Fatalf("expected %v, got %v", want, sl.Entries())
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if created, _, ok := sl.GetTimes("a"); !ok || created.IsZero() {
+		t.Fatal("expected the taken over entries to be timestamped")
+	}
+}
+
+func TestReplaceFromRacingReaders(t *testing.T) {
+	sl := boardOf(1000, 1)
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	var mixed error
+	var once sync.Once
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				// 每次读取看到的都必须是某一个完整的榜单：条目数与值一一对应，且所有值相同
+				// Every read must see one complete board: the size matches the value and all values are equal
+				entries := sl.Entries()
+				value := entries[0].Value
+				for _, entry := range entries {
+					if entry.Value != value || len(entries) != 1000*value {
+						once.Do(func() { mixed = errors.New("a read mixed two boards") })
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for round := 2; round <= 20; round++ {
+		value := 1 + round%2
+		if err := sl.ReplaceFrom(boardOf(1000*value, value)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(done)
+	wg.Wait()
+	if mixed != nil {
+		t.Fatal(mixed)
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
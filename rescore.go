@@ -0,0 +1,119 @@
+package ranklist
+
+// ApplyToAll 在一把写锁内把每个键的值替换为 fn(key, value)，用于赛季软重置等整体调整，例如所有分数减半。
+// monotonic 为 true 表示 fn 保持条目的顺序，此时只沿第 0 层遍历一次、原地改写节点的值，不移动任何节点；
+// 改写之前会校验新的值确实与原来的顺序一致，不一致（包括值相同时按键比较的顺序）时退回到重建。
+// monotonic 为 false 时收集全部新值，通过排序后的 O(n) 构建重建跳表。
+// 被 WithValueGuard 拒绝的键保留原值；开启 WithZeroMeansDelete 时结果为零值的键被删除，此时同样退回到重建。
+// 值改变的键条目版本递增、更新时间刷新，元数据保留；整体修改按一次内容替换写入预写日志和外部存储，版本只递增一次。
+// 返回是否原地完成
+// ApplyToAll replaces the value of every key with fn(key, value) under one write lock,
+// for wholesale adjustments such as a season soft reset halving every score.
+// monotonic true claims that fn preserves the order of the entries: level 0 is walked once and values are overwritten
+// in place without moving any node; the new values are verified to keep the old order first,
+// ties broken by key included, and any violation falls back to a rebuild.
+// With monotonic false all new values are collected and the list is rebuilt through the sorted O(n) build.
+// Keys rejected by WithValueGuard keep their old value; with WithZeroMeansDelete keys resulting in the zero value
+// are deleted, which falls back to a rebuild as well.
+// Keys whose value changed get their entry version bumped and their update time refreshed, metadata is kept;
+// the change is journaled and written through as one content replacement and bumps the version once.
+// It reports whether the update was done in place
+func (sl *RankList[K, V]) ApplyToAll(fn func(key K, value V) V, monotonic bool) bool {
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return false
+	}
+	zones := sl.zoneKeys()
+
+	values := make([]V, 0, sl.length)
+	changed, dropped := false, false
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		value := fn(curr.data.Key, curr.data.Value)
+		if sl.guardValue(curr.data.Value, value) != nil {
+			value = curr.data.Value
+		}
+		changed = changed || value != curr.data.Value
+		dropped = dropped || sl.zeroDeletes && value == ZeroValue[V]()
+		values = append(values, value)
+	}
+
+	inPlace := monotonic && !dropped && sl.keepsOrder(values)
+	switch {
+	case !changed:
+	case inPlace:
+		sl.rescore(values)
+	default:
+		sl.reload(values)
+	}
+	if changed {
+		sl.journalReset()
+	}
+	events := sl.zoneEvents(zones)
+	sl.Unlock()
+
+	fireThresholds(events)
+	return inPlace
+}
+
+// keepsOrder 判断按第 0 层顺序给出的新值是否保持条目原有的严格顺序，调用方需持有锁
+// keepsOrder reports whether new values given in level-0 order keep the strict order of the entries. The caller must hold the lock
+func (sl *RankList[K, V]) keepsOrder(values []V) bool {
+	var prev Entry[K, V]
+	i := 0
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		entry := Entry[K, V]{Key: curr.data.Key, Value: values[i]}
+		if i > 0 && sl.compare(prev, entry) >= 0 {
+			return false
+		}
+		prev = entry
+		i++
+	}
+	return true
+}
+
+// rescore 按第 0 层顺序原地改写每个节点的值，新值必须保持原有顺序，调用方需持有写锁。
+// Get 在字典锁内读取节点的值，因此在字典锁内修改
+// rescore overwrites the value of every node in level-0 order, the new values must keep the order.
+// The caller must hold the write lock. Get reads node values under the dictionary lock, so they are written under it
+func (sl *RankList[K, V]) rescore(values []V) {
+	sl.topCache.invalidate()
+	sl.version.Add(1)
+	sl.dictMu.Lock()
+	i := 0
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		if value := values[i]; value != curr.data.Value {
+			curr.version++
+			curr.times = sl.stamp().keepCreated(curr.times)
+			curr.data.Value = value
+		}
+		i++
+	}
+	sl.dictMu.Unlock()
+	sl.rebuildStale()
+	sl.buckets.rebuild(sl)
+	sl.recency.rebuild(sl)
+}
+
+// reload 用按第 0 层顺序给出的新值重建跳表，保留元数据、条目版本和创建时间，
+// 开启 WithZeroMeansDelete 时删除结果为零值的键，调用方需持有写锁
+// reload rebuilds the list from new values given in level-0 order, keeping metadata, entry versions and creation times;
+// with WithZeroMeansDelete keys resulting in the zero value are deleted. The caller must hold the write lock
+func (sl *RankList[K, V]) reload(values []V) {
+	states := sl.states()
+	entries := sl.entries()
+	kept := entries[:0]
+	for i, entry := range entries {
+		entry.Value = values[i]
+		if sl.zeroDeletes && entry.Value == ZeroValue[V]() {
+			delete(sl.meta, entry.Key)
+			continue
+		}
+		kept = append(kept, entry)
+	}
+
+	meta := sl.meta
+	sl.load(kept)
+	sl.meta = meta
+	sl.keepStates(states)
+}
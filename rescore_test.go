@@ -0,0 +1,136 @@
+package ranklist
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// rescoreFixture 创建一个带有值相同条目的跳表及其期望内容
+// rescoreFixture creates a list with tied entries together with its expected content
+func rescoreFixture(opts ...Option[string, int]) (*RankList[string, int], map[string]int) {
+	sl := New(opts...)
+	model := make(map[string]int)
+	for i := range 1000 {
+		key := strconv.Itoa(i)
+		value := rand.IntN(200) - 100
+		sl.Set(key, value)
+		model[key] = value
+	}
+	return sl, model
+}
+
+func TestApplyToAllMonotonic(t *testing.T) {
+	sl, model := rescoreFixture(WithCachedTop[string, int](5))
+	top := sl.Top(5)
+	sl.SetMeta("7", map[string]string{"team": "red"})
+	_, before, _ := sl.GetVersioned("7")
+	version := sl.Version()
+
+	double := func(key string, value int) int { return value * 2 }
+	if !sl.ApplyToAll(double, true) {
+		t.Fatal("an order-preserving function should be applied in place")
+	}
+	for key := range model {
+		model[key] *= 2
+	}
+	requireModel(t, sl, model)
+	if sl.Version() != version+1 {
+		t.Fatalf("expected the version to be bumped once, got %d after %d", sl.Version(), version)
+	}
+	if _, after, _ := sl.GetVersioned("7"); model["7"] != 0 && after != before+1 {
+		t.Fatalf("expected the entry version to be bumped, got %d after %d", after, before)
+	}
+	for i := range top {
+		top[i].Value *= 2
+	}
+	if !slices.Equal(sl.Top(5), top) {
+		t.Fatalf("expected the cached top %v, got %v", top, sl.Top(5))
+	}
+	if meta, _ := sl.GetMeta("7"); meta["team"] != "red" {
+		t.Fatal("metadata should survive ApplyToAll")
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyToAllRebuild(t *testing.T) {
+	sl, model := rescoreFixture()
+	negate := func(key string, value int) int { return -value }
+	if sl.ApplyToAll(negate, false) {
+		t.Fatal("a function without the monotonic claim should rebuild")
+	}
+	for key := range model {
+		model[key] = -model[key]
+	}
+	requireModel(t, sl, model)
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyToAllFalseClaim(t *testing.T) {
+	for name, fn := range map[string]func(key string, value int) int{
+		// 顺序整体反转
+		// The order is reversed as a whole
+		"negate": func(key string, value int) int { return -value },
+
+		// 按值保序但把所有值压成同一个，值相同时按键比较的顺序改变
+		// Order-preserving by value but collapsing every value into one, which changes the order of ties by key
+		"collapse": func(key string, value int) int { return 0 },
+
+		// 只有一个键越过了它的邻居
+		// A single key jumps past its neighbors
+		"one key": func(key string, value int) int {
+			if key == "3" {
+				return value + 1000
+			}
+			return value
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			sl, model := rescoreFixture()
+			if sl.ApplyToAll(fn, true) {
+				t.Fatal("a false monotonic claim should fall back to a rebuild")
+			}
+			for key, value := range model {
+				model[key] = fn(key, value)
+			}
+			requireModel(t, sl, model)
+			if err := sl.Check(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestApplyToAllOptions(t *testing.T) {
+	// 被拒绝的键保留原值，结果为零值的键被删除
+	// Rejected keys keep their old value and keys resulting in zero are deleted
+	errNegative := errors.New("negative")
+	sl := New(
+		WithZeroMeansDelete[string, int](),
+		WithValueGuard[string, int](func(old, new int) error {
+			if new < 0 {
+				return errNegative
+			}
+			return nil
+		}),
+		WithCachedTop[string, int](2),
+	)
+	sl.SetBatch([]Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}, {"d", 4}})
+	sl.Top(2)
+
+	if sl.ApplyToAll(func(key string, value int) int { return value - 2 }, true) {
+		t.Fatal("deleting keys should fall back to a rebuild")
+	}
+	if want := []Entry[string, int]{{"a", 1}, {"c", 1}, {"d", 2}}; !slices.Equal(sl.Entries(), want) {
+		t.Fatalf("expected %v, got %v", want, sl.Entries())
+	}
+	if want := []Entry[string, int]{{"d", 2}, {"c", 1}}; !slices.Equal(sl.Top(2), want) {
+		t.Fatalf("expected the cached top %v, got %v", want, sl.Top(2))
+	}
+}
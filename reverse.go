@@ -0,0 +1,98 @@
+package ranklist
+
+// RevRange 按值从高到低获取指定倒序排名区间内的榜单项（不包含END），倒序排名 1 是值最大的条目，start 小于 1 时按 1 处理。
+// 从尾部沿后向指针遍历，耗时 O(log n + k)
+// RevRange retrieves the entries within the specified reverse rank range (excluding END) from the highest value down,
+// reverse rank 1 is the entry with the largest value and a start below 1 is treated as 1.
+// It walks the backward pointers in O(log n + k)
+func (sl *RankList[K, V]) RevRange(start int, end int) []Entry[K, V] {
+	sl.vars.add(opRange)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	entries := make([]Entry[K, V], 0)
+	sl.walkRevRange(start, end, func(node *Node[K, V]) {
+		if !sl.hidden(node) {
+			entries = append(entries, node.data)
+		}
+	})
+	return entries
+}
+
+// Top 返回值最大的 n 个条目，按值从高到低排列。开启 WithCachedTop 且 n 不超过缓存大小时从缓存复制结果
+// Top returns the n entries with the largest values, from the highest value down.
+// With WithCachedTop and n within the cached size the result is copied from the cache
+func (sl *RankList[K, V]) Top(n int) []Entry[K, V] {
+	if sl.topCache != nil && n >= 1 && n <= sl.topCache.n {
+		sl.vars.add(opRange)
+		sl.RLock()
+		defer sl.RUnlock()
+		return sl.topCache.top(sl, n)
+	}
+
+	sl.vars.add(opRange)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	entries := make([]Entry[K, V], 0)
+	sl.walkTop(n, func(node *Node[K, V]) {
+		entries = append(entries, node.data)
+	})
+	return entries
+}
+
+// walkTop 从值最大的条目开始对 n 个不被跳过的节点按倒序调用 fn，占位条目默认被跳过，调用方需持有锁
+// walkTop calls fn for n nodes from the highest value down, skipping placeholders unless they are visible.
+// The caller must hold the lock
+func (sl *RankList[K, V]) walkTop(n int, fn func(node *Node[K, V])) {
+	for node := sl.tail; node != nil && n > 0; node = node.backward {
+		if !sl.hidden(node) {
+			fn(node)
+			n--
+		}
+	}
+}
+
+// walkRevRange 按倒序排名对指定区间内的每个节点调用 fn，调用方需持有锁
+// walkRevRange calls fn for every node within the specified reverse rank range in reverse rank order.
+// The caller must hold the lock
+func (sl *RankList[K, V]) walkRevRange(start int, end int, fn func(node *Node[K, V])) {
+	if start < 1 {
+		start = 1
+	}
+
+	node := sl.tail
+	if start > 1 {
+		node = sl.byRank(sl.length - start + 1)
+	}
+	for rank := start; node != nil && rank < end; rank++ {
+		fn(node)
+		node = node.backward
+	}
+}
+
+// RevRangeByScore 按值从高到低返回值在闭区间 [min, max] 内的条目，最多返回 limit 个，limit 为 0 时不限制数量。
+// 从上边界沿后向指针向下遍历，值相同的条目的顺序与 RangeByScore 完全相反，与 RangeByScore 一样跳过 Reserve 的占位条目，耗时 O(log n + k)
+// RevRangeByScore returns the entries whose value lies within the closed interval [min, max] from the highest value down,
+// at most limit of them, a limit of 0 means no limit.
+// It walks the backward pointers down from the upper bound, tied entries come out in exactly the reverse order
+// of RangeByScore and placeholders inserted by Reserve are skipped like RangeByScore does, so it runs in O(log n + k)
+func (sl *RankList[K, V]) RevRangeByScore(max V, min V, limit int) []Entry[K, V] {
+	sl.vars.add(opRange)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	start, end := sl.scoreWindow(ScoreRange[V]{Min: min, Max: max})
+	n := end - start
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	entries := make([]Entry[K, V], 0, n)
+	for node, rank := sl.byRank(end-1), end-1; node != nil && rank >= start && len(entries) < n; rank-- {
+		if !sl.hidden(node) {
+			entries = append(entries, node.data)
+		}
+		node = node.backward
+	}
+	return entries
+}
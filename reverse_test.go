@@ -0,0 +1,204 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// requireBackward 检查后向指针与第 0 层前向指针互为镜像，并且尾节点是最后一个节点
+// requireBackward checks that backward pointers mirror the level-0 forwards and that the tail is the last node
+func requireBackward[K Ordered, V Ordered](t *testing.T, sl *RankList[K, V]) {
+	t.Helper()
+
+	var prev *Node[K, V]
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		if curr.backward != prev {
+			t.Fatalf("Key %v: backward pointer does not point at its predecessor", curr.data.Key)
+		}
+		prev = curr
+	}
+	if sl.tail != prev {
+		t.Fatalf("tail does not point at the last node")
+	}
+}
+
+func TestRevRange(t *testing.T) {
+	sl := New[string, int]()
+	for i := 1; i <= 10; i++ {
+		sl.Set("k"+strconv.Itoa(i), i*10)
+	}
+	requireBackward(t, sl)
+
+	tests := []struct {
+		start, end int
+		expected   []int
+	}{
+		{1, 4, []int{100, 90, 80}},
+		{0, 3, []int{100, 90}},
+		{9, 20, []int{20, 10}},
+		{10, 11, []int{10}},
+		{11, 20, []int{}},
+		{3, 3, []int{}},
+	}
+	for _, tt := range tests {
+		values := []int{}
+		for _, entry := range sl.RevRange(tt.start, tt.end) {
+			values = append(values, entry.Value)
+		}
+		if !slices.Equal(values, tt.expected) {
+			t.Errorf("RevRange(%d, %d): expected %v, got %v", tt.start, tt.end, tt.expected, values)
+		}
+	}
+
+	forward := sl.Range(1, sl.Length()+1)
+	slices.Reverse(forward)
+	if !slices.Equal(sl.RevRange(1, sl.Length()+1), forward) {
+		t.Errorf("RevRange over the whole list should mirror Range")
+	}
+}
+
+func TestTop(t *testing.T) {
+	sl := New[string, int]()
+	if len(sl.Top(3)) != 0 {
+		t.Errorf("Top of an empty list should be empty")
+	}
+
+	sl.Set("a", 1)
+	sl.Set("b", 3)
+	sl.Set("c", 2)
+	expected := []Entry[string, int]{{Key: "b", Value: 3}, {Key: "c", Value: 2}}
+	if got := sl.Top(2); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if len(sl.Top(10)) != 3 || len(sl.Top(0)) != 0 || len(sl.Top(-1)) != 0 {
+		t.Errorf("Top should clamp n to the list length")
+	}
+}
+
+func TestBackwardSingleElement(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("only", 1)
+	requireBackward(t, sl)
+	if sl.tail == nil || sl.tail.data.Key != "only" || sl.tail.backward != nil {
+		t.Fatalf("single element should be the tail with no backward pointer")
+	}
+	if got := sl.Top(1); len(got) != 1 || got[0].Key != "only" {
+		t.Errorf("unexpected Top %v", got)
+	}
+
+	sl.Del("only")
+	requireBackward(t, sl)
+	if sl.tail != nil {
+		t.Errorf("tail should be nil once the list is empty")
+	}
+	if len(sl.Top(1)) != 0 {
+		t.Errorf("Top of an emptied list should be empty")
+	}
+}
+
+func TestBackwardTailEdges(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+
+	// 在尾部插入
+	// Insert at the tail
+	sl.Set("c", 3)
+	requireBackward(t, sl)
+	if sl.tail.data.Key != "c" {
+		t.Errorf("expected c to be the tail, got %v", sl.tail.data.Key)
+	}
+
+	// 删除尾部
+	// Delete the tail
+	sl.Del("c")
+	requireBackward(t, sl)
+	if sl.tail.data.Key != "b" {
+		t.Errorf("expected b to be the tail, got %v", sl.tail.data.Key)
+	}
+
+	// 把尾部移到头部，再把头部移到尾部
+	// Move the tail to the head, then the head to the tail
+	sl.Set("b", 0)
+	requireBackward(t, sl)
+	sl.Set("b", 5)
+	requireBackward(t, sl)
+	if sl.tail.data.Key != "b" {
+		t.Errorf("expected b to be the tail, got %v", sl.tail.data.Key)
+	}
+
+	sl.Clear()
+	requireBackward(t, sl)
+}
+
+func TestBackwardChurn(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 5000; i++ {
+		key := rand.IntN(300)
+		if rand.IntN(3) == 0 {
+			sl.Del(key)
+		} else {
+			sl.Set(key, rand.IntN(50))
+		}
+	}
+	requireBackward(t, sl)
+
+	sl.Restore(sl.Snapshot())
+	requireBackward(t, sl)
+}
+
+func TestRevRangeByScore(t *testing.T) {
+	sl := New[string, int]()
+	for i := 0; i < 30; i++ {
+		sl.Set("k"+strconv.Itoa(i), i%6*1000)
+	}
+
+	forward := sl.RangeByScore(2000, 4000)
+	slices.Reverse(forward)
+	if got := sl.RevRangeByScore(4000, 2000, 0); !slices.Equal(got, forward) {
+		t.Fatalf("expected the exact reverse of RangeByScore\nexpected %v\ngot      %v", forward, got)
+	}
+	if got := sl.RevRangeByScore(4000, 2000, 7); !slices.Equal(got, forward[:7]) {
+		t.Fatalf("limit 7: expected %v, got %v", forward[:7], got)
+	}
+	if got := sl.RevRangeByScore(4000, 2000, 100); len(got) != len(forward) {
+		t.Fatalf("a limit past the band should return the whole band, got %d entries", len(got))
+	}
+	if got := sl.RevRangeByScore(5500, 4500, 0); len(got) != 5 || got[0].Value != 5000 {
+		t.Fatalf("expected the five top entries, got %v", got)
+	}
+	if got := sl.RevRangeByScore(1000, 2000, 0); len(got) != 0 {
+		t.Fatalf("an inverted band should be empty, got %v", got)
+	}
+	if got := sl.RevRangeByScore(-1, -10, 0); len(got) != 0 {
+		t.Fatalf("a band below every value should be empty, got %v", got)
+	}
+}
+
+func TestRevRangeByScoreLimit(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+
+	// 超大的 limit 不决定分配的大小 / A huge limit does not size the allocation
+	if got := sl.RevRangeByScore(10, 0, 1<<40); !slices.Equal(got, []Entry[string, int]{{"a", 1}}) {
+		t.Fatalf("unexpected result %v", got)
+	}
+}
+
+func TestRevRangeByScorePlaceholders(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	sl.Reserve("p", 2)
+	sl.Set("b", 3)
+
+	forward := sl.RangeByScore(0, 10)
+	slices.Reverse(forward)
+	if got := sl.RevRangeByScore(10, 0, 0); !slices.Equal(got, forward) {
+		t.Fatalf("expected %v, got %v", forward, got)
+	}
+	if got := sl.RevRangeByScore(10, 0, 2); !slices.Equal(got, forward) {
+		t.Fatalf("the limit should count visible entries only, got %v", got)
+	}
+}
@@ -0,0 +1,83 @@
+package ranklist
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// SampleWeighted 随机抽取 n 个互不相同的条目，排名 r 被抽中的概率与 weight(r) 成正比，例如 1/r 偏向排名靠前的条目。
+// 排名与 Rank 相同，排名 1 是值最小的条目。weight 返回非正数、NaN 或无穷大的排名永远不会被抽中，
+// 可抽中的条目不足 n 个时全部返回。结果按抽中的顺序排列。
+// 实现方式：先在锁外对每个排名调用一次 weight 构建累积权重，再在一把读锁内按累积权重二分抽取排名，
+// 通过跨度下降定位条目，重复的排名被拒绝后重新抽取，这与每次从尚未抽中的条目中按权重抽取的分布相同；
+// 连续拒绝过多时会去掉已抽中的排名重建累积权重，因此总能结束。耗时 O(n + k log n)，k 是抽取次数。
+// 构建累积权重与抽取之间跳表可能发生变化，超出当前长度的排名同样被拒绝
+// SampleWeighted draws n distinct entries where the probability of rank r is proportional to weight(r),
+// e.g. 1/r favors the entries ranked first. Ranks follow Rank, rank 1 being the entry with the smallest value.
+// Ranks whose weight is not positive, NaN or infinite are never drawn,
+// and when fewer than n entries can be drawn all of them are returned. Entries come out in the order they were drawn.
+// The cumulative weight is built outside the lock by calling weight once per rank,
+// then ranks are drawn by binary searching it and resolved through the span descent under one read lock;
+// a repeated rank is rejected and drawn again, which matches drawing each time by weight among the entries not yet drawn,
+// and after too many rejections in a row the cumulative weight is rebuilt without the drawn ranks, so it always terminates.
+// It costs O(n + k log n) for k draws.
+// The list may change between building the cumulative weight and drawing, ranks beyond the current length are rejected too
+func (sl *RankList[K, V]) SampleWeighted(n int, weight func(rank int) float64) []Entry[K, V] {
+	return sl.SampleWeightedRand(n, weight, nil)
+}
+
+// SampleWeightedRand 与 SampleWeighted 相同，但从 r 取随机数，传入固定种子的 r 可以得到可复现的结果。
+// r 为 nil 时使用 math/rand/v2 的全局随机源
+// SampleWeightedRand behaves like SampleWeighted but takes its random numbers from r,
+// a seeded r makes the result reproducible. A nil r uses the global source of math/rand/v2
+func (sl *RankList[K, V]) SampleWeightedRand(n int, weight func(rank int) float64, r *rand.Rand) []Entry[K, V] {
+	float := rand.Float64
+	if r != nil {
+		float = r.Float64
+	}
+
+	weights := make([]float64, sl.Length())
+	for i := range weights {
+		if w := weight(i + 1); w > 0 && !math.IsInf(w, 1) {
+			weights[i] = w
+		}
+	}
+
+	sl.RLock()
+	defer sl.RUnlock()
+
+	weights = weights[:min(len(weights), sl.length)]
+	var cumulative []float64
+	drawable := 0
+	rebuild := func(drawn map[int]struct{}) {
+		cumulative, drawable = cumulative[:0], 0
+		total := 0.0
+		for i, w := range weights {
+			if _, ok := drawn[i+1]; !ok && w > 0 {
+				total += w
+				drawable++
+			}
+			cumulative = append(cumulative, total)
+		}
+	}
+	rebuild(nil)
+
+	entries := make([]Entry[K, V], 0, max(min(n, drawable), 0))
+	drawn := make(map[int]struct{}, cap(entries))
+	for rejected := 0; len(entries) < cap(entries); {
+		total := cumulative[len(cumulative)-1]
+		u := float() * total
+		rank := sort.Search(len(cumulative), func(i int) bool { return cumulative[i] > u }) + 1
+		if _, dup := drawn[rank]; dup || rank > len(cumulative) {
+			if rejected++; rejected > 16+cap(entries) {
+				rebuild(drawn)
+				rejected = 0
+			}
+			continue
+		}
+		drawn[rank] = struct{}{}
+		entries = append(entries, sl.byRank(rank).data)
+	}
+	return entries
+}
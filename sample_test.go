@@ -0,0 +1,109 @@
+package ranklist
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestSampleWeighted(t *testing.T) {
+	sl := New[string, int]()
+	for i := 1; i <= 100; i++ {
+		sl.Set(strconv.Itoa(i), i)
+	}
+	inverse := func(rank int) float64 { return 1 / float64(rank) }
+
+	// 相同种子的随机源给出相同的结果
+	// Sources with the same seed give the same result
+	a := sl.SampleWeightedRand(10, inverse, rand.New(rand.NewPCG(1, 2)))
+	b := sl.SampleWeightedRand(10, inverse, rand.New(rand.NewPCG(1, 2)))
+	if len(a) != 10 || !slices.Equal(a, b) {
+		t.Fatalf("expected reproducible samples, got %v and %v", a, b)
+	}
+	seen := make(map[string]bool)
+	for _, entry := range a {
+		if seen[entry.Key] {
+			t.Fatalf("entry %v drawn twice", entry)
+		}
+		seen[entry.Key] = true
+	}
+
+	// 按 1/r 加权时排名 1 到 10 被抽中的次数应当远多于排名 91 到 100
+	// Weighted by 1/r, ranks 1 to 10 should be drawn far more often than ranks 91 to 100
+	r := rand.New(rand.NewPCG(3, 4))
+	counts := make([]int, 101)
+	for i := 0; i < 20000; i++ {
+		entry := sl.SampleWeightedRand(1, inverse, r)[0]
+		rank, _ := sl.Rank(entry.Key)
+		counts[rank]++
+	}
+	low, high := 0, 0
+	for rank := 1; rank <= 10; rank++ {
+		low += counts[rank]
+		high += counts[rank+90]
+	}
+	if low < 5*high {
+		t.Fatalf("expected a strong bias towards the first ranks, got %d vs %d", low, high)
+	}
+	// 排名 1 的期望频率为 1/H(100) ≈ 0.193
+	// The expected frequency of rank 1 is 1/H(100) ≈ 0.193
+	if freq := float64(counts[1]) / 20000; math.Abs(freq-0.193) > 0.02 {
+		t.Fatalf("expected rank 1 about 19.3%% of the time, got %.3f", freq)
+	}
+}
+
+func TestSampleWeightedExhaustive(t *testing.T) {
+	sl := New[int, int]()
+	for i := 1; i <= 50; i++ {
+		sl.Set(i, i)
+	}
+
+	// 权重极度倾斜时仍然能抽完全部条目
+	// All entries can still be drawn under an extremely skewed weight
+	steep := func(rank int) float64 { return math.Pow(float64(rank), -12) }
+	entries := sl.SampleWeightedRand(100, steep, rand.New(rand.NewPCG(5, 6)))
+	if len(entries) != 50 {
+		t.Fatalf("expected all 50 entries, got %d", len(entries))
+	}
+	keys := make([]int, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+	slices.Sort(keys)
+	if keys = slices.Compact(keys); len(keys) != 50 {
+		t.Fatalf("expected 50 distinct entries, got %d", len(keys))
+	}
+
+	// 权重非正、NaN 或无穷大的排名不会被抽中
+	// Ranks with a weight that is not positive, NaN or infinite are never drawn
+	odd := func(rank int) float64 {
+		switch rank % 4 {
+		case 0:
+			return 0
+		case 1:
+			return math.NaN()
+		case 2:
+			return math.Inf(1)
+		default:
+			return 1
+		}
+	}
+	entries = sl.SampleWeighted(50, odd)
+	if len(entries) != 12 {
+		t.Fatalf("expected the 12 ranks with a usable weight, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if rank, _ := sl.Rank(entry.Key); rank%4 != 3 {
+			t.Fatalf("rank %d should never be drawn", rank)
+		}
+	}
+
+	if entries := New[int, int]().SampleWeighted(3, steep); len(entries) != 0 {
+		t.Fatalf("an empty list should give no samples, got %v", entries)
+	}
+	if entries := sl.SampleWeighted(0, steep); len(entries) != 0 {
+		t.Fatalf("n = 0 should give no samples, got %v", entries)
+	}
+}
@@ -0,0 +1,332 @@
+package ranklist
+
+import "reflect"
+
+// TieCount 返回与键的值相同的条目数量（包括键自身），键不存在时返回 false。
+// 占位条目与 CountByScore 相同默认不计入，但键自身总是计入。
+// 通过两次跨度下降分别定位该值的首尾位置，时间复杂度为 O(log n)，开启 WithValueBuckets 时为 O(1)；
+// 用过 Reserve 的跳表还需逐个检查并列条目的占位标记
+// TieCount returns the number of entries sharing the value of key (including the key itself),
+// returns false if the key does not exist. Placeholders are left out by default like in CountByScore,
+// though the key itself always counts.
+// It locates the first and last position of the value with two span descents, so it runs in O(log n),
+// O(1) with WithValueBuckets; a list that has used Reserve also checks the placeholder mark of every tied entry
+func (sl *RankList[K, V]) TieCount(key K) (int, bool) {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	node, exists := sl.lookup(key)
+	if !exists {
+		return 0, false
+	}
+	value := node.data.Value
+	var first *Node[K, V]
+	n := 0
+	if sl.buckets.ready() {
+		i, _ := sl.buckets.index(value)
+		first, n = sl.buckets.first[i], sl.buckets.count[i]
+	} else {
+		before, rank := sl.descendBefore(value, false)
+		first, n = before.forward[0], sl.countBefore(value, true)-rank
+	}
+	ties := sl.countVisible(first, n)
+	if sl.hidden(node) {
+		ties++
+	}
+	return ties, true
+}
+
+// CountByScore 返回值在闭区间 [min, max] 内的条目数量，min 大于 max 时返回 0。
+// 占位条目与 RangeByScore 相同默认不计入，因此结果总是等于 RangeByScore 返回的条目数。
+// 通过两次跨度下降完成，耗时 O(log n)，开启 WithValueBuckets 时为 O(log maxValue)；
+// 用过 Reserve 的跳表还需沿区间逐个检查占位标记，额外耗时 O(区间大小)
+// CountByScore returns the number of entries whose value lies within the closed interval [min, max],
+// 0 when min is greater than max. Placeholders are left out by default like in RangeByScore,
+// so the result always equals the number of entries RangeByScore returns.
+// It takes two span descents in O(log n), O(log maxValue) with WithValueBuckets;
+// a list that has used Reserve also checks the placeholder mark along the window, in O(window size) more
+func (sl *RankList[K, V]) CountByScore(min V, max V) int {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	r := ScoreRange[V]{Min: min, Max: max}
+	if sl.buckets.ready() {
+		lo, hi := sl.buckets.window(r)
+		n := sl.buckets.countWindow(lo, hi)
+		if n == 0 {
+			return 0
+		}
+		return sl.countVisible(sl.buckets.firstFrom(lo), n)
+	}
+	start, end := sl.scoreWindow(r)
+	if end == start {
+		return 0
+	}
+	return sl.countVisible(sl.byRank(start), end-start)
+}
+
+// DenseRank 返回键的密集排名，即值小于它的不同值的数量加 1，值相同的键排名相同且排名之间没有空缺，键不存在时返回 false。
+// 只由占位条目持有的值默认不计入，与 ValueCounts 相同。
+// 沿第 0 层数出较小的不同值，耗时 O(Rank)，开启 WithValueBuckets 且跳表中没有被跳过的占位条目时为 O(log maxValue)
+// DenseRank returns the dense rank of key, the number of distinct values below its value plus 1,
+// so tied keys share a rank and ranks have no gaps; false if the key does not exist.
+// Values held only by placeholders are left out by default, like in ValueCounts.
+// It counts the smaller distinct values along level 0 in O(Rank),
+// O(log maxValue) with WithValueBuckets when the list holds no skipped placeholders
+func (sl *RankList[K, V]) DenseRank(key K) (int, bool) {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	node, exists := sl.lookup(key)
+	if !exists {
+		return 0, false
+	}
+	value := node.data.Value
+	if sl.buckets.ready() && !sl.mayHide() {
+		i, _ := sl.buckets.index(value)
+		return sl.buckets.present.sum(i) + 1, true
+	}
+	rank := 1
+	visible := false
+	for curr := sl.header.forward[0]; curr != nil && sl.valueLess(curr.data.Value, value); curr = curr.forward[0] {
+		visible = visible || !sl.hidden(curr)
+		if next := curr.forward[0]; next == nil || !sl.sameValue(next.data.Value, curr.data.Value) {
+			if visible {
+				rank++
+			}
+			visible = false
+		}
+	}
+	return rank, true
+}
+
+// countBefore 返回值小于 value 的条目数量，inclusive 为 true 时包括等于 value 的条目，调用方需持有锁
+// countBefore returns the number of entries whose value is less than value,
+// entries equal to value are included when inclusive is true. The caller must hold the lock
+func (sl *RankList[K, V]) countBefore(value V, inclusive bool) int {
+	_, rank := sl.descendBefore(value, inclusive)
+	return rank
+}
+
+// descendBefore 下降到最后一个值小于 value 的节点并返回它及其排名，inclusive 为 true 时包括等于 value 的节点。
+// 不存在这样的节点时返回头节点和 0，调用方需持有锁
+// descendBefore descends to the last node whose value is less than value and returns it with its rank,
+// nodes equal to value are included when inclusive is true.
+// The header and 0 are returned when there is no such node. The caller must hold the lock
+func (sl *RankList[K, V]) descendBefore(value V, inclusive bool) (*Node[K, V], int) {
+	rank := 0
+	curr := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for curr.forward[i] != nil &&
+			(sl.valueLess(curr.forward[i].data.Value, value) || (inclusive && sl.sameValue(curr.forward[i].data.Value, value))) {
+			rank += curr.forward[i].span[i]
+			curr = curr.forward[i]
+		}
+	}
+	return curr, rank
+}
+
+// countLess 返回排序位置严格位于 entry 之前的条目数量，调用方需持有锁
+// countLess returns the number of entries that sort strictly before entry, the caller must hold the lock
+func (sl *RankList[K, V]) countLess(entry Entry[K, V]) int {
+	rank := 0
+	curr := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for curr.forward[i] != nil && sl.compare(curr.forward[i].data, entry) < 0 {
+			rank += curr.forward[i].span[i]
+			curr = curr.forward[i]
+		}
+	}
+	return rank
+}
+
+// ScoreRange 描述一个值区间，MinExclusive 和 MaxExclusive 为 true 时对应的边界为开区间，
+// 例如 ScoreRange{Min: 100, Max: 200, MinExclusive: true} 表示 (100, 200]
+// ScoreRange describes an interval of values, MinExclusive and MaxExclusive make the matching bound open,
+// e.g. ScoreRange{Min: 100, Max: 200, MinExclusive: true} is (100, 200]
+type ScoreRange[V Ordered] struct {
+	Min, Max     V
+	MinExclusive bool
+	MaxExclusive bool
+}
+
+// RangeByScore 按排名顺序返回值在闭区间 [min, max] 内的条目，min 大于 max 时返回空切片
+// RangeByScore returns the entries whose value lies within the closed interval [min, max] in rank order,
+// an empty slice is returned when min is greater than max
+func (sl *RankList[K, V]) RangeByScore(min V, max V) []Entry[K, V] {
+	return sl.RangeByScoreOpt(ScoreRange[V]{Min: min, Max: max})
+}
+
+// RangeByScoreOpt 按排名顺序返回值在区间 r 内的条目，边界可以分别为开或闭。
+// 两个边界各通过一次跨度下降定位，开边界会跳过所有与边界值相同的条目，耗时 O(log n + k)，开启 WithValueBuckets 时为 O(log maxValue + k)
+// RangeByScoreOpt returns the entries whose value lies within r in rank order, each bound may be open or closed.
+// Each bound is located with one span descent, an open bound skips every entry tied with the bound value,
+// so it runs in O(log n + k), O(log maxValue + k) with WithValueBuckets
+func (sl *RankList[K, V]) RangeByScoreOpt(r ScoreRange[V]) []Entry[K, V] {
+	sl.vars.add(opRange)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	if sl.buckets.ready() {
+		lo, hi := sl.buckets.window(r)
+		n := sl.buckets.countWindow(lo, hi)
+		entries := make([]Entry[K, V], 0, n)
+		if n == 0 {
+			return entries
+		}
+		for curr := sl.buckets.firstFrom(lo); n > 0; curr, n = curr.forward[0], n-1 {
+			if !sl.hidden(curr) {
+				entries = append(entries, curr.data)
+			}
+		}
+		return entries
+	}
+	start, end := sl.scoreWindow(r)
+	return sl.appendRange(make([]Entry[K, V], 0, sl.rangeSize(start, end)), start, end)
+}
+
+// scoreWindow 将值区间转换为排名区间 [start, end)，调用方需持有锁
+// scoreWindow converts an interval of values to the rank range [start, end), the caller must hold the lock
+func (sl *RankList[K, V]) scoreWindow(r ScoreRange[V]) (int, int) {
+	start := sl.countBefore(r.Min, r.MinExclusive) + 1
+	end := sl.countBefore(r.Max, !r.MaxExclusive) + 1
+	return start, max(start, end)
+}
+
+// DistinctValues 在一把读锁内遍历第 0 层一次，返回 ValueCounts 给出的不同值的数量，
+// 开启 WithValueBuckets 且跳表中没有被跳过的占位条目时不遍历，耗时 O(1)
+// DistinctValues walks level 0 once under one read lock and returns the number of distinct values ValueCounts reports,
+// without the walk in O(1) with WithValueBuckets when the list holds no skipped placeholders
+func (sl *RankList[K, V]) DistinctValues() int {
+	sl.RLock()
+	if sl.buckets.ready() && !sl.mayHide() {
+		defer sl.RUnlock()
+		return sl.buckets.distinct
+	}
+	sl.RUnlock()
+
+	n := 0
+	sl.ValueCounts(func(V, int) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// ValueCounts 在一把读锁内遍历第 0 层一次，按值从小到大对每个不同的值调用 fn，count 是持有该值的条目数。
+// 占位条目与 CountByScore 相同默认不计入，只由占位条目持有的值不会出现。
+// fn 返回 false 时停止遍历。fn 在读锁内调用，不能再调用跳表的写方法
+// ValueCounts walks level 0 once under one read lock and calls fn for every distinct value in ascending order,
+// count being the number of entries holding it. Placeholders are left out by default like in CountByScore,
+// and values held only by placeholders are not reported. The walk stops when fn returns false.
+// fn runs under the read lock and must not call the write methods of the list
+func (sl *RankList[K, V]) ValueCounts(fn func(value V, count int) bool) {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	curr := sl.header.forward[0]
+	for curr != nil {
+		value, count := curr.data.Value, 0
+		for ; curr != nil && sl.sameValue(curr.data.Value, value); curr = curr.forward[0] {
+			if !sl.hidden(curr) {
+				count++
+			}
+		}
+		if count > 0 && !fn(value, count) {
+			return
+		}
+	}
+}
+
+// KeysWithValue 按排名顺序（即值相同时的键顺序）返回值恰好为 value 的所有键，没有时返回空切片。
+// 占位条目与 RangeByScore 相同默认被跳过。
+// 通过一次跨度下降定位第一个该值的条目，再沿第 0 层收集到值改变为止，耗时 O(log n + k)，开启 WithValueBuckets 时为 O(k)
+// KeysWithValue returns every key holding exactly value in rank order, which is the tie-break order of keys,
+// an empty slice when nobody holds it. Placeholders are skipped by default like in RangeByScore.
+// One span descent locates the first entry with the value, then level 0 is walked until the value changes,
+// so it runs in O(log n + k), O(k) with WithValueBuckets
+func (sl *RankList[K, V]) KeysWithValue(value V) []K {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	if sl.buckets.ready() {
+		i, ok := sl.buckets.index(value)
+		if !ok || sl.buckets.count[i] == 0 {
+			return []K{}
+		}
+		keys := make([]K, 0, sl.buckets.count[i])
+		for curr, n := sl.buckets.first[i], sl.buckets.count[i]; n > 0; curr, n = curr.forward[0], n-1 {
+			if !sl.hidden(curr) {
+				keys = append(keys, curr.data.Key)
+			}
+		}
+		return keys
+	}
+	keys := make([]K, 0)
+	before, _ := sl.descendBefore(value, false)
+	for curr := before.forward[0]; curr != nil && sl.sameValue(curr.data.Value, value); curr = curr.forward[0] {
+		if !sl.hidden(curr) {
+			keys = append(keys, curr.data.Key)
+		}
+	}
+	return keys
+}
+
+// Nearest 返回值与 value 最接近的条目，跳表为空时返回 false。
+// 一次下降同时得到候选：值等于 value 时返回排名最前的那个条目，否则在紧邻目标位置的两个条目
+// （值小于 value 的最后一个和值大于 value 的第一个）中选择差值较小的一个，差值相同时选择值较小的一个。
+// 字符串类型的值没有数值上的距离，此时不比较距离，有较小的一侧时直接返回它。
+// 占位条目与 RangeByScore 相同默认被跳过，候选从下降位置起越过它们，跳表中只有被跳过的占位条目时返回 false
+// Nearest returns the entry whose value is closest to value, returns false if the list is empty.
+// One descent yields the candidates: on an exact match the first such entry in rank order is returned,
+// otherwise the closer of the two entries adjacent to the target position
+// (the last one below value and the first one above it) wins, ties prefer the lower value.
+// String values have no numeric distance, so no distance is compared and the lower side is returned when it exists.
+// Placeholders are skipped by default like in RangeByScore, the candidates step over them from the descent position,
+// and false is returned when the list holds skipped placeholders only
+func (sl *RankList[K, V]) Nearest(value V) (Entry[K, V], bool) {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	floor, _ := sl.descendBefore(value, false)
+	ceil := floor.forward[0]
+	if floor == sl.header {
+		floor = nil
+	}
+	for floor != nil && sl.hidden(floor) {
+		floor = floor.backward
+	}
+	for ceil != nil && sl.hidden(ceil) {
+		ceil = ceil.forward[0]
+	}
+	switch {
+	case floor == nil && ceil == nil:
+		return Entry[K, V]{}, false
+	case ceil != nil && sl.sameValue(ceil.data.Value, value):
+		return ceil.data, true
+	case floor == nil:
+		return ceil.data, true
+	case ceil == nil || !closerAbove(floor.data.Value, value, ceil.data.Value):
+		return floor.data, true
+	}
+	return ceil.data, true
+}
+
+// closerAbove 判断 hi 是否严格比 lo 更接近 v，要求 lo <= v <= hi。
+// 有符号整数翻转符号位后按无符号数计算差值，因此即使跨越整个取值范围也不会溢出；字符串始终返回 false
+// closerAbove reports whether hi is strictly closer to v than lo, given lo <= v <= hi.
+// Signed integers are compared as unsigned after flipping the sign bit, so differences never overflow
+// even across the whole range; strings always report false
+func closerAbove[V Ordered](lo V, v V, hi V) bool {
+	a, b, c := reflect.ValueOf(lo), reflect.ValueOf(v), reflect.ValueOf(hi)
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		flip := func(x reflect.Value) uint64 { return uint64(x.Int()) ^ (1 << 63) }
+		return flip(c)-flip(b) < flip(b)-flip(a)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return c.Uint()-b.Uint() < b.Uint()-a.Uint()
+	case reflect.Float32, reflect.Float64:
+		return c.Float()-b.Float() < b.Float()-a.Float()
+	}
+	return false
+}
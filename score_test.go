@@ -0,0 +1,254 @@
+package ranklist
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestTieCount(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("min1", 0)
+	sl.Set("min2", 0)
+	sl.Set("unique", 5)
+	for i := 0; i < 100; i++ {
+		sl.Set("tie"+strconv.Itoa(i), 10)
+	}
+	sl.Set("max1", 20)
+	sl.Set("max2", 20)
+	sl.Set("max3", 20)
+
+	testCases := []struct {
+		key      string
+		expected int
+	}{
+		{"unique", 1},
+		{"tie0", 100},
+		{"tie99", 100},
+		{"min1", 2},
+		{"max3", 3},
+	}
+
+	for _, tc := range testCases {
+		count, exists := sl.TieCount(tc.key)
+		if !exists {
+			t.Fatalf("Key %s should exist", tc.key)
+		}
+		if count != tc.expected {
+			t.Errorf("Key %s: expected tie count %d, got %d", tc.key, tc.expected, count)
+		}
+	}
+
+	if _, exists := sl.TieCount("missing"); exists {
+		t.Errorf("TieCount should return false for a missing key")
+	}
+}
+
+func TestRangeByScoreBounds(t *testing.T) {
+	sl := New[string, int]()
+	for i := 0; i < 5; i++ {
+		sl.Set("low"+strconv.Itoa(i), 50)
+		sl.Set("high"+strconv.Itoa(i), 250)
+		sl.Set("mid"+strconv.Itoa(i), 150)
+	}
+	for i := 0; i < 50; i++ {
+		sl.Set("min"+strconv.Itoa(i), 100)
+		sl.Set("max"+strconv.Itoa(i), 200)
+	}
+	all := sl.Range(1, sl.Length()+1)
+
+	tests := []struct {
+		r        ScoreRange[int]
+		expected int
+	}{
+		{ScoreRange[int]{Min: 100, Max: 200}, 105},
+		{ScoreRange[int]{Min: 100, Max: 200, MinExclusive: true}, 55},
+		{ScoreRange[int]{Min: 100, Max: 200, MaxExclusive: true}, 55},
+		{ScoreRange[int]{Min: 100, Max: 200, MinExclusive: true, MaxExclusive: true}, 5},
+		{ScoreRange[int]{Min: 100, Max: 100}, 50},
+		{ScoreRange[int]{Min: 100, Max: 100, MinExclusive: true}, 0},
+		{ScoreRange[int]{Min: 100, Max: 100, MaxExclusive: true}, 0},
+		{ScoreRange[int]{Min: 200, Max: 100}, 0},
+		{ScoreRange[int]{Min: 0, Max: 1000}, 115},
+		{ScoreRange[int]{Min: 251, Max: 1000}, 0},
+	}
+	for _, tt := range tests {
+		got := sl.RangeByScoreOpt(tt.r)
+		if len(got) != tt.expected {
+			t.Errorf("%+v: expected %d entries, got %d", tt.r, tt.expected, len(got))
+		}
+
+		var expected []Entry[string, int]
+		for _, entry := range all {
+			aboveMin := entry.Value > tt.r.Min || (!tt.r.MinExclusive && entry.Value == tt.r.Min)
+			belowMax := entry.Value < tt.r.Max || (!tt.r.MaxExclusive && entry.Value == tt.r.Max)
+			if aboveMin && belowMax {
+				expected = append(expected, entry)
+			}
+		}
+		if !slices.Equal(got, expected) {
+			t.Errorf("%+v: result disagrees with a filtered scan", tt.r)
+		}
+	}
+
+	if got := sl.RangeByScore(150, 150); len(got) != 5 || got[0].Key != "mid0" {
+		t.Errorf("RangeByScore(150, 150): expected the five mid entries in rank order, got %v", got)
+	}
+}
+
+func TestValueCounts(t *testing.T) {
+	type count struct{ value, count int }
+	collect := func(sl *RankList[string, int]) []count {
+		var counts []count
+		sl.ValueCounts(func(value int, n int) bool {
+			counts = append(counts, count{value, n})
+			return true
+		})
+		return counts
+	}
+
+	unique := New[string, int]()
+	for i := 0; i < 10; i++ {
+		unique.Set(strconv.Itoa(i), i)
+	}
+	if n := unique.DistinctValues(); n != 10 {
+		t.Errorf("unique: expected 10 distinct values, got %d", n)
+	}
+	for i, c := range collect(unique) {
+		if c != (count{i, 1}) {
+			t.Errorf("unique: at %d expected {%d 1}, got %v", i, i, c)
+		}
+	}
+
+	equal := New[string, int]()
+	for i := 0; i < 10; i++ {
+		equal.Set(strconv.Itoa(i), 7)
+	}
+	if got := collect(equal); !slices.Equal(got, []count{{7, 10}}) {
+		t.Errorf("equal: expected one run of 10, got %v", got)
+	}
+
+	mixed := New[string, int]()
+	for i, value := range []int{5, 1, 5, 3, 1, 5, 9} {
+		mixed.Set(strconv.Itoa(i), value)
+	}
+	if got := collect(mixed); !slices.Equal(got, []count{{1, 2}, {3, 1}, {5, 3}, {9, 1}}) {
+		t.Errorf("mixed: got %v", got)
+	}
+	if n := mixed.DistinctValues(); n != 4 {
+		t.Errorf("mixed: expected 4 distinct values, got %d", n)
+	}
+
+	var first []int
+	mixed.ValueCounts(func(value int, _ int) bool {
+		first = append(first, value)
+		return len(first) < 2
+	})
+	if !slices.Equal(first, []int{1, 3}) {
+		t.Errorf("returning false should stop the walk, got %v", first)
+	}
+
+	if n := New[string, int]().DistinctValues(); n != 0 {
+		t.Errorf("empty: expected 0 distinct values, got %d", n)
+	}
+}
+
+func TestKeysWithValue(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("min", 0)
+	sl.Set("single", 50)
+	for _, key := range []string{"t3", "t1", "t2"} {
+		sl.Set(key, 70)
+	}
+	sl.Set("max1", 100)
+	sl.Set("max2", 100)
+
+	tests := []struct {
+		value    int
+		expected []string
+	}{
+		{50, []string{"single"}},
+		{70, []string{"t1", "t2", "t3"}},
+		{0, []string{"min"}},
+		{100, []string{"max1", "max2"}},
+		{60, []string{}},
+		{-1, []string{}},
+		{101, []string{}},
+	}
+	for _, tt := range tests {
+		got := sl.KeysWithValue(tt.value)
+		if got == nil || !slices.Equal(got, tt.expected) {
+			t.Errorf("KeysWithValue(%d): expected %v, got %v", tt.value, tt.expected, got)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	sl := New[string, int]()
+	if _, ok := sl.Nearest(5); ok {
+		t.Fatalf("Nearest should return false for an empty list")
+	}
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("b2", 20)
+	sl.Set("c", 40)
+
+	tests := []struct {
+		target   int
+		expected string
+	}{
+		{-100, "a"},
+		{100, "c"},
+		{20, "b"},
+		{10, "a"},
+		{14, "a"},
+		{16, "b"},
+		{15, "a"},
+		{30, "b2"},
+		{31, "c"},
+	}
+	for _, tt := range tests {
+		if entry, ok := sl.Nearest(tt.target); !ok || entry.Key != tt.expected {
+			t.Errorf("Nearest(%d): expected %s, got %v, %v", tt.target, tt.expected, entry, ok)
+		}
+	}
+}
+
+func TestNearestExtremes(t *testing.T) {
+	signed := New[string, int64]()
+	signed.Set("min", math.MinInt64)
+	signed.Set("max", math.MaxInt64)
+	if entry, _ := signed.Nearest(math.MaxInt64 - 10); entry.Key != "max" {
+		t.Errorf("expected max across the whole int64 range, got %v", entry)
+	}
+	if entry, _ := signed.Nearest(-1); entry.Key != "min" {
+		t.Errorf("expected min for an equidistant target, got %v", entry)
+	}
+
+	unsigned := New[string, uint8]()
+	unsigned.Set("low", 0)
+	unsigned.Set("high", 255)
+	if entry, _ := unsigned.Nearest(200); entry.Key != "high" {
+		t.Errorf("expected high, got %v", entry)
+	}
+
+	floats := New[string, float64]()
+	floats.Set("x", 1.5)
+	floats.Set("y", 2.5)
+	if entry, _ := floats.Nearest(2.1); entry.Key != "y" {
+		t.Errorf("expected y, got %v", entry)
+	}
+
+	// 字符串没有数值距离，有较小的一侧时返回它
+	// Strings have no numeric distance, the lower side wins when it exists
+	strs := New[int, string]()
+	strs.Set(1, "apple")
+	strs.Set(2, "banana")
+	if entry, _ := strs.Nearest("az"); entry.Key != 1 {
+		t.Errorf("expected the floor for string values, got %v", entry)
+	}
+	if entry, _ := strs.Nearest("a"); entry.Key != 1 {
+		t.Errorf("expected the ceiling below every value, got %v", entry)
+	}
+}
@@ -0,0 +1,34 @@
+package ranklist_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/werbenhu/ranklist"
+	"github.com/werbenhu/ranklist/ranklisttest"
+)
+
+// TestScriptVariants 用 ranklisttest 的可复现脚本检验各个可选功能下的跳表，与外部使用者采用相同的有效性定义
+// TestScriptVariants exercises the skip list under each optional feature with the reproducible ranklisttest scripts,
+// sharing the definition of validity with external users
+func TestScriptVariants(t *testing.T) {
+	variants := []struct {
+		name string
+		opts []ranklist.Option[int, int]
+	}{
+		{"default", nil},
+		{"nodict", []ranklist.Option[int, int]{ranklist.WithNoDict[int, int]()}},
+		{"arena", []ranklist.Option[int, int]{ranklist.WithArena[int, int](16)}},
+		{"rankcache", []ranklist.Option[int, int]{ranklist.WithRankCache[int, int](8)}},
+		{"timestamps", []ranklist.Option[int, int]{ranklist.WithTimestamps[int, int](time.Now)}},
+	}
+
+	for _, variant := range variants {
+		t.Run(variant.name, func(t *testing.T) {
+			for seed := uint64(1); seed <= 3; seed++ {
+				script := ranklisttest.Script{Seed: seed, Steps: 3000, Keys: 64, Values: 8, CheckEvery: 250}
+				script.Run(t, ranklist.New(variant.opts...))
+			}
+		})
+	}
+}
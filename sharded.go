@@ -0,0 +1,233 @@
+package ranklist
+
+import (
+	"container/heap"
+	"hash/maphash"
+	"iter"
+	"reflect"
+	"unsafe"
+)
+
+// Sharded 按键的哈希将数据分散到多个 RankList 中，以降低单把锁对写入吞吐量的限制。
+// Set/Get/Del/IncrBy 只访问一个分片；Rank、Range、Top 和 Length 需要合并所有分片的结果。
+// 一致性模型：跨分片的读操作依次获取每个分片的读锁，不是原子快照，
+// 并发写入可能只被部分分片反映，结果是尽力而为的，单个分片内部始终一致
+// Sharded partitions keys across several RankLists by hash to lift the write throughput cap of a single lock.
+// Set/Get/Del/IncrBy touch exactly one shard, Rank, Range, Top and Length merge the results of every shard.
+// Consistency model: cross-shard reads take each shard's read lock in turn rather than one atomic snapshot,
+// so concurrent writes may be reflected by some shards and not others.
+// Results are best effort, each shard is always internally consistent
+type Sharded[K Ordered, V Ordered] struct {
+	shards []*RankList[K, V]
+	seed   maphash.Seed
+	str    bool
+}
+
+// NewSharded 创建一个包含 n 个分片的 Sharded，n 必须为正数
+// NewSharded creates a Sharded with n shards, n must be positive
+func NewSharded[K Ordered, V Ordered](n int) *Sharded[K, V] {
+	if n < 1 {
+		panic("ranklist: shard count must be positive")
+	}
+
+	s := &Sharded[K, V]{
+		shards: make([]*RankList[K, V], n),
+		seed:   maphash.MakeSeed(),
+		str:    kindOf[K]() == reflect.String,
+	}
+	for i := range s.shards {
+		s.shards[i] = New[K, V]()
+	}
+	return s
+}
+
+// shard 返回 key 所属的分片
+// shard returns the shard owning key
+func (s *Sharded[K, V]) shard(key K) *RankList[K, V] {
+	if len(s.shards) == 1 {
+		return s.shards[0]
+	}
+
+	// -0 与 +0 是同一个键，统一成 +0 后再哈希
+	// -0 and +0 are the same key, normalize to +0 before hashing
+	if key == ZeroValue[K]() {
+		key = ZeroValue[K]()
+	}
+
+	var h uint64
+	if s.str {
+		h = maphash.String(s.seed, *(*string)(unsafe.Pointer(&key)))
+	} else {
+		h = maphash.Bytes(s.seed, unsafe.Slice((*byte)(unsafe.Pointer(&key)), unsafe.Sizeof(key)))
+	}
+	return s.shards[h%uint64(len(s.shards))]
+}
+
+// Set 插入或更新键值对，只锁定键所属的分片，键是新插入的返回 true
+// Set inserts or updates a key-value pair, only the shard owning the key is locked.
+// Returns true if the key was newly inserted
+func (s *Sharded[K, V]) Set(key K, value V) bool {
+	return s.shard(key).Set(key, value)
+}
+
+// Get 获取键的值
+// Get retrieves the value associated with the key
+func (s *Sharded[K, V]) Get(key K) (V, bool) {
+	return s.shard(key).Get(key)
+}
+
+// Del 删除键，键不存在时返回 false
+// Del removes the key, returns false if the key does not exist
+func (s *Sharded[K, V]) Del(key K) bool {
+	return s.shard(key).Del(key)
+}
+
+// IncrBy 将键的值增加 delta 并返回新值
+// IncrBy increments the value of key by delta and returns the new value
+func (s *Sharded[K, V]) IncrBy(key K, delta V) V {
+	return s.shard(key).IncrBy(key, delta)
+}
+
+// Length 返回所有分片的元素总数
+// Length returns the total number of elements across all shards
+func (s *Sharded[K, V]) Length() int {
+	total := 0
+	for _, shard := range s.shards {
+		total += shard.Length()
+	}
+	return total
+}
+
+// Rank 返回键的全局排名：在键所属的分片中取得它的值，再累加每个分片中排序位置在 (值, 键) 之前的条目数，最后加一。
+// 值相同的条目在所有分片之间统一按键排序，因此结果与 Range 的归并顺序一致。
+// 与其他跨分片读操作一样，各分片依次加锁，结果是尽力而为的
+// Rank returns the global rank of the key: its value is taken from the shard owning it,
+// then the entries sorting before (value, key) in every shard are summed up and one is added.
+// Tied entries are ordered by key across all shards, so the result agrees with the merge order of Range.
+// Like every cross-shard read the shards are locked one after another and the result is best effort
+func (s *Sharded[K, V]) Rank(key K) (int, bool) {
+	value, exists := s.Get(key)
+	if !exists {
+		return 0, false
+	}
+
+	entry := Entry[K, V]{Key: key, Value: value}
+	rank := 1
+	for _, shard := range s.shards {
+		shard.RLock()
+		rank += shard.countLess(entry)
+		shard.RUnlock()
+	}
+	return rank, true
+}
+
+// Range 获取指定全局排名区间内的榜单项（不包含END），start 小于 1 时按 1 处理。
+// 通过一个大小为分片数 k 的小顶堆对每个分片的 All 迭代器做 k 路归并，跳过前 start-1 个条目后收集窗口，
+// 耗时 O(end log k)；每个分片按块读取，最多只比它实际贡献的条目多读一块，不会把整个分片复制出来。
+// 每个分片的迭代器遵循 All 的一致性模型，分片之间没有共同的快照
+// Range retrieves the entries within the specified global rank range (excluding END), a start below 1 is treated as 1.
+// The All iterators of the shards are merged k ways through a min-heap of size k, the number of shards,
+// skipping the first start-1 entries and collecting the window, in O(end log k);
+// every shard is read in chunks, at most one chunk beyond what it contributes, so no shard is copied as a whole.
+// Each shard's iterator follows the consistency model of All and there is no common snapshot across shards
+func (s *Sharded[K, V]) Range(start int, end int) []Entry[K, V] {
+	start = max(start, 1)
+	if end <= start {
+		return []Entry[K, V]{}
+	}
+
+	seqs := make([]iter.Seq2[K, V], len(s.shards))
+	for i, shard := range s.shards {
+		seqs[i] = shard.All()
+	}
+	return mergeSeqs(seqs, start-1, end-start, func(a, b Entry[K, V]) bool {
+		return compareEntries(a, b) < 0
+	})
+}
+
+// Top 返回所有分片中值最大的 n 个条目，按值从高到低排列。
+// 与 Range 相同，通过小顶堆对每个分片的 Backward 迭代器做 k 路归并，耗时 O(n log k)
+// Top returns the n entries with the largest values across all shards, from the highest value down.
+// Like Range it merges the Backward iterators of the shards k ways through a heap, in O(n log k)
+func (s *Sharded[K, V]) Top(n int) []Entry[K, V] {
+	seqs := make([]iter.Seq2[K, V], len(s.shards))
+	for i, shard := range s.shards {
+		seqs[i] = shard.Backward()
+	}
+	return mergeSeqs(seqs, 0, n, func(a, b Entry[K, V]) bool {
+		return compareEntries(a, b) > 0
+	})
+}
+
+// mergeSeqs 将多个已按 less 排序的迭代器归并为一个有序切片，跳过前 skip 个条目后最多返回 limit 个条目。
+// 迭代器只被拉取到归并所需的位置
+// mergeSeqs merges iterators that are each sorted by less into one sorted slice,
+// skipping the first skip entries and returning at most limit entries.
+// The iterators are only pulled as far as the merge needs
+func mergeSeqs[K Ordered, V Ordered](seqs []iter.Seq2[K, V], skip int, limit int, less func(a, b Entry[K, V]) bool) []Entry[K, V] {
+	merged := make([]Entry[K, V], 0)
+	if limit <= 0 {
+		return merged
+	}
+
+	h := &mergeHeap[K, V]{less: less}
+	for _, seq := range seqs {
+		next, stop := iter.Pull2(seq)
+		defer stop()
+		if key, value, ok := next(); ok {
+			h.cursors = append(h.cursors, &mergeCursor[K, V]{entry: Entry[K, V]{Key: key, Value: value}, next: next})
+		}
+	}
+	heap.Init(h)
+
+	for h.Len() > 0 && len(merged) < limit {
+		cursor := h.cursors[0]
+		if skip > 0 {
+			skip--
+		} else {
+			merged = append(merged, cursor.entry)
+		}
+		if key, value, ok := cursor.next(); ok {
+			cursor.entry = Entry[K, V]{Key: key, Value: value}
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return merged
+}
+
+// mergeCursor 一个参与归并的迭代器及其当前条目
+// mergeCursor is one iterator taking part in a merge together with its current entry
+type mergeCursor[K Ordered, V Ordered] struct {
+	entry Entry[K, V]
+	next  func() (K, V, bool)
+}
+
+// mergeHeap 按各迭代器当前条目排序的小顶堆
+// mergeHeap is a min-heap of iterators ordered by their current entries
+type mergeHeap[K Ordered, V Ordered] struct {
+	cursors []*mergeCursor[K, V]
+	less    func(a, b Entry[K, V]) bool
+}
+
+func (h *mergeHeap[K, V]) Len() int { return len(h.cursors) }
+
+func (h *mergeHeap[K, V]) Less(i, j int) bool {
+	return h.less(h.cursors[i].entry, h.cursors[j].entry)
+}
+
+func (h *mergeHeap[K, V]) Swap(i, j int) {
+	h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i]
+}
+
+func (h *mergeHeap[K, V]) Push(x any) {
+	h.cursors = append(h.cursors, x.(*mergeCursor[K, V]))
+}
+
+func (h *mergeHeap[K, V]) Pop() any {
+	cursor := h.cursors[len(h.cursors)-1]
+	h.cursors[len(h.cursors)-1] = nil
+	h.cursors = h.cursors[:len(h.cursors)-1]
+	return cursor
+}
@@ -0,0 +1,146 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestShardedMatchesControl(t *testing.T) {
+	sharded := NewSharded[string, int](8)
+	control := New[string, int]()
+
+	for i := 0; i < 5000; i++ {
+		key := "key" + strconv.Itoa(rand.IntN(800))
+		switch rand.IntN(4) {
+		case 0:
+			if sharded.Del(key) != control.Del(key) {
+				t.Fatalf("Del(%s) disagrees with the control list", key)
+			}
+		case 1:
+			delta := rand.IntN(20)
+			if sharded.IncrBy(key, delta) != control.IncrBy(key, delta) {
+				t.Fatalf("IncrBy(%s) disagrees with the control list", key)
+			}
+		default:
+			value := rand.IntN(100)
+			sharded.Set(key, value)
+			control.Set(key, value)
+		}
+	}
+
+	if sharded.Length() != control.Length() {
+		t.Fatalf("expected length %d, got %d", control.Length(), sharded.Length())
+	}
+	for _, entry := range control.Snapshot() {
+		value, ok := sharded.Get(entry.Key)
+		if !ok || value != entry.Value {
+			t.Fatalf("Key %v: expected value %v, got %v, %v", entry.Key, entry.Value, value, ok)
+		}
+		expected, _ := control.Rank(entry.Key)
+		if rank, ok := sharded.Rank(entry.Key); !ok || rank != expected {
+			t.Fatalf("Key %v: expected rank %d, got %d, %v", entry.Key, expected, rank, ok)
+		}
+	}
+	if _, ok := sharded.Rank("missing"); ok {
+		t.Errorf("missing key should not have a rank")
+	}
+
+	n := control.Length()
+	for _, window := range [][2]int{{1, 11}, {0, 5}, {50, 120}, {n - 3, n + 5}, {n + 1, n + 10}, {7, 7}, {9, 3}} {
+		expected := control.Range(max(window[0], 1), window[1])
+		if got := sharded.Range(window[0], window[1]); !slices.Equal(got, expected) {
+			t.Errorf("Range(%d, %d): expected %v, got %v", window[0], window[1], expected, got)
+		}
+	}
+	for _, k := range []int{0, 1, 10, n, n + 5} {
+		if got, expected := sharded.Top(k), control.Top(k); !slices.Equal(got, expected) {
+			t.Errorf("Top(%d): expected %v, got %v", k, expected, got)
+		}
+	}
+}
+
+func TestShardedNegativeZeroKey(t *testing.T) {
+	sharded := NewSharded[float64, int](16)
+	negativeZero := 0.0
+	negativeZero = -negativeZero
+
+	sharded.Set(0, 1)
+	sharded.Set(negativeZero, 2)
+	if sharded.Length() != 1 {
+		t.Fatalf("-0 and +0 should be the same key, got length %d", sharded.Length())
+	}
+	if value, _ := sharded.Get(0); value != 2 {
+		t.Errorf("expected 2, got %d", value)
+	}
+}
+
+func TestShardedConcurrent(t *testing.T) {
+	sharded := NewSharded[int, int](4)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := g*1000 + i
+				sharded.Set(key, i)
+				sharded.Rank(key)
+				sharded.Range(1, 10)
+				sharded.Top(5)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if sharded.Length() != 4000 {
+		t.Errorf("expected 4000 entries, got %d", sharded.Length())
+	}
+}
+
+func TestNewShardedPanicsOnZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for zero shards")
+		}
+	}()
+	NewSharded[int, int](0)
+}
+
+func TestShardedMergeTies(t *testing.T) {
+	// 只有少数几个不同的值，绝大多数条目在分片之间并列，归并必须按键决出顺序
+	// Only a handful of distinct values, so most entries tie across shards and the merge has to order them by key
+	sharded := NewSharded[int, int](5)
+	control := New[int, int]()
+	for i := 0; i < 3000; i++ {
+		key, value := rand.IntN(2000), rand.IntN(4)
+		sharded.Set(key, value)
+		control.Set(key, value)
+	}
+
+	n := control.Length()
+	for round := 0; round < 50; round++ {
+		start := rand.IntN(n+10) - 5
+		end := start + rand.IntN(700)
+		expected := control.Range(max(start, 1), end)
+		if got := sharded.Range(start, end); !slices.Equal(got, expected) {
+			t.Fatalf("Range(%d, %d) disagrees with the control list", start, end)
+		}
+		k := rand.IntN(n + 10)
+		if got := sharded.Top(k); !slices.Equal(got, control.Top(k)) {
+			t.Fatalf("Top(%d) disagrees with the control list", k)
+		}
+	}
+	for _, entry := range control.Entries() {
+		expected, _ := control.Rank(entry.Key)
+		if rank, _ := sharded.Rank(entry.Key); rank != expected {
+			t.Fatalf("Key %d: expected rank %d, got %d", entry.Key, expected, rank)
+		}
+	}
+	if got := sharded.Range(1, n+1); !slices.Equal(got, control.Entries()) {
+		t.Fatalf("a full Range should match the control list")
+	}
+}
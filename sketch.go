@@ -0,0 +1,123 @@
+package ranklist
+
+import (
+	"math"
+	"slices"
+	"sync/atomic"
+)
+
+// rankSketch 按固定间隔采样的排名草图，读取不需要跳表的锁
+// rankSketch is a rank sketch sampled at a fixed interval, read without the lock of the skip list
+type rankSketch[V Ordered] struct {
+	// 相邻检查点之间的排名间隔
+	// Rank interval between two adjacent checkpoints
+	every int
+
+	// 当前生效的快照
+	// The snapshot currently in effect
+	snap atomic.Pointer[sketchSnapshot[V]]
+}
+
+// sketchSnapshot 某次刷新时的检查点
+// sketchSnapshot holds the checkpoints taken by one refresh
+type sketchSnapshot[V Ordered] struct {
+	// 排名 every、2*every、... 上的值，非降序
+	// Values at ranks every, 2*every, ..., in non-descending order
+	checkpoints []V
+
+	// 刷新时的条目数量
+	// Number of entries at the time of the refresh
+	length int
+
+	// 刷新之后发生的修改次数，每次修改使任意值之前的条目数最多变化 1
+	// Mutations since the refresh, each of which moves the number of entries before any value by at most 1
+	drift atomic.Int64
+}
+
+// WithRankSketch 开启排名草图，供 ApproxRank 在不获取跳表锁的情况下估算排名。
+// 草图保存每隔 1/sampleRate 个排名的一个检查点值，约占 n*sampleRate 个值的内存；
+// 每次修改只增加一个计数，修改次数达到 max(1/sampleRate, n*sampleRate) 时在写锁内沿跨度重新采集检查点，
+// 刷新耗时 O(n*sampleRate*log n)，均摊到每次修改不超过 O(log n)。sampleRate 必须在 (0, 1] 之间，否则 panic
+// WithRankSketch enables a rank sketch that lets ApproxRank estimate ranks without taking the lock of the list.
+// The sketch keeps one checkpoint value every 1/sampleRate ranks, about n*sampleRate values of memory;
+// each mutation only bumps a counter, and once max(1/sampleRate, n*sampleRate) mutations have accumulated
+// the checkpoints are collected again by following spans under the write lock,
+// in O(n*sampleRate*log n), which amortizes to at most O(log n) per mutation. sampleRate must be within (0, 1] or it panics
+func WithRankSketch[K Ordered, V Ordered](sampleRate float64) Option[K, V] {
+	if !(sampleRate > 0 && sampleRate <= 1) {
+		panic("ranklist: sketch sample rate must be within (0, 1]")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.sketch = &rankSketch[V]{every: max(1, int(math.Round(1/sampleRate)))}
+		sl.sketch.snap.Store(&sketchSnapshot[V]{})
+	}
+}
+
+// ApproxRank 估算一个值为 value 的新条目的排名，即 1 加上值小于 value 的条目数量，并返回误差上限：
+// 真实排名位于 [估算值-误差, 估算值+误差] 之内。
+// 只读取草图，不获取跳表的锁，耗时 O(log(n*sampleRate))。
+// 误差上限为检查点间隔的一半加上草图刷新之后的修改次数，因此不超过约 1/(2*sampleRate) + max(1/sampleRate, n*sampleRate)。
+// 未开启 WithRankSketch 时返回 0 和 +Inf
+// ApproxRank estimates the rank a new entry with value would take, i.e. 1 plus the number of entries
+// whose value is less than value, and returns an error bound: the true rank lies within estimate ± bound.
+// It reads the sketch only, never the lock of the list, in O(log(n*sampleRate)).
+// The bound is half the checkpoint interval plus the mutations since the sketch was refreshed,
+// so it never exceeds about 1/(2*sampleRate) + max(1/sampleRate, n*sampleRate).
+// Without WithRankSketch it returns 0 and +Inf
+func (sl *RankList[K, V]) ApproxRank(value V) (int, float64) {
+	if sl.sketch == nil {
+		return 0, math.Inf(1)
+	}
+	snap := sl.sketch.snap.Load()
+	every := sl.sketch.every
+
+	// 检查点 j 位于排名 (j+1)*every，前 i 个检查点小于 value 说明至少有 i*every 个条目小于 value，
+	// 第 i+1 个检查点不小于 value 说明少于 (i+1)*every 个
+	// Checkpoint j sits at rank (j+1)*every: the first i checkpoints being below value means at least i*every
+	// entries are below it, checkpoint i+1 not being below value means fewer than (i+1)*every are
+	i, _ := slices.BinarySearch(snap.checkpoints, value)
+	lower, upper := i*every, snap.length
+	if i < len(snap.checkpoints) {
+		upper = (i+1)*every - 1
+	}
+	span := upper - lower
+	return 1 + lower + span/2, float64(span-span/2) + float64(snap.drift.Load())
+}
+
+// sketchMutation 记录一次修改，清空时或修改次数达到阈值时刷新草图，未开启时不做任何事，调用方需持有写锁
+// sketchMutation records one mutation, refreshing the sketch on a clear or once enough mutations accumulated.
+// It does nothing when the sketch is disabled. The caller must hold the write lock
+func (sl *RankList[K, V]) sketchMutation(op byte) {
+	if sl.sketch == nil {
+		return
+	}
+	snap := sl.sketch.snap.Load()
+	if op == walOpClear || snap.drift.Add(1) >= int64(max(sl.sketch.every, len(snap.checkpoints))) {
+		sl.refreshSketch()
+	}
+}
+
+// refreshSketch 重新采集检查点，耗时 O(n*sampleRate*log n)，未开启时不做任何事，调用方需持有写锁
+// refreshSketch collects fresh checkpoints in O(n*sampleRate*log n), it does nothing when the sketch is disabled.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) refreshSketch() {
+	if sl.sketch == nil {
+		return
+	}
+	every := sl.sketch.every
+	checkpoints := make([]V, 0, sl.length/every)
+
+	// 每个检查点都从上一个检查点出发沿跨度前进，不必逐个访问节点
+	// Every checkpoint is reached by following spans from the previous one instead of visiting each node
+	curr, rank := sl.header, 0
+	for target := every; target <= sl.length; target += every {
+		for i := len(curr.forward) - 1; i >= 0; i-- {
+			for curr.forward[i] != nil && rank+curr.forward[i].span[i] <= target {
+				rank += curr.forward[i].span[i]
+				curr = curr.forward[i]
+			}
+		}
+		checkpoints = append(checkpoints, curr.data.Value)
+	}
+	sl.sketch.snap.Store(&sketchSnapshot[V]{checkpoints: checkpoints, length: sl.length})
+}
@@ -0,0 +1,152 @@
+package ranklist
+
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"testing"
+)
+
+// exactRankOf 返回值为 value 的新条目的精确排名
+// exactRankOf returns the exact rank a new entry with value would take
+func exactRankOf[K Ordered, V Ordered](sl *RankList[K, V], value V) int {
+	sl.RLock()
+	defer sl.RUnlock()
+	return sl.countBefore(value, false) + 1
+}
+
+func requireApproxRank(t *testing.T, sl *RankList[int, int], value int) float64 {
+	t.Helper()
+	estimate, bound := sl.ApproxRank(value)
+	exact := exactRankOf(sl, value)
+	if math.Abs(float64(estimate-exact)) > bound {
+		t.Fatalf("value %d: estimate %d ± %v misses the exact rank %d", value, estimate, bound, exact)
+	}
+	return bound
+}
+
+func TestApproxRankDistributions(t *testing.T) {
+	distributions := map[string]func() int{
+		"uniform": func() int { return rand.IntN(1000000) },
+		// 大量重复的小值和长尾
+		// Heavily repeated small values with a long tail
+		"skewed": func() int { return int(math.Exp(rand.ExpFloat64() * 3)) },
+	}
+	for name, next := range distributions {
+		t.Run(name, func(t *testing.T) {
+			sl := New(WithRankSketch[int, int](0.01))
+			for i := 0; i < 20000; i++ {
+				sl.Set(i, next())
+			}
+
+			maxBound := 0.0
+			for i := 0; i < 2000; i++ {
+				maxBound = max(maxBound, requireApproxRank(t, sl, next()))
+
+				// 修改穿插在查询之间，误差上限需要覆盖尚未刷新的修改
+				// Mutations are interleaved with queries, the bound has to cover those not yet refreshed
+				switch rand.IntN(3) {
+				case 0:
+					sl.Del(rand.IntN(20000))
+				default:
+					sl.Set(rand.IntN(25000), next())
+				}
+			}
+			for _, value := range []int{math.MinInt, 0, 1, math.MaxInt} {
+				requireApproxRank(t, sl, value)
+			}
+
+			// 间隔 100、最多 25000 个条目：误差不应超过半个间隔加上最多 250 次修改的刷新阈值
+			// An interval of 100 and at most 25000 entries:
+			// the bound stays within half an interval plus a refresh threshold of at most 250 mutations
+			if limit := 50.0 + 250; maxBound > limit {
+				t.Fatalf("bound %v exceeds the documented limit %v", maxBound, limit)
+			}
+		})
+	}
+}
+
+func TestApproxRankExactWhenFullySampled(t *testing.T) {
+	source := New[int, int]()
+	for i := 0; i < 100; i++ {
+		source.Set(i, i*2)
+	}
+	data, _ := source.MarshalJSON()
+
+	// 批量替换之后立即刷新，每个排名都是检查点，因此估算是精确的
+	// A bulk replacement refreshes right away and every rank is a checkpoint, so the estimate is exact
+	sl := New(WithRankSketch[int, int](1))
+	sl.UnmarshalJSON(data)
+	for _, value := range []int{-1, 0, 1, 99, 100, 198, 199} {
+		if estimate, bound := sl.ApproxRank(value); estimate != exactRankOf(sl, value) || bound != 0 {
+			t.Fatalf("value %d: expected the exact rank %d, got %d ± %v", value, exactRankOf(sl, value), estimate, bound)
+		}
+	}
+
+	sl.Set(1000, 3)
+	if _, bound := sl.ApproxRank(3); bound != 1 {
+		t.Fatalf("one mutation after the refresh should widen the bound to 1, got %v", bound)
+	}
+	sl.Clear()
+	if estimate, bound := sl.ApproxRank(3); estimate != 1 || bound != 0 {
+		t.Fatalf("Clear should refresh the sketch, got %d ± %v", estimate, bound)
+	}
+}
+
+func TestApproxRankBulkLoad(t *testing.T) {
+	source := New[int, int]()
+	for i := 0; i < 5000; i++ {
+		source.Set(i, rand.IntN(100000))
+	}
+	data, _ := source.MarshalJSON()
+
+	sl := New(WithRankSketch[int, int](0.05))
+	if err := sl.UnmarshalJSON(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 200; i++ {
+		if bound := requireApproxRank(t, sl, rand.IntN(100000)); bound > 10 {
+			t.Fatalf("a bulk load should refresh the sketch, got bound %v", bound)
+		}
+	}
+
+	if estimate, bound := New[int, int]().ApproxRank(1); estimate != 0 || !math.IsInf(bound, 1) {
+		t.Fatalf("without a sketch expected 0 and +Inf, got %d, %v", estimate, bound)
+	}
+}
+
+func TestApproxRankConcurrent(t *testing.T) {
+	sl := New(WithRankSketch[int, int](0.02))
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20000; i++ {
+			sl.Set(rand.IntN(5000), rand.IntN(10000))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20000; i++ {
+			if estimate, bound := sl.ApproxRank(rand.IntN(10000)); estimate < 1 || bound < 0 {
+				t.Errorf("unexpected estimate %d ± %v", estimate, bound)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+	requireApproxRank(t, sl, 5000)
+}
+
+func TestWithRankSketchPanics(t *testing.T) {
+	for _, rate := range []float64{0, -1, 1.5, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("sample rate %v should panic", rate)
+				}
+			}()
+			WithRankSketch[int, int](rate)
+		}()
+	}
+}
@@ -0,0 +1,207 @@
+package ranklist
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Stats 描述跳表的结构指标
+// Stats describes the structural metrics of a skip list
+type Stats struct {
+	// 元素数量
+	// Number of elements
+	Length int
+
+	// 当前层级
+	// Current level
+	Level int
+
+	// LevelHistogram[i] 是高度恰好为 i+1 的节点数量，总和等于 Length
+	// LevelHistogram[i] is the number of nodes whose height is exactly i+1, it sums to Length
+	LevelHistogram []int
+
+	// LevelCounts[i] 是出现在第 i+1 层的节点数量，随层级单调不增
+	// LevelCounts[i] is the number of nodes present at level i+1, it never increases with the level
+	LevelCounts []int
+
+	// 从头节点查找到一个元素平均需要经过的前向指针数
+	// Average number of forward pointer hops needed to reach an element from the header
+	AvgSearchDepth float64
+
+	// 节点（不含跨度）、跨度和字典占用的近似字节数，以及三者之和
+	// Approximate bytes used by nodes (excluding spans), spans and the dictionary, and their sum
+	NodeBytes int
+	SpanBytes int
+	DictBytes int
+	Bytes     int
+
+	// 被 Suspend 暂停、尚未被 Resume、Del 或 Sweep 清除的条目数，它们不在跳表中，不计入 Length 和以上各项字节数
+	// Number of entries parked by Suspend and not yet removed by Resume, Del or Sweep,
+	// they are outside the list and counted neither in Length nor in the byte counts above
+	Tombstones int
+}
+
+// Stats 在一把读锁内遍历跳表一次，返回其结构指标
+// Stats walks the skip list once under one read lock and returns its structural metrics
+func (sl *RankList[K, V]) Stats() Stats {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	stats := Stats{
+		Length:         sl.length,
+		Level:          sl.level,
+		Tombstones:     len(sl.suspended),
+		LevelHistogram: make([]int, sl.level),
+		LevelCounts:    make([]int, sl.level),
+	}
+
+	// steps[i] 是自上一个高于第 i+1 层的节点之后，第 i 层上经过的节点数，
+	// 即查找时在第 i 层需要横向移动的次数
+	// steps[i] is the number of level-i nodes passed since the last node taller than level i+1,
+	// which is how many hops a search makes along level i
+	var steps [MaxLevel]int
+	hops := 0
+	keyBytes := 0
+	slots := MaxLevel
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		stats.LevelHistogram[curr.level-1]++
+		slots += curr.level
+		for i := 0; i < curr.level; i++ {
+			stats.LevelCounts[i]++
+		}
+
+		hops++
+		for i := curr.level - 1; i < sl.level; i++ {
+			hops += steps[i]
+		}
+		for i := 0; i < curr.level-1; i++ {
+			steps[i] = 0
+		}
+		steps[curr.level-1]++
+
+		keyBytes += orderedBytes(curr.data.Key)
+	}
+	if sl.length > 0 {
+		stats.AvgSearchDepth = float64(hops) / float64(sl.length)
+	}
+
+	// 前向指针和跨度按节点层级分配，slots 是包括头节点在内的层级总数
+	// Forward pointers and spans are sized to each node's level, slots is the total number of levels including the header
+	var node Node[K, V]
+	stats.SpanBytes = slots * int(unsafe.Sizeof(0))
+	stats.NodeBytes = (sl.length+1)*int(unsafe.Sizeof(node)) + slots*int(unsafe.Sizeof(&node))
+
+	// 字典的每个条目按键、节点指针和一个字节的 tophash 估算，字符串内容只计一次
+	// Each dictionary entry is estimated as key, node pointer and one tophash byte, string contents are counted once
+	// 没有字典时字符串内容计入节点
+	// Without a dictionary string contents are counted with the nodes
+	if sl.noDict {
+		stats.NodeBytes += keyBytes
+	} else {
+		stats.DictBytes = len(sl.dict)*int(unsafe.Sizeof(node.data.Key)+unsafe.Sizeof(&node)+1) + keyBytes
+	}
+	stats.Bytes = stats.NodeBytes + stats.SpanBytes + stats.DictBytes
+	return stats
+}
+
+// MemoryBreakdown 按组件估算跳表占用的字节数。
+// 估算基于 unsafe.Sizeof 和字符串的长度，不包括内存分配器的对齐和元数据、map 的桶结构以及切片未使用的容量，
+// 因此并不精确，但会随元素数量和键的长度正确地缩放，适合用来比较不同的配置，例如有无字典、字符串键或整数键
+// MemoryBreakdown estimates the bytes used by the skip list per component.
+// The estimate is based on unsafe.Sizeof and string lengths and leaves out allocator alignment and metadata,
+// the bucket structure of maps and unused slice capacity, so it is not exact,
+// but it scales correctly with the element count and the key sizes and is meant for comparing configurations,
+// such as with or without the dictionary, or string against integer keys
+type MemoryBreakdown struct {
+	// 节点结构本身（不含键值）以及按层级分配的前向指针和跨度，包括头节点；开启 WithUpdateOrder 时还包括更新顺序的链接
+	// The node structs themselves excluding keys and values, plus the forward pointers and spans sized to each level,
+	// including the header, and the update order links with WithUpdateOrder
+	Nodes int
+
+	// Levels[i] 是第 i+1 层上的前向指针和跨度占用的字节数，包括头节点，已计入 Nodes
+	// Levels[i] is the bytes used by forward pointers and spans at level i+1, including the header, already counted in Nodes
+	Levels []int
+
+	// 条目的键和值，按 unsafe.Sizeof 计算，字符串再加上内容的长度
+	// Keys and values of the entries, by unsafe.Sizeof plus the content length for strings
+	Entries int
+
+	// 字典的每个条目按键、节点指针和一个字节的 tophash 估算，字符串内容已计入 Entries，没有字典时为 0
+	// The dictionary, each entry estimated as key, node pointer and one tophash byte,
+	// string contents are already counted in Entries. 0 without a dictionary
+	Dict int
+
+	// 以上各项之和，不重复计算 Levels
+	// The sum of the above, not counting Levels twice
+	Total int
+}
+
+// MemoryUsage 在一把读锁内遍历跳表一次，按组件估算其占用的字节数，估算的局限见 MemoryBreakdown
+// MemoryUsage walks the skip list once under one read lock and estimates the bytes it uses per component,
+// see MemoryBreakdown for the limits of the estimate
+func (sl *RankList[K, V]) MemoryUsage() MemoryBreakdown {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	var node Node[K, V]
+	slot := int(unsafe.Sizeof(&node) + unsafe.Sizeof(0))
+	usage := MemoryBreakdown{Levels: make([]int, sl.level)}
+
+	// 头节点在每一层都有前向指针和跨度
+	// The header has a forward pointer and a span at every level
+	slots := MaxLevel
+	for i := range usage.Levels {
+		usage.Levels[i] = slot
+	}
+	contents := 0
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		slots += curr.level
+		for i := 0; i < curr.level; i++ {
+			usage.Levels[i] += slot
+		}
+		contents += orderedBytes(curr.data.Key) + orderedBytes(curr.data.Value)
+	}
+
+	usage.Nodes = (sl.length+1)*int(unsafe.Sizeof(node)-unsafe.Sizeof(node.data)) + slots*slot
+	if sl.recency != nil {
+		usage.Nodes += sl.length * int(unsafe.Sizeof(recencyLink[K, V]{}))
+	}
+	usage.Entries = sl.length*int(unsafe.Sizeof(node.data)) + contents
+	if !sl.noDict {
+		usage.Dict = len(sl.dict) * int(unsafe.Sizeof(node.data.Key)+unsafe.Sizeof(&node)+1)
+	}
+	usage.Total = usage.Nodes + usage.Entries + usage.Dict
+	return usage
+}
+
+// orderedBytes 返回字符串类型值所引用内容的字节数，其他类型返回 0
+// orderedBytes returns the length of the content referenced by a string value, 0 for other kinds
+func orderedBytes[T Ordered](v T) int {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.String {
+		return rv.Len()
+	}
+	return 0
+}
+
+// Level 返回跳表当前的层级，即最高节点的高度，空跳表为 1，与 Stats().Level 相同但不遍历跳表
+// Level returns the current level of the list, the height of its tallest node and 1 when empty,
+// the same as Stats().Level without walking the list
+func (sl *RankList[K, V]) Level() int {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	return sl.level
+}
+
+// LevelOf 返回键所在节点的高度，取值范围为 [1, MaxLevel]，键不存在时返回 false
+// LevelOf returns the height of the node holding key, within [1, MaxLevel], false if the key does not exist
+func (sl *RankList[K, V]) LevelOf(key K) (int, bool) {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	node, exists := sl.lookup(key)
+	if !exists {
+		return 0, false
+	}
+	return node.level, true
+}
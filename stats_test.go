@@ -0,0 +1,191 @@
+package ranklist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// searchHops 模拟一次查找，统计到达 key 所经过的前向指针数
+// searchHops simulates a search and counts the forward pointer hops needed to reach key
+func searchHops[K Ordered, V Ordered](sl *RankList[K, V], entry Entry[K, V]) int {
+	hops := 0
+	curr := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for curr.forward[i] != nil && compareEntries(curr.forward[i].data, entry) <= 0 {
+			curr = curr.forward[i]
+			hops++
+			if curr.data == entry {
+				return hops
+			}
+		}
+	}
+	return hops
+}
+
+func TestStats(t *testing.T) {
+	sl := New(WithSeed[string, int](1))
+	for i := 0; i < 5000; i++ {
+		sl.Set("key"+strconv.Itoa(i), i%97)
+	}
+	for i := 0; i < 1000; i++ {
+		sl.Del("key" + strconv.Itoa(i*3))
+	}
+
+	stats := sl.Stats()
+	if stats.Length != sl.Length() || stats.Level != sl.level {
+		t.Fatalf("unexpected length/level %d/%d", stats.Length, stats.Level)
+	}
+	if len(stats.LevelHistogram) != stats.Level || len(stats.LevelCounts) != stats.Level {
+		t.Fatalf("expected one histogram bucket per level")
+	}
+
+	sum := 0
+	for _, count := range stats.LevelHistogram {
+		sum += count
+	}
+	if sum != stats.Length {
+		t.Errorf("histogram sums to %d, expected %d", sum, stats.Length)
+	}
+
+	if stats.LevelCounts[0] != stats.Length {
+		t.Errorf("every node should be present at level 1, got %d", stats.LevelCounts[0])
+	}
+	for i := 1; i < len(stats.LevelCounts); i++ {
+		if stats.LevelCounts[i] > stats.LevelCounts[i-1] {
+			t.Errorf("level counts increase at level %d: %v", i+1, stats.LevelCounts)
+		}
+		if stats.LevelCounts[i-1]-stats.LevelCounts[i] != stats.LevelHistogram[i-1] {
+			t.Errorf("level counts disagree with the histogram at level %d", i)
+		}
+	}
+
+	hops := 0
+	for _, entry := range sl.Snapshot() {
+		hops += searchHops(sl, entry)
+	}
+	if expected := float64(hops) / float64(sl.Length()); stats.AvgSearchDepth != expected {
+		t.Errorf("expected average search depth %v, got %v", expected, stats.AvgSearchDepth)
+	}
+
+	if stats.NodeBytes <= 0 || stats.SpanBytes <= 0 || stats.DictBytes <= 0 {
+		t.Errorf("expected positive byte estimates, got %+v", stats)
+	}
+	if stats.Bytes != stats.NodeBytes+stats.SpanBytes+stats.DictBytes {
+		t.Errorf("total bytes should be the sum of its parts")
+	}
+}
+
+func TestStatsEmpty(t *testing.T) {
+	stats := New[int, int]().Stats()
+	if stats.Length != 0 || stats.Level != 1 || stats.AvgSearchDepth != 0 {
+		t.Errorf("unexpected stats for an empty list: %+v", stats)
+	}
+	if len(stats.LevelHistogram) != 1 || stats.LevelHistogram[0] != 0 {
+		t.Errorf("unexpected histogram for an empty list: %v", stats.LevelHistogram)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	sl := New(WithSeed[string, int](1))
+	if sl.Level() != 1 {
+		t.Fatalf("expected level 1 for an empty list, got %d", sl.Level())
+	}
+	if _, ok := sl.LevelOf("missing"); ok {
+		t.Fatal("LevelOf should return false for a missing key")
+	}
+
+	last := sl.Level()
+	for i := 0; i < 10000; i++ {
+		key := "key" + strconv.Itoa(i)
+		sl.Set(key, i)
+		level := sl.Level()
+		if level < last {
+			t.Fatalf("level dropped from %d to %d on insert", last, level)
+		}
+		last = level
+
+		height, ok := sl.LevelOf(key)
+		if !ok || height < 1 || height > level {
+			t.Fatalf("LevelOf(%s) = %d, %v with list level %d", key, height, ok, level)
+		}
+	}
+	if last < 4 || last != sl.Stats().Level {
+		t.Fatalf("expected 10000 elements to grow the list to the Stats level, got %d vs %d", last, sl.Stats().Level)
+	}
+
+	// 节点高度之和等于 Stats 的直方图
+	// The node heights add up to the Stats histogram
+	histogram := make([]int, last)
+	for i := 0; i < 10000; i++ {
+		height, _ := sl.LevelOf("key" + strconv.Itoa(i))
+		histogram[height-1]++
+	}
+	for i, count := range sl.Stats().LevelHistogram {
+		if histogram[i] != count {
+			t.Fatalf("height %d: LevelOf counts %d nodes, Stats %d", i+1, histogram[i], count)
+		}
+	}
+
+	if _, ok := sl.LevelOf("missing"); ok {
+		t.Fatal("LevelOf should return false for a missing key")
+	}
+}
+
+// memoryBoard 创建一个有 n 个条目、键带有 pad 个字节填充的跳表
+// memoryBoard creates a list of n entries whose keys carry pad bytes of padding
+func memoryBoard(n int, pad int, opts ...Option[string, int]) *RankList[string, int] {
+	sl := New(opts...)
+	padding := strings.Repeat("x", pad)
+	for i := range n {
+		sl.Set(fmt.Sprintf("key-%08d%s", i, padding), i%100)
+	}
+	return sl
+}
+
+func TestMemoryUsage(t *testing.T) {
+	small, large := memoryBoard(10000, 0).MemoryUsage(), memoryBoard(100000, 0).MemoryUsage()
+	if large.Entries != 10*small.Entries || large.Dict != 10*small.Dict {
+		t.Fatalf("entries and dictionary should scale linearly, got %+v and %+v", small, large)
+	}
+	if ratio := float64(large.Nodes) / float64(small.Nodes); ratio < 9 || ratio > 11 {
+		t.Fatalf("nodes should scale about linearly, got ratio %v", ratio)
+	}
+	for _, usage := range []MemoryBreakdown{small, large} {
+		if usage.Total != usage.Nodes+usage.Entries+usage.Dict {
+			t.Fatalf("total should be the sum of its parts, got %+v", usage)
+		}
+		levels := 0
+		for i, bytes := range usage.Levels {
+			if i > 0 && bytes > usage.Levels[i-1] {
+				t.Fatalf("level bytes increase at level %d: %v", i+1, usage.Levels)
+			}
+			levels += bytes
+		}
+		if levels <= 0 || levels >= usage.Nodes {
+			t.Fatalf("level bytes should be part of the node bytes, got %d of %d", levels, usage.Nodes)
+		}
+	}
+
+	// 更长的键只增加条目内容的字节数
+	// Longer keys only add to the bytes of the entry contents
+	padded := memoryBoard(10000, 64).MemoryUsage()
+	if padded.Entries-small.Entries != 10000*64 || padded.Dict != small.Dict {
+		t.Fatalf("expected 64 more bytes per key, got %+v and %+v", small, padded)
+	}
+
+	noDict := memoryBoard(10000, 0, WithNoDict[string, int]()).MemoryUsage()
+	if noDict.Dict != 0 || noDict.Entries != small.Entries {
+		t.Fatalf("without a dictionary only the dictionary bytes should vanish, got %+v", noDict)
+	}
+
+	ints := New[int, int]()
+	for i := range 1000 {
+		ints.Set(i, i)
+	}
+	if usage := ints.MemoryUsage(); usage.Entries != 1000*int(unsafe.Sizeof(Entry[int, int]{})) {
+		t.Fatalf("integer entries should cost their size only, got %d", usage.Entries)
+	}
+}
@@ -0,0 +1,71 @@
+package ranklist
+
+import "context"
+
+// streamChunkSize RangeStream 每次持有读锁读取的条目数
+// streamChunkSize is how many entries RangeStream reads per read lock acquisition
+const streamChunkSize = 1024
+
+// RangeStream 将排名区间 [start, end) 内的条目依次发送到返回的通道中，buf 是通道的缓冲大小。
+// 遍历按块进行，每块在读锁内读取，发送时释放读锁，下一块从上一块最后一个条目之后继续，
+// 因此即使窗口很大也不会在内存中构建完整的切片，写操作也不会被整个遍历阻塞。
+// 全部发送完毕、ctx 被取消或跳表被 Close 关闭时关闭通道，取消后不会再持有读锁。
+// 一致性模型：窗口的起点和条目数在读取第一块时确定，每一块内部是一致的，但块与块之间可能发生写入；
+// 遍历期间移动到游标另一侧的键可能被跳过或重复输出，此时后续条目的实际排名可能与窗口有偏差
+// RangeStream sends the entries within the rank range [start, end) one by one on the returned channel,
+// buf is the buffer size of the channel.
+// The walk proceeds in chunks, each read under the read lock which is released while sending,
+// and every chunk resumes right after the last entry of the previous one,
+// so a large window is never materialized as a slice and writers are not blocked for the whole walk.
+// The channel is closed once everything is sent, when ctx is cancelled or when the list is closed,
+// no read lock is held after cancellation.
+// Consistency model: the start and the number of entries of the window are fixed when the first chunk is read,
+// each chunk is internally consistent but writes may land between chunks;
+// keys that move across the cursor during the walk may be skipped or emitted twice,
+// and the actual ranks of later entries may then drift from the window
+func (sl *RankList[K, V]) RangeStream(ctx context.Context, start int, end int, buf int) <-chan Entry[K, V] {
+	sl.vars.add(opRange)
+	ch := make(chan Entry[K, V], buf)
+	if !sl.startBackground() {
+		close(ch)
+		return ch
+	}
+
+	go func() {
+		defer sl.background.Done()
+		defer close(ch)
+
+		chunk := make([]Entry[K, V], 0, streamChunkSize)
+		remaining := -1
+		for remaining != 0 && ctx.Err() == nil {
+			sl.RLock()
+			var curr *Node[K, V]
+			if remaining < 0 {
+				remaining = sl.rangeSize(start, end)
+				curr = sl.byRank(max(start, 1))
+			} else {
+				curr = sl.seekAfter(chunk[len(chunk)-1])
+			}
+			chunk = chunk[:0]
+			for ; curr != nil && len(chunk) < min(remaining, streamChunkSize); curr = curr.forward[0] {
+				chunk = append(chunk, curr.data)
+			}
+			sl.RUnlock()
+
+			for _, entry := range chunk {
+				select {
+				case ch <- entry:
+				case <-ctx.Done():
+					return
+				case <-sl.done:
+					return
+				}
+			}
+			if len(chunk) < streamChunkSize {
+				return
+			}
+			remaining -= len(chunk)
+		}
+	}()
+	return ch
+}
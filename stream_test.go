@@ -0,0 +1,68 @@
+package ranklist
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRangeStream(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 3*streamChunkSize+10; i++ {
+		sl.Set(i, i%100)
+	}
+
+	windows := [][2]int{
+		{1, sl.Length() + 1},
+		{streamChunkSize, 2*streamChunkSize + 1},
+		{1, streamChunkSize + 1},
+		{sl.Length(), sl.Length() + 10},
+		{sl.Length() + 1, sl.Length() + 10},
+		{5, 5},
+	}
+	for _, w := range windows {
+		var got []Entry[int, int]
+		for entry := range sl.RangeStream(context.Background(), w[0], w[1], 16) {
+			got = append(got, entry)
+		}
+		if expected := sl.Range(w[0], w[1]); !slices.Equal(got, expected) {
+			t.Fatalf("window %v: expected %d entries matching Range, got %d", w, len(expected), len(got))
+		}
+	}
+}
+
+func TestRangeStreamCancel(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 3*streamChunkSize; i++ {
+		sl.Set(i, i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := sl.RangeStream(ctx, 1, sl.Length()+1, 0)
+	for i := 0; i < 10; i++ {
+		<-ch
+	}
+	cancel()
+
+	// 取消后读锁必须很快释放，写操作不会被阻塞
+	// The read lock must be released promptly after cancellation, so writers are not blocked
+	done := make(chan struct{})
+	go func() {
+		sl.Set(-1, -1)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Set blocked after the stream was cancelled")
+	}
+
+	n := 0
+	for range ch {
+		n++
+	}
+	if n > 1 {
+		t.Fatalf("expected at most one entry after cancellation, got %d", n)
+	}
+}
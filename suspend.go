@@ -0,0 +1,120 @@
+package ranklist
+
+// suspendedEntry 暂停期间保存的条目及其状态
+// suspendedEntry holds a suspended entry together with its state
+type suspendedEntry[V Ordered] struct {
+	entryState[V]
+	meta map[string]string
+
+	// 暂停的序号，用来识别清理队列中的过期项
+	// Sequence number of the suspension, identifying stale items in the sweep queue
+	seq uint64
+}
+
+// Suspend 暂停键的排名：键从跳表中移除，Range、Rank、Length 和 Get 都视其为不存在，
+// 但它的值、元数据、条目版本和时间戳被保留下来，之后可以通过 Resume 原样恢复。
+// 键不存在或已被暂停时返回 false。
+// 暂停对阈值回调和预写日志而言就是一次删除，被暂停的条目只保存在内存中，不会写入快照或日志，
+// 直到被 Resume、Del 或 Sweep 清除之前一直占用内存
+// Suspend takes key out of the standings: it is removed from the skip list, so Range, Rank, Length and Get
+// all treat it as absent, but its value, metadata, entry version and timestamps are parked and can be put back
+// unchanged by Resume. Returns false if the key does not exist or is already suspended.
+// To threshold callbacks and the write-ahead log a suspension is a delete,
+// parked entries live in memory only and are never written to snapshots or the log,
+// and they hold on to that memory until Resume, Del or Sweep removes them
+func (sl *RankList[K, V]) Suspend(key K) bool {
+	sl.Lock()
+	node, exists := sl.lookup(key)
+	if !exists || sl.closed || sl.persist(walOpDel, key, ZeroValue[V]()) != nil {
+		sl.Unlock()
+		return false
+	}
+	parked := suspendedEntry[V]{
+		entryState: entryState[V]{value: node.data.Value, version: node.version, times: node.times, placeholder: node.placeholder},
+		meta:       sl.meta[key],
+	}
+
+	probes := sl.probeThresholds(key)
+	ok, divergence := sl.del(key)
+	if ok {
+		sl.park(key, parked)
+		delete(sl.meta, key)
+		sl.journal(walOpDel, key, ZeroValue[V]())
+	}
+	events := sl.thresholdEvents(key, probes)
+	sl.Unlock()
+
+	sl.reportDivergence(key, divergence)
+	fireThresholds(events)
+	return ok
+}
+
+// Resume 将被暂停的键以暂停时的值重新插入跳表，并恢复它的元数据、条目版本和创建、更新时间，
+// 恢复不经过 WithValueGuard 的校验。键未被暂停时返回 false，跳表达到 WithHardLimit 时同样返回 false，键保持暂停。
+// 暂停期间如果键被 Set 重新写入，新写入的条目优先：暂停时保存的条目被丢弃并返回 false。
+// 注意 Restore 是整体替换内容的批量操作，与本方法无关
+// Resume reinserts a suspended key at the value it had when suspended,
+// restoring its metadata, entry version and creation and update times; the value skips WithValueGuard.
+// Returns false if the key is not suspended, and also when the list is at its WithHardLimit, the key then stays suspended.
+// When the key was written again by Set while suspended the new entry wins:
+// the parked entry is discarded and false is returned.
+// Note that Restore is the unrelated bulk operation replacing the whole content
+func (sl *RankList[K, V]) Resume(key K) bool {
+	sl.Lock()
+	parked, suspended := sl.suspended[key]
+	if !suspended || sl.closed {
+		sl.Unlock()
+		return false
+	}
+	if _, exists := sl.lookup(key); exists {
+		delete(sl.suspended, key)
+		sl.Unlock()
+		return false
+	}
+	op := walOpSet
+	if parked.placeholder {
+		op = walOpReserve
+	}
+	if sl.guardFull(key, parked.value, sl.length) != nil || sl.persist(op, key, parked.value) != nil {
+		sl.Unlock()
+		return false
+	}
+	delete(sl.suspended, key)
+
+	probes := sl.probeThresholds(key)
+	sl.set(key, parked.value)
+	if node, exists := sl.lookup(key); exists {
+		sl.dictMu.Lock()
+		node.version = parked.version
+		node.times = parked.times
+		node.placeholder = parked.placeholder
+		sl.dictMu.Unlock()
+		sl.trackStale(node)
+		if parked.meta != nil {
+			if sl.meta == nil {
+				sl.meta = make(map[K]map[string]string)
+			}
+			sl.meta[key] = parked.meta
+		}
+	}
+	sl.journal(op, key, parked.value)
+	sl.evictOverflow()
+	events := sl.thresholdEvents(key, probes)
+	sl.Unlock()
+
+	sl.vars.add(opSet)
+	fireThresholds(events)
+	return true
+}
+
+// Suspended 返回被暂停的键在暂停时的值，键未被暂停时返回 false
+// Suspended returns the value a suspended key had when it was suspended, false if the key is not suspended
+func (sl *RankList[K, V]) Suspended(key K) (V, bool) {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	if parked, suspended := sl.suspended[key]; suspended {
+		return parked.value, true
+	}
+	return ZeroValue[V](), false
+}
@@ -0,0 +1,111 @@
+package ranklist
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuspendResume(t *testing.T) {
+	clock := &fakeClock{}
+	sl := New(WithTimestamps[string, int](clock.read))
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	sl.Set("b", 25)
+	sl.SetMeta("b", map[string]string{"team": "red"})
+	_, version, _ := sl.GetVersioned("b")
+	created, updated, _ := sl.GetTimes("b")
+
+	if !sl.Suspend("b") {
+		t.Fatalf("expected b to be suspended")
+	}
+	if sl.Suspend("b") || sl.Suspend("missing") {
+		t.Fatalf("suspending a suspended or missing key should return false")
+	}
+	if _, ok := sl.Get("b"); ok {
+		t.Fatalf("a suspended key should be reported as absent by Get")
+	}
+	if _, ok := sl.Rank("b"); ok || sl.Length() != 2 {
+		t.Fatalf("a suspended key should be excluded from ranks and length")
+	}
+	if entries := sl.Range(1, 10); !slices.Equal(entries, []Entry[string, int]{{"a", 10}, {"c", 30}}) {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+	if value, ok := sl.Suspended("b"); !ok || value != 25 {
+		t.Fatalf("expected b to be suspended at 25, got %d, %v", value, ok)
+	}
+
+	if !sl.Resume("b") {
+		t.Fatalf("expected b to be resumed")
+	}
+	if sl.Resume("b") {
+		t.Fatalf("resuming twice should return false")
+	}
+	if rank, ok := sl.Rank("b"); !ok || rank != 2 {
+		t.Fatalf("expected b back at rank 2, got %d, %v", rank, ok)
+	}
+	if _, v, _ := sl.GetVersioned("b"); v != version {
+		t.Fatalf("expected version %d to survive, got %d", version, v)
+	}
+	requireTimes(t, sl, "b", created, updated)
+	if meta, _ := sl.GetMeta("b"); meta["team"] != "red" {
+		t.Fatalf("expected metadata to survive, got %v", meta)
+	}
+	if _, ok := sl.Suspended("b"); ok {
+		t.Fatalf("a resumed key should no longer be suspended")
+	}
+}
+
+func TestSuspendDel(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 10)
+	sl.Suspend("a")
+
+	if !sl.Del("a") {
+		t.Fatalf("deleting a suspended key should return true")
+	}
+	if sl.Del("a") || sl.Resume("a") {
+		t.Fatalf("a deleted suspended key should be gone for good")
+	}
+	if sl.Length() != 0 {
+		t.Fatalf("expected an empty list, got %d", sl.Length())
+	}
+
+	sl.Set("b", 20)
+	sl.Suspend("b")
+	sl.Clear()
+	if sl.Resume("b") {
+		t.Fatalf("Clear should discard suspended keys")
+	}
+}
+
+func TestSuspendSetWins(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 10)
+	sl.Suspend("a")
+	sl.Set("a", 40)
+
+	if sl.Resume("a") {
+		t.Fatalf("resuming over a live key should return false")
+	}
+	if value, _ := sl.Get("a"); value != 40 {
+		t.Fatalf("expected the newer value 40 to win, got %d", value)
+	}
+	if _, ok := sl.Suspended("a"); ok {
+		t.Fatalf("the parked entry should be discarded")
+	}
+}
+
+func TestSuspendThresholds(t *testing.T) {
+	var events []bool
+	sl := New(WithThreshold[string, int](1, func(key string, entered bool) {
+		events = append(events, entered)
+	}))
+	sl.Set("a", 10)
+	sl.Suspend("a")
+	sl.Resume("a")
+
+	if !slices.Equal(events, []bool{true, false, true}) {
+		t.Fatalf("expected enter, leave, enter, got %v", events)
+	}
+}
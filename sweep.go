@@ -0,0 +1,99 @@
+package ranklist
+
+// autoSweepBatch 每次自动清理最多清除的暂停条目数，使清理的开销分摊到多次 Suspend 上
+// autoSweepBatch is the most parked entries one automatic sweep removes, spreading the cost over many Suspend calls
+const autoSweepBatch = 16
+
+// sweepItem 清理队列中的一项，seq 与键当前保存的条目不一致时说明该项已过期
+// sweepItem is one item of the sweep queue, it is stale once seq no longer matches the entry parked for the key
+type sweepItem[K Ordered] struct {
+	key K
+	seq uint64
+}
+
+// WithAutoSweep 在暂停条目占跳表成员与暂停条目之和的比例超过 ratio 时自动清理，
+// 每次 Suspend 之后检查一次，每次最多按暂停的先后清除 16 个最早暂停的条目，开销分摊到之后的多次 Suspend 上。
+// 被清除的条目与 Sweep 相同无法再被 Resume 恢复，因此这相当于为软删除设置保留上限。
+// ratio 不在 (0, 1) 区间内时 panic
+// WithAutoSweep sweeps automatically once parked entries make up more than ratio of the list members plus the parked entries.
+// The ratio is checked after every Suspend and each sweep removes at most the 16 earliest suspended entries,
+// spreading the cost over the following Suspend calls.
+// As with Sweep, removed entries can no longer be resumed, so this puts a retention bound on soft deletes.
+// It panics if ratio is not within (0, 1)
+func WithAutoSweep[K Ordered, V Ordered](ratio float64) Option[K, V] {
+	if ratio <= 0 || ratio >= 1 {
+		panic("ranklist: sweep ratio must be within (0, 1)")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.sweepRatio = ratio
+	}
+}
+
+// Sweep 按暂停的先后永久清除最多 limit 个被 Suspend 暂停的条目，返回实际清除的数量，
+// 调用方可以每次清除少量条目来分摊开销。被清除的键无法再被 Resume 恢复，Suspended 也不再报告它们。
+// 暂停条目不在跳表中，因此清理前后 Range、Rank 和 Length 的结果完全相同；剩余的暂停条目数见 Stats 的 Tombstones。
+// 每个条目的开销为均摊 O(1)；跳表被 Close 关闭后返回 0
+// Sweep permanently removes up to limit entries parked by Suspend, the earliest suspended first,
+// and returns how many it removed, so callers can amortize the work over several calls.
+// Removed keys can no longer be resumed and Suspended no longer reports them.
+// Parked entries are outside the list, so Range, Rank and Length return exactly the same before and after a sweep;
+// the number of parked entries left is reported as Tombstones by Stats.
+// It costs amortized O(1) per entry and returns 0 once the list is closed
+func (sl *RankList[K, V]) Sweep(limit int) int {
+	sl.Lock()
+	defer sl.Unlock()
+
+	if sl.closed {
+		return 0
+	}
+	return sl.sweep(limit)
+}
+
+// sweep 按暂停的先后清除最多 limit 个暂停条目，跳过队列中的过期项，调用方需持有写锁
+// sweep removes up to limit parked entries in suspension order, skipping stale queue items. The caller must hold the write lock
+func (sl *RankList[K, V]) sweep(limit int) int {
+	swept := 0
+	for swept < limit && len(sl.sweepQueue) > 0 {
+		item := sl.sweepQueue[0]
+		sl.sweepQueue[0] = sweepItem[K]{}
+		sl.sweepQueue = sl.sweepQueue[1:]
+		if parked, ok := sl.suspended[item.key]; ok && parked.seq == item.seq {
+			delete(sl.suspended, item.key)
+			swept++
+		}
+	}
+	if len(sl.suspended) == 0 {
+		sl.suspended, sl.sweepQueue = nil, nil
+	}
+	return swept
+}
+
+// park 保存被暂停的条目并将它排入清理队列，开启 WithAutoSweep 时按需清理，调用方需持有写锁
+// park keeps a suspended entry and queues it for sweeping, sweeping when WithAutoSweep asks for it.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) park(key K, parked suspendedEntry[V]) {
+	if sl.suspended == nil {
+		sl.suspended = make(map[K]suspendedEntry[V])
+	}
+	sl.parkSeq++
+	parked.seq = sl.parkSeq
+	sl.suspended[key] = parked
+	sl.sweepQueue = append(sl.sweepQueue, sweepItem[K]{key: key, seq: parked.seq})
+
+	// Resume 和 Del 留下的过期项超过一半时压缩队列，使队列的长度与暂停条目数成正比
+	// Compact the queue once most of it is stale items left by Resume and Del, keeping it proportional to the parked entries
+	if len(sl.sweepQueue) > 2*len(sl.suspended)+autoSweepBatch {
+		live := sl.sweepQueue[:0]
+		for _, item := range sl.sweepQueue {
+			if p, ok := sl.suspended[item.key]; ok && p.seq == item.seq {
+				live = append(live, item)
+			}
+		}
+		clear(sl.sweepQueue[len(live):])
+		sl.sweepQueue = live
+	}
+
+	if sl.sweepRatio > 0 && float64(len(sl.suspended)) > sl.sweepRatio*float64(sl.length+len(sl.suspended)) {
+		sl.sweep(autoSweepBatch)
+	}
+}
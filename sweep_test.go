@@ -0,0 +1,120 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestSweep(t *testing.T) {
+	sl := New[string, int]()
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		sl.Set(key, (i+1)*10)
+	}
+	for _, key := range []string{"b", "d", "a", "e"} {
+		sl.Suspend(key)
+	}
+	sl.Resume("d")
+	sl.Del("a")
+	if stats := sl.Stats(); stats.Tombstones != 2 || stats.Length != 2 {
+		t.Fatalf("expected 2 tombstones and length 2, got %d and %d", stats.Tombstones, stats.Length)
+	}
+
+	// 最早暂停的 b 先被清除，d 和 a 留下的过期项被跳过
+	// b, suspended first, goes first, the stale items left by d and a are skipped
+	if swept := sl.Sweep(1); swept != 1 {
+		t.Fatalf("expected 1 entry swept, got %d", swept)
+	}
+	if _, ok := sl.Suspended("b"); ok || sl.Resume("b") {
+		t.Fatalf("a swept key should no longer be suspended")
+	}
+	if _, ok := sl.Suspended("e"); !ok {
+		t.Fatalf("expected e to still be suspended")
+	}
+	if swept := sl.Sweep(10); swept != 1 {
+		t.Fatalf("expected the last entry swept, got %d", swept)
+	}
+	if swept := sl.Sweep(10); swept != 0 || sl.Stats().Tombstones != 0 {
+		t.Fatalf("nothing should be left to sweep, swept %d", swept)
+	}
+	requireModel(t, sl, map[string]int{"c": 30, "d": 40})
+
+	sl.Suspend("c")
+	sl.Close()
+	if swept := sl.Sweep(10); swept != 0 {
+		t.Fatalf("a closed list should not be swept, got %d", swept)
+	}
+}
+
+func TestAutoSweep(t *testing.T) {
+	sl := New(WithAutoSweep[int, int](0.25))
+	for i := 0; i < 100; i++ {
+		sl.Set(i, i)
+	}
+	for i := 0; i < 60; i++ {
+		sl.Suspend(i)
+		if stats := sl.Stats(); float64(stats.Tombstones) > 0.25*float64(stats.Length+stats.Tombstones)+1 {
+			t.Fatalf("suspension %d: %d tombstones next to %d members", i, stats.Tombstones, stats.Length)
+		}
+	}
+	// 最早暂停的条目先被清除 / The earliest suspended entries are swept first
+	if _, ok := sl.Suspended(0); ok {
+		t.Fatalf("expected the earliest suspension to be swept")
+	}
+	if _, ok := sl.Suspended(59); !ok {
+		t.Fatalf("expected the latest suspension to be kept")
+	}
+	if sl.Length() != 40 {
+		t.Fatalf("sweeping should not touch the list, got length %d", sl.Length())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for a ratio outside (0, 1)")
+		}
+	}()
+	WithAutoSweep[int, int](1)
+}
+
+func TestSweepRandomized(t *testing.T) {
+	for seed := uint64(1); seed <= 20; seed++ {
+		rng := rand.New(rand.NewPCG(seed, seed))
+		sl := New[string, int]()
+		for step := 0; step < 2000; step++ {
+			key := strconv.Itoa(rng.IntN(200))
+			switch op := rng.IntN(10); {
+			case op < 5:
+				sl.Set(key, rng.IntN(50))
+			case op < 7:
+				sl.Suspend(key)
+			case op < 8:
+				sl.Resume(key)
+			case op < 9:
+				sl.Del(key)
+			default:
+				entries := sl.Range(1, sl.Length()+1)
+				ranks := make([]int, len(entries))
+				for i, entry := range entries {
+					ranks[i], _ = sl.Rank(entry.Key)
+				}
+				tombstones := sl.Stats().Tombstones
+				swept := sl.Sweep(rng.IntN(10))
+				if after := sl.Stats().Tombstones; after != tombstones-swept {
+					t.Fatalf("seed %d, step %d: expected %d tombstones, got %d", seed, step, tombstones-swept, after)
+				}
+				if got := sl.Range(1, sl.Length()+1); !slices.Equal(got, entries) {
+					t.Fatalf("seed %d, step %d: Range changed across a sweep", seed, step)
+				}
+				for i, entry := range entries {
+					if rank, ok := sl.Rank(entry.Key); !ok || rank != ranks[i] {
+						t.Fatalf("seed %d, step %d: rank of %s changed across a sweep", seed, step, entry.Key)
+					}
+				}
+			}
+		}
+		if err := sl.Check(); err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+	}
+}
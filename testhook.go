@@ -0,0 +1,31 @@
+package ranklist
+
+import "github.com/werbenhu/ranklist/internal/testhook"
+
+func init() {
+	testhook.CorruptSpan = func(list any, rank, level int) bool {
+		sl, ok := list.(spanCorrupter)
+		return ok && sl.corruptSpan(rank, level)
+	}
+}
+
+// spanCorrupter 由任意类型参数的 *RankList 实现，让 testhook 不必知道类型参数
+// spanCorrupter is implemented by *RankList of any type arguments, so testhook does not need to know them
+type spanCorrupter interface {
+	corruptSpan(rank, level int) bool
+}
+
+// corruptSpan 在写锁内将排名为 rank 的节点在第 level 层（从 1 开始）的跨度加一，只用于测试 Check 和 WithAuditor
+// corruptSpan adds one to the span of the node ranked rank on level level, counted from 1, under the write lock.
+// It is only meant for testing Check and WithAuditor
+func (sl *RankList[K, V]) corruptSpan(rank, level int) bool {
+	sl.Lock()
+	defer sl.Unlock()
+
+	node := sl.byRank(rank)
+	if node == nil || level < 1 || level > node.level {
+		return false
+	}
+	node.span[level-1]++
+	return true
+}
@@ -0,0 +1,106 @@
+package ranklist
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// entryJSON 与 Entry 字段相同但没有方法，用于以对象形式编码 Entry
+// entryJSON has the same fields as Entry without its methods, used to encode Entry as an object
+type entryJSON[K Ordered, V Ordered] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// String 以 "key=alice value=4200" 的格式返回条目，需要时字符串会加上引号
+// String returns the entry formatted as "key=alice value=4200", strings are quoted when needed
+func (e Entry[K, V]) String() string {
+	return "key=" + formatText(e.Key) + " value=" + formatText(e.Value)
+}
+
+// MarshalText 实现 encoding.TextMarshaler，格式与 String 相同，是 ParseEntry 的逆操作
+// MarshalText implements encoding.TextMarshaler with the same format as String, it is the inverse of ParseEntry
+func (e Entry[K, V]) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// MarshalJSON 将条目编码为 {"key":..,"value":..} 对象。
+// 实现 TextMarshaler 后 encoding/json 会默认把条目编码为字符串，因此需要显式保留对象形式
+// MarshalJSON encodes the entry as a {"key":..,"value":..} object.
+// Implementing TextMarshaler would otherwise make encoding/json encode entries as strings,
+// so the object form is kept explicitly
+func (e Entry[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(entryJSON[K, V](e))
+}
+
+// ParseEntry 解析 MarshalText 输出的文本
+// ParseEntry parses text produced by MarshalText
+func ParseEntry[K Ordered, V Ordered](s string) (Entry[K, V], error) {
+	var entry Entry[K, V]
+
+	rest, ok := strings.CutPrefix(s, "key=")
+	if !ok {
+		return entry, fmt.Errorf("ranklist: parse entry %q: missing key", s)
+	}
+	token, rest, err := cutText(rest)
+	if err != nil {
+		return entry, fmt.Errorf("ranklist: parse entry %q: key: %w", s, err)
+	}
+	if entry.Key, err = parseOrdered[K](token); err != nil {
+		return entry, fmt.Errorf("ranklist: parse entry %q: key: %w", s, err)
+	}
+
+	rest, ok = strings.CutPrefix(rest, " value=")
+	if !ok {
+		return entry, fmt.Errorf("ranklist: parse entry %q: missing value", s)
+	}
+	token, rest, err = cutText(rest)
+	if err != nil {
+		return entry, fmt.Errorf("ranklist: parse entry %q: value: %w", s, err)
+	}
+	if rest != "" {
+		return entry, fmt.Errorf("ranklist: parse entry %q: trailing text", s)
+	}
+	if entry.Value, err = parseOrdered[V](token); err != nil {
+		return entry, fmt.Errorf("ranklist: parse entry %q: value: %w", s, err)
+	}
+	return entry, nil
+}
+
+// formatText 格式化一个字段，包含空格、等号、引号或不可打印字符的字符串以及空字符串会被加上引号
+// formatText formats one field, strings that are empty or contain spaces, '=', quotes or unprintable characters are quoted
+func formatText[T Ordered](v T) string {
+	s := formatOrdered(v)
+	if reflect.ValueOf(v).Kind() != reflect.String {
+		return s
+	}
+	if s == "" || strings.IndexFunc(s, needsQuote) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func needsQuote(r rune) bool {
+	return r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r)
+}
+
+// cutText 从 s 的开头切出一个字段，返回其未加引号的内容和剩余部分
+// cutText cuts one field off the front of s, returning its unquoted content and the remainder
+func cutText(s string) (string, string, error) {
+	if strings.HasPrefix(s, `"`) {
+		quoted, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return "", s, err
+		}
+		token, err := strconv.Unquote(quoted)
+		return token, s[len(quoted):], err
+	}
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		return s[:i], s[i:], nil
+	}
+	return s, "", nil
+}
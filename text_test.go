@@ -0,0 +1,107 @@
+package ranklist
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+)
+
+var _ encoding.TextMarshaler = Entry[string, int]{}
+
+func TestEntryString(t *testing.T) {
+	tests := []struct {
+		entry    fmt.Stringer
+		expected string
+	}{
+		{Entry[string, int]{Key: "alice", Value: 4200}, "key=alice value=4200"},
+		{Entry[string, int]{Key: "a b", Value: -1}, `key="a b" value=-1`},
+		{Entry[string, int]{Key: "a=b", Value: 0}, `key="a=b" value=0`},
+		{Entry[string, int]{Key: "", Value: 0}, `key="" value=0`},
+		{Entry[int, float64]{Key: 7, Value: 1.5}, "key=7 value=1.5"},
+		{Entry[int, string]{Key: 7, Value: "x y"}, `key=7 value="x y"`},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.String(); got != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, got)
+		}
+		if got := fmt.Sprint(tt.entry); got != tt.expected {
+			t.Errorf("%%v should use String, got %q", got)
+		}
+	}
+}
+
+func TestEntryJSONStaysObject(t *testing.T) {
+	data, err := json.Marshal([]Entry[string, int]{{Key: "a", Value: 1}})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(data) != `[{"key":"a","value":1}]` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+
+	var entries []Entry[string, int]
+	if err := json.Unmarshal(data, &entries); err != nil || entries[0] != (Entry[string, int]{Key: "a", Value: 1}) {
+		t.Errorf("JSON round trip failed: %v %v", entries, err)
+	}
+}
+
+func TestParseEntry(t *testing.T) {
+	entry, err := ParseEntry[string, int]("key=alice value=4200")
+	if err != nil || entry != (Entry[string, int]{Key: "alice", Value: 4200}) {
+		t.Errorf("unexpected entry %v, %v", entry, err)
+	}
+
+	entry, err = ParseEntry[string, int](`key="x=1 value=2" value=3`)
+	if err != nil || entry != (Entry[string, int]{Key: "x=1 value=2", Value: 3}) {
+		t.Errorf("unexpected entry %v, %v", entry, err)
+	}
+
+	for _, s := range []string{
+		"",
+		"alice value=1",
+		"key=alice",
+		"key=alice value=x",
+		"key=alice value=1 extra",
+		`key="alice value=1`,
+		"key=alice  value=1",
+	} {
+		if _, err := ParseEntry[string, int](s); err == nil {
+			t.Errorf("expected %q to be rejected", s)
+		}
+	}
+
+	floats, err := ParseEntry[int, float64](Entry[int, float64]{Key: 1, Value: math.Inf(-1)}.String())
+	if err != nil || !math.IsInf(floats.Value, -1) {
+		t.Errorf("unexpected entry %v, %v", floats, err)
+	}
+}
+
+func FuzzEntryText(f *testing.F) {
+	for _, seed := range []struct{ key, value string }{
+		{"alice", "bob"},
+		{"a b", "c=d"},
+		{"key=x value=y", `"quoted"`},
+		{"", " "},
+		{"tab\there", "new\nline"},
+		{"\x00\xff", "é"},
+	} {
+		f.Add(seed.key, seed.value)
+	}
+
+	f.Fuzz(func(t *testing.T, key, value string) {
+		entry := Entry[string, string]{Key: key, Value: value}
+		text, err := entry.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText failed: %v", err)
+		}
+		parsed, err := ParseEntry[string, string](string(text))
+		if err != nil {
+			t.Fatalf("ParseEntry(%q) failed: %v", text, err)
+		}
+		if parsed != entry {
+			t.Fatalf("round trip of %q gave %#v, expected %#v", text, parsed, entry)
+		}
+	})
+}
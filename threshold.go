@@ -0,0 +1,171 @@
+package ranklist
+
+// threshold 记录一个排名阈值及其回调
+// threshold records a rank boundary and its callback
+type threshold[K Ordered] struct {
+	rank int
+	fn   func(key K, entered bool)
+}
+
+// thresholdEvent 记录一次跨越阈值的事件，在解锁后触发
+// thresholdEvent records a boundary crossing, fired after the lock is released
+type thresholdEvent[K Ordered] struct {
+	fn      func(key K, entered bool)
+	key     K
+	entered bool
+}
+
+// zoneProbe 记录写入前某个阈值附近的状态
+// zoneProbe records the state around one threshold before a write
+type zoneProbe[K Ordered] struct {
+	// 被写入的键是否在区域内
+	// Whether the written key was inside the zone
+	in bool
+
+	// 写入前位于阈值排名和阈值下一名的成员
+	// Members at the threshold rank and right below it before the write
+	edge, next       K
+	hasEdge, hasNext bool
+
+	// 开启 WithMaxSize 时写入前区域内的全部成员。淘汰会让其他成员移动，只看边界不再足够
+	// Every member inside the zone before the write when WithMaxSize is enabled.
+	// Evictions shift other members too, so watching the boundary is no longer enough
+	zone map[K]struct{}
+}
+
+// WithThreshold 注册一个排名阈值回调，排名 1 到 rank 为阈值区域。
+// 每次写入后，进入区域的成员以 entered=true 回调，离开区域的成员以 entered=false 回调。
+// 回调在释放锁之后执行，可以注册多个阈值
+// WithThreshold registers a rank threshold callback, ranks 1 through rank form the zone.
+// After each mutation, members that entered the zone are reported with entered=true
+// and members that left it with entered=false.
+// Callbacks run after the lock is released, multiple thresholds are allowed
+func WithThreshold[K Ordered, V Ordered](rank int, fn func(key K, entered bool)) Option[K, V] {
+	if rank < 1 {
+		panic("ranklist: threshold rank must be positive")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.thresholds = append(sl.thresholds, threshold[K]{rank: rank, fn: fn})
+	}
+}
+
+// probeThresholds 在写入 key 之前记录每个阈值附近的状态，调用方需持有写锁
+// probeThresholds records the state around each threshold before key is written.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) probeThresholds(key K) []zoneProbe[K] {
+	if len(sl.thresholds) == 0 {
+		return nil
+	}
+
+	probes := make([]zoneProbe[K], len(sl.thresholds))
+	if sl.maxSize > 0 {
+		for i, zone := range sl.zoneKeys() {
+			probes[i].zone = zone
+		}
+		return probes
+	}
+	rank, exists := sl.rank(key)
+	for i, t := range sl.thresholds {
+		probes[i].in = exists && rank <= t.rank
+		if node := sl.byRank(t.rank); node != nil {
+			probes[i].edge, probes[i].hasEdge = node.data.Key, true
+		}
+		if node := sl.byRank(t.rank + 1); node != nil {
+			probes[i].next, probes[i].hasNext = node.data.Key, true
+		}
+	}
+	return probes
+}
+
+// thresholdEvents 根据写入前的状态计算跨越阈值的成员，连同写入期间的淘汰一起返回，调用方需持有写锁。
+// 单次写入只会让被写入的键移动，其他成员最多移动一位，因此只有阈值边界上的成员可能跨越阈值；
+// 写入可能触发淘汰时退回到对比整个区域
+// thresholdEvents computes the members that crossed each threshold and returns them together with the evictions
+// made during the write. The caller must hold the write lock.
+// A single write only moves the written key, every other member shifts by at most one position,
+// so only the members sitting on the boundary can cross it; when the write may evict, whole zones are compared instead
+func (sl *RankList[K, V]) thresholdEvents(key K, probes []zoneProbe[K]) []thresholdEvent[K] {
+	if len(probes) > 0 && probes[0].zone != nil {
+		zones := make([]map[K]struct{}, len(probes))
+		for i, probe := range probes {
+			zones[i] = probe.zone
+		}
+		return sl.zoneEvents(zones)
+	}
+
+	events := append(append(sl.keyspaceEvents(), sl.rankEvents()...), sl.evictEvents()...)
+	if len(probes) == 0 {
+		return events
+	}
+
+	rank, exists := sl.rank(key)
+	for i, t := range sl.thresholds {
+		probe := probes[i]
+		in := exists && rank <= t.rank
+		if in != probe.in {
+			events = append(events, thresholdEvent[K]{fn: t.fn, key: key, entered: in})
+		}
+		if probe.hasEdge && probe.edge != key {
+			if r, ok := sl.rank(probe.edge); ok && r > t.rank {
+				events = append(events, thresholdEvent[K]{fn: t.fn, key: probe.edge, entered: false})
+			}
+		}
+		if probe.hasNext && probe.next != key {
+			if r, ok := sl.rank(probe.next); ok && r <= t.rank {
+				events = append(events, thresholdEvent[K]{fn: t.fn, key: probe.next, entered: true})
+			}
+		}
+	}
+	return events
+}
+
+// zoneKeys 记录批量修改前每个阈值区域内的成员，调用方需持有写锁
+// zoneKeys records the members inside each threshold zone before a bulk change.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) zoneKeys() []map[K]struct{} {
+	if len(sl.thresholds) == 0 {
+		return nil
+	}
+
+	zones := make([]map[K]struct{}, len(sl.thresholds))
+	for i, t := range sl.thresholds {
+		zones[i] = make(map[K]struct{}, t.rank)
+		for curr, rank := sl.header.forward[0], 1; curr != nil && rank <= t.rank; curr, rank = curr.forward[0], rank+1 {
+			zones[i][curr.data.Key] = struct{}{}
+		}
+	}
+	return zones
+}
+
+// zoneEvents 对比批量修改前后的阈值区域，计算进入和离开的成员，连同修改期间的淘汰一起返回，调用方需持有写锁
+// zoneEvents compares each threshold zone before and after a bulk change and reports the members that entered or left it,
+// together with the evictions made during the change. The caller must hold the write lock
+func (sl *RankList[K, V]) zoneEvents(before []map[K]struct{}) []thresholdEvent[K] {
+	events := append(append(sl.keyspaceEvents(), sl.rankEvents()...), sl.evictEvents()...)
+	if len(before) == 0 {
+		return events
+	}
+
+	after := sl.zoneKeys()
+	for i, t := range sl.thresholds {
+		for key := range before[i] {
+			if _, ok := after[i][key]; !ok {
+				events = append(events, thresholdEvent[K]{fn: t.fn, key: key, entered: false})
+			}
+		}
+		for key := range after[i] {
+			if _, ok := before[i][key]; !ok {
+				events = append(events, thresholdEvent[K]{fn: t.fn, key: key, entered: true})
+			}
+		}
+	}
+	return events
+}
+
+// fireThresholds 执行阈值回调和淘汰回调，必须在释放锁之后调用
+// fireThresholds invokes threshold and eviction callbacks, it must be called after the lock is released
+func fireThresholds[K Ordered](events []thresholdEvent[K]) {
+	for _, e := range events {
+		e.fn(e.key, e.entered)
+	}
+}
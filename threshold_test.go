@@ -0,0 +1,191 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+type crossing struct {
+	key     string
+	entered bool
+}
+
+func newThresholdList(rank int) (*RankList[string, int], *[]crossing) {
+	var events []crossing
+	sl := New(WithThreshold[string, int](rank, func(key string, entered bool) {
+		events = append(events, crossing{key, entered})
+	}))
+	return sl, &events
+}
+
+func sortCrossings(events []crossing) {
+	sort.Slice(events, func(i, j int) bool { return events[i].key < events[j].key })
+}
+
+func TestThresholdInsertPushesOut(t *testing.T) {
+	sl, events := newThresholdList(2)
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	*events = nil
+
+	sl.Set("d", 5)
+	sortCrossings(*events)
+	expected := []crossing{{"b", false}, {"d", true}}
+	if len(*events) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, *events)
+	}
+	for i := range expected {
+		if (*events)[i] != expected[i] {
+			t.Errorf("at index %d: expected %v, got %v", i, expected[i], (*events)[i])
+		}
+	}
+}
+
+func TestThresholdDelPullsIn(t *testing.T) {
+	sl, events := newThresholdList(2)
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	*events = nil
+
+	sl.Del("a")
+	sortCrossings(*events)
+	expected := []crossing{{"a", false}, {"c", true}}
+	if len(*events) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, *events)
+	}
+	for i := range expected {
+		if (*events)[i] != expected[i] {
+			t.Errorf("at index %d: expected %v, got %v", i, expected[i], (*events)[i])
+		}
+	}
+}
+
+func TestThresholdNoCrossing(t *testing.T) {
+	sl, events := newThresholdList(2)
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	*events = nil
+
+	sl.Set("a", 15)
+	sl.Set("c", 40)
+	sl.Del("x")
+	if len(*events) != 0 {
+		t.Errorf("expected no crossings, got %v", *events)
+	}
+}
+
+func TestThresholdUpdateCrosses(t *testing.T) {
+	sl, events := newThresholdList(2)
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	*events = nil
+
+	sl.Set("a", 50)
+	sortCrossings(*events)
+	expected := []crossing{{"a", false}, {"c", true}}
+	if len(*events) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, *events)
+	}
+	for i := range expected {
+		if (*events)[i] != expected[i] {
+			t.Errorf("at index %d: expected %v, got %v", i, expected[i], (*events)[i])
+		}
+	}
+}
+
+func TestThresholdMultiple(t *testing.T) {
+	var top1, top3 []crossing
+	sl := New(
+		WithThreshold[string, int](1, func(key string, entered bool) {
+			top1 = append(top1, crossing{key, entered})
+		}),
+		WithThreshold[string, int](3, func(key string, entered bool) {
+			top3 = append(top3, crossing{key, entered})
+		}),
+	)
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	sl.Set("d", 40)
+	top1, top3 = nil, nil
+
+	sl.Set("d", 1)
+	sortCrossings(top1)
+	sortCrossings(top3)
+	if len(top1) != 2 || top1[0] != (crossing{"a", false}) || top1[1] != (crossing{"d", true}) {
+		t.Errorf("unexpected top1 crossings %v", top1)
+	}
+	if len(top3) != 2 || top3[0] != (crossing{"c", false}) || top3[1] != (crossing{"d", true}) {
+		t.Errorf("unexpected top3 crossings %v", top3)
+	}
+}
+
+func TestThresholdClear(t *testing.T) {
+	sl, events := newThresholdList(2)
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+	*events = nil
+
+	sl.Clear()
+	sortCrossings(*events)
+	expected := []crossing{{"a", false}, {"b", false}}
+	if len(*events) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, *events)
+	}
+	for i := range expected {
+		if (*events)[i] != expected[i] {
+			t.Errorf("at index %d: expected %v, got %v", i, expected[i], (*events)[i])
+		}
+	}
+}
+
+func TestThresholdCallbackAfterUnlock(t *testing.T) {
+	var sl *RankList[string, int]
+	sl = New(WithThreshold[string, int](1, func(key string, entered bool) {
+		// 回调中可以再次访问跳表，不会死锁
+		// Callbacks can access the list again without deadlocking
+		sl.Rank(key)
+	}))
+	sl.Set("a", 1)
+}
+
+func TestThresholdRandom(t *testing.T) {
+	zone := make(map[string]bool)
+	sl := New(WithThreshold[string, int](5, func(key string, entered bool) {
+		if zone[key] == entered {
+			t.Fatalf("Key %s reported entered=%v twice", key, entered)
+		}
+		zone[key] = entered
+	}))
+
+	for i := 0; i < 5000; i++ {
+		key := strconv.Itoa(rand.IntN(20))
+		if rand.IntN(4) == 0 {
+			sl.Del(key)
+		} else {
+			sl.Set(key, rand.IntN(10))
+		}
+
+		for _, entry := range sl.Range(1, 6) {
+			if !zone[entry.Key] {
+				t.Fatalf("Key %s should be in zone", entry.Key)
+			}
+		}
+		count := 0
+		for _, in := range zone {
+			if in {
+				count++
+			}
+		}
+		if count != min(5, sl.Length()) {
+			t.Fatalf("expected %d members in zone, got %d", min(5, sl.Length()), count)
+		}
+	}
+}
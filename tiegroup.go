@@ -0,0 +1,64 @@
+package ranklist
+
+// TieGroup 是 RangeGrouped 返回的一组值相同的连续条目
+// TieGroup is a run of consecutive entries sharing one value, as returned by RangeGrouped
+type TieGroup[K Ordered, V Ordered] struct {
+	// 组内条目共同的值
+	// The value shared by the entries of the group
+	Value V `json:"value"`
+
+	// 值为 Value 的第一个条目在整个跳表中的排名，即使它位于窗口之外，可以用来显示 "=12th"
+	// Rank of the first entry with Value in the whole list, even when it lies outside the window,
+	// suitable for displaying "=12th"
+	StartRank int `json:"startRank"`
+
+	// 窗口内值为 Value 的键，按排名顺序排列
+	// Keys within the window holding Value, in rank order
+	Keys []K `json:"keys"`
+}
+
+// RangeGrouped 与 Range 使用相同的排名区间 [start, end)，但在遍历第 0 层时把值相同的连续条目合并为一组，
+// 调用方不必再遍历一次结果。窗口从一组并列条目的中间开始或结束时，该组只包含窗口内的键，
+// 但 StartRank 仍是这个值在整个跳表中的第一个排名。占位条目与 Range 相同默认被跳过，但仍占据排名，
+// 只有占位条目的组不会出现在结果中。耗时 O(log n + 窗口大小)
+// RangeGrouped covers the same rank window [start, end) as Range, but merges consecutive entries sharing a value
+// into one group during the level-0 walk, so callers need no second pass over the results.
+// When the window starts or ends in the middle of a tie block the group only holds the keys inside the window,
+// but StartRank is still the first rank of that value in the whole list.
+// Placeholders are skipped like in Range while still holding their ranks, and a group of placeholders only is left out.
+// It runs in O(log n + window size)
+func (sl *RankList[K, V]) RangeGrouped(start int, end int) []TieGroup[K, V] {
+	sl.vars.add(opRange)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	groups := make([]TieGroup[K, V], 0)
+	if sl.rangeSize(start, end) == 0 {
+		return groups
+	}
+
+	var group TieGroup[K, V]
+	rank := max(start, 1) - 1
+	flush := func() {
+		if len(group.Keys) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	sl.walkRange(start, end, func(node *Node[K, V]) {
+		rank++
+		switch {
+		case group.StartRank == 0:
+			// 第一组可能从窗口之前开始，下降到这个值的第一个排名
+			// The first group may begin before the window, descend to the first rank of its value
+			group = TieGroup[K, V]{Value: node.data.Value, StartRank: sl.countBefore(node.data.Value, false) + 1}
+		case !sl.sameValue(node.data.Value, group.Value):
+			flush()
+			group = TieGroup[K, V]{Value: node.data.Value, StartRank: rank}
+		}
+		if !sl.hidden(node) {
+			group.Keys = append(group.Keys, node.data.Key)
+		}
+	})
+	flush()
+	return groups
+}
@@ -0,0 +1,109 @@
+package ranklist
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+)
+
+// tieFixture 返回值依次为 10, 20, 20, 20, 30, 40, 40 的跳表，键 a..g
+// tieFixture returns a list with keys a..g holding 10, 20, 20, 20, 30, 40, 40
+func tieFixture() *RankList[string, int] {
+	sl := New[string, int]()
+	for i, value := range []int{10, 20, 20, 20, 30, 40, 40} {
+		sl.Set(string(rune('a'+i)), value)
+	}
+	return sl
+}
+
+func TestRangeGrouped(t *testing.T) {
+	sl := tieFixture()
+	groups := sl.RangeGrouped(1, 8)
+	if got := fmt.Sprint(groups); got != "[{10 1 [a]} {20 2 [b c d]} {30 5 [e]} {40 6 [f g]}]" {
+		t.Fatalf("unexpected groups %s", got)
+	}
+	if groups := sl.RangeGrouped(8, 10); len(groups) != 0 {
+		t.Fatalf("expected no groups past the end, got %v", groups)
+	}
+}
+
+func TestRangeGroupedPartialTies(t *testing.T) {
+	sl := tieFixture()
+	tests := []struct {
+		start, end int
+		expected   string
+	}{
+		// 窗口从并列组中间开始 / The window starts in the middle of a tie block
+		{3, 6, "[{20 2 [c d]} {30 5 [e]}]"},
+		// 窗口在并列组中间结束 / The window ends in the middle of a tie block
+		{1, 3, "[{10 1 [a]} {20 2 [b]}]"},
+		// 窗口落在同一个并列组内部 / The window lies within one tie block
+		{3, 4, "[{20 2 [c]}]"},
+		{7, 8, "[{40 6 [g]}]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(sl.RangeGrouped(tt.start, tt.end)); got != tt.expected {
+			t.Errorf("RangeGrouped(%d, %d): expected %s, got %s", tt.start, tt.end, tt.expected, got)
+		}
+	}
+}
+
+func TestRangeGroupedPlaceholders(t *testing.T) {
+	sl := tieFixture()
+	sl.Reserve("p", 5)
+	sl.Reserve("q", 20)
+	// 排名：p a b c d q e f g / Ranks: p a b c d q e f g
+	if got := fmt.Sprint(sl.RangeGrouped(1, 10)); got != "[{10 2 [a]} {20 3 [b c d]} {30 7 [e]} {40 8 [f g]}]" {
+		t.Fatalf("unexpected groups %s", got)
+	}
+}
+
+func TestRangeGroupedRandomized(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	sl := New[string, int]()
+	for i := 0; i < 300; i++ {
+		sl.Set(strconv.Itoa(i), rng.IntN(40))
+	}
+	for i := 0; i < 200; i++ {
+		start, end := rng.IntN(310), rng.IntN(310)
+		entries := sl.Range(start, end)
+		groups := sl.RangeGrouped(start, end)
+
+		n := 0
+		for j, group := range groups {
+			if j > 0 && groups[j-1].Value == group.Value {
+				t.Fatalf("adjacent groups share the value %d", group.Value)
+			}
+			for _, key := range group.Keys {
+				if entries[n].Key != key || entries[n].Value != group.Value {
+					t.Fatalf("RangeGrouped(%d, %d) disagrees with Range at %d", start, end, n)
+				}
+				n++
+			}
+			first := sl.Range(group.StartRank, group.StartRank+1)
+			before := sl.Range(group.StartRank-1, group.StartRank)
+			if first[0].Value != group.Value || (len(before) == 1 && before[0].Value == group.Value) {
+				t.Fatalf("group %d starts at rank %d, which is not the first rank of its value", group.Value, group.StartRank)
+			}
+		}
+		if n != len(entries) {
+			t.Fatalf("RangeGrouped(%d, %d) holds %d keys, Range returned %d", start, end, n, len(entries))
+		}
+	}
+}
+
+func TestRangeGroupedCollation(t *testing.T) {
+	sl := New(WithCollation[int, string](FoldCaseLess))
+	sl.Set(1, "Bob")
+	sl.Set(2, "alice")
+	sl.Set(3, "ALICE")
+	sl.Set(4, "Alice")
+	// 忽略大小写后相同的值归为一组 / Values equal under folding share one group
+	if got := fmt.Sprint(sl.RangeGrouped(1, 5)); got != "[{alice 1 [2 3 4]} {Bob 4 [1]}]" {
+		t.Fatalf("unexpected groups %s", got)
+	}
+	if got := fmt.Sprint(sl.RangeGrouped(2, 5)); got != "[{ALICE 1 [3 4]} {Bob 4 [1]}]" {
+		t.Fatalf("unexpected groups %s", got)
+	}
+}
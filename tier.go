@@ -0,0 +1,107 @@
+package ranklist
+
+import "math"
+
+// Tiers 按百分位为每个成员分档，cutoffs 为 (0, 1] 区间内的升序比例，
+// 百分位不超过 cutoffs[i] 的成员分到第 i 档，超过所有比例的成员分到第 len(cutoffs) 档。
+// 分数相同的成员使用其中最好的排名计算百分位，因此总是分在同一档
+// Tiers assigns every member a tier by percentile. cutoffs are ascending fractions in (0, 1],
+// members whose percentile is within cutoffs[i] get tier i, members beyond every cutoff get tier len(cutoffs).
+// Members sharing a value use the best rank of their tie block, so they always land in the same tier
+func (sl *RankList[K, V]) Tiers(cutoffs []float64) (map[K]int, error) {
+	tiers := make(map[K]int, sl.Length())
+	err := sl.EachTier(cutoffs, func(key K, tier int) bool {
+		tiers[key] = tier
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+// EachTier 与 Tiers 相同，但按排名顺序对每个成员调用 fn 而不是构建一个 map，
+// fn 返回 false 时停止遍历。fn 在读锁内执行，不能再调用当前跳表的写方法
+// EachTier behaves like Tiers, but calls fn for every member in rank order instead of building a map,
+// iteration stops when fn returns false. fn runs under the read lock and must not write to the list
+func (sl *RankList[K, V]) EachTier(cutoffs []float64, fn func(key K, tier int) bool) error {
+	for i, c := range cutoffs {
+		if c <= 0 || c > 1 || (i > 0 && c < cutoffs[i-1]) {
+			return ErrInvalidCutoffs
+		}
+	}
+
+	sl.RLock()
+	defer sl.RUnlock()
+
+	rank, tieRank, tier := 0, 0, 0
+	var prev *Node[K, V]
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		rank++
+		if prev == nil || !sl.sameValue(prev.data.Value, curr.data.Value) {
+			tieRank = rank
+		}
+		for tier < len(cutoffs) && float64(tieRank) > cutoffs[tier]*float64(sl.length) {
+			tier++
+		}
+		if !fn(curr.data.Key, tier) {
+			return nil
+		}
+		prev = curr
+	}
+	return nil
+}
+
+// PercentileBounds 在一把读锁内返回每个百分位对应的值，ps 为 (0, 1] 区间内的升序比例。
+// 百分位 p 按最近排名法对应排名 ceil(p*n)，即至少覆盖 p 比例成员的最小排名，返回该排名上的值，
+// 所有边界来自同一时刻，因此相互一致。ps 不合法时返回 ErrInvalidPercentiles，跳表为空时返回空切片
+// PercentileBounds returns the value at every percentile under one read lock, ps are ascending fractions in (0, 1].
+// Percentile p maps to rank ceil(p*n) by the nearest-rank rule, the smallest rank covering a p fraction of the members,
+// and the value at that rank is returned. All bounds come from the same moment, so they are mutually consistent.
+// ErrInvalidPercentiles is returned for invalid ps, and an empty slice for an empty list
+func (sl *RankList[K, V]) PercentileBounds(ps []float64) ([]V, error) {
+	for i, p := range ps {
+		if p <= 0 || p > 1 || (i > 0 && p < ps[i-1]) {
+			return nil, ErrInvalidPercentiles
+		}
+	}
+
+	sl.RLock()
+	defer sl.RUnlock()
+
+	if sl.length == 0 {
+		return []V{}, nil
+	}
+	values := make([]V, len(ps))
+	for i, p := range ps {
+		values[i] = sl.byRank(nearestRank(p, sl.length)).data.Value
+	}
+	return values, nil
+}
+
+// EntryAtPercentile 返回百分位 p 上的完整条目，排名按与 PercentileBounds 相同的最近排名法计算。
+// 跳表为空或 p 不在 (0, 1] 区间内时返回 false
+// EntryAtPercentile returns the complete entry at percentile p, resolving the rank by the same nearest-rank rule
+// as PercentileBounds. Returns false for an empty list or a p outside (0, 1]
+func (sl *RankList[K, V]) EntryAtPercentile(p float64) (Entry[K, V], bool) {
+	if p <= 0 || p > 1 {
+		return Entry[K, V]{}, false
+	}
+
+	sl.RLock()
+	defer sl.RUnlock()
+
+	if sl.length == 0 {
+		return Entry[K, V]{}, false
+	}
+	return sl.byRank(nearestRank(p, sl.length)).data, true
+}
+
+// nearestRank 按最近排名法返回 n 个成员中百分位 p 对应的排名 ceil(p*n)，结果在 [1, n] 之内。
+// p*n 的浮点误差（例如 0.7*10 = 7.000000000000001）在取整前被容忍
+// nearestRank returns the rank ceil(p*n) of percentile p among n members by the nearest-rank rule, within [1, n].
+// Floating point noise in p*n (e.g. 0.7*10 = 7.000000000000001) is tolerated before rounding up
+func nearestRank(p float64, n int) int {
+	rank := int(math.Ceil(p*float64(n) - 1e-9))
+	return min(max(rank, 1), n)
+}
@@ -0,0 +1,192 @@
+package ranklist
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestTiersInvalidCutoffs(t *testing.T) {
+	sl := New[int, int]()
+	for _, cutoffs := range [][]float64{
+		{0},
+		{-0.1},
+		{1.5},
+		{0.5, 0.1},
+	} {
+		if _, err := sl.Tiers(cutoffs); !errors.Is(err, ErrInvalidCutoffs) {
+			t.Errorf("cutoffs %v: expected ErrInvalidCutoffs, got %v", cutoffs, err)
+		}
+	}
+}
+
+func TestTiersSimple(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	sl.Set("c", 2)
+	sl.Set("d", 3)
+
+	tiers, err := sl.Tiers([]float64{0.25, 0.5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// b 和 c 同分，都使用排名 2 计算百分位
+	// b and c are tied, both use rank 2 for their percentile
+	expected := map[string]int{"a": 0, "b": 1, "c": 1, "d": 2}
+	for key, tier := range expected {
+		if tiers[key] != tier {
+			t.Errorf("Key %s: expected tier %d, got %d", key, tier, tiers[key])
+		}
+	}
+}
+
+func TestTiersEarlyStop(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 100; i++ {
+		sl.Set(i, i)
+	}
+
+	count := 0
+	err := sl.EachTier([]float64{0.5}, func(key int, tier int) bool {
+		count++
+		return count < 10
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 10 {
+		t.Errorf("expected iteration to stop after 10 members, got %d", count)
+	}
+}
+
+func TestTiersBruteForce(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 10000; i++ {
+		sl.Set(i, rand.IntN(500))
+	}
+
+	cutoffs := []float64{0.01, 0.1, 0.25, 0.5}
+	tiers, err := sl.Tiers(cutoffs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := make([]Entry[int, int], 0, 10000)
+	for i := 0; i < 10000; i++ {
+		value, _ := sl.Get(i)
+		entries = append(entries, Entry[int, int]{Key: i, Value: value})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Value != entries[j].Value {
+			return entries[i].Value < entries[j].Value
+		}
+		return entries[i].Key < entries[j].Key
+	})
+
+	for i, entry := range entries {
+		best := i
+		for best > 0 && entries[best-1].Value == entry.Value {
+			best--
+		}
+		expected := len(cutoffs)
+		for k, c := range cutoffs {
+			if float64(best+1) <= c*float64(len(entries)) {
+				expected = k
+				break
+			}
+		}
+		if tiers[entry.Key] != expected {
+			t.Fatalf("Key %d: expected tier %d, got %d", entry.Key, expected, tiers[entry.Key])
+		}
+	}
+}
+
+func TestPercentileBounds(t *testing.T) {
+	sl := New[int, int]()
+	values := make([]int, 10000)
+	for i := range values {
+		values[i] = rand.IntN(1000000)
+		sl.Set(i, values[i])
+	}
+	sort.Ints(values)
+
+	ps := []float64{0.0001, 0.1, 0.25, 0.5, 0.7, 0.9, 0.999, 1}
+	bounds, err := sl.PercentileBounds(ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range ps {
+		// 最近排名法：至少覆盖 p 比例成员的最小排名
+		// Nearest rank: the smallest rank covering a p fraction of the members
+		rank := 1
+		for float64(rank) < p*float64(len(values))-1e-9 {
+			rank++
+		}
+		if bounds[i] != values[rank-1] {
+			t.Errorf("p=%v: expected %d at rank %d, got %d", p, values[rank-1], rank, bounds[i])
+		}
+	}
+}
+
+func TestPercentileBoundsEdges(t *testing.T) {
+	sl := New[string, int]()
+	if bounds, err := sl.PercentileBounds([]float64{0.5}); err != nil || len(bounds) != 0 {
+		t.Errorf("empty list: expected an empty result, got %v, %v", bounds, err)
+	}
+	for _, ps := range [][]float64{{0}, {1.5}, {0.5, 0.2}, {-0.1}} {
+		if _, err := sl.PercentileBounds(ps); !errors.Is(err, ErrInvalidPercentiles) {
+			t.Errorf("%v: expected ErrInvalidPercentiles, got %v", ps, err)
+		}
+	}
+
+	for i := 1; i <= 10; i++ {
+		sl.Set(string(rune('a'+i-1)), i*10)
+	}
+	bounds, _ := sl.PercentileBounds([]float64{0.1, 0.7, 0.75, 1})
+	if expected := []int{10, 70, 80, 100}; !slices.Equal(bounds, expected) {
+		t.Errorf("expected %v, got %v", expected, bounds)
+	}
+}
+
+func TestEntryAtPercentile(t *testing.T) {
+	sl := New[int, int]()
+	if _, ok := sl.EntryAtPercentile(0.5); ok {
+		t.Errorf("an empty list should return false")
+	}
+
+	for _, size := range []int{1, 7, 10000} {
+		sl.Clear()
+		for i := 0; i < size; i++ {
+			sl.Set(i, rand.IntN(1000))
+		}
+		for _, p := range []float64{0.0001, 0.5, 1} {
+			entry, ok := sl.EntryAtPercentile(p)
+			expected, _ := sl.GetByRank(nearestRank(p, size))
+			if !ok || entry != expected {
+				t.Errorf("size %d, p=%v: expected %v, got %v, %v", size, p, expected, entry, ok)
+			}
+			bounds, _ := sl.PercentileBounds([]float64{p})
+			if bounds[0] != entry.Value {
+				t.Errorf("size %d, p=%v: disagrees with PercentileBounds %v", size, p, bounds)
+			}
+		}
+		first, _ := sl.First()
+		if entry, _ := sl.EntryAtPercentile(0.0001); size < 10000 && entry != first {
+			t.Errorf("size %d: a tiny p should select rank 1", size)
+		}
+		last, _ := sl.Last()
+		if entry, _ := sl.EntryAtPercentile(1); entry != last {
+			t.Errorf("size %d: p=1 should select the last rank", size)
+		}
+	}
+
+	for _, p := range []float64{0, -0.5, 1.01} {
+		if _, ok := sl.EntryAtPercentile(p); ok {
+			t.Errorf("p=%v should be rejected", p)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package ranklist
+
+import "time"
+
+// entryTimes 记录条目的创建和更新时间。创建后不再修改，时间变化时替换为新的实例，
+// 因此多个节点可以共享同一个实例，读取时也无需额外同步
+// entryTimes records when an entry was created and last updated. It is never modified once created,
+// a change replaces it with a new instance, so nodes may share one and readers need no extra synchronization
+type entryTimes struct {
+	created time.Time
+	updated time.Time
+}
+
+// TimedEntry 表示附带创建和更新时间的键值对
+// TimedEntry represents a key-value pair together with its creation and update times
+type TimedEntry[K Ordered, V Ordered] struct {
+	Entry[K, V]
+	Created time.Time
+	Updated time.Time
+}
+
+// WithTimestamps 为每个条目记录创建时间和值最后一次改变的时间，时间由 clock 提供，clock 为 nil 时使用 time.Now。
+// 更新会刷新更新时间但保留创建时间，即使更新在内部重新插入了节点；写入相同的值不刷新更新时间。
+// 键被删除后重新创建时两个时间都重新开始，Restore、Load 等整体替换内容的操作同样如此
+// WithTimestamps records for every entry when it was created and when its value last changed,
+// the times come from clock, and time.Now is used when clock is nil.
+// Updates refresh the update time and keep the creation time, even when the update reinserts the node internally;
+// writing an unchanged value does not refresh the update time.
+// A key that is deleted and created again starts both times over, and so does every key after
+// whole-content replacements such as Restore and Load
+func WithTimestamps[K Ordered, V Ordered](clock func() time.Time) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		if clock == nil {
+			clock = time.Now
+		}
+		sl.clock = clock
+	}
+}
+
+// GetTimes 返回键的创建时间和值最后一次改变的时间，键不存在时返回 false，未开启时间戳时两个时间均为零值。
+// 加锁方式与 Get 相同
+// GetTimes returns when key was created and when its value last changed, false if the key does not exist.
+// Both times are zero when timestamps are disabled. It locks the same way Get does
+func (sl *RankList[K, V]) GetTimes(key K) (created, updated time.Time, ok bool) {
+	sl.vars.add(opGet)
+	if sl.noDict {
+		sl.RLock()
+		defer sl.RUnlock()
+	} else {
+		sl.dictMu.RLock()
+		defer sl.dictMu.RUnlock()
+	}
+
+	node, exists := sl.lookup(key)
+	if !exists {
+		return time.Time{}, time.Time{}, false
+	}
+	if node.times != nil {
+		return node.times.created, node.times.updated, true
+	}
+	return time.Time{}, time.Time{}, true
+}
+
+// RangeWithTimes 与 Range 相同，但在同一把读锁内同时返回每个条目的创建和更新时间
+// RangeWithTimes behaves like Range, but returns the creation and update times of every entry under the same read lock
+func (sl *RankList[K, V]) RangeWithTimes(start int, end int) []TimedEntry[K, V] {
+	sl.vars.add(opRange)
+	sl.RLock()
+	defer sl.RUnlock()
+
+	entries := make([]TimedEntry[K, V], 0, sl.rangeSize(start, end))
+	sl.walkRange(start, end, func(node *Node[K, V]) {
+		entry := TimedEntry[K, V]{Entry: node.data}
+		if node.times != nil {
+			entry.Created, entry.Updated = node.times.created, node.times.updated
+		}
+		entries = append(entries, entry)
+	})
+	return entries
+}
+
+// stamp 返回以当前时间作为创建和更新时间的时间戳，未开启时间戳时返回 nil
+// stamp returns timestamps created and updated at the current time, nil when timestamps are disabled
+func (sl *RankList[K, V]) stamp() *entryTimes {
+	if sl.clock == nil {
+		return nil
+	}
+	now := sl.clock()
+	return &entryTimes{created: now, updated: now}
+}
+
+// keepCreated 将新的时间戳的创建时间替换为 old 的创建时间，只能用于尚未共享的新实例
+// keepCreated carries the creation time of old over to fresh timestamps, only for an instance that is not yet shared
+func (t *entryTimes) keepCreated(old *entryTimes) *entryTimes {
+	if t != nil && old != nil {
+		t.created = old.created
+	}
+	return t
+}
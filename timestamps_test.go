@@ -0,0 +1,88 @@
+package ranklist
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock 每次读取前进一秒的测试时钟
+// fakeClock is a test clock that advances one second on every reading
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) read() time.Time {
+	c.now = c.now.Add(time.Second)
+	return c.now
+}
+
+func (c *fakeClock) at(seconds int) time.Time {
+	return time.Unix(0, 0).Add(time.Duration(seconds) * time.Second)
+}
+
+func TestTimestamps(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	sl := New(WithTimestamps[string, int](clock.read))
+
+	sl.Set("a", 10) // 1
+	sl.Set("b", 20) // 2
+	requireTimes(t, sl, "a", clock.at(1), clock.at(1))
+
+	// 不改变位置的更新和重新插入节点的更新都保留创建时间
+	// Updates in place and updates that reinsert the node both keep the creation time
+	sl.Set("a", 11) // 3, in place
+	requireTimes(t, sl, "a", clock.at(1), clock.at(3))
+	sl.Set("a", 30) // 4, reinserted after b
+	requireTimes(t, sl, "a", clock.at(1), clock.at(4))
+	sl.IncrBy("a", 5) // 5
+	requireTimes(t, sl, "a", clock.at(1), clock.at(5))
+
+	// 写入相同的值不刷新更新时间
+	// Writing an unchanged value does not refresh the update time
+	sl.Set("a", 35)
+	requireTimes(t, sl, "a", clock.at(1), clock.at(5))
+
+	sl.Compact()
+	requireTimes(t, sl, "a", clock.at(1), clock.at(5))
+
+	now := clock.now
+	sl.LoadMap(map[string]int{"a": 35, "b": 21, "c": 1}, false)
+	requireTimes(t, sl, "a", clock.at(1), clock.at(5))
+	requireTimes(t, sl, "b", clock.at(2), now.Add(time.Second))
+	requireTimes(t, sl, "c", now.Add(time.Second), now.Add(time.Second))
+
+	sl.Del("b")
+	sl.Set("b", 1)
+	requireTimes(t, sl, "b", clock.now, clock.now)
+
+	entries := sl.RangeWithTimes(1, 4)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %v", entries)
+	}
+	for _, entry := range entries {
+		created, updated, _ := sl.GetTimes(entry.Key)
+		if !entry.Created.Equal(created) || !entry.Updated.Equal(updated) {
+			t.Fatalf("key %s: RangeWithTimes disagrees with GetTimes", entry.Key)
+		}
+	}
+}
+
+func TestTimestampsDisabled(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	created, updated, ok := sl.GetTimes("a")
+	if !ok || !created.IsZero() || !updated.IsZero() {
+		t.Fatalf("expected zero times, got %v, %v, %v", created, updated, ok)
+	}
+	if _, _, ok := sl.GetTimes("x"); ok {
+		t.Fatalf("a missing key should return false")
+	}
+}
+
+func requireTimes[K Ordered, V Ordered](t *testing.T, sl *RankList[K, V], key K, created, updated time.Time) {
+	t.Helper()
+	gotCreated, gotUpdated, ok := sl.GetTimes(key)
+	if !ok || !gotCreated.Equal(created) || !gotUpdated.Equal(updated) {
+		t.Fatalf("key %v: expected %v / %v, got %v / %v, %v", key, created, updated, gotCreated, gotUpdated, ok)
+	}
+}
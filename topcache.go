@@ -0,0 +1,83 @@
+package ranklist
+
+import "sync"
+
+// topCache 缓存值最大的 n 个条目。写入只有在新条目不低于缓存中最低的条目、或键本身在缓存中时才会让缓存失效，
+// 其余写入不影响前 n 名，缓存保持不变；失效后由下一次 Top 在读锁内重建。
+// 修改缓存的写入方持有写锁，重建方持有读锁和 mu，因此两者不会同时访问缓存
+// topCache caches the n entries with the largest values. A write only invalidates it when the new entry is not below
+// the lowest cached one or the key itself is cached, any other write cannot affect the top n and leaves the cache alone;
+// the next Top rebuilds it under the read lock once invalidated.
+// Writers touch the cache under the write lock and rebuilders hold the read lock and mu, so they never overlap
+type topCache[K Ordered, V Ordered] struct {
+	n int
+
+	mu      sync.Mutex
+	valid   bool
+	entries []Entry[K, V]
+	keys    map[K]struct{}
+}
+
+// WithCachedTop 增量维护值最大的 n 个条目，k 不超过 n 的 Top(k) 直接从缓存复制结果，
+// 适合 Top 被频繁查询而写入相对较少的场景。写入只有可能改变前 n 名时才会让缓存失效，
+// 因此大部分写入的额外开销只是一次比较。n 小于 1 时 panic
+// WithCachedTop maintains the n entries with the largest values incrementally,
+// Top(k) for k up to n copies its result from the cache,
+// which suits boards whose top is queried far more often than they are written.
+// A write only invalidates the cache when it may change the top n,
+// so most writes pay a single extra comparison. It panics if n is less than 1
+func WithCachedTop[K Ordered, V Ordered](n int) Option[K, V] {
+	if n < 1 {
+		panic("ranklist: cached top size must be positive")
+	}
+	return func(sl *RankList[K, V]) {
+		sl.topCache = &topCache[K, V]{
+			n:       n,
+			entries: make([]Entry[K, V], 0, n),
+			keys:    make(map[K]struct{}, n),
+		}
+	}
+}
+
+// touch 在 entry 被写入或删除前调用，entry 可能改变前 n 名时让缓存失效，调用方需持有写锁
+// touch is called before entry is written or removed and invalidates the cache when entry may change the top n.
+// The caller must hold the write lock
+func (c *topCache[K, V]) touch(sl *RankList[K, V], entry Entry[K, V]) {
+	if c == nil || !c.valid {
+		return
+	}
+	if len(c.entries) < c.n || sl.compare(entry, c.entries[len(c.entries)-1]) >= 0 {
+		c.valid = false
+		return
+	}
+	if _, ok := c.keys[entry.Key]; ok {
+		c.valid = false
+	}
+}
+
+// invalidate 让缓存失效，用于重建跳表等整体修改，调用方需持有写锁
+// invalidate drops the cache on wholesale changes such as a rebuild. The caller must hold the write lock
+func (c *topCache[K, V]) invalidate() {
+	if c != nil {
+		c.valid = false
+	}
+}
+
+// top 返回值最大的 k 个条目，缓存失效时先重建，k 不能超过 n，调用方需持有读锁
+// top returns the k entries with the largest values, rebuilding the cache first when it is invalid.
+// k must not exceed n and the caller must hold the read lock
+func (c *topCache[K, V]) top(sl *RankList[K, V], k int) []Entry[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.valid {
+		c.entries = c.entries[:0]
+		clear(c.keys)
+		sl.walkTop(c.n, func(node *Node[K, V]) {
+			c.entries = append(c.entries, node.data)
+			c.keys[node.data.Key] = struct{}{}
+		})
+		c.valid = true
+	}
+	return append([]Entry[K, V](nil), c.entries[:min(k, len(c.entries))]...)
+}
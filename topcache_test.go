@@ -0,0 +1,80 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestCachedTop(t *testing.T) {
+	const n = 10
+	sl := New(WithCachedTop[int, int](n))
+	control := New[int, int]()
+
+	requireTop := func(step int) {
+		t.Helper()
+		for _, k := range []int{1, n / 2, n, n + 1} {
+			if got, want := sl.Top(k), control.Top(k); !slices.Equal(got, want) {
+				t.Fatalf("step %d: Top(%d) = %v, expected %v", step, k, got, want)
+			}
+		}
+	}
+
+	requireTop(-1)
+	for step := 0; step < 20000; step++ {
+		key, value := rand.IntN(300), rand.IntN(1000)
+		switch op := rand.IntN(100); {
+		case op < 50:
+			sl.Set(key, value)
+			control.Set(key, value)
+		case op < 70:
+			sl.Del(key)
+			control.Del(key)
+		case op < 85:
+			delta := rand.IntN(200) - 100
+			sl.IncrBy(key, delta)
+			control.IncrBy(key, delta)
+		case op < 90:
+			// 把当前的第一名压到底部，缓存中的键必须让缓存失效
+			// Push the current leader to the bottom, a cached key must invalidate the cache
+			if top := control.Top(1); len(top) == 1 {
+				sl.Set(top[0].Key, -1)
+				control.Set(top[0].Key, -1)
+			}
+		case op < 95:
+			entries := []Entry[int, int]{{key, value}, {key + 1, value + 1}}
+			sl.SetBatch(entries)
+			control.SetBatch(entries)
+		case op < 99:
+			sl.Txn(func(tx *Tx[int, int]) error {
+				tx.Del(key)
+				return tx.Set(key+2, value)
+			})
+			control.Del(key)
+			control.Set(key+2, value)
+		default:
+			m, replace := map[int]int{key: value, key + 3: value}, rand.IntN(4) == 0
+			sl.LoadMap(m, replace)
+			control.LoadMap(m, replace)
+		}
+		if step%3 == 0 {
+			requireTop(step)
+		}
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatalf("corrupted list: %v", err)
+	}
+
+	// 返回的切片是副本，修改它不影响缓存
+	// The returned slice is a copy, changing it leaves the cache alone
+	top := sl.Top(n)
+	top[0].Value = -100
+	requireTop(-2)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic for a non-positive size")
+		}
+	}()
+	WithCachedTop[int, int](0)
+}
@@ -0,0 +1,101 @@
+package ranklist
+
+// TraceOp 表示被追踪的操作类型
+// TraceOp identifies the kind of a traced operation
+type TraceOp string
+
+const (
+	// TraceSet 一次 Set，包括 SetChecked、TrySet 以及 IncrBy 系列写入
+	// TraceSet is one Set, including SetChecked, TrySet and the IncrBy family
+	TraceSet TraceOp = "set"
+
+	// TraceDel 一次 Del
+	// TraceDel is one Del
+	TraceDel TraceOp = "del"
+
+	// TraceRank 一次 Rank
+	// TraceRank is one Rank
+	TraceRank TraceOp = "rank"
+
+	// TraceRange 一次 Range
+	// TraceRange is one Range
+	TraceRange TraceOp = "range"
+)
+
+// TraceEvent 描述一次被追踪的操作以及操作之后跳表的状态
+// TraceEvent describes one traced operation and the state of the skip list after it
+type TraceEvent[K Ordered] struct {
+	// 操作类型
+	// Kind of the operation
+	Op TraceOp
+
+	// 操作的键，Range 时为零值
+	// Key of the operation, the zero value for Range
+	Key K
+
+	// 操作是否插入或摘除了节点；原地更新值、未命中的删除和读操作为 false
+	// Whether the operation linked or unlinked a node; false for in-place value updates, missed deletes and reads
+	Structural bool
+
+	// 插入或删除的节点的层级，没有结构性修改时为 0
+	// Level of the node inserted or removed, 0 without a structural change
+	NodeLevel int
+
+	// 操作之后跳表的最大层级和长度
+	// Maximum level and length of the skip list after the operation
+	Level  int
+	Length int
+}
+
+// WithTracer 注册追踪回调，Set、Del、Rank 和 Range 每执行一次调用一次 fn，用于排查线上问题，例如接入 slog。
+// 写操作的事件在写锁内采集，读操作的事件在操作之后另取一次读锁采集；fn 总是在锁外调用，可以调用跳表的任何方法。
+// AddAll 为每个修改的键调用一次 fn，其余批量写入、整体替换内容的操作和其余读方法不会被追踪。未注册时热路径上只多一次 nil 判断
+// WithTracer registers a trace callback, fn is called once per Set, Del, Rank and Range,
+// to diagnose production incidents, e.g. by forwarding to slog.
+// Events of writes are captured under the write lock, events of reads under a separate read lock right after the read;
+// fn always runs outside the lock and may call any method of the list.
+// AddAll calls fn once per key it changes, while the other batch writes, whole-content replacements
+// and the remaining read methods are not traced.
+// Without a tracer the hot path only pays a nil check
+func WithTracer[K Ordered, V Ordered](fn func(event TraceEvent[K])) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.tracer = fn
+	}
+}
+
+// traceEvent 采集一次操作之后的事件，调用方需持有锁
+// traceEvent captures the event following an operation, the caller must hold the lock
+func (sl *RankList[K, V]) traceEvent(op TraceOp, key K, structural bool, nodeLevel int) TraceEvent[K] {
+	if !structural {
+		nodeLevel = 0
+	}
+	return TraceEvent[K]{
+		Op:         op,
+		Key:        key,
+		Structural: structural,
+		NodeLevel:  nodeLevel,
+		Level:      sl.level,
+		Length:     sl.length,
+	}
+}
+
+// traceSet 采集一次写入之后的 TraceSet 事件，gen 是写入之前的结构版本，调用方需持有写锁
+// traceSet captures the TraceSet event following a write to key, gen being the structure generation before the write.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) traceSet(key K, gen uint64) TraceEvent[K] {
+	var nodeLevel int
+	if node, exists := sl.lookup(key); exists {
+		nodeLevel = node.level
+	}
+	return sl.traceEvent(TraceSet, key, sl.gen != gen, nodeLevel)
+}
+
+// traceRead 在读锁内采集读操作的事件并在锁外调用追踪回调，调用方不能持有锁，只在注册了追踪回调时调用
+// traceRead captures the event of a read under the read lock and calls the tracer outside of it.
+// The caller must not hold the lock, and only calls it when a tracer is registered
+func (sl *RankList[K, V]) traceRead(op TraceOp, key K) {
+	sl.RLock()
+	event := sl.traceEvent(op, key, false, 0)
+	sl.RUnlock()
+	sl.tracer(event)
+}
@@ -0,0 +1,125 @@
+package ranklist
+
+import (
+	"testing"
+)
+
+func TestTracer(t *testing.T) {
+	var events []TraceEvent[string]
+	sl := New(WithTracer[string, int](func(event TraceEvent[string]) {
+		events = append(events, event)
+	}))
+	last := func() TraceEvent[string] {
+		t.Helper()
+		if len(events) == 0 {
+			t.Fatalf("expected an event")
+		}
+		event := events[len(events)-1]
+		events = events[:0]
+		return event
+	}
+
+	sl.Set("a", 10)
+	event := last()
+	if event.Op != TraceSet || event.Key != "a" || !event.Structural || event.Length != 1 {
+		t.Fatalf("unexpected insert event %+v", event)
+	}
+	if event.NodeLevel < 1 || event.Level < event.NodeLevel {
+		t.Fatalf("expected a node level within the list level, got %+v", event)
+	}
+
+	sl.Set("b", 20)
+	last()
+
+	// 原地更新不是结构性修改
+	// An in-place update is not a structural change
+	sl.Set("a", 15)
+	if event := last(); event.Op != TraceSet || event.Structural || event.NodeLevel != 0 || event.Length != 2 {
+		t.Fatalf("unexpected in-place update event %+v", event)
+	}
+
+	// 越过邻居的更新需要重新插入节点
+	// An update passing a neighbor reinserts the node
+	sl.Set("a", 30)
+	if event := last(); !event.Structural || event.NodeLevel < 1 || event.Length != 2 {
+		t.Fatalf("unexpected reinsert event %+v", event)
+	}
+
+	if rank, _ := sl.Rank("a"); rank != 2 {
+		t.Fatalf("expected rank 2, got %d", rank)
+	}
+	if event := last(); event.Op != TraceRank || event.Key != "a" || event.Structural || event.Length != 2 {
+		t.Fatalf("unexpected rank event %+v", event)
+	}
+
+	sl.Range(1, 3)
+	if event := last(); event.Op != TraceRange || event.Key != "" || event.Structural || event.Length != 2 {
+		t.Fatalf("unexpected range event %+v", event)
+	}
+
+	sl.Del("a")
+	if event := last(); event.Op != TraceDel || event.Key != "a" || !event.Structural || event.NodeLevel < 1 || event.Length != 1 {
+		t.Fatalf("unexpected delete event %+v", event)
+	}
+	sl.Del("missing")
+	if event := last(); event.Op != TraceDel || event.Structural || event.NodeLevel != 0 || event.Length != 1 {
+		t.Fatalf("unexpected missed delete event %+v", event)
+	}
+}
+
+func TestTracerReentrant(t *testing.T) {
+	var sl *RankList[string, int]
+	lengths := 0
+	sl = New(WithTracer[string, int](func(event TraceEvent[string]) {
+		if event.Op == TraceSet {
+			lengths += sl.Length()
+		}
+	}))
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	if lengths != 3 {
+		t.Fatalf("expected the tracer to run outside the lock, got %d", lengths)
+	}
+}
+
+func TestTracerIncrBy(t *testing.T) {
+	var events []TraceEvent[string]
+	sl := New(WithTracer[string, int](func(event TraceEvent[string]) {
+		events = append(events, event)
+	}))
+	take := func() []TraceEvent[string] {
+		taken := events
+		events = nil
+		return taken
+	}
+
+	sl.IncrBy("a", 10)
+	if got := take(); len(got) != 1 || got[0].Op != TraceSet || got[0].Key != "a" || !got[0].Structural || got[0].Length != 1 {
+		t.Fatalf("unexpected insert events %+v", got)
+	}
+	sl.IncrBy("a", 5)
+	if got := take(); len(got) != 1 || got[0].Structural || got[0].NodeLevel != 0 {
+		t.Fatalf("unexpected in-place increment events %+v", got)
+	}
+
+	// 去重命中的增量没有写入，不产生事件
+	// An increment deduplicated by its token writes nothing and emits no event
+	sl.IncrByIdempotent("a", 1, "t1")
+	sl.IncrByIdempotent("a", 1, "t1")
+	if got := take(); len(got) != 1 {
+		t.Fatalf("expected one event for the token, got %+v", got)
+	}
+
+	// AddAll 为每个修改的键产生一个事件
+	// AddAll emits one event per key it changes
+	sl.AddAll(map[string]int{"a": 1, "b": 2})
+	got := take()
+	if len(got) != 2 {
+		t.Fatalf("expected two AddAll events, got %+v", got)
+	}
+	for _, event := range got {
+		if event.Op != TraceSet || (event.Key == "b" && !event.Structural) {
+			t.Fatalf("unexpected AddAll event %+v", event)
+		}
+	}
+}
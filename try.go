@@ -0,0 +1,142 @@
+package ranklist
+
+import (
+	"context"
+	"time"
+)
+
+// 获取锁失败后重试的初始间隔和最大间隔，每次重试间隔翻倍
+// Initial and maximum delay between lock attempts, the delay doubles after every attempt
+const (
+	tryMinDelay = 10 * time.Microsecond
+	tryMaxDelay = time.Millisecond
+)
+
+// TryGet 与 Get 相同，但最多等待 timeout 获取锁，超时后返回 ErrBusy 而不是一直阻塞。
+// 适用于宁可放弃也不愿排在长时间写操作之后的读路径
+// TryGet behaves like Get, but waits at most timeout for the lock and returns ErrBusy instead of blocking indefinitely.
+// It suits read paths that would rather give up than queue behind a long write
+func (sl *RankList[K, V]) TryGet(key K, timeout time.Duration) (V, bool, error) {
+	// Get 只获取字典的读锁，没有字典时获取跳表的读锁
+	// Get only takes the dictionary's read lock, or the list's read lock without a dictionary
+	tryLock, unlock := sl.dictMu.TryRLock, sl.dictMu.RUnlock
+	if sl.noDict {
+		tryLock, unlock = sl.TryRLock, sl.RUnlock
+	}
+	if !tryUntil(tryLock, timeout) {
+		return ZeroValue[V](), false, ErrBusy
+	}
+	defer unlock()
+
+	sl.vars.add(opGet)
+	if node, exists := sl.lookup(key); exists {
+		return node.data.Value, true, nil
+	}
+	return ZeroValue[V](), false, nil
+}
+
+// TrySet 与 Set 相同，但最多等待 timeout 获取写锁，超时后不做任何修改并返回 ErrBusy
+// TrySet behaves like Set, but waits at most timeout for the write lock,
+// nothing is changed and ErrBusy is returned once the timeout expires
+func (sl *RankList[K, V]) TrySet(key K, value V, timeout time.Duration) (bool, error) {
+	if !tryUntil(sl.TryLock, timeout) {
+		return false, ErrBusy
+	}
+	if sl.closed {
+		sl.Unlock()
+		return false, ErrClosed
+	}
+	if err := sl.guardWrite(key, value); err != nil {
+		sl.Unlock()
+		return false, err
+	}
+	if err := sl.persistWrite(key, value); err != nil {
+		sl.Unlock()
+		return false, err
+	}
+	return sl.setAndUnlock(key, value, keyspaceZadd), nil
+}
+
+// GetCtx 与 Get 相同，但在 ctx 结束之前仍未获取到锁时放弃并返回 ctx.Err()，不会一直阻塞。
+// 锁可以立即获取时不检查 ctx
+// GetCtx behaves like Get, but gives up and returns ctx.Err() when ctx ends before the lock is acquired
+// instead of blocking indefinitely. ctx is not consulted when the lock is free right away
+func (sl *RankList[K, V]) GetCtx(ctx context.Context, key K) (V, bool, error) {
+	tryLock, unlock := sl.dictMu.TryRLock, sl.dictMu.RUnlock
+	if sl.noDict {
+		tryLock, unlock = sl.TryRLock, sl.RUnlock
+	}
+	if err := tryCtx(ctx, tryLock); err != nil {
+		return ZeroValue[V](), false, err
+	}
+	defer unlock()
+
+	sl.vars.add(opGet)
+	if node, exists := sl.lookup(key); exists {
+		return node.data.Value, true, nil
+	}
+	return ZeroValue[V](), false, nil
+}
+
+// RankCtx 与 Rank 相同，但在 ctx 结束之前仍未获取到读锁时放弃并返回 ctx.Err()。
+// 字典与跳表的不一致需要写锁才能修复，这里只报告键不存在，由之后的 Rank 修复
+// RankCtx behaves like Rank, but gives up and returns ctx.Err() when ctx ends before the read lock is acquired.
+// Repairing a divergence between the dictionary and the list needs the write lock,
+// so here the key is merely reported missing and a later Rank repairs it
+func (sl *RankList[K, V]) RankCtx(ctx context.Context, key K) (int, bool, error) {
+	if err := tryCtx(ctx, sl.TryRLock); err != nil {
+		return 0, false, err
+	}
+	defer sl.RUnlock()
+
+	sl.vars.add(opRank)
+	rank, ok, _ := sl.cachedRankLocked(key)
+	return rank, ok, nil
+}
+
+// RangeCtx 与 Range 相同，但在 ctx 结束之前仍未获取到读锁时放弃并返回 ctx.Err()
+// RangeCtx behaves like Range, but gives up and returns ctx.Err() when ctx ends before the read lock is acquired
+func (sl *RankList[K, V]) RangeCtx(ctx context.Context, start int, end int) ([]Entry[K, V], error) {
+	if err := tryCtx(ctx, sl.TryRLock); err != nil {
+		return nil, err
+	}
+	defer sl.RUnlock()
+
+	sl.vars.add(opRange)
+	return sl.appendRange(make([]Entry[K, V], 0, sl.rangeSize(start, end)), start, end), nil
+}
+
+// tryUntil 反复调用 tryLock 直到成功或超过 timeout，重试方式与 tryCtx 相同
+// tryUntil calls tryLock until it succeeds or timeout passes, retrying the way tryCtx does
+func tryUntil(tryLock func() bool, timeout time.Duration) bool {
+	if tryLock() {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return tryCtx(ctx, tryLock) == nil
+}
+
+// tryCtx 反复调用 tryLock 直到成功或 ctx 结束，重试间隔从 tryMinDelay 翻倍到 tryMaxDelay，ctx 结束时返回 ctx.Err()
+// tryCtx calls tryLock until it succeeds or ctx ends, the delay between attempts doubling from tryMinDelay
+// up to tryMaxDelay. It returns ctx.Err() once ctx ends
+func tryCtx(ctx context.Context, tryLock func() bool) error {
+	if tryLock() {
+		return nil
+	}
+	timer := time.NewTimer(tryMinDelay)
+	defer timer.Stop()
+	delay := tryMinDelay
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if tryLock() {
+			return nil
+		}
+		delay = min(delay*2, tryMaxDelay)
+		timer.Reset(delay)
+	}
+}
@@ -0,0 +1,168 @@
+package ranklist
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTryFree(t *testing.T) {
+	sl := New[string, int]()
+	if inserted, err := sl.TrySet("a", 1, time.Millisecond); err != nil || !inserted {
+		t.Fatalf("TrySet on a free list: %v, %v", inserted, err)
+	}
+	value, ok, err := sl.TryGet("a", time.Millisecond)
+	if err != nil || !ok || value != 1 {
+		t.Fatalf("expected 1, true, nil, got %d, %v, %v", value, ok, err)
+	}
+	if _, ok, err := sl.TryGet("x", time.Millisecond); err != nil || ok {
+		t.Fatalf("expected a miss without error, got %v, %v", ok, err)
+	}
+}
+
+func TestTrySetBusy(t *testing.T) {
+	sl := New[string, int]()
+	sl.Lock()
+
+	start := time.Now()
+	_, err := sl.TrySet("a", 1, 20*time.Millisecond)
+	if !errors.Is(err, ErrBusy) {
+		t.Fatalf("expected ErrBusy, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed > time.Second {
+		t.Fatalf("TrySet should give up right after the timeout, took %v", elapsed)
+	}
+
+	// 普通的 Set 会一直阻塞到写锁释放
+	// A plain Set blocks until the write lock is released
+	done := make(chan struct{})
+	go func() {
+		sl.Set("a", 2)
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatalf("Set should block behind the write lock")
+	case <-time.After(20 * time.Millisecond):
+	}
+	sl.Unlock()
+	<-done
+
+	if value, _ := sl.Get("a"); value != 2 {
+		t.Fatalf("expected only the plain Set to land, got %d", value)
+	}
+}
+
+func TestTryGetBusy(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		sl   *RankList[string, int]
+		lock func(sl *RankList[string, int]) func()
+	}{
+		// Get 只在写者修改字典时阻塞，因此持有字典锁
+		// Get only blocks while a writer modifies the dictionary, so hold the dictionary lock
+		{"dict", New[string, int](), func(sl *RankList[string, int]) func() {
+			sl.dictMu.Lock()
+			return sl.dictMu.Unlock
+		}},
+		{"nodict", New(WithNoDict[string, int]()), func(sl *RankList[string, int]) func() {
+			sl.Lock()
+			return sl.Unlock
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sl := tt.sl
+			sl.Set("a", 1)
+			unlock := tt.lock(sl)
+
+			start := time.Now()
+			if _, _, err := sl.TryGet("a", 20*time.Millisecond); !errors.Is(err, ErrBusy) {
+				t.Fatalf("expected ErrBusy, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed > time.Second {
+				t.Fatalf("TryGet should give up right after the timeout, took %v", elapsed)
+			}
+
+			done := make(chan int)
+			go func() {
+				value, _ := sl.Get("a")
+				done <- value
+			}()
+			select {
+			case <-done:
+				t.Fatalf("Get should block behind the lock")
+			case <-time.After(20 * time.Millisecond):
+			}
+			unlock()
+			if value := <-done; value != 1 {
+				t.Fatalf("expected 1, got %d", value)
+			}
+		})
+	}
+}
+
+func TestCtxVariantsDeadline(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+
+	// 空闲时与普通方法相同，即使 ctx 已经结束
+	// On a free list they match the plain methods, even with a finished ctx
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if value, ok, err := sl.GetCtx(cancelled, "a"); err != nil || !ok || value != 1 {
+		t.Fatalf("GetCtx: expected 1, true, nil, got %d, %v, %v", value, ok, err)
+	}
+	if rank, ok, err := sl.RankCtx(cancelled, "b"); err != nil || !ok || rank != 2 {
+		t.Fatalf("RankCtx: expected 2, true, nil, got %d, %v, %v", rank, ok, err)
+	}
+	if entries, err := sl.RangeCtx(cancelled, 1, 3); err != nil || !slices.Equal(entries, sl.Range(1, 3)) {
+		t.Fatalf("RangeCtx: unexpected %v, %v", entries, err)
+	}
+
+	// 写者持有两把锁，模拟一次长时间的导入
+	// A writer holds both locks, simulating a long import
+	sl.Lock()
+	sl.dictMu.Lock()
+	calls := map[string]func(ctx context.Context) error{
+		"GetCtx": func(ctx context.Context) error {
+			_, _, err := sl.GetCtx(ctx, "a")
+			return err
+		},
+		"RankCtx": func(ctx context.Context) error {
+			_, _, err := sl.RankCtx(ctx, "a")
+			return err
+		},
+		"RangeCtx": func(ctx context.Context) error {
+			_, err := sl.RangeCtx(ctx, 1, 3)
+			return err
+		},
+	}
+	for name, call := range calls {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		start := time.Now()
+		err := call(ctx)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%s: expected context.DeadlineExceeded, got %v", name, err)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("%s: should give up promptly after the deadline, took %v", name, elapsed)
+		}
+	}
+
+	// 锁在 ctx 结束之前释放时照常返回结果
+	// When the lock is released before ctx ends the result comes back as usual
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		sl.dictMu.Unlock()
+		sl.Unlock()
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if rank, ok, err := sl.RankCtx(ctx, "a"); err != nil || !ok || rank != 1 {
+		t.Fatalf("RankCtx after release: expected 1, true, nil, got %d, %v, %v", rank, ok, err)
+	}
+}
@@ -0,0 +1,267 @@
+package ranklist
+
+// Tx 是 Txn 回调中使用的事务，修改先暂存在事务内，回调成功返回后才一并写入跳表。
+// Tx 只能在回调内使用，不能被多个 goroutine 同时使用
+// Tx is the transaction handed to a Txn callback. Changes are staged in the transaction
+// and only written to the list together once the callback returns successfully.
+// A Tx is only valid inside the callback and must not be used by several goroutines at once
+type Tx[K Ordered, V Ordered] struct {
+	sl *RankList[K, V]
+
+	// 暂存的修改和键第一次被修改的顺序，提交时按该顺序写入
+	// Staged changes and the order keys were first changed in, which is the order they are committed in
+	staged map[K]txWrite[V]
+	order  []K
+}
+
+// txWrite 是事务内对一个键的暂存修改
+// txWrite is the staged change of one key within a transaction
+type txWrite[V Ordered] struct {
+	value   V
+	deleted bool
+}
+
+// Txn 在一把写锁内调用 fn，fn 通过 tx 读取和修改若干个键，例如在两个玩家之间转移积分。
+// fn 返回 nil 时暂存的修改在同一把写锁内按顺序全部写入，返回错误或 panic 时全部丢弃，跳表保持不变，fn 的错误原样返回。
+// tx.Get 能看到本事务暂存的修改；Set 和 IncrBy 在暂存时即按 WithValueGuard 和 WithMaxDelta 检查，被拒绝的修改不会暂存。
+// fn 执行期间其他读写都会阻塞，因此 fn 应当尽量简短，并且不能调用这个跳表的任何方法，否则会死锁。
+// 提交会使成员数超出 WithHardLimit 时丢弃全部修改并返回 ErrFull。
+// 跳表已关闭时不调用 fn，直接返回 ErrClosed
+// Txn calls fn under one write lock, fn reading and changing several keys through tx,
+// e.g. to transfer points from one player to another.
+// When fn returns nil the staged changes are all written in order under the same write lock,
+// when it returns an error or panics they are all discarded and the list is left untouched; the error of fn is returned as is.
+// tx.Get sees the changes staged by the transaction; Set and IncrBy are checked against WithValueGuard and WithMaxDelta
+// when staged, and a rejected change is not staged.
+// Every other read and write blocks while fn runs, so fn must be short, and it must not call any method of this list or it deadlocks.
+// A commit that would take the size past WithHardLimit discards every change and returns ErrFull.
+// fn is not called once the list is closed, ErrClosed is returned instead
+func (sl *RankList[K, V]) Txn(fn func(tx *Tx[K, V]) error) error {
+	return TxnAll([]*RankList[K, V]{sl}, func(txs []*Tx[K, V]) error {
+		return fn(txs[0])
+	})
+}
+
+// TxnAll 与 Txn 相同，但同时持有 lists 中所有跳表的写锁，fn 通过与 lists 一一对应的事务修改它们，
+// 例如把同一个成绩原子地写入日榜、周榜和总榜。fn 返回 nil 时所有跳表的修改一并写入，否则全部丢弃。
+// 写锁按与 SnapshotAll 相同的固定顺序获取，因此与其他多跳表操作并发时不会死锁；
+// 同一个跳表出现多次时对应同一个事务。任意一个跳表已关闭时不调用 fn，直接返回 ErrClosed。
+// 写入 WithStore 的外部存储不是原子的：所有跳表先一并检查 WithHardLimit，全部通过后才按加锁顺序逐个跳表写穿暂存的修改，
+// 任何一次写穿失败都会放弃所有跳表在内存中的提交并返回属于 ErrStore 的错误，
+// 但此前已经写入外部存储的修改，包括其他跳表的修改，不会撤销，重新写入这些键即可恢复一致
+// TxnAll behaves like Txn but holds the write locks of all lists together,
+// fn changing them through transactions matching lists by index,
+// e.g. to write one score to the daily, weekly and all-time boards atomically.
+// When fn returns nil the changes to every list are written together, otherwise they are all discarded.
+// The write locks are taken in the same fixed order as SnapshotAll, so it never deadlocks with other operations
+// spanning several lists; a list appearing more than once maps to the same transaction.
+// fn is not called when any of the lists is closed, ErrClosed is returned instead.
+// Writes to the external stores of WithStore are not atomic: every list is checked against WithHardLimit first,
+// and only once all of them pass are the staged changes written through list by list in lock order.
+// Any failure to write through abandons the in-memory commit of every list and returns an ErrStore,
+// but the changes already written to a store, including those of other lists, are not undone;
+// writing those keys again reconciles them
+func TxnAll[K Ordered, V Ordered](lists []*RankList[K, V], fn func(txs []*Tx[K, V]) error) error {
+	locked := lockOrder(lists)
+	for _, sl := range locked {
+		sl.Lock()
+	}
+	unlock := func() {
+		for _, sl := range locked {
+			sl.Unlock()
+		}
+	}
+	for _, sl := range locked {
+		if sl.closed {
+			unlock()
+			return ErrClosed
+		}
+	}
+
+	byList := make(map[*RankList[K, V]]*Tx[K, V], len(locked))
+	for _, sl := range locked {
+		byList[sl] = &Tx[K, V]{sl: sl, staged: make(map[K]txWrite[V])}
+	}
+	txs := make([]*Tx[K, V], len(lists))
+	for i, sl := range lists {
+		txs[i] = byList[sl]
+	}
+
+	err := func() error {
+		defer func() {
+			if r := recover(); r != nil {
+				unlock()
+				panic(r)
+			}
+		}()
+		return fn(txs)
+	}()
+	if err != nil {
+		unlock()
+		return err
+	}
+
+	for _, sl := range locked {
+		if err := byList[sl].guardFull(); err != nil {
+			unlock()
+			return err
+		}
+	}
+	for _, sl := range locked {
+		if err := byList[sl].persist(); err != nil {
+			unlock()
+			return err
+		}
+	}
+	afters := make([]func(), 0, len(locked))
+	for _, sl := range locked {
+		afters = append(afters, byList[sl].commit())
+	}
+	unlock()
+
+	for _, after := range afters {
+		after()
+	}
+	return nil
+}
+
+// guardFull 在提交之前检查暂存的修改是否会使成员数超出 WithHardLimit，超出时返回 ErrFull，调用方需持有写锁
+// guardFull checks before the commit whether the staged changes would take the size past WithHardLimit,
+// returning ErrFull if so. The caller must hold the write lock
+func (tx *Tx[K, V]) guardFull() error {
+	sl := tx.sl
+	if sl.hardLimit == 0 {
+		return nil
+	}
+	size := sl.length
+	for _, key := range tx.order {
+		write := tx.staged[key]
+		_, exists := sl.lookup(key)
+		switch {
+		case exists && write.deleted:
+			size--
+		case !exists && !write.deleted && !(sl.zeroDeletes && write.value == ZeroValue[V]()):
+			size++
+		}
+	}
+	if size > sl.hardLimit && size > sl.length {
+		sl.vars.add(opRejected)
+		return ErrFull
+	}
+	return nil
+}
+
+// persist 在提交之前按顺序写穿暂存的修改，任何一次失败都返回错误，调用方需持有写锁
+// persist writes the staged changes through in order before the commit and returns the first failure.
+// The caller must hold the write lock
+func (tx *Tx[K, V]) persist() error {
+	sl := tx.sl
+	for _, key := range tx.order {
+		write := tx.staged[key]
+		if !write.deleted {
+			if err := sl.persistWrite(key, write.value); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, exists := sl.lookup(key); exists {
+			if err := sl.persist(walOpDel, key, ZeroValue[V]()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// commit 按顺序写入暂存的修改，返回需要在释放写锁之后调用的函数，调用方需持有写锁
+// commit writes the staged changes in order and returns a function to call once the write lock is released.
+// The caller must hold the write lock
+func (tx *Tx[K, V]) commit() (after func()) {
+	sl := tx.sl
+	if len(tx.order) == 0 {
+		return func() {}
+	}
+
+	zones := sl.zoneKeys()
+	results := make([]storeResult, 0, len(tx.order))
+	for _, key := range tx.order {
+		write := tx.staged[key]
+		if !write.deleted {
+			results = append(results, sl.store(key, write.value, keyspaceZadd))
+			continue
+		}
+		if ok, _ := sl.del(key); ok {
+			delete(sl.meta, key)
+			sl.journal(walOpDel, key, ZeroValue[V]())
+			results = append(results, storeDeleted)
+		}
+	}
+	events := sl.zoneEvents(zones)
+
+	return func() {
+		for _, result := range results {
+			result.count(sl.vars)
+		}
+		fireThresholds(events)
+	}
+}
+
+// Get 返回键在事务内的值，包括本事务暂存的修改
+// Get returns the value of key as seen by the transaction, changes staged by it included
+func (tx *Tx[K, V]) Get(key K) (V, bool) {
+	if write, ok := tx.staged[key]; ok {
+		return write.value, !write.deleted
+	}
+	if node, exists := tx.sl.lookup(key); exists {
+		return node.data.Value, true
+	}
+	return ZeroValue[V](), false
+}
+
+// Set 暂存将键的值设置为 value 的修改，新值被 WithValueGuard 拒绝时不暂存并返回错误
+// Set stages setting key to value. When WithValueGuard rejects the new value nothing is staged and the error is returned
+func (tx *Tx[K, V]) Set(key K, value V) error {
+	old, _ := tx.Get(key)
+	if err := tx.sl.guardValue(old, value); err != nil {
+		return err
+	}
+	tx.stage(key, txWrite[V]{value: value})
+	return nil
+}
+
+// IncrBy 暂存将键的值增加 delta 的修改并返回新值，键不存在时从零值开始累加。
+// 增量被 WithMaxDelta 拒绝或新值被 WithValueGuard 拒绝时不暂存，返回当前值和错误
+// IncrBy stages incrementing key by delta and returns the new value, a missing key starts from the zero value.
+// When WithMaxDelta rejects the delta or WithValueGuard rejects the new value nothing is staged
+// and the current value is returned with the error
+func (tx *Tx[K, V]) IncrBy(key K, delta V) (V, error) {
+	value, _ := tx.Get(key)
+	if !tx.sl.deltaAllowed(delta) {
+		return value, ErrDeltaTooLarge
+	}
+	if err := tx.sl.guardValue(value, value+delta); err != nil {
+		return value, err
+	}
+	value += delta
+	tx.stage(key, txWrite[V]{value: value})
+	return value, nil
+}
+
+// Del 暂存删除键的修改，键在事务内存在时返回 true
+// Del stages deleting key and returns true if the key exists as seen by the transaction
+func (tx *Tx[K, V]) Del(key K) bool {
+	_, exists := tx.Get(key)
+	if exists {
+		tx.stage(key, txWrite[V]{deleted: true})
+	}
+	return exists
+}
+
+// stage 记录对键的暂存修改
+// stage records the staged change of key
+func (tx *Tx[K, V]) stage(key K, write txWrite[V]) {
+	if _, ok := tx.staged[key]; !ok {
+		tx.order = append(tx.order, key)
+	}
+	tx.staged[key] = write
+}
@@ -0,0 +1,244 @@
+package ranklist
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// transfer 在一个事务内将 amount 积分从 from 转给 to，余额不足时回滚
+// transfer moves amount points from from to to within one transaction, rolling back on insufficient funds
+func transfer(sl *RankList[string, int], from, to string, amount int) error {
+	return sl.Txn(func(tx *Tx[string, int]) error {
+		if _, err := tx.IncrBy(to, amount); err != nil {
+			return err
+		}
+		balance, err := tx.IncrBy(from, -amount)
+		if err != nil {
+			return err
+		}
+		if balance < 0 {
+			return errors.New("insufficient funds")
+		}
+		return nil
+	})
+}
+
+func TestTxnTransfer(t *testing.T) {
+	var wal bytes.Buffer
+	sl := New(WithWAL[string, int](&wal))
+	sl.Set("alice", 100)
+	sl.Set("bob", 50)
+	sl.Set("carol", 10)
+
+	if err := transfer(sl, "alice", "bob", 30); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries := sl.Entries(); !slices.Equal(entries, []Entry[string, int]{{"carol", 10}, {"alice", 70}, {"bob", 80}}) {
+		t.Fatalf("unexpected content after the transfer: %v", entries)
+	}
+
+	err := sl.Txn(func(tx *Tx[string, int]) error {
+		if !tx.Del("carol") || tx.Del("dave") {
+			t.Errorf("Del should report whether the key exists in the transaction")
+		}
+		if _, ok := tx.Get("carol"); ok {
+			t.Errorf("a staged delete should hide the key")
+		}
+		if err := tx.Set("dave", 5); err != nil {
+			return err
+		}
+		if value, ok := tx.Get("dave"); !ok || value != 5 {
+			t.Errorf("a staged set should be visible, got %d, %v", value, ok)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries := sl.Entries(); !slices.Equal(entries, []Entry[string, int]{{"dave", 5}, {"alice", 70}, {"bob", 80}}) {
+		t.Fatalf("unexpected content after the second transaction: %v", entries)
+	}
+
+	replayed := New[string, int]()
+	if err := replayed.Replay(&wal); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(replayed.Entries(), sl.Entries()) {
+		t.Fatalf("the log should replay to the committed content, got %v", replayed.Entries())
+	}
+}
+
+func TestTxnRollback(t *testing.T) {
+	sl := New(WithMaxDelta[string, int](100))
+	sl.Set("alice", 20)
+	sl.Set("bob", 50)
+	version := sl.Version()
+
+	if err := transfer(sl, "alice", "bob", 30); err == nil || err.Error() != "insufficient funds" {
+		t.Fatalf("expected the error of fn, got %v", err)
+	}
+	if err := transfer(sl, "bob", "alice", 500); !errors.Is(err, ErrDeltaTooLarge) {
+		t.Fatalf("expected ErrDeltaTooLarge, got %v", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected the panic of fn to propagate")
+			}
+		}()
+		sl.Txn(func(tx *Tx[string, int]) error {
+			tx.Del("alice")
+			panic("boom")
+		})
+	}()
+
+	if entries := sl.Entries(); !slices.Equal(entries, []Entry[string, int]{{"alice", 20}, {"bob", 50}}) {
+		t.Fatalf("a rolled back transaction should leave the list untouched, got %v", entries)
+	}
+	if sl.Version() != version {
+		t.Fatalf("a rolled back transaction should not change the version")
+	}
+
+	sl.Close()
+	called := false
+	if err := sl.Txn(func(tx *Tx[string, int]) error { called = true; return nil }); !errors.Is(err, ErrClosed) || called {
+		t.Fatalf("expected ErrClosed without calling fn, got %v, %v", err, called)
+	}
+}
+
+func TestTxnConcurrent(t *testing.T) {
+	sl := New[string, int]()
+	const players = 10
+	for i := 0; i < players; i++ {
+		sl.Set("p"+strconv.Itoa(i), 1000)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				from := "p" + strconv.Itoa((g+i)%players)
+				to := "p" + strconv.Itoa((g*3+i+1)%players)
+				transfer(sl, from, to, 1+i%7)
+			}
+		}(g)
+	}
+
+	// 转账期间读取的总额始终不变
+	// The total read while transfers run never changes
+	for i := 0; i < 200; i++ {
+		total := 0
+		for _, entry := range sl.Entries() {
+			total += entry.Value
+			if entry.Value < 0 {
+				t.Fatalf("a balance went negative: %v", entry)
+			}
+		}
+		if total != players*1000 {
+			t.Fatalf("expected a total of %d, got %d", players*1000, total)
+		}
+	}
+	wg.Wait()
+
+	if err := sl.Check(); err != nil {
+		t.Fatalf("corrupted list: %v", err)
+	}
+	total := 0
+	for _, entry := range sl.Entries() {
+		total += entry.Value
+	}
+	if total != players*1000 {
+		t.Fatalf("expected a total of %d, got %d", players*1000, total)
+	}
+}
+
+func TestTxnAll(t *testing.T) {
+	a, b := New[string, int](), New[string, int]()
+	a.Set("x", 1)
+
+	// 任意一个跳表的修改失败时两个跳表都不变
+	// When the change to either list fails neither list changes
+	err := TxnAll([]*RankList[string, int]{a, b}, func(txs []*Tx[string, int]) error {
+		txs[0].Set("x", 2)
+		txs[1].Set("x", 2)
+		return errors.New("abort")
+	})
+	if err == nil || a.Version() != 1 || b.Length() != 0 {
+		t.Fatalf("expected both lists untouched, got %v", err)
+	}
+
+	// 同一个跳表出现多次时共享同一个事务
+	// A list appearing more than once shares one transaction
+	err = TxnAll([]*RankList[string, int]{a, b, a}, func(txs []*Tx[string, int]) error {
+		if txs[0] != txs[2] {
+			t.Errorf("expected a repeated list to share its transaction")
+		}
+		txs[0].IncrBy("x", 10)
+		txs[2].IncrBy("x", 10)
+		txs[1].Set("y", 5)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, _ := a.Get("x"); value != 21 {
+		t.Fatalf("expected 21, got %d", value)
+	}
+	if value, _ := b.Get("y"); value != 5 {
+		t.Fatalf("expected 5, got %d", value)
+	}
+
+	b.Close()
+	if err := TxnAll([]*RankList[string, int]{a, b}, func([]*Tx[string, int]) error { return nil }); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	if !a.Set("z", 1) {
+		t.Fatalf("the open list should have been unlocked")
+	}
+}
+
+func TestTxnAllStore(t *testing.T) {
+	store := NewMemStore[string, int]()
+	a := New(WithStore[string, int](store, true))
+	b := New(WithHardLimit[string, int](1))
+	b.Set("y", 1)
+	write := func() error {
+		return TxnAll([]*RankList[string, int]{a, b}, func(txs []*Tx[string, int]) error {
+			txs[0].Set("x", 1)
+			txs[1].Set("z", 1)
+			return nil
+		})
+	}
+
+	// 所有跳表都通过检查之后才写穿外部存储
+	// Nothing reaches the store until every list passes its checks
+	if err := write(); !errors.Is(err, ErrFull) {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+	if _, ok := store.Get("x"); ok || a.Length() != 0 {
+		t.Fatal("a rejected transaction should not reach the store")
+	}
+
+	// 任意一个跳表写穿失败时所有跳表的提交都被放弃
+	// A failure to write through abandons the commit of every list
+	failing := NewMemStore[string, int]()
+	failing.Fail = func(string, string) error { return errStoreDown }
+	c := New(WithStore[string, int](failing, true))
+	err := TxnAll([]*RankList[string, int]{a, c}, func(txs []*Tx[string, int]) error {
+		txs[0].Set("x", 1)
+		txs[1].Set("x", 1)
+		return nil
+	})
+	if !errors.Is(err, ErrStore) {
+		t.Fatalf("expected ErrStore, got %v", err)
+	}
+	if a.Length() != 0 || c.Length() != 0 {
+		t.Fatal("expected both lists untouched")
+	}
+}
@@ -0,0 +1,133 @@
+package ranklist
+
+// GetVersioned 返回键的值及其条目版本，键不存在时返回 false，加锁方式与 Get 相同。
+// 键创建时版本为 1，值每改变一次递增 1，写入相同的值不改变版本；
+// 键被删除后重新创建时版本重新从 1 开始，Restore、Load 等整体替换内容的操作同样会让所有键从 1 开始，
+// 因此只有在键一直存在期间，版本相同才表示值未被修改
+// GetVersioned returns the value of key and its entry version, false if the key does not exist.
+// It locks the same way Get does.
+// A key starts at version 1 and every value change bumps it by one, writing the same value leaves it alone;
+// a key that is deleted and created again restarts at version 1, and so does every key after
+// whole-content replacements such as Restore and Load,
+// so an equal version only proves an unchanged value while the key has existed throughout
+func (sl *RankList[K, V]) GetVersioned(key K) (V, uint64, bool) {
+	sl.vars.add(opGet)
+	if sl.noDict {
+		sl.RLock()
+		defer sl.RUnlock()
+	} else {
+		sl.dictMu.RLock()
+		defer sl.dictMu.RUnlock()
+	}
+
+	if node, exists := sl.lookup(key); exists {
+		return node.data.Value, node.version, true
+	}
+	return ZeroValue[V](), 0, false
+}
+
+// SetIfVersion 仅在键当前的条目版本等于 expectedVersion 时写入 value，返回是否写入。
+// expectedVersion 为 0 表示期望键不存在，此时只在键不存在时创建它。
+// 成功的 SetIfVersion 总会使版本前进，即使值没有改变，因此持有同一版本的多个调用方中恰好只有一个成功
+// SetIfVersion writes value only when the current entry version of key equals expectedVersion, and reports whether it did.
+// An expectedVersion of 0 expects the key to be absent, creating it only in that case.
+// A successful SetIfVersion always advances the version, even for an unchanged value,
+// so exactly one of several callers holding the same version succeeds
+func (sl *RankList[K, V]) SetIfVersion(key K, value V, expectedVersion uint64) bool {
+	return sl.SetIfVersionChecked(key, value, expectedVersion) == nil
+}
+
+// SetIfVersionChecked 与 SetIfVersion 相同，但以错误说明没有写入的原因：
+// 版本不一致时返回 ErrVersionMismatch，值被 WithValueGuard 拒绝时返回属于 ErrRejected 的错误，跳表已关闭时返回 ErrClosed
+// SetIfVersionChecked behaves like SetIfVersion, but reports why nothing was written:
+// ErrVersionMismatch when the versions differ, an ErrRejected when WithValueGuard rejects the value
+// and ErrClosed once the list is closed
+func (sl *RankList[K, V]) SetIfVersionChecked(key K, value V, expectedVersion uint64) error {
+	sl.Lock()
+	if sl.closed {
+		sl.Unlock()
+		return ErrClosed
+	}
+	var version uint64
+	if node, exists := sl.lookup(key); exists {
+		version = node.version
+	}
+	if version != expectedVersion {
+		sl.Unlock()
+		return ErrVersionMismatch
+	}
+	if err := sl.guardWrite(key, value); err != nil {
+		sl.Unlock()
+		return err
+	}
+	if err := sl.persist(walOpSet, key, value); err != nil {
+		sl.Unlock()
+		return err
+	}
+
+	probes := sl.probeThresholds(key)
+	inserted := sl.set(key, value)
+	if node, exists := sl.lookup(key); exists && node.version == expectedVersion {
+		sl.dictMu.Lock()
+		node.version++
+		sl.dictMu.Unlock()
+	}
+	sl.journal(walOpSet, key, value)
+	sl.evictOverflow()
+	events := sl.thresholdEvents(key, probes)
+	sl.Unlock()
+
+	sl.vars.add(opSet)
+	if !inserted {
+		sl.vars.add(opUpdate)
+	}
+	fireThresholds(events)
+	return nil
+}
+
+// entryState 记录重建跳表时需要为每个条目保留的状态
+// entryState records the per-entry state to carry across a rebuild of the skip list
+type entryState[V Ordered] struct {
+	value       V
+	version     uint64
+	times       *entryTimes
+	placeholder bool
+}
+
+// states 返回每个键当前的值、条目版本和时间戳，调用方需持有锁
+// states returns the current value, entry version and timestamps of every key, the caller must hold the lock
+func (sl *RankList[K, V]) states() map[K]entryState[V] {
+	states := make(map[K]entryState[V], sl.length)
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		states[curr.data.Key] = entryState[V]{value: curr.data.Value, version: curr.version, times: curr.times, placeholder: curr.placeholder}
+	}
+	return states
+}
+
+// keepStates 在重建之后恢复原有键的条目版本和创建时间，值发生改变的键版本递增 1、更新时间刷新，调用方需持有写锁。
+// 重建后的字典已经对 Get 可见，因此在字典锁内修改
+// keepStates restores the entry versions and creation times of pre-existing keys after a rebuild,
+// keys whose value changed get their version bumped by one and their update time refreshed.
+// The caller must hold the write lock.
+// The rebuilt dictionary is already visible to Get, so the state is written under the dictionary lock
+func (sl *RankList[K, V]) keepStates(states map[K]entryState[V]) {
+	defer sl.rebuildStale()
+	sl.dictMu.Lock()
+	defer sl.dictMu.Unlock()
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		prev, exists := states[curr.data.Key]
+		if !exists {
+			continue
+		}
+		curr.version = prev.version
+		curr.placeholder = prev.placeholder
+		if prev.value == curr.data.Value {
+			curr.times = prev.times
+			continue
+		}
+		curr.version++
+		if prev.times != nil {
+			curr.times = &entryTimes{created: prev.times.created, updated: curr.times.updated}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package ranklist
+
+import "testing"
+
+func TestVersion(t *testing.T) {
+	sl := New[string, int]()
+	steps := []struct {
+		name string
+		op   func()
+		bump uint64
+	}{
+		{"insert", func() { sl.Set("a", 1) }, 1},
+		{"insert", func() { sl.Set("b", 2) }, 1},
+		{"unchanged value", func() { sl.Set("a", 1) }, 0},
+		{"in-place update", func() { sl.Set("a", 0) }, 1},
+		{"reordering update", func() { sl.Set("a", 5) }, 1},
+		{"zero increment", func() { sl.IncrBy("b", 0) }, 0},
+		{"increment", func() { sl.IncrBy("b", 1) }, 1},
+		{"reads", func() {
+			sl.Get("a")
+			sl.Rank("a")
+			sl.Range(1, 3)
+			sl.Snapshot()
+		}, 0},
+		{"missing delete", func() { sl.Del("x") }, 0},
+		{"delete", func() { sl.Del("b") }, 1},
+		{"batch", func() {
+			sl.SetBatch([]Entry[string, int]{{"c", 3}, {"a", 5}, {"d", 4}})
+		}, 2},
+		{"stale delete by value", func() { sl.DelByValue("c", 9) }, 0},
+		{"delete by value", func() { sl.DelByValue("c", 3) }, 1},
+		{"compact", func() { sl.Compact() }, 0},
+		{"restore", func() { sl.Restore([]Entry[string, int]{{"e", 1}}) }, 1},
+		{"load map", func() { sl.LoadMap(map[string]int{"f": 1, "g": 2}, false) }, 1},
+		{"clear", func() { sl.Clear() }, 1},
+	}
+
+	for _, step := range steps {
+		before := sl.Version()
+		step.op()
+		if bump := sl.Version() - before; bump != step.bump {
+			t.Errorf("%s: expected the version to grow by %d, got %d", step.name, step.bump, bump)
+		}
+	}
+}
+
+func TestEntryVersion(t *testing.T) {
+	sl := New[string, int]()
+	if _, version, ok := sl.GetVersioned("a"); ok || version != 0 {
+		t.Fatalf("a missing key should report version 0 and false")
+	}
+
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	requireEntryVersion(t, sl, "a", 1)
+	sl.Set("a", 10)
+	requireEntryVersion(t, sl, "a", 1)
+	sl.Set("a", 11)
+	requireEntryVersion(t, sl, "a", 2)
+	sl.Set("a", 30)
+	requireEntryVersion(t, sl, "a", 3)
+	sl.IncrBy("a", 1)
+	requireEntryVersion(t, sl, "a", 4)
+
+	sl.Compact()
+	requireEntryVersion(t, sl, "a", 4)
+	sl.LoadMap(map[string]int{"a": 31, "b": 21, "c": 1}, false)
+	requireEntryVersion(t, sl, "a", 4)
+	requireEntryVersion(t, sl, "b", 2)
+	requireEntryVersion(t, sl, "c", 1)
+}
+
+func TestSetIfVersion(t *testing.T) {
+	sl := New[string, int]()
+	if !sl.SetIfVersion("a", 10, 0) {
+		t.Fatalf("version 0 should create a missing key")
+	}
+	if sl.SetIfVersion("a", 20, 0) {
+		t.Fatalf("version 0 should fail for an existing key")
+	}
+	if sl.SetIfVersion("a", 20, 2) {
+		t.Fatalf("a stale version should fail")
+	}
+	if !sl.SetIfVersion("a", 10, 1) {
+		t.Fatalf("the current version should succeed")
+	}
+	// 成功的写入即使值未变也会推进版本
+	// A successful write advances the version even for an unchanged value
+	requireEntryVersion(t, sl, "a", 2)
+
+	// 删除后重新创建的键从版本 1 重新开始，持有旧版本 1 的调用方会误判为未修改
+	// A deleted and recreated key restarts at version 1, so a caller holding the old version 1 cannot tell
+	_, stale, _ := sl.GetVersioned("a")
+	sl.Del("a")
+	if sl.SetIfVersion("a", 5, stale) {
+		t.Fatalf("a deleted key should not match a stale version")
+	}
+	sl.Set("a", 99)
+	requireEntryVersion(t, sl, "a", 1)
+	if !sl.SetIfVersion("a", 5, 1) {
+		t.Fatalf("the recreated key should accept version 1")
+	}
+}
+
+func TestSetIfVersionRace(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 0)
+
+	for round := 0; round < 50; round++ {
+		_, version, _ := sl.GetVersioned("a")
+		results := make(chan bool)
+		for i := 0; i < 8; i++ {
+			go func() {
+				results <- sl.SetIfVersion("a", round%2, version)
+			}()
+		}
+		winners := 0
+		for i := 0; i < 8; i++ {
+			if <-results {
+				winners++
+			}
+		}
+		if winners != 1 {
+			t.Fatalf("round %d: expected exactly one winner, got %d", round, winners)
+		}
+	}
+}
+
+func requireEntryVersion[K Ordered, V Ordered](t *testing.T, sl *RankList[K, V], key K, expected uint64) {
+	t.Helper()
+	if _, version, ok := sl.GetVersioned(key); !ok || version != expected {
+		t.Fatalf("key %v: expected version %d, got %d, %v", key, expected, version, ok)
+	}
+}
@@ -0,0 +1,94 @@
+package ranklist
+
+import "slices"
+
+// View 是跳表在某一时刻的只读视图，读取时不需要任何锁。
+// 内存开销：视图持有全部条目的有序副本和一份字典副本，约为跳表本身数据量的两倍。
+// 时效性：视图反映的是创建时的状态，之后跳表的修改不会体现在视图中
+// View is a read-only point-in-time view of a skip list, reading it takes no locks.
+// Memory cost: a view holds a sorted copy of every entry and a copy of the dictionary,
+// roughly twice the data held by the list itself.
+// Staleness: a view reflects the state at the moment it was taken, later changes to the list are not visible
+type View[K Ordered, V Ordered] struct {
+	entries []Entry[K, V]
+	dict    map[K]V
+
+	// 创建视图的跳表的排序规则
+	// Order of the list the view was taken from
+	compare func(a, b Entry[K, V]) int
+}
+
+// SnapshotView 在一把读锁内复制当前的全部条目，返回一个不可变的视图，之后对视图的读取不再持有跳表的锁
+// SnapshotView copies all current entries under one read lock and returns an immutable view,
+// later reads from the view never hold the list's lock
+func (sl *RankList[K, V]) SnapshotView() *View[K, V] {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	entries := sl.entries()
+	dict := make(map[K]V, len(entries))
+	for _, entry := range entries {
+		dict[entry.Key] = entry.Value
+	}
+	return &View[K, V]{entries: entries, dict: dict, compare: sl.compare}
+}
+
+// Length 返回视图中的元素数量
+// Length returns the number of elements in the view
+func (v *View[K, V]) Length() int {
+	return len(v.entries)
+}
+
+// Get 返回键在视图中的值
+// Get returns the value of the key in the view
+func (v *View[K, V]) Get(key K) (V, bool) {
+	value, exists := v.dict[key]
+	return value, exists
+}
+
+// Rank 通过二分查找返回键在视图中的排名
+// Rank returns the rank of the key in the view using a binary search
+func (v *View[K, V]) Rank(key K) (int, bool) {
+	value, exists := v.dict[key]
+	if !exists {
+		return 0, false
+	}
+	i, found := slices.BinarySearchFunc(v.entries, Entry[K, V]{Key: key, Value: value}, v.compare)
+	return i + 1, found
+}
+
+// Range 获取视图中指定排名区间内的榜单项（不包含END），start 小于 1 时按 1 处理，返回的切片是副本
+// Range retrieves the entries of the view within the specified rank range (excluding END),
+// a start below 1 is treated as 1 and the returned slice is a copy
+func (v *View[K, V]) Range(start int, end int) []Entry[K, V] {
+	start = max(start, 1)
+	end = min(end, len(v.entries)+1)
+	if start >= end {
+		return []Entry[K, V]{}
+	}
+	return slices.Clone(v.entries[start-1 : end-1])
+}
+
+// GetByRank 返回视图中指定排名上的条目，超出 [1, Length()] 时返回 false，耗时 O(1)
+// GetByRank returns the entry at the given 1-based rank of the view, false outside [1, Length()], in O(1)
+func (v *View[K, V]) GetByRank(rank int) (Entry[K, V], bool) {
+	if rank < 1 || rank > len(v.entries) {
+		return Entry[K, V]{}, false
+	}
+	return v.entries[rank-1], true
+}
+
+// Top 返回视图中值最大的 n 个条目，按值从高到低排列，与 RankList.Top 相同
+// Top returns the n entries of the view with the largest values from the highest value down, like RankList.Top
+func (v *View[K, V]) Top(n int) []Entry[K, V] {
+	n = min(max(n, 0), len(v.entries))
+	entries := slices.Clone(v.entries[len(v.entries)-n:])
+	slices.Reverse(entries)
+	return entries
+}
+
+// Entries 按排名顺序返回视图的全部条目，返回的切片是副本
+// Entries returns every entry of the view in rank order, the returned slice is a copy
+func (v *View[K, V]) Entries() []Entry[K, V] {
+	return slices.Clone(v.entries)
+}
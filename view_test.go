@@ -0,0 +1,108 @@
+package ranklist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSnapshotView(t *testing.T) {
+	sl := New[string, int]()
+	for i := 0; i < 500; i++ {
+		sl.Set("key"+strconv.Itoa(i), rand.IntN(100))
+	}
+
+	view := sl.SnapshotView()
+	expected := sl.Snapshot()
+	ranks := make(map[string]int, len(expected))
+	for _, entry := range expected {
+		ranks[entry.Key], _ = sl.Rank(entry.Key)
+	}
+
+	check := func() {
+		t.Helper()
+		if view.Length() != len(expected) {
+			t.Fatalf("expected view length %d, got %d", len(expected), view.Length())
+		}
+		if got := view.Range(1, len(expected)+1); !slices.Equal(got, expected) {
+			t.Fatalf("view range changed")
+		}
+		for _, entry := range expected {
+			if value, ok := view.Get(entry.Key); !ok || value != entry.Value {
+				t.Fatalf("Key %v: expected value %v, got %v, %v", entry.Key, entry.Value, value, ok)
+			}
+			if rank, ok := view.Rank(entry.Key); !ok || rank != ranks[entry.Key] {
+				t.Fatalf("Key %v: expected rank %d, got %d, %v", entry.Key, ranks[entry.Key], rank, ok)
+			}
+		}
+	}
+	check()
+
+	// 视图创建之后大量修改跳表，包括并发修改
+	// Mutate the list heavily after taking the view, concurrently as well
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := "key" + strconv.Itoa(rand.IntN(600))
+				if rand.IntN(3) == 0 {
+					sl.Del(key)
+				} else {
+					sl.Set(key, rand.IntN(100))
+				}
+			}
+		}()
+	}
+	check()
+	wg.Wait()
+	sl.Clear()
+	check()
+
+	if _, ok := view.Get("key599"); ok {
+		t.Errorf("keys added after the view should not be visible")
+	}
+	if _, ok := view.Rank("missing"); ok {
+		t.Errorf("missing key should not have a rank")
+	}
+}
+
+func TestSnapshotViewRange(t *testing.T) {
+	sl := New[string, int]()
+	for i := 1; i <= 5; i++ {
+		sl.Set("k"+strconv.Itoa(i), i)
+	}
+	view := sl.SnapshotView()
+
+	tests := []struct {
+		start, end int
+		expected   []int
+	}{
+		{1, 3, []int{1, 2}},
+		{0, 3, []int{1, 2}},
+		{4, 10, []int{4, 5}},
+		{6, 10, []int{}},
+		{3, 3, []int{}},
+		{4, 2, []int{}},
+	}
+	for _, tt := range tests {
+		values := []int{}
+		for _, entry := range view.Range(tt.start, tt.end) {
+			values = append(values, entry.Value)
+		}
+		if !slices.Equal(values, tt.expected) {
+			t.Errorf("Range(%d, %d): expected %v, got %v", tt.start, tt.end, tt.expected, values)
+		}
+	}
+
+	// 修改返回的切片不会影响视图
+	// Modifying the returned slice does not affect the view
+	window := view.Range(1, 2)
+	window[0].Value = 100
+	if view.Range(1, 2)[0].Value != 1 {
+		t.Errorf("Range should return a copy")
+	}
+}
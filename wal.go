@@ -0,0 +1,249 @@
+package ranklist
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+)
+
+const (
+	// 预写日志中的操作类型
+	// Operation types of write-ahead log records
+	walOpSet     byte = 1
+	walOpDel     byte = 2
+	walOpClear   byte = 3
+	walOpReserve byte = 4
+)
+
+// wal 记录预写日志的写入状态
+// wal holds the state of the write-ahead log
+type wal struct {
+	w   io.Writer
+	seq uint64
+	err error
+	buf []byte
+}
+
+// WithWAL 开启预写日志，每次修改提交后都会向 w 追加一条记录。
+// 记录格式为：4 字节小端长度、记录体（操作类型、序号、键、值）、记录体的 4 字节 CRC32 校验和。
+// IncrBy 以增量后的结果值记录为写入操作，因此重放是幂等的；批量替换（Clear、Load 等）
+// 记录为一次清空加上新内容的全部写入。Reserve 插入的占位条目单独记录，重放时仍是占位条目。元数据不会写入日志。
+// 记录在写锁内写入，以保证日志顺序与修改顺序一致，传入带缓冲的 w 可以降低开销。
+// 第一次写入失败后日志停止记录，错误可以通过 WALError 获取
+// WithWAL enables the write-ahead log, every committed mutation appends a record to w.
+// A record is a 4-byte little-endian length, the body (op, sequence, key, value)
+// and a 4-byte CRC32 of the body.
+// IncrBy is journaled as a set of the resulting value so replay is idempotent, bulk replacements
+// (Clear, Load, ...) are journaled as a clear followed by a set for every new entry.
+// Placeholders inserted by Reserve get records of their own and replay as placeholders. Metadata is not journaled.
+// Records are written under the write lock so the log order matches the mutation order,
+// pass a buffered w to reduce the cost.
+// Logging stops after the first write failure, which is reported by WALError
+func WithWAL[K Ordered, V Ordered](w io.Writer) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.wal = &wal{w: w}
+	}
+}
+
+// WALError 返回预写日志第一次写入失败的错误
+// WALError returns the first error that occurred while writing the write-ahead log
+func (sl *RankList[K, V]) WALError() error {
+	sl.RLock()
+	defer sl.RUnlock()
+
+	if sl.wal == nil {
+		return nil
+	}
+	return sl.wal.err
+}
+
+// journal 向预写日志追加一条记录，并记录对应的键空间事件，调用方需持有写锁
+// journal appends a record to the write-ahead log and records the matching keyspace event.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) journal(op byte, key K, value V) {
+	switch op {
+	case walOpSet, walOpReserve:
+		sl.journalAs(op, keyspaceZadd, key, value)
+	case walOpDel:
+		sl.journalAs(op, keyspaceZrem, key, value)
+	default:
+		sl.journalAs(op, keyspaceDel, key, value)
+	}
+}
+
+// journalAs 与 journal 相同，但以 event 作为键空间事件名，例如把增量写入记为 zincr，调用方需持有写锁
+// journalAs behaves like journal but records event as the keyspace event name,
+// e.g. zincr for a write made by an increment. The caller must hold the write lock
+func (sl *RankList[K, V]) journalAs(op byte, event string, key K, value V) {
+	sl.sketchMutation(op)
+	sl.journalRecord(op, event, key, value)
+}
+
+// journalRecord 发出键空间事件并写入预写日志，不计入排名草图的修改次数，调用方需持有写锁
+// journalRecord emits the keyspace event and writes the write-ahead log
+// without counting a mutation against the rank sketch. The caller must hold the write lock
+func (sl *RankList[K, V]) journalRecord(op byte, event string, key K, value V) {
+	if op == walOpClear {
+		sl.keyspace.notifyBoard(event)
+	} else {
+		sl.keyspace.notify(event, key)
+	}
+	if sl.wal == nil || sl.wal.err != nil {
+		return
+	}
+
+	l := sl.wal
+	l.seq++
+	body := append(l.buf[:0], 0, 0, 0, 0, op)
+	body = binary.AppendUvarint(body, l.seq)
+	if op != walOpClear {
+		body = appendOrdered(body, key)
+	}
+	if op == walOpSet || op == walOpReserve {
+		body = appendOrdered(body, value)
+	}
+	binary.LittleEndian.PutUint32(body, uint32(len(body)-4))
+	body = binary.LittleEndian.AppendUint32(body, crc32.ChecksumIEEE(body[4:]))
+	l.buf = body
+
+	_, l.err = l.w.Write(body)
+}
+
+// journalReset 将一次批量替换记录为清空加上当前全部内容的写入，写穿到外部存储，并重新采集排名草图，调用方需持有写锁
+// journalReset journals a bulk replacement as a clear followed by a set for every current entry,
+// writes it through to the external store and refreshes the rank sketch. The caller must hold the write lock
+func (sl *RankList[K, V]) journalReset() {
+	sl.mirrorReset()
+	sl.journalContent()
+}
+
+// journalContent 与 journalReset 相同但不写穿外部存储，用于内容本就来自外部存储的情况，调用方需持有写锁
+// journalContent is journalReset without the write-through, for content that came from the external store in the first place.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) journalContent() {
+	sl.refreshSketch()
+	if sl.wal == nil && sl.keyspace == nil {
+		return
+	}
+	sl.journalRecord(walOpClear, keyspaceDel, ZeroValue[K](), ZeroValue[V]())
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		op := walOpSet
+		if curr.placeholder {
+			op = walOpReserve
+		}
+		sl.journalRecord(op, keyspaceZadd, curr.data.Key, curr.data.Value)
+	}
+}
+
+// Replay 从 r 读取预写日志并依次应用到跳表，通常用于空跳表或刚通过 Load 恢复快照的跳表。
+// 末尾不完整或校验失败的记录被视为崩溃时写了一半的记录而跳过，损坏的长度字段同样按数据不足处理，不会按它分配内存；
+// 中间的记录损坏时返回 ErrInvalidWAL，此前的记录已经被应用
+// Replay reads a write-ahead log from r and applies it to the list in order,
+// typically to an empty list or one just restored with Load.
+// An incomplete or corrupted final record is treated as torn by a crash and skipped,
+// as is a corrupted length field running past the data, which never sizes an allocation on its own;
+// a corrupted record in the middle returns ErrInvalidWAL after the preceding records have been applied
+func (sl *RankList[K, V]) Replay(r io.Reader) error {
+	if sl.Closed() {
+		return ErrClosed
+	}
+	br := bufio.NewReader(r)
+	var header [4]byte
+	for {
+		if _, err := io.ReadFull(br, header[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return err
+		}
+
+		record, err := readRecord(br, int(binary.LittleEndian.Uint32(header[:]))+4)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return err
+		}
+
+		body, sum := record[:len(record)-4], binary.LittleEndian.Uint32(record[len(record)-4:])
+		if crc32.ChecksumIEEE(body) != sum {
+			if _, err := br.Peek(1); errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("%w: checksum mismatch", ErrInvalidWAL)
+		}
+		if err := sl.apply(body); err != nil {
+			return err
+		}
+	}
+}
+
+// walReadChunk 是读取一条预写日志记录时缓冲区每次最多增长的字节数
+// walReadChunk is the most the buffer grows by at a time while reading one write-ahead log record
+const walReadChunk = 64 << 10
+
+// readRecord 从 r 读取 size 个字节，缓冲区按读到的数据分段增长，
+// 因此损坏的长度字段分配的内存不会超过实际存在的数据量，数据不足时返回 io.ErrUnexpectedEOF 或 io.EOF
+// readRecord reads size bytes from r, growing the buffer in chunks as data arrives,
+// so a corrupted length field never allocates much more than the data actually present.
+// It returns io.ErrUnexpectedEOF or io.EOF when the data runs out
+func readRecord(r io.Reader, size int) ([]byte, error) {
+	record := make([]byte, 0, min(size, walReadChunk))
+	for len(record) < size {
+		n := min(size-len(record), walReadChunk)
+		record = slices.Grow(record, n)
+		read, err := io.ReadFull(r, record[len(record):len(record)+n])
+		record = record[:len(record)+read]
+		if err != nil {
+			return record, err
+		}
+	}
+	return record, nil
+}
+
+// apply 解码并应用一条预写日志记录
+// apply decodes and applies one write-ahead log record
+func (sl *RankList[K, V]) apply(body []byte) error {
+	if len(body) == 0 {
+		return fmt.Errorf("%w: empty record", ErrInvalidWAL)
+	}
+	op := body[0]
+	_, n := binary.Uvarint(body[1:])
+	if n <= 0 {
+		return fmt.Errorf("%w: invalid sequence", ErrInvalidWAL)
+	}
+	body = body[1+n:]
+
+	if op == walOpClear {
+		sl.Clear()
+		return nil
+	}
+
+	key, n, err := readOrdered[K](body)
+	if err != nil {
+		return fmt.Errorf("%w: invalid key", ErrInvalidWAL)
+	}
+	body = body[n:]
+
+	switch op {
+	case walOpSet, walOpReserve:
+		value, _, err := readOrdered[V](body)
+		if err != nil {
+			return fmt.Errorf("%w: invalid value", ErrInvalidWAL)
+		}
+		if op == walOpReserve {
+			sl.Reserve(key, value)
+		} else {
+			sl.Set(key, value)
+		}
+	case walOpDel:
+		sl.Del(key)
+	default:
+		return fmt.Errorf("%w: unknown operation %d", ErrInvalidWAL, op)
+	}
+	return nil
+}
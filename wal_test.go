@@ -0,0 +1,254 @@
+package ranklist
+
+import (
+	"bytes"
+	"errors"
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func requireSameEntries[K Ordered, V Ordered](t *testing.T, expected, result *RankList[K, V]) {
+	t.Helper()
+
+	a := expected.Range(1, expected.Length()+1)
+	b := result.Range(1, result.Length()+1)
+	if len(a) != len(b) {
+		t.Fatalf("expected %d entries, got %d", len(a), len(b))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("at index %d: expected %v, got %v", i, a[i], b[i])
+		}
+	}
+}
+
+func TestWALReplay(t *testing.T) {
+	var log bytes.Buffer
+	sl := New(WithWAL[string, int](&log))
+
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	sl.IncrBy("a", 5)
+	sl.Del("b")
+	sl.Del("missing")
+	sl.Set("c", 3)
+
+	replayed := New[string, int]()
+	if err := replayed.Replay(&log); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	requireSameEntries(t, sl, replayed)
+}
+
+func TestWALReplayBulk(t *testing.T) {
+	var log bytes.Buffer
+	sl := New(WithWAL[string, int](&log))
+
+	sl.Set("x", 1)
+	sl.Clear()
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	sl.ResetAndSnapshotWith(func(rank int, entry Entry[string, int]) (int, bool) {
+		return 10, rank == 1
+	})
+	sl.Set("c", 3)
+
+	replayed := New[string, int]()
+	if err := replayed.Replay(&log); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	requireSameEntries(t, sl, replayed)
+}
+
+func TestWALReplayEviction(t *testing.T) {
+	var log bytes.Buffer
+	sl := New(WithWAL[string, int](&log), WithMaxSize[string, int](2))
+
+	sl.Set("a", 5)
+	sl.Set("b", 6)
+	// 新插入的键自己被淘汰，日志中写入必须排在淘汰之前
+	// The new key is the one evicted, the log must record the write before the eviction
+	sl.Set("c", 1)
+	sl.Set("d", 7)
+
+	// 重放到没有容量限制的跳表，只靠日志中的淘汰记录得到相同的内容
+	// Replay into a list without a capacity, relying on the logged evictions alone
+	replayed := New[string, int]()
+	if err := replayed.Replay(&log); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	requireSameEntries(t, sl, replayed)
+}
+
+func TestWALReplayPlaceholders(t *testing.T) {
+	var log bytes.Buffer
+	sl := New(WithWAL[string, int](&log))
+
+	sl.Set("a", 1)
+	sl.Reserve("seed", 10)
+	sl.Set("seed", 20)
+	sl.Reserve("spare", 5)
+	sl.Suspend("spare")
+	sl.Resume("spare")
+	sl.Reserve("taken", 30)
+	sl.Claim("taken", "b")
+
+	replayed := New[string, int]()
+	if err := replayed.Replay(&log); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	requireSameEntries(t, sl, replayed)
+	for _, key := range []string{"a", "seed", "spare", "b"} {
+		if a, b := sl.IsPlaceholder(key), replayed.IsPlaceholder(key); a != b {
+			t.Fatalf("%s: placeholder %v after replay, expected %v", key, b, a)
+		}
+	}
+
+	// 批量替换记录的内容同样保留占位标记
+	// The content journaled by a bulk replacement keeps the mark as well
+	log.Reset()
+	other := New[string, int]()
+	other.Set("a", 1)
+	other.Reserve("late", 40)
+	if err := sl.ReplaceFrom(other); err != nil {
+		t.Fatal(err)
+	}
+	replayed = New[string, int]()
+	if err := replayed.Replay(&log); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	requireSameEntries(t, sl, replayed)
+	if !replayed.IsPlaceholder("late") || replayed.IsPlaceholder("a") {
+		t.Fatal("expected only late to replay as a placeholder")
+	}
+}
+
+func TestWALSnapshotAndTornTail(t *testing.T) {
+	var log bytes.Buffer
+	sl := New(WithWAL[string, int](&log))
+	for i := 0; i < 1000; i++ {
+		sl.Set(strconv.Itoa(rand.IntN(200)), rand.IntN(100))
+	}
+
+	var snapshot bytes.Buffer
+	if err := sl.Save(&snapshot); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pos := log.Len()
+
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(rand.IntN(200))
+		switch rand.IntN(3) {
+		case 0:
+			sl.Del(key)
+		case 1:
+			sl.IncrBy(key, rand.IntN(10))
+		default:
+			sl.Set(key, rand.IntN(100))
+		}
+	}
+
+	var expected bytes.Buffer
+	if err := sl.Save(&expected); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := New[string, int]()
+	if err := want.Load(&expected); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 最后一次写入只写了一半就崩溃
+	// The last write is torn by a crash
+	before := log.Len()
+	sl.Set("torn", 1)
+	tail := log.Bytes()[pos : before+(log.Len()-before)/2]
+
+	restored := New[string, int]()
+	if err := restored.Load(&snapshot); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := restored.Replay(bytes.NewReader(tail)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	requireSameEntries(t, want, restored)
+}
+
+func TestWALCorruptedFinalRecord(t *testing.T) {
+	var log bytes.Buffer
+	sl := New(WithWAL[string, int](&log))
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+
+	data := bytes.Clone(log.Bytes())
+	data[len(data)-1] ^= 0xff
+
+	replayed := New[string, int]()
+	if err := replayed.Replay(bytes.NewReader(data)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replayed.Length() != 1 {
+		t.Errorf("expected the corrupted final record to be skipped, got %d entries", replayed.Length())
+	}
+}
+
+func TestWALCorruptedLength(t *testing.T) {
+	var log bytes.Buffer
+	sl := New(WithWAL[string, int](&log))
+	sl.Set("a", 1)
+
+	// 长度字段声称接近 4 GiB 的记录，后面只有几个字节
+	// A length field claiming a record of almost 4 GiB, followed by a few bytes
+	data := bytes.Clone(log.Bytes())
+	data = append(data, 0xf0, 0xff, 0xff, 0xff, 1, 2, 3)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	replayed := New[string, int]()
+	if err := replayed.Replay(bytes.NewReader(data)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	runtime.ReadMemStats(&after)
+	if replayed.Length() != 1 {
+		t.Errorf("expected the corrupted tail to be skipped, got %d entries", replayed.Length())
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("expected the corrupted length not to size the allocation, allocated %d bytes", allocated)
+	}
+}
+
+func TestWALCorruptedMiddleRecord(t *testing.T) {
+	var log bytes.Buffer
+	sl := New(WithWAL[string, int](&log))
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+
+	data := bytes.Clone(log.Bytes())
+	data[6] ^= 0xff
+
+	if err := New[string, int]().Replay(bytes.NewReader(data)); !errors.Is(err, ErrInvalidWAL) {
+		t.Errorf("expected ErrInvalidWAL, got %v", err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWALWriteError(t *testing.T) {
+	sl := New(WithWAL[string, int](failingWriter{}))
+	sl.Set("a", 1)
+
+	if sl.WALError() == nil {
+		t.Errorf("expected the write error to be reported")
+	}
+	if value, _ := sl.Get("a"); value != 1 {
+		t.Errorf("mutation should still be applied, got %d", value)
+	}
+	if New[string, int]().WALError() != nil {
+		t.Errorf("expected no error without a WAL")
+	}
+}
@@ -0,0 +1,350 @@
+package ranklist
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// Store 外部持久化存储的写穿适配器，例如把每次修改同步写入数据库，崩溃后可以从中重建榜单而不需要预写日志文件
+// Store is a write-through adapter for external persistence, e.g. mirroring every mutation into a database
+// so the board can be rebuilt from it after a crash without a write-ahead log file
+type Store[K Ordered, V Ordered] interface {
+	// Put 保存键的新值
+	// Put saves the new value of key
+	Put(key K, value V) error
+
+	// Delete 删除键，键不存在时也应当成功
+	// Delete removes key, it should succeed when the key does not exist too
+	Delete(key K) error
+}
+
+// StoreClearer 是 Store 可以额外实现的接口，Clear 以及 Restore、Load 等整体替换内容的操作通过它清空外部存储。
+// 没有实现时这些操作无法清除外部存储中已有的键，会向 WithStoreErrorHandler 报告一个属于 ErrStore 的错误
+// StoreClearer is an interface a Store may additionally implement, Clear and the operations replacing
+// the whole content such as Restore and Load empty the external store through it.
+// Without it those operations cannot remove the keys already in the external store
+// and report an ErrStore to WithStoreErrorHandler
+type StoreClearer interface {
+	Clear() error
+}
+
+// 异步模式下排队等待写入外部存储的修改数量上限，队列满时写操作在写锁内等待
+// Maximum number of mutations queued for the external store in async mode, writers wait under the write lock when it is full
+const storeQueueSize = 1024
+
+// storeOp 一次排队写入外部存储的修改，op 与预写日志的操作码相同
+// storeOp is one mutation queued for the external store, op being the write-ahead log opcode
+type storeOp[K Ordered, V Ordered] struct {
+	op    byte
+	key   K
+	value V
+}
+
+// writeThrough 保存外部存储及其写入方式
+// writeThrough holds the external store and how it is written
+type writeThrough[K Ordered, V Ordered] struct {
+	store   Store[K, V]
+	sync    bool
+	onError func(err error)
+
+	// 异步模式下的修改队列，同步模式下为 nil
+	// Queue of mutations in async mode, nil in sync mode
+	queue chan storeOp[K, V]
+
+	// 保护 onError 的调用，使异步 goroutine 与写操作报告的错误不会并发
+	// Serializes onError so errors from the async goroutine and from writers are never reported concurrently
+	mu sync.Mutex
+}
+
+// WithStore 将每次修改写穿到外部存储 s，s 为 nil 时 panic。
+// sync 为 true 时，单键的写操作在修改内存之前于写锁内调用 s，s 返回错误时放弃这次修改，内存中的跳表保持不变：
+// SetChecked、TrySet、IncrByChecked、DelChecked、SetIfVersionChecked 和 Txn 返回属于 ErrStore 的错误，
+// Set、IncrBy、Del、IncrByClamped、DelByValue、UpdateByValue、Reserve、Claim、Suspend 和 Resume 按未修改返回，
+// AddAll 和 SetBatch 跳过失败的键。Txn 先把全部暂存的修改写入 s，任何一次失败都会放弃整个事务，但已经写入 s 的修改不会撤销。
+// 淘汰以及 Clear、Restore、Load 等整体替换内容的操作无法放弃，在修改之后写入 s，错误交给 WithStoreErrorHandler。
+// sync 为 false 时，所有修改在释放写锁之前按顺序进入队列，由一个后台 goroutine 依次写入 s，错误交给 WithStoreErrorHandler；
+// Close 会等待队列中的修改全部写完。开启 WithZeroMeansDelete 时零值的写入以 Delete 写穿
+// WithStore writes every mutation through to the external store s, it panics if s is nil.
+// With sync, single-key writes call s under the write lock before the in-memory change,
+// and an error from s abandons the change, leaving the list untouched:
+// SetChecked, TrySet, IncrByChecked, DelChecked, SetIfVersionChecked and Txn return an ErrStore,
+// Set, IncrBy, Del, IncrByClamped, DelByValue, UpdateByValue, Reserve, Claim, Suspend and Resume report no change,
+// and AddAll and SetBatch skip the failing keys. Txn writes every staged change to s first and any failure abandons
+// the whole transaction, though the changes already written to s are not undone.
+// Evictions and the operations replacing the whole content such as Clear, Restore and Load cannot be abandoned,
+// they are written to s after the change and errors go to WithStoreErrorHandler.
+// Without sync every mutation is queued in order before the write lock is released and a background goroutine
+// writes them to s one by one, with errors going to WithStoreErrorHandler;
+// Close waits until the queue has been written. With WithZeroMeansDelete a write of the zero value goes through as Delete
+func WithStore[K Ordered, V Ordered](s Store[K, V], sync bool) Option[K, V] {
+	if s == nil {
+		panic("ranklist: nil store")
+	}
+	return func(sl *RankList[K, V]) {
+		if sl.writeThrough == nil {
+			sl.writeThrough = &writeThrough[K, V]{}
+		}
+		sl.writeThrough.store = s
+		sl.writeThrough.sync = sync
+	}
+}
+
+// WithStoreErrorHandler 接收无法放弃修改时外部存储返回的错误，err 属于 ErrStore 并说明失败的键，
+// 在异步模式下由后台 goroutine 调用，其他情况下在写锁内调用，因此 fn 不能访问跳表。未设置时这些错误被丢弃
+// WithStoreErrorHandler receives the errors of the external store for changes that cannot be abandoned,
+// err being an ErrStore naming the failing key. It is called by the background goroutine in async mode
+// and under the write lock otherwise, so fn must not access the list. Without it those errors are dropped
+func WithStoreErrorHandler[K Ordered, V Ordered](fn func(err error)) Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		if sl.writeThrough == nil {
+			sl.writeThrough = &writeThrough[K, V]{}
+		}
+		sl.writeThrough.onError = fn
+	}
+}
+
+// startWriteThrough 在异步模式下创建队列并启动写入外部存储的后台 goroutine，在初始化时调用
+// startWriteThrough creates the queue and starts the background goroutine writing the external store in async mode,
+// called during initialization
+func (sl *RankList[K, V]) startWriteThrough() {
+	wt := sl.writeThrough
+	if wt == nil {
+		return
+	}
+	if wt.store == nil {
+		panic("ranklist: WithStoreErrorHandler requires WithStore")
+	}
+	if wt.sync {
+		return
+	}
+
+	wt.queue = make(chan storeOp[K, V], storeQueueSize)
+	sl.background.Add(1)
+	go func() {
+		defer sl.background.Done()
+		for {
+			select {
+			case op := <-wt.queue:
+				wt.report(wt.apply(op))
+			case <-sl.done:
+				// Close 之后不会再有修改入队，写完剩余的修改后退出
+				// Nothing is queued after Close, write what is left and exit
+				for {
+					select {
+					case op := <-wt.queue:
+						wt.report(wt.apply(op))
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+}
+
+// apply 将一次修改写入外部存储，返回属于 ErrStore 的错误
+// apply writes one mutation to the external store and returns an ErrStore on failure
+func (wt *writeThrough[K, V]) apply(op storeOp[K, V]) error {
+	var err error
+	switch op.op {
+	case walOpSet:
+		if err = wt.store.Put(op.key, op.value); err != nil {
+			return fmt.Errorf("%w: put %v: %w", ErrStore, op.key, err)
+		}
+	case walOpDel:
+		if err = wt.store.Delete(op.key); err != nil {
+			return fmt.Errorf("%w: delete %v: %w", ErrStore, op.key, err)
+		}
+	case walOpClear:
+		clearer, ok := wt.store.(StoreClearer)
+		if !ok {
+			return fmt.Errorf("%w: clear: store does not implement StoreClearer", ErrStore)
+		}
+		if err = clearer.Clear(); err != nil {
+			return fmt.Errorf("%w: clear: %w", ErrStore, err)
+		}
+	}
+	return nil
+}
+
+// report 将错误交给 WithStoreErrorHandler，err 为 nil 或未设置时不做任何事
+// report hands err to WithStoreErrorHandler, it does nothing when err is nil or no handler is set
+func (wt *writeThrough[K, V]) report(err error) {
+	if err == nil || wt.onError == nil {
+		return
+	}
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+	wt.onError(err)
+}
+
+// persist 在修改内存之前写穿一次修改：同步模式下直接写入外部存储并返回它的错误，异步模式下入队并返回 nil。
+// Store 无法表示占位标记，插入或写入占位条目时什么也不写。必须在修改已经确定会发生之后调用，调用方需持有写锁
+// persist writes one mutation through before the in-memory change: in sync mode it writes the external store
+// and returns its error, in async mode it queues the mutation and returns nil.
+// A Store cannot hold the placeholder mark, so reserving or writing a placeholder writes nothing.
+// It must be called once the change is certain to happen. The caller must hold the write lock
+func (sl *RankList[K, V]) persist(op byte, key K, value V) error {
+	wt := sl.writeThrough
+	if wt == nil {
+		return nil
+	}
+	switch op {
+	case walOpReserve:
+		return nil
+	case walOpSet:
+		if node, exists := sl.lookup(key); exists && node.placeholder {
+			return nil
+		}
+	}
+	if !wt.sync {
+		wt.queue <- storeOp[K, V]{op: op, key: key, value: value}
+		return nil
+	}
+	return wt.apply(storeOp[K, V]{op: op, key: key, value: value})
+}
+
+// persistWrite 写穿将键写为 value 的修改，开启 WithZeroMeansDelete 且 value 是零值时写穿删除，
+// 键本来就不存在时什么也不写，与 store 的处理一致，调用方需持有写锁
+// persistWrite writes through setting key to value, with WithZeroMeansDelete and a zero value a delete goes through,
+// or nothing when the key is missing anyway, matching what store does. The caller must hold the write lock
+func (sl *RankList[K, V]) persistWrite(key K, value V) error {
+	if sl.writeThrough == nil {
+		return nil
+	}
+	if sl.zeroDeletes && value == ZeroValue[V]() {
+		if _, exists := sl.lookup(key); !exists {
+			return nil
+		}
+		return sl.persist(walOpDel, key, value)
+	}
+	return sl.persist(walOpSet, key, value)
+}
+
+// mirror 在修改之后写穿一次无法放弃的修改，同步模式下的错误交给 WithStoreErrorHandler，调用方需持有写锁
+// mirror writes through a mutation that cannot be abandoned after the change,
+// errors in sync mode go to WithStoreErrorHandler. The caller must hold the write lock
+func (sl *RankList[K, V]) mirror(op byte, key K, value V) {
+	if sl.writeThrough != nil {
+		sl.writeThrough.report(sl.persist(op, key, value))
+	}
+}
+
+// mirrorReset 在整体替换内容之后写穿清空和当前的全部条目，调用方需持有写锁
+// mirrorReset writes through a clear followed by every current entry after the whole content was replaced.
+// The caller must hold the write lock
+func (sl *RankList[K, V]) mirrorReset() {
+	if sl.writeThrough == nil {
+		return
+	}
+	sl.mirror(walOpClear, ZeroValue[K](), ZeroValue[V]())
+	for curr := sl.header.forward[0]; curr != nil; curr = curr.forward[0] {
+		sl.mirror(walOpSet, curr.data.Key, curr.data.Value)
+	}
+}
+
+// MemStore 是保存在内存中的 Store，主要用于测试，可以被多个 goroutine 并发使用。
+// Fail 不为 nil 时每次写入之前调用它，返回的错误使这次写入失败，用于模拟外部存储故障
+// MemStore is a Store kept in memory, mainly for tests, safe for concurrent use by multiple goroutines.
+// When Fail is not nil it is called before every write and an error it returns fails that write,
+// for simulating failures of the external store
+type MemStore[K Ordered, V Ordered] struct {
+	mu     sync.Mutex
+	values map[K]V
+
+	// 模拟故障的回调，op 为 "put"、"delete" 或 "clear"
+	// Callback simulating failures, op being "put", "delete" or "clear"
+	Fail func(op string, key K) error
+}
+
+// NewMemStore 创建一个空的 MemStore
+// NewMemStore creates an empty MemStore
+func NewMemStore[K Ordered, V Ordered]() *MemStore[K, V] {
+	return &MemStore[K, V]{values: make(map[K]V)}
+}
+
+// Put 实现 Store
+// Put implements Store
+func (m *MemStore[K, V]) Put(key K, value V) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("put", key); err != nil {
+		return err
+	}
+	m.values[key] = value
+	return nil
+}
+
+// Delete 实现 Store
+// Delete implements Store
+func (m *MemStore[K, V]) Delete(key K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("delete", key); err != nil {
+		return err
+	}
+	delete(m.values, key)
+	return nil
+}
+
+// Clear 实现 StoreClearer
+// Clear implements StoreClearer
+func (m *MemStore[K, V]) Clear() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("clear", ZeroValue[K]()); err != nil {
+		return err
+	}
+	clear(m.values)
+	return nil
+}
+
+// Get 返回保存的键值
+// Get returns the stored value of key
+func (m *MemStore[K, V]) Get(key K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Entries 按排名顺序返回保存的全部条目，可以直接传给 RankList.Restore 重建榜单
+// Entries returns every stored entry in rank order, ready to be passed to RankList.Restore to rebuild the board
+func (m *MemStore[K, V]) Entries() []Entry[K, V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries := make([]Entry[K, V], 0, len(m.values))
+	for key, value := range m.values {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+	}
+	return sortEntries(entries)
+}
+
+// All 遍历保存的全部键值，顺序不确定，可以直接传给 RankList.HydrateFrom。遍历的是调用时的快照，期间不持有锁
+// All iterates every stored key and value in no particular order, ready to be passed to RankList.HydrateFrom.
+// It iterates a snapshot taken when called and holds no lock meanwhile
+func (m *MemStore[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.mu.Lock()
+		values := make(map[K]V, len(m.values))
+		for key, value := range m.values {
+			values[key] = value
+		}
+		m.mu.Unlock()
+		for key, value := range values {
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// fail 调用 Fail 模拟故障，调用方需持有 m.mu
+// fail consults Fail to simulate a failure, the caller must hold m.mu
+func (m *MemStore[K, V]) fail(op string, key K) error {
+	if m.Fail == nil {
+		return nil
+	}
+	return m.Fail(op, key)
+}
@@ -0,0 +1,287 @@
+package ranklist
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// errStoreDown 是测试中模拟的外部存储故障
+// errStoreDown is the simulated failure of the external store in tests
+var errStoreDown = errors.New("store down")
+
+// requireMirrored 检查外部存储与跳表的内容一致
+// requireMirrored checks that the external store holds the same content as the list
+func requireMirrored(t *testing.T, sl *RankList[string, int], store *MemStore[string, int]) {
+	t.Helper()
+	if got, want := store.Entries(), sl.Entries(); !slices.Equal(got, want) {
+		t.Fatalf("store holds %v, list holds %v", got, want)
+	}
+}
+
+func TestStoreSyncMirrors(t *testing.T) {
+	store := NewMemStore[string, int]()
+	sl := New(WithStore[string, int](store, true))
+
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	sl.IncrBy("a", 5)
+	sl.AddAll(map[string]int{"c": 3, "b": 1})
+	sl.SetBatch([]Entry[string, int]{{"d", 4}, {"e", 5}})
+	sl.Del("e")
+	requireMirrored(t, sl, store)
+
+	if err := sl.Txn(func(tx *Tx[string, int]) error {
+		tx.Set("f", 6)
+		tx.Del("d")
+		_, err := tx.IncrBy("a", -2)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	requireMirrored(t, sl, store)
+
+	sl.Suspend("a")
+	requireMirrored(t, sl, store)
+	sl.Resume("a")
+	sl.Reserve("seed", 10)
+	sl.Claim("seed", "g")
+	sl.UpdateByValue("c", 3, 30)
+	sl.DelByValue("b", 3)
+	_, version, _ := sl.GetVersioned("f")
+	sl.SetIfVersion("f", 60, version)
+	requireMirrored(t, sl, store)
+
+	sl.Restore([]Entry[string, int]{{"x", 1}, {"y", 2}})
+	requireMirrored(t, sl, store)
+	sl.Clear()
+	requireMirrored(t, sl, store)
+}
+
+func TestStoreSyncAbort(t *testing.T) {
+	store := NewMemStore[string, int]()
+	sl := New(WithStore[string, int](store, true))
+	sl.Set("a", 1)
+	sl.Set("x", 2)
+	store.Fail = func(op string, key string) error {
+		if key == "x" || key == "y" {
+			return errStoreDown
+		}
+		return nil
+	}
+
+	before, version := sl.Entries(), sl.Version()
+	unchanged := func(step string) {
+		t.Helper()
+		if !slices.Equal(sl.Entries(), before) || sl.Version() != version {
+			t.Fatalf("%s: the list changed to %v", step, sl.Entries())
+		}
+		requireMirrored(t, sl, store)
+	}
+
+	if _, err := sl.SetChecked("x", 5); !errors.Is(err, ErrStore) || !errors.Is(err, errStoreDown) {
+		t.Fatalf("SetChecked: expected ErrStore wrapping the store error, got %v", err)
+	}
+	unchanged("SetChecked")
+	if sl.Set("y", 1) {
+		t.Fatal("Set should report no insert when the store fails")
+	}
+	unchanged("Set")
+	if _, err := sl.IncrByChecked("x", 1); !errors.Is(err, ErrStore) {
+		t.Fatalf("IncrByChecked: expected ErrStore, got %v", err)
+	}
+	unchanged("IncrByChecked")
+	if err := sl.DelChecked("x"); !errors.Is(err, ErrStore) {
+		t.Fatalf("DelChecked: expected ErrStore, got %v", err)
+	}
+	unchanged("DelChecked")
+	if sl.Suspend("x") || sl.DelByValue("x", 2) || sl.UpdateByValue("x", 2, 3) {
+		t.Fatal("bool mutators should report no change when the store fails")
+	}
+	unchanged("bool mutators")
+
+	// 事务中任何一个修改写穿失败都会放弃整个事务
+	// A transaction is abandoned as a whole when any of its changes fails to go through
+	err := sl.Txn(func(tx *Tx[string, int]) error {
+		tx.Set("a", 10)
+		tx.Set("x", 20)
+		return nil
+	})
+	if !errors.Is(err, ErrStore) {
+		t.Fatalf("Txn: expected ErrStore, got %v", err)
+	}
+	if value, _ := sl.Get("a"); value != 1 {
+		t.Fatalf("Txn: expected a to stay at 1, got %d", value)
+	}
+
+	// 失败之前已经写入外部存储的修改不会撤销，重新写入即可恢复一致
+	// The changes written to the store before the failure are not undone, writing the key again reconciles it
+	if value, _ := store.Get("a"); value != 10 {
+		t.Fatalf("Txn: expected the store to keep a at 10, got %d", value)
+	}
+	sl.Set("a", 1)
+	unchanged("Txn")
+
+	// 批量写入跳过失败的键
+	// Batch writes skip the failing keys
+	inserted, updated := sl.SetBatch([]Entry[string, int]{{"b", 2}, {"x", 9}, {"y", 9}})
+	if inserted != 1 || updated != 0 {
+		t.Fatalf("SetBatch: expected 1 insert, got %d, %d", inserted, updated)
+	}
+	values := sl.AddAll(map[string]int{"b": 1, "x": 1})
+	if values["b"] != 3 || values["x"] != 2 {
+		t.Fatalf("AddAll: expected b 3 and x left at 2, got %v", values)
+	}
+	requireMirrored(t, sl, store)
+	if err := sl.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStorePlaceholders(t *testing.T) {
+	store := NewMemStore[string, int]()
+	sl := New(WithStore[string, int](store, true))
+	sl.Set("a", 1)
+
+	// 占位条目及对它的写入都不写穿，Claim 之后才进入外部存储
+	// Neither the placeholder nor writes to it go through, it reaches the store once claimed
+	sl.Reserve("seed", 10)
+	sl.Set("seed", 20)
+	sl.Suspend("seed")
+	sl.Resume("seed")
+	other := New[string, int]()
+	other.Set("a", 1)
+	other.Reserve("seed", 20)
+	if err := sl.ReplaceFrom(other); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Get("seed"); ok {
+		t.Fatal("a placeholder should never reach the store")
+	}
+	if !sl.IsPlaceholder("seed") {
+		t.Fatal("expected seed to stay a placeholder")
+	}
+
+	sl.Claim("seed", "b")
+	if value, ok := store.Get("b"); !ok || value != 20 {
+		t.Fatalf("expected the claimed entry in the store, got %d, %v", value, ok)
+	}
+	requireMirrored(t, sl, store)
+}
+
+func TestStoreZeroMeansDelete(t *testing.T) {
+	store := NewMemStore[string, int]()
+	sl := New(WithStore[string, int](store, true), WithZeroMeansDelete[string, int]())
+	sl.Set("a", 2)
+	sl.IncrBy("a", -2)
+	sl.Set("b", 0)
+	if _, ok := store.Get("a"); ok || sl.Length() != 0 {
+		t.Fatal("a zero write should delete the key from the store as well")
+	}
+	requireMirrored(t, sl, store)
+}
+
+func TestStoreAsync(t *testing.T) {
+	store := NewMemStore[string, int]()
+	store.Fail = func(op string, key string) error {
+		if key == "bad" {
+			return errStoreDown
+		}
+		return nil
+	}
+	var mu sync.Mutex
+	var reported []error
+	sl := New(
+		WithStore[string, int](store, false),
+		WithStoreErrorHandler[string, int](func(err error) {
+			mu.Lock()
+			reported = append(reported, err)
+			mu.Unlock()
+		}),
+	)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				key := strconv.Itoa(i % 300)
+				switch i % 5 {
+				case 0:
+					sl.Del(key)
+				case 1:
+					sl.IncrBy(key, g+1)
+				default:
+					sl.Set(key, i*g)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 异步模式下外部存储的失败不影响内存中的修改
+	// In async mode a failure of the external store does not affect the in-memory change
+	if !sl.Set("bad", 1) {
+		t.Fatal("Set should succeed in async mode regardless of the store")
+	}
+	if err := sl.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := slices.DeleteFunc(sl.Entries(), func(entry Entry[string, int]) bool { return entry.Key == "bad" })
+	if got := store.Entries(); !slices.Equal(got, want) {
+		t.Fatalf("after Close the store holds %d entries, the list %d", len(got), len(want))
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], ErrStore) || !errors.Is(reported[0], errStoreDown) {
+		t.Fatalf("expected one ErrStore for the failing key, got %v", reported)
+	}
+
+	// 从外部存储重建的榜单与原来的一致
+	// A board rebuilt from the external store matches the original
+	rebuilt := New[string, int]()
+	rebuilt.Restore(store.Entries())
+	if !slices.Equal(rebuilt.Entries(), want) {
+		t.Fatal("the board rebuilt from the store differs")
+	}
+}
+
+// putDeleteStore 只实现 Put 和 Delete 的 Store，嵌入接口不会带上 MemStore 的 Clear
+// putDeleteStore is a Store implementing only Put and Delete, embedding the interface hides the Clear of MemStore
+type putDeleteStore struct {
+	Store[string, int]
+}
+
+func TestStoreWithoutClearer(t *testing.T) {
+	var reported []error
+	sl := New(
+		WithStore[string, int](putDeleteStore{NewMemStore[string, int]()}, true),
+		WithStoreErrorHandler[string, int](func(err error) { reported = append(reported, err) }),
+	)
+	sl.Set("a", 1)
+	sl.Clear()
+	if sl.Length() != 0 {
+		t.Fatal("Clear cannot be abandoned and must empty the list")
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], ErrStore) {
+		t.Fatalf("expected one ErrStore for the missing StoreClearer, got %v", reported)
+	}
+}
+
+func TestStoreOptionsPanic(t *testing.T) {
+	for name, fn := range map[string]func(){
+		"nil store":     func() { WithStore[string, int](nil, true) },
+		"handler alone": func() { New(WithStoreErrorHandler[string, int](func(error) {})) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s: expected a panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,49 @@
+package ranklist
+
+import "math"
+
+// 本文件提供排名从 0 开始的并行方法。Rank、Range 和 RevRange 的排名从 1 开始，
+// 以 0 结尾的方法排名从 0 开始，两者只在返回和接收排名时相差 1，同一个跳表可以同时使用两套方法而不会互相影响
+// This file provides parallel methods with 0-based ranks. Rank, Range and RevRange use 1-based ranks,
+// the methods ending in 0 use 0-based ranks; the two only differ by one in the ranks they return and accept,
+// so both sets can be used on the same list without affecting each other
+
+// Rank0 与 Rank 相同，但排名从 0 开始，值最小的条目排名为 0
+// Rank0 behaves like Rank with 0-based ranks, the entry with the smallest value has rank 0
+func (sl *RankList[K, V]) Rank0(key K) (int, bool) {
+	rank, ok := sl.Rank(key)
+	if !ok {
+		return 0, false
+	}
+	return rank - 1, true
+}
+
+// Range0 与 Range 相同，但排名从 0 开始，返回排名区间 [start, end) 内的条目，start 小于 0 时按 0 处理。
+// 例如 Range0(0, 2) 返回值最小的两个条目
+// Range0 behaves like Range with 0-based ranks, returning the entries within [start, end),
+// a start below 0 is treated as 0.
+// For example Range0(0, 2) returns the two entries with the smallest values
+func (sl *RankList[K, V]) Range0(start int, end int) []Entry[K, V] {
+	return sl.Range(oneBased(start), oneBased(end))
+}
+
+// RevRange0 与 RevRange 相同，但倒序排名从 0 开始，值最大的条目倒序排名为 0
+// RevRange0 behaves like RevRange with 0-based reverse ranks, the entry with the largest value has reverse rank 0
+func (sl *RankList[K, V]) RevRange0(start int, end int) []Entry[K, V] {
+	return sl.RevRange(oneBased(start), oneBased(end))
+}
+
+// oneBased 将从 0 开始的排名转换为从 1 开始的排名，math.MaxInt 保持不变以免溢出
+// oneBased converts a 0-based rank to a 1-based one, math.MaxInt is kept as is to avoid overflow
+func oneBased(rank int) int {
+	if rank == math.MaxInt {
+		return rank
+	}
+	return rank + 1
+}
+
+// GetByRank0 与 GetByRank 相同，但排名从 0 开始，超出 [0, Length()) 时返回 false
+// GetByRank0 behaves like GetByRank with a 0-based rank, returns false outside [0, Length())
+func (sl *RankList[K, V]) GetByRank0(rank int) (Entry[K, V], bool) {
+	return sl.GetByRank(oneBased(rank))
+}
@@ -0,0 +1,94 @@
+package ranklist
+
+import (
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+)
+
+func TestRank0(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 10)
+	sl.Set("b", 20)
+	sl.Set("c", 30)
+
+	for i, key := range []string{"a", "b", "c"} {
+		if rank, ok := sl.Rank0(key); !ok || rank != i {
+			t.Errorf("Key %s: expected rank %d, got %d, %v", key, i, rank, ok)
+		}
+	}
+	if rank, ok := sl.Rank0("x"); ok || rank != 0 {
+		t.Errorf("missing key: expected 0, false, got %d, %v", rank, ok)
+	}
+}
+
+func TestRange0Semantics(t *testing.T) {
+	sl := New[string, int]()
+	for i, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		sl.Set(key, (i+1)*10)
+	}
+
+	// 与 TestRangeSemantics 相同的期望，排名整体减一：want[end+1] 是 Range0(start, end) 的结果，end 取 -1 到 7
+	// The expectations of TestRangeSemantics shifted down by one: want[end+1] is Range0(start, end), for end from -1 to 7
+	tests := []struct {
+		start int
+		want  [9]string
+	}{
+		{-2, [9]string{"", "", "a", "ab", "abc", "abcd", "abcde", "abcdef", "abcdef"}},
+		{-1, [9]string{"", "", "a", "ab", "abc", "abcd", "abcde", "abcdef", "abcdef"}},
+		{0, [9]string{"", "", "a", "ab", "abc", "abcd", "abcde", "abcdef", "abcdef"}},
+		{1, [9]string{"", "", "", "b", "bc", "bcd", "bcde", "bcdef", "bcdef"}},
+		{2, [9]string{"", "", "", "", "c", "cd", "cde", "cdef", "cdef"}},
+		{3, [9]string{"", "", "", "", "", "d", "de", "def", "def"}},
+		{4, [9]string{"", "", "", "", "", "", "e", "ef", "ef"}},
+		{5, [9]string{"", "", "", "", "", "", "", "f", "f"}},
+		{6, [9]string{"", "", "", "", "", "", "", "", ""}},
+		{7, [9]string{"", "", "", "", "", "", "", "", ""}},
+	}
+	for _, tt := range tests {
+		for i, want := range tt.want {
+			end := i - 1
+			got := ""
+			for _, entry := range sl.Range0(tt.start, end) {
+				got += entry.Key
+			}
+			if got != want {
+				t.Errorf("Range0(%d, %d): expected %q, got %q", tt.start, end, want, got)
+			}
+		}
+	}
+}
+
+func TestZeroBasedMatchesOneBased(t *testing.T) {
+	sl := New[string, int]()
+	for i := 0; i < 500; i++ {
+		sl.Set(strconv.Itoa(i), rand.IntN(50))
+	}
+
+	for i := 0; i < 500; i++ {
+		key := strconv.Itoa(i)
+		rank, _ := sl.Rank(key)
+		rank0, _ := sl.Rank0(key)
+		if rank0 != rank-1 {
+			t.Fatalf("Key %s: Rank0 %d does not match Rank %d", key, rank0, rank)
+		}
+		if got := sl.Range0(rank0, rank0+1); len(got) != 1 || got[0].Key != key {
+			t.Fatalf("Key %s: Range0(%d, %d) returned %v", key, rank0, rank0+1, got)
+		}
+	}
+
+	if got := sl.Range0(0, math.MaxInt); len(got) != sl.Length() {
+		t.Fatalf("Range0(0, math.MaxInt): expected %d entries, got %d", sl.Length(), len(got))
+	}
+
+	top := sl.RevRange0(0, 10)
+	if len(top) != 10 {
+		t.Fatalf("expected 10 entries, got %d", len(top))
+	}
+	for i, entry := range sl.RevRange(1, 11) {
+		if top[i] != entry {
+			t.Fatalf("RevRange0 index %d: expected %v, got %v", i, entry, top[i])
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package ranklist
+
+// storeResult 描述一次 store 对键做了什么
+// storeResult describes what one store did to its key
+type storeResult int
+
+const (
+	storeInserted storeResult = iota
+	storeUpdated
+	storeDeleted
+	storeSkipped
+)
+
+// WithZeroMeansDelete 让结果为 V 的零值的写入删除键而不是保存零值，与 Redis 中零分即下榜的用法相同，Length 因此只统计非零的键。
+// 适用于 Set、SetChecked、TrySet、IncrBy 及其变体、AddAll、SetBatch 及其变体和 Txn；
+// 写入零值时键不存在则什么也不做，这些方法按未插入处理，IncrBy 返回零值。
+// 未开启时零值与其他值一样可以保存，排在所有正值之前
+// WithZeroMeansDelete makes writes resulting in the zero value of V delete the key instead of storing a zero,
+// like boards where a zero score drops the member off in Redis, so Length only counts non-zero keys.
+// It covers Set, SetChecked, TrySet, IncrBy and its variants, AddAll, SetBatch and its variants and Txn;
+// writing a zero for a missing key does nothing, those methods report it as not inserted and IncrBy returns the zero value.
+// Without it a zero is stored like any other value, ranking ahead of every positive value
+func WithZeroMeansDelete[K Ordered, V Ordered]() Option[K, V] {
+	return func(sl *RankList[K, V]) {
+		sl.zeroDeletes = true
+	}
+}
+
+// store 写入键值对并记录到日志，event 是这次写入的键空间事件名；
+// 开启 WithZeroMeansDelete 且 value 是零值时改为删除键，调用方需持有写锁
+// store writes a key-value pair and journals it, event being the keyspace event name of the write;
+// with WithZeroMeansDelete and a zero value the key is deleted instead. The caller must hold the write lock
+func (sl *RankList[K, V]) store(key K, value V, event string) storeResult {
+	old, exists := sl.lookup(key)
+	return sl.storeNode(old, exists, key, value, event)
+}
+
+// storeNode 与 store 相同，exists 为 true 时 old 是键当前所在的节点，
+// 供已经按值下降找到节点的调用方跳过按键查找，调用方需持有写锁
+// storeNode behaves like store, old being the node currently holding key when exists is true,
+// so callers that already found the node by a descent by value skip the lookup by key. The caller must hold the write lock
+func (sl *RankList[K, V]) storeNode(old *Node[K, V], exists bool, key K, value V, event string) storeResult {
+	if sl.zeroDeletes && value == ZeroValue[V]() {
+		var deleted bool
+		if exists {
+			deleted, _ = sl.delNode(key, old)
+		} else {
+			deleted, _ = sl.del(key)
+		}
+		if !deleted {
+			return storeSkipped
+		}
+		delete(sl.meta, key)
+		sl.journal(walOpDel, key, value)
+		return storeDeleted
+	}
+	inserted := sl.setNode(old, exists, key, value, sl.randomLevel())
+	sl.journalAs(walOpSet, event, key, value)
+	sl.evictOverflow()
+	if inserted {
+		return storeInserted
+	}
+	return storeUpdated
+}
+
+// count 按写入结果递增对应的操作计数，未开启时不做任何事
+// count bumps the operation counters matching the result of a write, it does nothing when disabled
+func (r storeResult) count(vars *opVars) {
+	switch r {
+	case storeInserted:
+		vars.add(opSet)
+	case storeUpdated:
+		vars.add(opSet)
+		vars.add(opUpdate)
+	case storeDeleted:
+		vars.add(opDel)
+	}
+}
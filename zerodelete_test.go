@@ -0,0 +1,102 @@
+package ranklist
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestZeroValueStored(t *testing.T) {
+	sl := New[string, int]()
+	sl.Set("a", 5)
+	sl.Set("b", 1)
+
+	// 键和值都与头节点的零值相同的条目也能正常保存和查询
+	// An entry whose key and value both equal the header's zero values is stored and found like any other
+	if !sl.Set("", 0) || !sl.Set("zero", 0) {
+		t.Fatalf("expected the zero values to be inserted")
+	}
+	if sl.Length() != 4 {
+		t.Fatalf("expected 4 entries, got %d", sl.Length())
+	}
+	if rank, ok := sl.Rank(""); !ok || rank != 1 {
+		t.Fatalf("expected the empty key with a zero value first, got %d, %v", rank, ok)
+	}
+	if value, ok := sl.Get("zero"); !ok || value != 0 {
+		t.Fatalf("expected the stored zero, got %d, %v", value, ok)
+	}
+	if entries := sl.Range(1, 3); !slices.Equal(entries, []Entry[string, int]{{"", 0}, {"zero", 0}}) {
+		t.Fatalf("unexpected Range result %v", entries)
+	}
+	if value := sl.IncrBy("a", -5); value != 0 || sl.Length() != 4 {
+		t.Fatalf("IncrBy down to zero should keep the key, got %d and length %d", value, sl.Length())
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatalf("corrupted list: %v", err)
+	}
+}
+
+func TestZeroMeansDelete(t *testing.T) {
+	var wal bytes.Buffer
+	sl := New(WithZeroMeansDelete[string, int](), WithWAL[string, int](&wal))
+	sl.Set("a", 5)
+	sl.Set("b", 3)
+	sl.Set("c", 7)
+	sl.SetMeta("a", map[string]string{"team": "red"})
+
+	if sl.Set("a", 0) {
+		t.Fatalf("a zero write should not report an insert")
+	}
+	if _, ok := sl.Get("a"); ok || sl.Length() != 2 {
+		t.Fatalf("expected a to be deleted, length %d", sl.Length())
+	}
+	if _, ok := sl.GetMeta("a"); ok {
+		t.Fatalf("the metadata should go with the key")
+	}
+	if sl.Set("missing", 0) || sl.Length() != 2 {
+		t.Fatalf("a zero write for a missing key should do nothing")
+	}
+
+	// 增量减到零时删除键
+	// Incrementing down to zero deletes the key
+	if value := sl.IncrBy("b", -1); value != 2 {
+		t.Fatalf("expected 2, got %d", value)
+	}
+	if value := sl.IncrBy("b", -2); value != 0 {
+		t.Fatalf("expected 0, got %d", value)
+	}
+	if _, ok := sl.Get("b"); ok {
+		t.Fatalf("expected b to be deleted")
+	}
+	if value, clamped := sl.IncrByClamped("c", -10, 0, 100); value != 0 || clamped != -1 {
+		t.Fatalf("expected a clamp to 0, got %d, %d", value, clamped)
+	}
+	if sl.Length() != 0 {
+		t.Fatalf("expected an empty list, got %v", sl.Entries())
+	}
+
+	if values := sl.AddAll(map[string]int{"x": 4, "y": 0}); values["x"] != 4 || values["y"] != 0 {
+		t.Fatalf("unexpected AddAll result %v", values)
+	}
+	if inserted, updated := sl.SetBatch([]Entry[string, int]{{"z", 1}, {"x", 0}, {"w", 0}}); inserted != 1 || updated != 0 {
+		t.Fatalf("zero writes should count as neither inserts nor updates, got %d, %d", inserted, updated)
+	}
+	sl.Txn(func(tx *Tx[string, int]) error {
+		_, err := tx.IncrBy("z", -1)
+		return err
+	})
+	if sl.Length() != 0 {
+		t.Fatalf("expected an empty list, got %v", sl.Entries())
+	}
+
+	replayed := New[string, int]()
+	if err := replayed.Replay(&wal); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replayed.Length() != 0 {
+		t.Fatalf("the log should record the deletes, replayed %v", replayed.Entries())
+	}
+	if err := sl.Check(); err != nil {
+		t.Fatalf("corrupted list: %v", err)
+	}
+}